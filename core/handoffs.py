@@ -460,8 +460,15 @@ class HandoffsMixin:
         files_pattern = re.compile(r"^\s*-\s*\*\*Files\*\*:\s*(.*)$")
         # Pattern for description line: - **Description**: desc
         desc_pattern = re.compile(r"^\s*-\s*\*\*Description\*\*:\s*(.*)$")
-        # Pattern for tried item: N. [outcome] description
-        tried_pattern = re.compile(r"^\s*\d+\.\s*\[(\w+)\]\s*(.+)$")
+        # Pattern for tried item: N. [outcome] description or N. [outcome YYYY-MM-DD] description
+        tried_pattern = re.compile(r"^\s*\d+\.\s*\[(\w+)(?:\s+(\d{4}-\d{2}-\d{2}))?\]\s*(.+)$")
+        # Any metadata line: - **Name**: value
+        metadata_pattern = re.compile(r"^\s*-\s*\*\*([^*]+)\*\*:")
+        # Metadata fields parsed below; other fields (e.g. Priority and Stale,
+        # written by the Go CLI) are kept verbatim so a rewrite doesn't drop them
+        known_metadata = {"Refs", "Files", "Description", "Checkpoint", "Last Session", "Blocked By", "Sessions"}
+        # Section header: **Name**: or **Tried** (3 steps):
+        section_pattern = re.compile(r"^\*\*([^*]+)\*\*(?:\s*\([^)]*\))?:")
 
         idx = 0
         while idx < len(lines):
@@ -522,6 +529,17 @@ class HandoffsMixin:
                 # Malformed - skip this handoff
                 continue
 
+            # Pull unknown metadata lines out of the metadata block so the
+            # fixed-order parsing below sees only the fields it knows
+            extra_metadata = []
+            scan = idx
+            while scan < len(lines) and lines[scan].strip() and lines[scan].strip() != "---":
+                metadata_match = metadata_pattern.match(lines[scan])
+                if metadata_match and metadata_match.group(1) not in known_metadata:
+                    extra_metadata.append(lines.pop(scan).strip())
+                    continue
+                scan += 1
+
             # Parse refs/files line - try new Refs format first, then legacy Files format
             refs = []
             if idx < len(lines):
@@ -645,8 +663,9 @@ class HandoffsMixin:
 
             # Parse tried section
             tried = []
-            # Look for **Tried**: header
-            while idx < len(lines) and not lines[idx].strip().startswith("**Tried**"):
+            # Look for **Tried**: header, stopping at any other section (the
+            # Go CLI omits an empty Tried section) or the separator
+            while idx < len(lines) and not section_pattern.match(lines[idx].strip()) and lines[idx].strip() != "---":
                 idx += 1
             if idx < len(lines) and "**Tried**:" in lines[idx]:
                 idx += 1
@@ -658,14 +677,24 @@ class HandoffsMixin:
                     if tried_match:
                         tried.append(TriedStep(
                             outcome=tried_match.group(1),
-                            description=tried_match.group(2).strip()
+                            description=tried_match.group(3).strip(),
+                            logged=tried_match.group(2),
                         ))
                     idx += 1
 
+            # Keep sections this version doesn't parse (Milestones, Notes, Time
+            # Log, and Checkpoint History from the Go CLI) verbatim, blank
+            # lines included, up to **Next**
+            extra_sections = []
+            while idx < len(lines) and lines[idx].strip() != "---" and not lines[idx].strip().startswith("**Next**"):
+                if extra_sections or section_pattern.match(lines[idx].strip()):
+                    extra_sections.append(lines[idx].rstrip())
+                idx += 1
+            while extra_sections and not extra_sections[-1].strip():
+                extra_sections.pop()
+
             # Parse next steps
             next_steps = ""
-            while idx < len(lines) and not lines[idx].strip().startswith("**Next**"):
-                idx += 1
             if idx < len(lines) and "**Next**:" in lines[idx]:
                 # Extract text after **Next**:
                 next_match = re.match(r"^\*\*Next\*\*:\s*(.*)$", lines[idx].strip())
@@ -696,6 +725,8 @@ class HandoffsMixin:
                 blocked_by=blocked_by,
                 stealth=stealth,
                 sessions=sessions,
+                extra_metadata=extra_metadata,
+                extra_sections=extra_sections,
             ))
 
         return handoffs
@@ -706,6 +737,7 @@ class HandoffsMixin:
             f"### [{handoff.id}] {handoff.title}",
             f"- **Status**: {handoff.status} | **Phase**: {handoff.phase} | **Agent**: {handoff.agent}",
             f"- **Created**: {handoff.created.isoformat()} | **Updated**: {handoff.updated.isoformat()}",
+            *handoff.extra_metadata,
             f"- **Refs**: {' | '.join(handoff.refs)}",
             f"- **Description**: {handoff.description}",
         ]
@@ -743,7 +775,12 @@ class HandoffsMixin:
 
         lines.append("**Tried**:")
         for i, tried in enumerate(handoff.tried, 1):
-            lines.append(f"{i}. [{tried.outcome}] {tried.description}")
+            outcome = f"{tried.outcome} {tried.logged}" if tried.logged else tried.outcome
+            lines.append(f"{i}. [{outcome}] {tried.description}")
+
+        if handoff.extra_sections:
+            lines.append("")
+            lines.extend(handoff.extra_sections)
 
         lines.append("")
         lines.append(f"**Next**: {handoff.next_steps}")
@@ -1040,7 +1077,7 @@ class HandoffsMixin:
                 for handoff in handoffs:
                     if handoff.id == handoff_id:
                         update_fn(handoff)
-                        handoff.updated = date.today()
+                        self._mark_updated(handoff)
                         self._write_handoffs_file(handoffs)
                         return handoff
 
@@ -1051,12 +1088,20 @@ class HandoffsMixin:
                 for handoff in handoffs:
                     if handoff.id == handoff_id:
                         update_fn(handoff)
-                        handoff.updated = date.today()
+                        self._mark_updated(handoff)
                         self._write_stealth_handoffs_file(handoffs)
                         return handoff
 
         raise ValueError(f"Handoff {handoff_id} not found")
 
+    @staticmethod
+    def _mark_updated(handoff: Handoff) -> None:
+        """Stamp an update. Like the Go CLI, any update clears the Stale flag."""
+        handoff.updated = date.today()
+        handoff.extra_metadata = [
+            line for line in handoff.extra_metadata if not line.startswith("- **Stale**:")
+        ]
+
     def _update_handoff_field(self, handoff_id: str, field: str, value: Any) -> None:
         """
         Update a single field on a handoff.
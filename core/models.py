@@ -355,9 +355,11 @@ class TriedStep:
     Attributes:
         description: What was attempted
         outcome: 'success', 'fail', or 'partial'
+        logged: Date the step was logged (YYYY-MM-DD), if recorded
     """
     outcome: str  # success|fail|partial
     description: str
+    logged: Optional[str] = None
 
 
 # DEPRECATED (remove after 2025-06-01): Use TriedStep instead
@@ -384,6 +386,8 @@ class Handoff:
     blocked_by: List[str] = field(default_factory=list)  # IDs of blocking handoffs
     stealth: bool = False  # If True, stored in HANDOFFS_LOCAL.md (not committed to git)
     sessions: List[str] = field(default_factory=list)  # Session IDs linked to this handoff
+    extra_metadata: List[str] = field(default_factory=list)  # Unparsed "- **X**:" lines (e.g. Priority from the Go CLI), kept verbatim
+    extra_sections: List[str] = field(default_factory=list)  # Unparsed "**X**:" sections (e.g. Milestones, Notes), kept verbatim
 
     # Backward compatibility: 'files' is an alias for 'refs'
     @property
@@ -151,7 +151,7 @@ class StateReader:
     HANDOFF_DESCRIPTION_PATTERN = re.compile(r"^\*\*Description\*\*:\s*(.+)$")
     HANDOFF_TRIED_HEADER_PATTERN = re.compile(r"^\*\*Tried\*\*\s*(?:\(\d+\s*steps?\))?:")
     HANDOFF_TRIED_STEP_PATTERN = re.compile(
-        r"^\s*\d+\.\s*\[(success|fail|partial)\]\s*(.+)$"
+        r"^\s*\d+\.\s*\[(success|fail|partial)(?:\s+\d{4}-\d{2}-\d{2})?\]\s*(.+)$"
     )
     HANDOFF_NEXT_HEADER_PATTERN = re.compile(r"^\*\*Next\*\*:\s*(.*)$")
     # Match bullet items starting with dash, exclude separator lines (---, ----, etc.)
@@ -49,6 +49,7 @@ type handoffOp struct {
 	Status      string            `json:"status,omitempty"`
 	Phase       string            `json:"phase,omitempty"`
 	Outcome     string            `json:"outcome,omitempty"` // for tried
+	Refs        []string          `json:"refs,omitempty"`    // for add (multi-line blocks)
 	Updates     map[string]string `json:"updates,omitempty"`
 }
 
@@ -92,13 +93,15 @@ func runStopHookBatch() int {
 	}
 
 	// Set up stores
-	projectLessonsPath := filepath.Join(projectDir, ".claude-recall", "LESSONS.md")
-	systemLessonsPath := filepath.Join(cfg.StateDir, "LESSONS.md")
-	lessonStore := lessons.NewStore(projectLessonsPath, systemLessonsPath)
+	lessonStore := openLessonStore(cfg, projectDir)
+	lessonStore.SetAuditLog(lessons.NewAuditLog(cfg.StateDir))
 
 	handoffsPath := filepath.Join(projectDir, ".claude-recall", "HANDOFFS.md")
 	stealthPath := filepath.Join(projectDir, ".claude-recall", "HANDOFFS_LOCAL.md")
 	handoffStore := handoffs.NewStore(handoffsPath, stealthPath)
+	if cfg.WebhookURL != "" {
+		handoffStore.SetNotifier(handoffs.WebhookNotifier(cfg.WebhookURL, cfg.WebhookEvents, os.Stderr))
+	}
 
 	// Process citations
 	citations := input.Citations
@@ -108,19 +111,9 @@ func runStopHookBatch() int {
 	}
 
 	// Deduplicate and process citations
-	seen := make(map[string]bool)
-	for _, id := range citations {
-		if seen[id] {
-			continue
-		}
-		seen[id] = true
-
-		if err := lessonStore.Cite(id); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("cite %s: %v", id, err))
-			continue
-		}
-		result.CitationsProcessed++
-	}
+	processed, errs := citeLessons(lessonStore, cfg.StateDir, input.SessionID, uniqueIDs(citations))
+	result.CitationsProcessed = processed
+	result.Errors = append(result.Errors, errs...)
 
 	// Add AI lessons
 	for _, al := range input.AILessons {
@@ -158,8 +151,27 @@ func runStopHookBatch() int {
 	return 0
 }
 
+// citeLessons cites each ID on behalf of sessionID and records the ones that
+// succeeded as cited together, returning how many were cited and an error
+// string per failure
+func citeLessons(store *lessons.Store, stateDir, sessionID string, ids []string) (int, []string) {
+	var cited, errs []string
+	for _, id := range ids {
+		if err := store.CiteInSession(id, sessionID); err != nil {
+			errs = append(errs, fmt.Sprintf("cite %s: %v", id, err))
+			continue
+		}
+		cited = append(cited, id)
+	}
+
+	if err := lessons.RecordCoCitations(stateDir, cited); err != nil {
+		errs = append(errs, fmt.Sprintf("record co-citations: %v", err))
+	}
+	return len(cited), errs
+}
+
 // Citation patterns
-var citationPattern = regexp.MustCompile(`\[([LS]\d{3})\]`)
+var citationPattern = regexp.MustCompile(`\[([LSW]\d{3})\]`)
 
 // extractCitationsFromTexts extracts citation IDs from assistant texts
 func extractCitationsFromTexts(texts []string) []string {
@@ -184,9 +196,6 @@ func extractCitationsFromTexts(texts []string) []string {
 
 // Handoff patterns
 var (
-	// HANDOFF: title - starts a new handoff
-	handoffStartPattern = regexp.MustCompile(`(?m)^HANDOFF:\s*(.+)$`)
-
 	// HANDOFF UPDATE <id>: <status> - <description>
 	handoffUpdatePattern = regexp.MustCompile(`(?m)^HANDOFF\s+UPDATE\s+([A-Za-z0-9-]+):\s*(tried\s+)?(success|fail|partial)?\s*[-–]?\s*(.*)$`)
 
@@ -202,15 +211,15 @@ func parseHandoffOps(texts []string) []handoffOp {
 	var ops []handoffOp
 
 	for _, text := range texts {
-		// Check for HANDOFF: (new handoff)
-		startMatches := handoffStartPattern.FindAllStringSubmatch(text, -1)
-		for _, match := range startMatches {
-			if len(match) > 1 {
-				ops = append(ops, handoffOp{
-					Op:    "add",
-					Title: strings.TrimSpace(match[1]),
-				})
-			}
+		// Check for HANDOFF: (new handoff, optionally with indented desc/phase/refs)
+		for _, block := range handoffs.ParseStartBlocks(text) {
+			ops = append(ops, handoffOp{
+				Op:          "add",
+				Title:       block.Title,
+				Description: block.Description,
+				Phase:       block.Phase,
+				Refs:        block.Refs,
+			})
 		}
 
 		// Check for HANDOFF UPDATE
@@ -272,6 +281,12 @@ func executeHandoffOp(store *handoffs.Store, op handoffOp) (string, error) {
 		if err != nil {
 			return "", err
 		}
+		block := handoffs.StartBlock{Phase: op.Phase, Refs: op.Refs}
+		if updates := block.Updates(); updates != nil {
+			if err := store.Update(h.ID, updates); err != nil {
+				return "", err
+			}
+		}
 		return fmt.Sprintf("added %s", h.ID), nil
 
 	case "update":
@@ -279,9 +294,6 @@ func executeHandoffOp(store *handoffs.Store, op handoffOp) (string, error) {
 		if op.Status != "" {
 			updates["status"] = op.Status
 		}
-		if op.Phase != "" {
-			updates["phase"] = op.Phase
-		}
 		if op.Description != "" {
 			updates["description"] = op.Description
 		}
@@ -290,6 +302,14 @@ func executeHandoffOp(store *handoffs.Store, op handoffOp) (string, error) {
 				return "", err
 			}
 		}
+		// The phase goes in its own update so an invalid one doesn't drop
+		// the status and description; agent-reported phases skip the
+		// transition rules
+		if op.Phase != "" {
+			if err := store.Update(op.ID, map[string]interface{}{"phase": op.Phase, "force_phase": true}); err != nil {
+				return "", err
+			}
+		}
 		return fmt.Sprintf("updated %s", op.ID), nil
 
 	case "tried":
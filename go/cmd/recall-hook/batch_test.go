@@ -0,0 +1,90 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+func Test_ParseHandoffOps_MultiLineBlocks(t *testing.T) {
+	texts := []string{
+		"HANDOFF: Quick fix\nDone for now.",
+		"HANDOFF: Rework auth\n  desc: Replace session cookies with JWT\n  phase: implementing\n  refs: auth.go:10, middleware.go:42\nThen more text.",
+	}
+
+	ops := parseHandoffOps(texts)
+	if len(ops) != 2 {
+		t.Fatalf("expected 2 ops, got %d: %+v", len(ops), ops)
+	}
+	if ops[0].Op != "add" || ops[0].Title != "Quick fix" || ops[0].Description != "" || ops[0].Phase != "" {
+		t.Errorf("unexpected single-line op: %+v", ops[0])
+	}
+	if ops[1].Title != "Rework auth" || ops[1].Description != "Replace session cookies with JWT" ||
+		ops[1].Phase != "implementing" || !reflect.DeepEqual(ops[1].Refs, []string{"auth.go:10", "middleware.go:42"}) {
+		t.Errorf("unexpected multi-line op: %+v", ops[1])
+	}
+
+	dir := t.TempDir()
+	store := handoffs.NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	if _, err := executeHandoffOp(store, ops[1]); err != nil {
+		t.Fatalf("executeHandoffOp failed: %v", err)
+	}
+	all, _ := store.List()
+	if len(all) != 1 {
+		t.Fatalf("expected 1 handoff, got %d", len(all))
+	}
+	h := all[0]
+	if h.Description != "Replace session cookies with JWT" || h.Phase != "implementing" || !reflect.DeepEqual(h.Refs, []string{"auth.go:10", "middleware.go:42"}) {
+		t.Errorf("fields not applied to handoff: %+v", h)
+	}
+}
+
+func Test_ExecuteHandoffOp_InvalidPhaseKeepsOtherUpdates(t *testing.T) {
+	dir := t.TempDir()
+	store := handoffs.NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	h, _ := store.Add("Rework auth", "", false)
+
+	op := handoffOp{Op: "update", ID: h.ID, Status: "in_progress", Phase: "bogus", Description: "Swap cookies for JWT"}
+	if _, err := executeHandoffOp(store, op); err == nil {
+		t.Error("expected an error for an invalid phase")
+	}
+	got, _ := store.Get(h.ID)
+	if got.Status != "in_progress" || got.Description != "Swap cookies for JWT" {
+		t.Errorf("status and description should survive an invalid phase: %+v", got)
+	}
+
+	// Agent-reported phases skip the transition rules
+	op = handoffOp{Op: "update", ID: h.ID, Phase: "review"}
+	if _, err := executeHandoffOp(store, op); err != nil {
+		t.Fatalf("executeHandoffOp failed: %v", err)
+	}
+	if got, _ := store.Get(h.ID); got.Phase != "review" {
+		t.Errorf("expected phase review, got %q", got.Phase)
+	}
+}
+
+func Test_CiteLessons_RecordsCoCitations(t *testing.T) {
+	dir := t.TempDir()
+	store := lessons.NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	a, _ := store.Add("project", "pattern", "First", "First lesson")
+	b, _ := store.Add("project", "pattern", "Second", "Second lesson")
+
+	processed, errs := citeLessons(store, dir, "", []string{a.ID, "L999", b.ID})
+	if processed != 2 || len(errs) != 1 {
+		t.Errorf("processed = %d, errs = %v; want 2 cited and 1 error", processed, errs)
+	}
+
+	graph, err := lessons.LoadCoGraph(lessons.CoGraphPath(dir))
+	if err != nil {
+		t.Fatalf("LoadCoGraph failed: %v", err)
+	}
+	if graph[a.ID][b.ID] != 1 || graph[b.ID][a.ID] != 1 {
+		t.Errorf("expected %s and %s co-cited once, got %v", a.ID, b.ID, graph)
+	}
+	if _, ok := graph["L999"]; ok {
+		t.Errorf("failed citation should not be recorded: %v", graph)
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"github.com/pbrown/claude-recall/internal/config"
 	"github.com/pbrown/claude-recall/internal/debuglog"
 	"github.com/pbrown/claude-recall/internal/handoffs"
-	"github.com/pbrown/claude-recall/internal/lessons"
 	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/scoring"
 )
 
 // injectInput is the optional JSON input for inject commands
@@ -46,10 +46,7 @@ func runInject() int {
 		return 1
 	}
 
-	// Set up lesson store paths
-	projectLessonsPath := filepath.Join(cfg.ProjectDir, ".claude-recall", "LESSONS.md")
-	systemLessonsPath := filepath.Join(cfg.StateDir, "LESSONS.md")
-	store := lessons.NewStore(projectLessonsPath, systemLessonsPath)
+	store := openLessonStore(cfg, cfg.ProjectDir)
 
 	// Get and sort lessons
 	allLessons, err := store.List()
@@ -58,12 +55,9 @@ func runInject() int {
 		return 1
 	}
 
-	// Sort by combined score (uses + velocity)
-	sort.Slice(allLessons, func(i, j int) bool {
-		scoreI := float64(allLessons[i].Uses) + allLessons[i].Velocity
-		scoreJ := float64(allLessons[j].Uses) + allLessons[j].Velocity
-		return scoreI > scoreJ
-	})
+	// Sort by combined score (uses + velocity + preventions), scaled by
+	// confidence and weight
+	sortByInjectScore(allLessons, *cfg.PreventionWeight)
 
 	// Take top n
 	if n > len(allLessons) {
@@ -113,10 +107,7 @@ func runInjectCombined() int {
 		projectDir = input.Cwd
 	}
 
-	// Set up lesson store paths
-	projectLessonsPath := filepath.Join(projectDir, ".claude-recall", "LESSONS.md")
-	systemLessonsPath := filepath.Join(cfg.StateDir, "LESSONS.md")
-	lessonStore := lessons.NewStore(projectLessonsPath, systemLessonsPath)
+	lessonStore := openLessonStore(cfg, projectDir)
 
 	// Get and sort lessons
 	allLessons, err := lessonStore.List()
@@ -126,11 +117,7 @@ func runInjectCombined() int {
 	}
 
 	// Sort by combined score
-	sort.Slice(allLessons, func(i, j int) bool {
-		scoreI := float64(allLessons[i].Uses) + allLessons[i].Velocity
-		scoreJ := float64(allLessons[j].Uses) + allLessons[j].Velocity
-		return scoreI > scoreJ
-	})
+	sortByInjectScore(allLessons, *cfg.PreventionWeight)
 
 	// Take top n
 	if n > len(allLessons) {
@@ -219,6 +206,10 @@ func formatHandoffsForInjection(handoffList []*models.Handoff) string {
 			output += fmt.Sprintf("- **Description**: %s\n", h.Description)
 		}
 
+		if done, total := h.MilestoneProgress(); total > 0 {
+			output += fmt.Sprintf("- **Milestones**: [%d/%d milestones]\n", done, total)
+		}
+
 		if h.Checkpoint != "" {
 			output += fmt.Sprintf("- **Checkpoint**: %s\n", h.Checkpoint)
 		}
@@ -281,3 +272,11 @@ func formatTodosForInjection(handoffList []*models.Handoff) string {
 
 	return output
 }
+
+// sortByInjectScore orders lessons by scoring.InjectScore, highest first,
+// matching the ranking of recall inject
+func sortByInjectScore(all []*models.Lesson, preventionWeight float64) {
+	sort.SliceStable(all, func(i, j int) bool {
+		return scoring.InjectScore(all[i], preventionWeight) > scoring.InjectScore(all[j], preventionWeight)
+	})
+}
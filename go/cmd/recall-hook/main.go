@@ -4,6 +4,10 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+
+	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/lessons"
 )
 
 func main() {
@@ -65,3 +69,24 @@ Options:
 // runInject is implemented in inject.go
 // runInjectCombined is implemented in inject.go
 // runStopHookBatch is implemented in batch.go
+
+// openLessonStore returns a store over every lessons file configured for
+// projectDir: project, system, shared libraries, and workspace, using the
+// configured dedup threshold and algorithm
+func openLessonStore(cfg *config.Config, projectDir string) *lessons.Store {
+	store := lessons.OpenStore(lessons.StorePaths{
+		Project:   filepath.Join(projectDir, ".claude-recall", "LESSONS.md"),
+		System:    filepath.Join(cfg.StateDir, "LESSONS.md"),
+		Shared:    cfg.SharedPaths,
+		Workspace: cfg.WorkspacePath,
+	})
+	if cfg.DedupThreshold > 0 {
+		store.SetDedupThreshold(cfg.DedupThreshold)
+	}
+	if cfg.DedupAlgo != "" {
+		if err := store.SetDedupAlgo(cfg.DedupAlgo); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	return store
+}
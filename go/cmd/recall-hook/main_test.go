@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+func Test_OpenLessonStore_AppliesDedupConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The default threshold rejects a lesson with the same title
+	store := openLessonStore(&config.Config{StateDir: tmpDir}, tmpDir)
+	if _, err := store.Add("project", "pattern", "Check inputs", "Validate before use"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add("project", "pattern", "Check inputs", "Something else"); !errors.Is(err, lessons.ErrDuplicateLesson) {
+		t.Fatalf("expected a duplicate error, got %v", err)
+	}
+
+	// A threshold above 1 disables duplicate detection
+	cfg := &config.Config{StateDir: tmpDir, DedupThreshold: 1.5, DedupAlgo: "cosine"}
+	if _, err := openLessonStore(cfg, tmpDir).Add("project", "pattern", "Check inputs", "Something else"); err != nil {
+		t.Errorf("expected the configured threshold to allow the lesson, got %v", err)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pbrown/claude-recall/internal/checkpoint"
 	"github.com/pbrown/claude-recall/internal/citations"
 	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/debuglog"
 	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
 	"github.com/pbrown/claude-recall/internal/transcript"
 )
 
@@ -27,6 +30,8 @@ type stopOutput struct {
 	Citations         []string `json:"citations"`
 	CitationsProcessed int     `json:"citations_processed"`
 	MessagesProcessed int      `json:"messages_processed"`
+
+	Metrics models.SessionMetrics `json:"metrics"`
 }
 
 // runStop implements the stop hook command.
@@ -52,11 +57,12 @@ func runStop() int {
 	}
 
 	// Execute the stop hook
-	result, err := executeStop(input, cfg.StateDir, projectDir)
+	result, err := executeStop(input, cfg, projectDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error executing stop: %v\n", err)
 		return 1
 	}
+	debuglog.New(cfg.StateDir, cfg.DebugLevel).LogCitationBatch(input.SessionID, projectDir, uniqueIDs(result.Citations))
 
 	// Output JSON result
 	outputBytes, err := json.Marshal(result)
@@ -79,6 +85,19 @@ func parseStopInput(r io.Reader) (stopInput, error) {
 	return input, nil
 }
 
+// uniqueIDs returns ids without duplicates, keeping first-seen order.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
 // expandTilde expands ~ to the user's home directory.
 func expandTilde(path string) string {
 	if path == "" {
@@ -102,7 +121,9 @@ func expandTilde(path string) string {
 }
 
 // executeStop performs the stop hook logic.
-func executeStop(input stopInput, stateDir, projectDir string) (stopOutput, error) {
+func executeStop(input stopInput, cfg *config.Config, projectDir string) (stopOutput, error) {
+	stateDir := cfg.StateDir
+
 	// Expand tilde in transcript path
 	transcriptPath := expandTilde(input.TranscriptPath)
 
@@ -143,10 +164,8 @@ func executeStop(input stopInput, stateDir, projectDir string) (stopOutput, erro
 	// Process citations - increment uses/velocity for each
 	citationsProcessed := 0
 	if len(citationIDs) > 0 {
-		// Set up lesson store paths
-		projectLessonsPath := filepath.Join(projectDir, ".claude-recall", "LESSONS.md")
-		systemLessonsPath := filepath.Join(stateDir, "LESSONS.md")
-		store := lessons.NewStore(projectLessonsPath, systemLessonsPath)
+		store := openLessonStore(cfg, projectDir)
+		store.SetAuditLog(lessons.NewAuditLog(stateDir))
 
 		// Deduplicate citations before processing
 		seen := make(map[string]bool)
@@ -159,13 +178,20 @@ func executeStop(input stopInput, stateDir, projectDir string) (stopOutput, erro
 		}
 
 		// Process each unique citation
+		var cited []string
 		for _, id := range uniqueCitations {
-			if err := store.Cite(id); err != nil {
+			if err := store.CiteInSession(id, input.SessionID); err != nil {
 				// Log error but continue processing other citations
 				fmt.Fprintf(os.Stderr, "warning: failed to cite %s: %v\n", id, err)
 				continue
 			}
 			citationsProcessed++
+			cited = append(cited, id)
+		}
+
+		// Track which lessons were cited together in this batch
+		if err := lessons.RecordCoCitations(stateDir, cited); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to record co-citations: %v\n", err)
 		}
 	}
 
@@ -174,9 +200,21 @@ func executeStop(input stopInput, stateDir, projectDir string) (stopOutput, erro
 		return stopOutput{}, fmt.Errorf("failed to update checkpoint: %w", err)
 	}
 
+	// The stop hook only handles citations, so lessons added and handoff
+	// ops stay zero here
+	timestamps := make([]time.Time, len(messages))
+	for i, m := range messages {
+		timestamps[i] = m.Timestamp
+	}
+
 	return stopOutput{
 		Citations:          citationIDs,
 		CitationsProcessed: citationsProcessed,
 		MessagesProcessed:  len(messages),
+		Metrics: models.SessionMetrics{
+			CitationCount:           len(citationIDs),
+			MessagesProcessed:       len(messages),
+			SessionDurationEstimate: models.EstimateSessionDuration(timestamps),
+		},
 	}, nil
 }
@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/models"
 )
 
 
@@ -109,7 +112,7 @@ func Test_StopHook_IncrementalParsing(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, stateDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: stateDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -143,7 +146,7 @@ func Test_StopHook_ExtractsCitations(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, tmpDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -183,7 +186,7 @@ func Test_StopHook_OutputsJSON(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, tmpDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -217,7 +220,7 @@ func Test_StopHook_MissingTranscript(t *testing.T) {
 		TranscriptPath: filepath.Join(tmpDir, "nonexistent.jsonl"),
 	}
 
-	_, err := executeStop(input, tmpDir, tmpDir)
+	_, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err == nil {
 		t.Error("expected error for missing transcript, got nil")
 	}
@@ -238,7 +241,7 @@ func Test_StopHook_EmptyTranscript(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, tmpDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -268,7 +271,7 @@ func Test_StopHook_UpdatesCheckpoint(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	_, err := executeStop(input, tmpDir, tmpDir)
+	_, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -303,7 +306,7 @@ func Test_StopHook_NoCitationsInUserMessages(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, tmpDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -349,7 +352,7 @@ func Test_StopHook_ProcessesCitations(t *testing.T) {
 		TranscriptPath: transcriptPath,
 	}
 
-	result, err := executeStop(input, tmpDir, tmpDir)
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
 	if err != nil {
 		t.Fatalf("executeStop failed: %v", err)
 	}
@@ -378,3 +381,61 @@ func Test_StopHook_ProcessesCitations(t *testing.T) {
 		t.Errorf("expected Velocity to be incremented to 3.0, got: %s", string(updatedContent))
 	}
 }
+
+func Test_StopHook_Metrics(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	transcriptPath := filepath.Join(tmpDir, "transcript.jsonl")
+	transcript := `{"type":"user","timestamp":"2025-03-01T09:00:00Z","message":{"role":"user","content":[{"type":"text","text":"Fix it"}]}}
+{"type":"assistant","timestamp":"2025-03-01T09:20:00Z","message":{"role":"assistant","content":[{"type":"text","text":"Using [L001] and [S002]"}]}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Done, per [L001]"}]}}
+`
+	if err := os.WriteFile(transcriptPath, []byte(transcript), 0644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	input := stopInput{SessionID: "test-session", TranscriptPath: transcriptPath}
+	result, err := executeStop(input, &config.Config{StateDir: tmpDir}, tmpDir)
+	if err != nil {
+		t.Fatalf("executeStop failed: %v", err)
+	}
+
+	want := models.SessionMetrics{
+		CitationCount:           2,
+		MessagesProcessed:       3,
+		SessionDurationEstimate: "20m0s",
+	}
+	if result.Metrics != want {
+		t.Errorf("metrics = %+v, want %+v", result.Metrics, want)
+	}
+}
+
+func Test_StopHook_CitesWorkspaceLessons(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{StateDir: tmpDir, WorkspacePath: filepath.Join(tmpDir, "workspace", "LESSONS.md")}
+
+	store := openLessonStore(cfg, tmpDir)
+	w, err := store.Add("workspace", "pattern", "Team convention", "Follow the team style guide")
+	if err != nil {
+		t.Fatalf("failed to add workspace lesson: %v", err)
+	}
+
+	transcriptPath := filepath.Join(tmpDir, "transcript.jsonl")
+	transcript := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Applying [` + w.ID + `] here"}]}}
+`
+	if err := os.WriteFile(transcriptPath, []byte(transcript), 0644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	input := stopInput{Cwd: tmpDir, SessionID: "ws-session", TranscriptPath: transcriptPath}
+	result, err := executeStop(input, cfg, tmpDir)
+	if err != nil {
+		t.Fatalf("executeStop failed: %v", err)
+	}
+	if result.CitationsProcessed != 1 {
+		t.Errorf("citations_processed = %d, want 1", result.CitationsProcessed)
+	}
+	if cited, _ := store.Get(w.ID); cited == nil || cited.Uses != 1 {
+		t.Errorf("expected %s cited once, got %+v", w.ID, cited)
+	}
+}
@@ -5,14 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
+	"time"
 
 	"github.com/pbrown/claude-recall/internal/checkpoint"
 	"github.com/pbrown/claude-recall/internal/citations"
 	"github.com/pbrown/claude-recall/internal/config"
 	"github.com/pbrown/claude-recall/internal/debuglog"
 	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
 	"github.com/pbrown/claude-recall/internal/transcript"
 )
 
@@ -29,11 +29,9 @@ type stopAllOutput struct {
 	LessonsAdded       int      `json:"lessons_added"`
 	CitationIDs        []string `json:"citation_ids"`
 	Errors             []string `json:"errors,omitempty"`
-}
 
-// AI LESSON pattern: "AI LESSON: category: title - content"
-// or "AI LESSON [type]: category: title - content"
-var aiLessonPattern = regexp.MustCompile(`(?m)AI LESSON(?:\s+\[[a-z]+\])?:\s*(.+)`)
+	Metrics models.SessionMetrics `json:"metrics"`
+}
 
 // runStopAll replaces the entire bash stop hook with a single Go call.
 // Reads raw Claude Code hook input, parses transcript, extracts citations
@@ -73,6 +71,38 @@ func runStopAll() int {
 		return 0
 	}
 
+	result, err := executeStopAll(input, cfg, projectDir, transcriptPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error opening transcript: %v\n", err)
+		return 1
+	}
+
+	// Log injection stats
+	dlog := debuglog.New(cfg.StateDir, cfg.DebugLevel)
+	if result.CitationsProcessed > 0 {
+		fmt.Fprintf(os.Stderr, "[lessons] %d lesson(s) cited\n", result.CitationsProcessed)
+	}
+	if result.LessonsAdded > 0 {
+		fmt.Fprintf(os.Stderr, "[lessons] %d AI lesson(s) added\n", result.LessonsAdded)
+	}
+	dlog.LogStopHook(input.SessionID, result.CitationsProcessed, result.CitationIDs, result.LessonsAdded, result.Errors)
+	dlog.LogCitationBatch(input.SessionID, projectDir, uniqueIDs(result.CitationIDs))
+
+	// Output JSON result
+	output, err := json.Marshal(result)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error marshaling output: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(output))
+	return 0
+}
+
+// executeStopAll processes the transcript from the session checkpoint:
+// cites lessons, records co-citations, adds AI lessons, and advances the
+// checkpoint. Only a transcript that cannot be opened is fatal; everything
+// else is collected in the output's Errors.
+func executeStopAll(input stopAllInput, cfg *config.Config, projectDir, transcriptPath string) (stopAllOutput, error) {
 	result := stopAllOutput{
 		CitationIDs: []string{},
 		Errors:      []string{},
@@ -89,8 +119,7 @@ func runStopAll() int {
 	// Open and parse transcript from offset
 	file, err := os.Open(transcriptPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error opening transcript: %v\n", err)
-		return 1
+		return stopAllOutput{}, err
 	}
 	defer file.Close()
 
@@ -100,37 +129,24 @@ func runStopAll() int {
 	}
 
 	// Set up lesson store
-	projectLessonsPath := filepath.Join(projectDir, ".claude-recall", "LESSONS.md")
-	systemLessonsPath := filepath.Join(cfg.StateDir, "LESSONS.md")
-	lessonStore := lessons.NewStore(projectLessonsPath, systemLessonsPath)
+	lessonStore := openLessonStore(cfg, projectDir)
+	lessonStore.SetAuditLog(lessons.NewAuditLog(cfg.StateDir))
 
 	// Extract and process citations
-	extractedCitations := citations.ExtractFromMessages(messages)
-	for _, c := range extractedCitations {
+	for _, c := range citations.ExtractFromMessages(messages) {
 		result.CitationIDs = append(result.CitationIDs, c.ID)
-		if err := lessonStore.Cite(c.ID); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("cite %s: %v", c.ID, err))
-			continue
-		}
-		result.CitationsProcessed++
 	}
+	processed, errs := citeLessons(lessonStore, cfg.StateDir, input.SessionID, result.CitationIDs)
+	result.CitationsProcessed = processed
+	result.Errors = append(result.Errors, errs...)
 
 	// Extract and add AI lessons from assistant text
 	for _, msg := range messages {
 		if msg.Type != "assistant" {
 			continue
 		}
-		matches := aiLessonPattern.FindAllStringSubmatch(msg.Content, -1)
-		for _, match := range matches {
-			if len(match) < 2 {
-				continue
-			}
-			remainder := strings.TrimSpace(match[1])
-			category, title, content := parseAILesson(remainder)
-			if title == "" {
-				continue
-			}
-			if _, err := lessonStore.Add("project", category, title, content); err != nil {
+		for _, l := range lessons.ExtractAILessons(msg.Content) {
+			if _, err := lessonStore.Add("project", l.Category, l.Title, l.Content); err != nil {
 				result.Errors = append(result.Errors, fmt.Sprintf("add lesson: %v", err))
 				continue
 			}
@@ -143,54 +159,16 @@ func runStopAll() int {
 		result.Errors = append(result.Errors, fmt.Sprintf("checkpoint write: %v", err))
 	}
 
-	// Log injection stats
-	dlog := debuglog.New(cfg.StateDir, cfg.DebugLevel)
-	if result.CitationsProcessed > 0 {
-		fmt.Fprintf(os.Stderr, "[lessons] %d lesson(s) cited\n", result.CitationsProcessed)
-	}
-	if result.LessonsAdded > 0 {
-		fmt.Fprintf(os.Stderr, "[lessons] %d AI lesson(s) added\n", result.LessonsAdded)
+	timestamps := make([]time.Time, len(messages))
+	for i, m := range messages {
+		timestamps[i] = m.Timestamp
 	}
-	dlog.LogStopHook(input.SessionID, result.CitationsProcessed, result.CitationIDs, result.LessonsAdded, result.Errors)
-
-	// Output JSON result
-	output, err := json.Marshal(result)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error marshaling output: %v\n", err)
-		return 1
-	}
-	fmt.Println(string(output))
-	return 0
-}
-
-// parseAILesson parses "category: title - content" from an AI LESSON match
-func parseAILesson(remainder string) (category, title, content string) {
-	// Split on first colon for category
-	parts := strings.SplitN(remainder, ":", 2)
-	if len(parts) < 2 {
-		// No category, treat whole thing as title
-		category = "pattern"
-		title = strings.TrimSpace(remainder)
-		return
-	}
-
-	category = strings.ToLower(strings.TrimSpace(parts[0]))
-	titleContent := strings.TrimSpace(parts[1])
-
-	// Validate category
-	switch category {
-	case "pattern", "correction", "decision", "gotcha", "preference":
-		// valid
-	default:
-		category = "pattern"
-	}
-
-	// Split on " - " for title vs content
-	titleParts := strings.SplitN(titleContent, " - ", 2)
-	title = strings.TrimSpace(titleParts[0])
-	if len(titleParts) > 1 {
-		content = strings.TrimSpace(titleParts[1])
+	result.Metrics = models.SessionMetrics{
+		CitationCount:           len(result.CitationIDs),
+		LessonsAdded:            result.LessonsAdded,
+		MessagesProcessed:       len(messages),
+		SessionDurationEstimate: models.EstimateSessionDuration(timestamps),
 	}
 
-	return
+	return result, nil
 }
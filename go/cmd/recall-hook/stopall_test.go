@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func Test_StopAll_RecordsCoCitations(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{StateDir: filepath.Join(tmpDir, "state")}
+
+	store := openLessonStore(cfg, tmpDir)
+	a, _ := store.Add("project", "pattern", "First", "First lesson")
+	b, _ := store.Add("project", "pattern", "Second", "Second lesson")
+
+	transcriptPath := filepath.Join(tmpDir, "transcript.jsonl")
+	transcript := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Applying [` + a.ID + `] and [` + b.ID + `]"}]}}
+`
+	if err := os.WriteFile(transcriptPath, []byte(transcript), 0644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	input := stopAllInput{Cwd: tmpDir, SessionID: "test-session", TranscriptPath: transcriptPath}
+	result, err := executeStopAll(input, cfg, tmpDir, transcriptPath)
+	if err != nil {
+		t.Fatalf("executeStopAll failed: %v", err)
+	}
+	if result.CitationsProcessed != 2 || len(result.Errors) != 0 {
+		t.Errorf("citations_processed = %d, errors = %v; want 2 and none", result.CitationsProcessed, result.Errors)
+	}
+
+	graph, err := lessons.LoadCoGraph(lessons.CoGraphPath(cfg.StateDir))
+	if err != nil {
+		t.Fatalf("LoadCoGraph failed: %v", err)
+	}
+	if graph[a.ID][b.ID] != 1 {
+		t.Errorf("expected %s and %s co-cited once, got %v", a.ID, b.ID, graph)
+	}
+
+	events, err := lessons.NewAuditLog(cfg.StateDir).Read()
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	cites := 0
+	for _, e := range events {
+		if e.Event == lessons.AuditCite && e.SessionID == "test-session" {
+			cites++
+		}
+	}
+	if cites != 2 {
+		t.Errorf("expected 2 cites audited with the session ID, got %+v", events)
+	}
+}
+
+func Test_StopAll_Metrics(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	transcriptPath := filepath.Join(tmpDir, "transcript.jsonl")
+	transcript := `{"type":"user","timestamp":"2025-03-01T09:00:00Z","message":{"role":"user","content":[{"type":"text","text":"Fix it"}]}}
+{"type":"assistant","timestamp":"2025-03-01T09:45:00Z","message":{"role":"assistant","content":[{"type":"text","text":"Using [L001]\nAI LESSON: pattern: Check inputs - Validate before use"}]}}
+`
+	if err := os.WriteFile(transcriptPath, []byte(transcript), 0644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	input := stopAllInput{SessionID: "test-session", TranscriptPath: transcriptPath}
+	result, err := executeStopAll(input, &config.Config{StateDir: tmpDir}, tmpDir, transcriptPath)
+	if err != nil {
+		t.Fatalf("executeStopAll failed: %v", err)
+	}
+
+	want := models.SessionMetrics{
+		CitationCount:           1,
+		LessonsAdded:            1,
+		MessagesProcessed:       2,
+		SessionDurationEstimate: "45m0s",
+	}
+	if result.Metrics != want {
+		t.Errorf("metrics = %+v, want %+v", result.Metrics, want)
+	}
+}
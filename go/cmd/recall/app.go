@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pbrown/claude-recall/internal/anthropic"
@@ -19,6 +21,7 @@ import (
 	"github.com/pbrown/claude-recall/internal/lessons"
 	"github.com/pbrown/claude-recall/internal/models"
 	"github.com/pbrown/claude-recall/internal/scoring"
+	"github.com/pbrown/claude-recall/internal/transcript"
 )
 
 // App encapsulates CLI state and dependencies for testability
@@ -31,8 +34,46 @@ type App struct {
 	handoffsPath string // Path to HANDOFFS.md
 	stealthPath  string // Path to HANDOFFS_LOCAL.md (stealth)
 	stateDir     string // Path to state directory
+	baseDir      string // Code directory holding handoff templates
 	projectDir   string // Project root directory
 	debugLevel   int    // Debug level 0-3
+	configPath   string // Path to config.json (default: ~/.config/claude-recall/config.json)
+
+	scoreCacheTTL time.Duration // Relevance score cache TTL (0 = anthropic default)
+	sharedPaths   []string      // Read-only shared LESSONS.md files
+	syncRemote    string        // Directory for sync push/pull
+	maxTokens     int           // Token budget for inject output (0 = unlimited)
+	recencyWeight float64       // Share of inject ranking given to recency (0-1)
+	workspacePath string        // Team workspace LESSONS.md ("" = no workspace level)
+
+	handoffsRecencyBias float64 // Share of handoff inject ordering given to recency (0-1)
+
+	preventionWeight float64 // Uses each recorded prevention is worth in inject ranking
+	dedupThreshold   float64 // Similarity at which add rejects a duplicate (0 = store default)
+	dedupAlgo        string  // Dedup similarity algorithm ("" = store default)
+
+	webhookURL    string   // Handoff status events are POSTed here ("" = off)
+	webhookEvents []string // Events to send (empty = all)
+
+	config         *config.WatchedConfig // Config file state, reloaded by initPaths
+	configLoadedAt time.Time             // When config was last applied (zero = never)
+	noReload       bool                  // Apply config once and never reload (--no-reload)
+	explicitPaths  appPaths              // Paths set before config was first applied
+	now            func() time.Time      // Clock for config reloads (stubbed in tests)
+
+	gitProvider lessons.GitContextProvider // Git context for new lessons (default: git CLI)
+
+	// execCommand runs external commands such as git (stubbed in tests)
+	execCommand func(dir, name string, args ...string) ([]byte, error)
+
+	// improveLesson asks the API for a rewritten lesson (stubbed in tests)
+	improveLesson func(title, content, model string) (string, error)
+
+	// extractLessons asks the API for session lesson candidates (stubbed in tests)
+	extractLessons func(texts []string) ([]anthropic.SuggestedLesson, error)
+
+	// scoreUsefulness asks the API to score lessons for lesson-audit (stubbed in tests)
+	scoreUsefulness func(lessons []*models.Lesson) (map[string]int, error)
 }
 
 // NewApp creates a new App with default stdout/stderr/stdin
@@ -41,43 +82,145 @@ func NewApp() *App {
 		stdin:  os.Stdin,
 		stdout: os.Stdout,
 		stderr: os.Stderr,
+
+		preventionWeight: config.DefaultPreventionWeight,
 	}
 }
 
-// initPaths initializes paths from config if not already set
+// ConfigReloadInterval is how long a loaded config is reused before
+// initPaths reads the config file again
+const ConfigReloadInterval = 30 * time.Second
+
+// appPaths are the App paths that config supplies unless set explicitly
+type appPaths struct {
+	projectPath, systemPath, handoffsPath, stealthPath, stateDir, baseDir string
+}
+
+// initPaths initializes paths from config if not already set. A long-lived
+// App reloads the config file once ConfigReloadInterval has passed since it
+// was last applied, unless noReload is set. Paths set before the first load
+// always win over config.
 func (a *App) initPaths() error {
-	if a.projectPath != "" && a.systemPath != "" && a.handoffsPath != "" && a.stealthPath != "" {
+	if a.configLoadedAt.IsZero() {
+		if a.projectPath != "" && a.systemPath != "" && a.handoffsPath != "" && a.stealthPath != "" {
+			return nil
+		}
+		a.explicitPaths = appPaths{a.projectPath, a.systemPath, a.handoffsPath, a.stealthPath, a.stateDir, a.baseDir}
+	} else if a.noReload || (a.config.Path() == a.getConfigPath() && a.clock().Sub(a.configLoadedAt) < ConfigReloadInterval) {
 		return nil
 	}
 
-	// Load config (from default path)
-	homeDir, _ := os.UserHomeDir()
-	configPath := filepath.Join(homeDir, ".config", "claude-recall", "config.json")
-	cfg, err := config.Load(configPath)
+	if a.config == nil || a.config.Path() != a.getConfigPath() {
+		a.config = config.NewWatchedConfig(a.getConfigPath())
+	}
+	cfg, err := a.config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Set paths based on config
-	if a.projectPath == "" {
-		a.projectPath = filepath.Join(cfg.ProjectDir, ".claude-recall", "LESSONS.md")
+	explicit := a.explicitPaths
+	a.projectPath = orDefault(explicit.projectPath, filepath.Join(cfg.ProjectDir, ".claude-recall", "LESSONS.md"))
+	a.systemPath = orDefault(explicit.systemPath, filepath.Join(cfg.StateDir, "LESSONS.md"))
+	a.handoffsPath = orDefault(explicit.handoffsPath, filepath.Join(cfg.ProjectDir, ".claude-recall", "HANDOFFS.md"))
+	a.stealthPath = orDefault(explicit.stealthPath, filepath.Join(cfg.ProjectDir, ".claude-recall", "HANDOFFS_LOCAL.md"))
+	a.stateDir = orDefault(explicit.stateDir, cfg.StateDir)
+	a.baseDir = orDefault(explicit.baseDir, cfg.Base)
+	a.projectDir = cfg.ProjectDir
+	a.debugLevel = cfg.DebugLevel
+	a.scoreCacheTTL = time.Duration(cfg.ScoreCacheTTL) * time.Second
+	a.sharedPaths = cfg.SharedPaths
+	a.syncRemote = cfg.SyncRemote
+	a.maxTokens = cfg.MaxTokens
+	a.recencyWeight = cfg.RecencyWeight
+	a.workspacePath = cfg.WorkspacePath
+	a.handoffsRecencyBias = cfg.HandoffsRecencyBias
+	a.preventionWeight = *cfg.PreventionWeight
+	a.dedupThreshold = cfg.DedupThreshold
+	a.dedupAlgo = cfg.DedupAlgo
+	a.webhookURL = cfg.WebhookURL
+	a.webhookEvents = cfg.WebhookEvents
+	a.configLoadedAt = a.clock()
+
+	return nil
+}
+
+// clock returns the current time (stubbable via a.now)
+func (a *App) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// orDefault returns value, or def when value is empty
+func orDefault(value, def string) string {
+	if value != "" {
+		return value
 	}
-	if a.systemPath == "" {
-		a.systemPath = filepath.Join(cfg.StateDir, "LESSONS.md")
+	return def
+}
+
+// getConfigPath returns the config file path, defaulting to ~/.config/claude-recall/config.json
+func (a *App) getConfigPath() string {
+	if a.configPath != "" {
+		return a.configPath
 	}
-	if a.handoffsPath == "" {
-		a.handoffsPath = filepath.Join(cfg.ProjectDir, ".claude-recall", "HANDOFFS.md")
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "claude-recall", "config.json")
+}
+
+// lessonPaths returns every configured lessons file: project, system,
+// shared libraries, and workspace
+func (a *App) lessonPaths() lessons.StorePaths {
+	return lessons.StorePaths{
+		Project:   a.projectPath,
+		System:    a.systemPath,
+		Shared:    a.sharedPaths,
+		Workspace: a.workspacePath,
 	}
-	if a.stealthPath == "" {
-		a.stealthPath = filepath.Join(cfg.ProjectDir, ".claude-recall", "HANDOFFS_LOCAL.md")
+}
+
+// localLessonStore returns a lesson store over the writable lessons files
+// only (no shared libraries, which belong to their own repos)
+func (a *App) localLessonStore() *lessons.Store {
+	paths := a.lessonPaths()
+	paths.Shared = nil
+	return lessons.OpenStore(paths)
+}
+
+// lessonStore returns a lesson store over every configured lessons file
+// that records changes in the audit log
+func (a *App) lessonStore() *lessons.Store {
+	store := lessons.OpenStore(a.lessonPaths())
+	store.SetAuditLog(lessons.NewAuditLog(a.stateDir))
+	if a.dedupThreshold > 0 {
+		store.SetDedupThreshold(a.dedupThreshold)
+	}
+	if a.dedupAlgo != "" {
+		if err := store.SetDedupAlgo(a.dedupAlgo); err != nil {
+			fmt.Fprintf(a.stderr, "warning: %v\n", err)
+		}
 	}
-	if a.stateDir == "" {
-		a.stateDir = cfg.StateDir
+	return store
+}
+
+// handoffStore returns a handoff store over the configured project and
+// stealth paths that reports status changes to the webhook, if configured
+func (a *App) handoffStore() *handoffs.Store {
+	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	if a.webhookURL != "" {
+		store.SetNotifier(handoffs.WebhookNotifier(a.webhookURL, a.webhookEvents, a.stderr))
 	}
-	a.projectDir = cfg.ProjectDir
-	a.debugLevel = cfg.DebugLevel
+	return store
+}
 
-	return nil
+// getGitProvider returns the git context provider for new lessons
+func (a *App) getGitProvider() lessons.GitContextProvider {
+	if a.gitProvider != nil {
+		return a.gitProvider
+	}
+	return lessons.ExecGitContext{}
 }
 
 // Run parses arguments and dispatches to commands
@@ -87,6 +230,12 @@ func (a *App) Run(args []string) int {
 		return 1
 	}
 
+	// Global flags come before the command
+	for len(args) > 2 && args[1] == "--no-reload" {
+		a.noReload = true
+		args = append(args[:1:1], args[2:]...)
+	}
+
 	cmd := args[1]
 	cmdArgs := args[2:]
 
@@ -96,10 +245,16 @@ func (a *App) Run(args []string) int {
 		return 0
 	}
 
+	// Config commands must run even when the config file is broken
+	if cmd == "config" {
+		return a.runConfig(cmdArgs)
+	}
+
 	if err := a.initPaths(); err != nil {
 		fmt.Fprintf(a.stderr, "error: %v\n", err)
 		return 1
 	}
+	anthropic.SetLogger(debuglog.New(a.stateDir, a.debugLevel))
 
 	switch cmd {
 	case "inject":
@@ -112,10 +267,22 @@ func (a *App) Run(args []string) int {
 		return a.runList(cmdArgs)
 	case "show":
 		return a.runShow(cmdArgs)
+	case "find":
+		return a.runFind(cmdArgs)
 	case "edit":
 		return a.runEdit(cmdArgs)
 	case "delete":
 		return a.runDelete(cmdArgs)
+	case "promote":
+		return a.runPromote(cmdArgs)
+	case "split":
+		return a.runSplit(cmdArgs)
+	case "improve":
+		return a.runImprove(cmdArgs)
+	case "category":
+		return a.runCategory(cmdArgs)
+	case "audit":
+		return a.runAudit(cmdArgs)
 	case "decay":
 		return a.runDecay(cmdArgs)
 	case "handoff":
@@ -126,12 +293,40 @@ func (a *App) Run(args []string) int {
 		return a.runScoreRelevance(cmdArgs)
 	case "score-local":
 		return a.runScoreLocal(cmdArgs)
+	case "cache":
+		return a.runCache(cmdArgs)
 	case "extract-context":
 		return a.runExtractContext(cmdArgs)
 	case "prescore-cache":
 		return a.runPrescoreCache(cmdArgs)
 	case "opencode":
 		return a.runOpencode(cmdArgs)
+	case "lesson":
+		return a.runLesson(cmdArgs)
+	case "lesson-graph":
+		return a.runLessonGraph(cmdArgs)
+	case "lesson-prevented":
+		return a.runLessonPrevented(cmdArgs)
+	case "lesson-audit":
+		return a.runLessonAudit(cmdArgs)
+	case "export":
+		return a.runExport(cmdArgs)
+	case "import":
+		return a.runImport(cmdArgs)
+	case "search":
+		return a.runSearch(cmdArgs)
+	case "stats":
+		return a.runStats(cmdArgs)
+	case "lint":
+		return a.runLint(cmdArgs)
+	case "watch":
+		return a.runWatch(cmdArgs)
+	case "sync":
+		return a.runSync(cmdArgs)
+	case "backup":
+		return a.runBackup(cmdArgs)
+	case "restore":
+		return a.runRestore(cmdArgs)
 	default:
 		fmt.Fprintf(a.stderr, "unknown command: %s\n", cmd)
 		a.printHelp()
@@ -146,42 +341,202 @@ func (a *App) printHelp() {
 Usage: recall <command> [args...]
 
 Commands:
-  inject [n]                       Output top n lessons for context injection
-  add <cat> <title> <content>      Add a new lesson (--system for system level)
+  inject [n] [--tag T]             Output top n lessons for context injection
+                                   (--source shared for shared_paths lessons only,
+                                   --max-tokens N to cap the output size,
+                                   --query Q to rank by local BM25 relevance,
+                                   --recency-weight W to blend in recency 0-1,
+                                   --co-cited ID for lessons cited alongside ID,
+                                   --randomize [--seed N] to sample n weighted
+                                   by score instead of always the same top n,
+                                   --format markdown|condensed|plain|json,
+                                   --template PATH for a text/template file;
+                                   default <base>/inject-templates/lessons.tmpl)
+  add <cat> <title> <content>      Add a new lesson (--system for system level,
+                                   --workspace for the workspace_path level,
+                                   --force to skip duplicate detection, --tag T,
+                                   --no-git to skip recording branch@commit,
+                                   --confidence N for 0-100 certainty,
+                                   --weight W to scale injection ranking)
+       <cat> <title> --from-file P Read content from file P (- for stdin)
   cite <id> [id...]                Cite one or more lessons (increment uses)
-  list                             List all lessons with ratings
+       --file <path>               Cite IDs listed one per line (# comments ok)
+       --session <id>              Record the citing session in the audit log
+  list [--tag T] [--json]          List all lessons with ratings
+       [--min-confidence N]        (only lessons with confidence >= N)
+       [--level L]                 (only project, system, workspace, or shared)
+       [--sort-by preventions]     (most mistakes prevented first)
+       [--used-today]              (also --used-this-week, --used-this-month)
+       [--never-used]              (only lessons with no uses)
   show <id>                        Show detailed lesson information
+  find <text> [--category C]       Find lessons by partial title or category
+                                   (shows details for a single match)
   edit <id> [--title T] [...]      Edit a lesson's properties
+                                   (--add-tag T, --remove-tag T, --confidence N,
+                                   --weight W)
+  edit --bulk <json>               Apply [{"id","title","content","category"}...]
+                                   all at once, or none if any ID is missing
   delete <id>                      Delete a lesson
-  decay [--force]                  Run velocity decay cycle
-
-  handoff list                     List active handoffs
-  handoff add <title> [opts]       Add new handoff (--desc D, --stealth)
-  handoff update <id> [opts]       Update handoff (--status, --phase, --next)
+  promote <id>                     Move a project lesson to system level
+  split <id> --title2 T --content2 C
+                                   Move content C out of a lesson into a new one
+  improve <id> [--auto-accept]     Suggest a clearer rewrite via the API, then
+       [--model M]                 confirm before saving (default model: Haiku)
+  category rename <old> <new>      Rename a category across all lessons (--dry-run)
+  audit list [--lesson ID]         Show lesson adds, edits, deletes, and citations
+             [--since DATE]        (recorded in lessons-audit.log in state dir)
+  decay [--force] [--dry-run]      Run velocity decay cycle (--dry-run previews
+                                   velocity changes without writing)
+                                   (--ttl 90d --ttl-action warn|delete to expire
+                                   never-cited lessons)
+  export [--json] [-o path]        Export lessons and handoffs as a JSON snapshot
+  export --csv [--fields a,b]      Export lessons as CSV (--sort-by field, -o path)
+  import [--json] <path|-> [opts]  Import a snapshot (--conflict=skip|overwrite|renumber,
+                                   --level project|system, --category C to
+                                   recategorize all, --category-map '{"old":"new"}'
+                                   per category; mapped entries beat --category)
+  import --from-markdown <path>    Import lessons from Markdown notes (## Title,
+                                   ### [category] Title, > tip: ...; --category C,
+                                   --level project|system)
+
+  handoff list [--json]            List active handoffs (--sort-by priority;
+                                   filter with --status, --phase, --agent;
+                                   --count prints only the number;
+                                   --stale shows handoffs flagged stale;
+                                   --blocked shows blocked handoffs with
+                                   their blockers, --resolve-ids=false
+                                   omits the blocker titles)
+  handoff add <title> [opts]       Add new handoff (--desc D, --stealth,
+                                   --priority critical|high|medium|low,
+                                   or --template NAME for a template's defaults)
+  handoff template list            List handoff templates
+  handoff template save <id> <n>   Save a handoff's phase, description, next
+                                   steps, and refs as template n
+  handoff update <id> [opts]       Update handoff (--status, --phase, --next,
+                                   --blocked-by ID,ID, --priority P;
+                                   --force-phase skips transition checks)
+  handoff bulk-update [opts]       Update all active handoffs matching
+                                   --filter-status/--filter-phase/--filter-agent
+                                   with --set-status/--set-phase/--set-agent/
+                                   --set-next (--dry-run to preview)
+  handoff show <id>                Show handoff details, tried steps, and notes
   handoff tried <id> <out> <desc>  Log attempt (outcome: success/fail/partial)
+  handoff tried --from-transcript <path>
+                                   Log every "HANDOFF UPDATE <id>: tried" line in a
+                                   JSONL transcript (--dry-run to preview)
+  handoff note <id> <text>         Append a timestamped note to a handoff
+  handoff log-time <id> <minutes>  Log time spent on a handoff (--session S)
+  handoff time-report [--id <id>]  Total minutes logged per handoff (or per
+                                   session for one handoff)
+  handoff search <query> [opts]    Rank handoffs by text match
+                                   (--status S, --top N, --json)
+  handoff milestone <id> add|complete <name>
+                                   Track intermediate milestones (or: list)
+  handoff checkpoint-history <id>  Show next steps snapshotted at each phase change
   handoff complete <id>            Mark handoff completed
+  handoff clone <id> [--title T]   Duplicate a handoff as a fresh not_started copy
   handoff archive                  Archive old completed handoffs
-  handoff inject                   Output handoffs for context injection
+                                   (--older-than 30d|2w|1m, --keep-min N,
+                                   --stealth for stealth handoffs only)
+  handoff inject [--max-tokens N]  Output handoffs for context injection
+                                   (--only-in-progress, --only-blocked,
+                                   --max-handoffs N, --compact one-liners,
+                                   --recency-bias B to favor recent updates 0-1,
+                                   --stealth-only, --public-only)
+  handoff inject-structured        Output handoffs as a JSON array for
+                                   tool-use API integrations
+                                   (--stealth-only, --public-only)
   handoff inject-todos             Format todos for continuation prompt
+                                   (--stealth-only, --public-only)
   handoff sync-todos <json>        Sync TodoWrite output to handoff
   handoff set-context <id> --json  Set structured context from precompact
   handoff set-session <hf> <sess>  Link session to handoff
   handoff get-session-handoff <s>  Lookup handoff for session
   handoff process-transcript       Parse transcript for handoff patterns
+  handoff timeline <id>            Show a handoff's activity chronologically
+  handoff export --csv [opts]      Export handoffs as CSV (--fields, --sort-by, -o)
+  handoff report [opts]            Markdown status report of active handoffs
+                                   (--filter-stale N, --include-metrics,
+                                   --out FILE)
+  handoff check-deps               Report circular blocked-by dependencies
+  handoff check-stale [--days N]   Report active handoffs not updated in N days
+                                   (default 14; --auto-flag marks them stale
+                                   for handoff list --stale)
+  handoff dependency add|remove    Mark <id> blocked (or no longer blocked) by
+          <id> <dep-id>            <dep-id>; adding rejects cycles
+  handoff graph [--format F]       Dependency graph as dot (default), json, or ascii
+  handoff git-sync [--since N]     Complete handoffs named in merge commits from
+                                   the last N days (default 7; --dry-run)
 
   debug log <message>              Log a debug message
   debug log-error <key> <msg>      Log an error event
   debug hook-phase <h> <p> <ms>    Log hook phase timing
   debug hook-end <h> <ms> [--phases json]  Log hook completion
   debug injection-budget <t> <l> <h> <d>   Log token budget breakdown
+  debug citation-stats [--session <id>]    Rank lessons by logged citations
 
   score-relevance <query> [opts]   Score lessons by relevance (Haiku API)
+                                   --cache-ttl N overrides score_cache_ttl (seconds)
+                                   --json for the same schema as score-local
+                                   --cache-only never calls the API (exit 2 on miss)
+  cache clear                      Delete cached relevance scores
+  cache stats                      Show cache hits, misses, and oldest entry age
+  cache list                       List cached queries with timestamps and sizes
+  cache delete <query>             Delete the cached scores for one query
   score-local <query> [opts]       Score lessons locally using BM25 (no API key)
+                                   Words ending in * match by prefix (err*)
+                                   --algo tfidf for TF-IDF cosine similarity
+                                   --cache reuses a saved BM25 index in state dir
+                                   --json for {query, results, cache_hit, algo}
+  search <query> [opts]            Full-text search of lessons and handoffs
+                                   (--type lessons|handoffs|all, --top N, --json)
+  stats [--json] [--since DATE]    Usage metrics across lessons and handoffs
+       [--category C]              (metrics for one category instead)
+  stats categories [--json]        Categories by citations with counts and velocity
+  config validate [--config path]  Check config file, paths, and API key
+       [--strict]                  (fail on warnings too, not just errors)
+  lint [--json]                    Check LESSONS.md and HANDOFFS.md for bad IDs,
+                                   duplicates, dates, counters, and blocked-by refs
+                                   (--verify-checksums to also check file footers)
+  watch start <transcript> [opts]  Cite lessons as they appear in a transcript, in
+                                   the background (--session ID, --interval D)
+  watch stop|status                Stop or check the background watcher
+  sync push|pull [opts]            Share lessons via JSON in the sync_remote directory
+                                   (--level project|system, --remote DIR, --dry-run,
+                                   --force to push over conflicts,
+                                   --conflict=skip|overwrite|renumber for pull)
+  backup <dest>                    Archive lessons, handoffs, and state (tar.gz)
+  restore <src> [--dry-run]        Restore files from a backup archive
   extract-context <path> [opts]    Extract handoff context from transcript
   prescore-cache --transcript <p>  Pre-warm relevance cache
+  opencode transcript-analyze <p>  Session report: messages, citations, lessons,
+                                   handoff ops, file edits, tokens, health
+                                   (--json, --top-lessons N)
+  opencode batch --sessions <dir>  Run session-idle over every *.json session in
+                                   dir; prints a JSON array of results
+                                   (--parallel N, --dry-run)
+
+  lesson score-local <query>       Same as score-local (wildcards supported)
+  lesson smart-inject [n] [opts]   Inject top n lessons reranked by --context-summary
+  lesson find-by-triggers <text>   List lessons whose triggers appear in text
+  lesson diff <id1> <id2>          Diff two lessons' content and metadata
+                                   (differing fields marked *; --json)
+  lesson report <id> [--json]      Citations, sessions, co-citations, velocity
+                                   trend, and similar lessons for one lesson
+  lesson-graph <id>                List lessons most often cited alongside <id>
+  lesson-prevented <id>            Record that a lesson prevented a mistake
+       [--desc TEXT]               (what it caught, kept in the audit log)
+  lesson-audit                     Review lessons one by one: keep, edit, or delete
+       [--last-used-before DATE]   (only lessons not cited since DATE)
+       [--min-age DAYS]            (only lessons learned at least DAYS ago)
+       [--batch-size N]            (review N per run, resuming where the last stopped)
+       [--auto-audit]              (score via the API instead of prompting;
+       [--min-score N]             flags lessons scoring below N, default 4)
 
 Options:
   help, --help, -h                 Show this help message
+  --no-reload <command>            Never reload config.json in a long-lived
+                                   process (normally reloaded every 30s)
 `
 	fmt.Fprint(a.stdout, help)
 }
@@ -189,25 +544,143 @@ Options:
 // runInject outputs top n lessons
 func (a *App) runInject(args []string) int {
 	n := 5
-	if len(args) > 0 {
-		if parsed, err := strconv.Atoi(args[0]); err == nil {
+	var tag string
+	var query string
+	var coCited string
+	source := "all"
+	maxTokens := a.maxTokens
+	recencyWeight := a.recencyWeight
+	randomize := false
+	seed := time.Now().Unix()
+	format := injectFormatMarkdown
+	var templatePath string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--tag" && i+1 < len(args) {
+			tag = args[i+1]
+			i++
+		} else if args[i] == "--template" && i+1 < len(args) {
+			templatePath = args[i+1]
+			i++
+		} else if args[i] == "--format" && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		} else if args[i] == "--randomize" {
+			randomize = true
+		} else if args[i] == "--seed" && i+1 < len(args) {
+			parsed, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				fmt.Fprintf(a.stderr, "error: invalid --seed '%s'\n", args[i+1])
+				return 1
+			}
+			seed = parsed
+			i++
+		} else if args[i] == "--recency-weight" && i+1 < len(args) {
+			parsed, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				fmt.Fprintf(a.stderr, "error: invalid --recency-weight '%s' (use 0.0-1.0)\n", args[i+1])
+				return 1
+			}
+			recencyWeight = parsed
+			i++
+		} else if args[i] == "--query" && i+1 < len(args) {
+			query = args[i+1]
+			i++
+		} else if args[i] == "--co-cited" && i+1 < len(args) {
+			coCited = args[i+1]
+			i++
+		} else if args[i] == "--source" && i+1 < len(args) {
+			source = args[i+1]
+			i++
+		} else if args[i] == "--max-tokens" && i+1 < len(args) {
+			parsed, err := strconv.Atoi(args[i+1])
+			if err != nil || parsed < 0 {
+				fmt.Fprintf(a.stderr, "error: invalid --max-tokens '%s'\n", args[i+1])
+				return 1
+			}
+			maxTokens = parsed
+			i++
+		} else if parsed, err := strconv.Atoi(args[i]); err == nil {
 			n = parsed
 		}
 	}
+	if randomize && (query != "" || coCited != "") {
+		fmt.Fprintln(a.stderr, "error: --randomize cannot be combined with --query or --co-cited")
+		return 1
+	}
+	layout, ok := injectLayouts[format]
+	if !ok && format != injectFormatJSON {
+		fmt.Fprintf(a.stderr, "error: invalid format '%s': must be markdown, condensed, plain, or json\n", format)
+		return 1
+	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
-	allLessons, err := store.List()
+	store := a.lessonStore()
+	var allLessons []*models.Lesson
+	var err error
+	switch source {
+	case "all":
+		allLessons, err = store.List()
+	case "shared":
+		allLessons, err = store.ListShared()
+	default:
+		fmt.Fprintf(a.stderr, "error: invalid source '%s': must be all or shared\n", source)
+		return 1
+	}
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
 		return 1
 	}
+	if tag != "" {
+		allLessons = filterByTag(allLessons, tag)
+	}
 
-	// Sort by uses + velocity (combined score)
-	sort.Slice(allLessons, func(i, j int) bool {
-		scoreI := float64(allLessons[i].Uses) + allLessons[i].Velocity
-		scoreJ := float64(allLessons[j].Uses) + allLessons[j].Velocity
-		return scoreI > scoreJ
-	})
+	hook := "session_start"
+	if coCited != "" {
+		// Keep only lessons cited alongside coCited, most frequent first
+		hook = "co_cited_inject"
+		graph, err := lessons.LoadCoGraph(lessons.CoGraphPath(a.stateDir))
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error loading co-citation graph: %v\n", err)
+			return 1
+		}
+		byID := make(map[string]*models.Lesson, len(allLessons))
+		for _, l := range allLessons {
+			byID[l.ID] = l
+		}
+		allLessons = nil
+		for _, c := range graph.Related(coCited) {
+			if l, ok := byID[c.ID]; ok {
+				allLessons = append(allLessons, l)
+			}
+		}
+	} else if query != "" {
+		// Rank by local BM25 relevance to the query (ties broken by uses)
+		hook = "query_inject"
+		scored := scoring.NewBM25Scorer(allLessons).Score(query)
+		allLessons = make([]*models.Lesson, len(scored))
+		for i, sl := range scored {
+			allLessons[i] = sl.Lesson
+		}
+	} else {
+		// Sort by uses + velocity + preventions (combined score), weighted by
+		// confidence and weight, optionally blended with how recently each
+		// lesson was used
+		now := time.Now()
+		score := func(l *models.Lesson) float64 {
+			if recencyWeight == 0 {
+				return scoring.InjectScore(l, a.preventionWeight)
+			}
+			return (1-recencyWeight)*scoring.InjectScore(l, a.preventionWeight) + recencyWeight*recencyScore(l, now)
+		}
+		if randomize {
+			// Weighted by score, so strong lessons still usually appear but
+			// the same top n isn't injected every session
+			allLessons = lessons.WeightedRandomSample(allLessons, n, seed, score)
+		} else {
+			sort.SliceStable(allLessons, func(i, j int) bool {
+				return score(allLessons[i]) > score(allLessons[j])
+			})
+		}
+	}
 
 	// Take top n
 	if n > len(allLessons) {
@@ -215,72 +688,389 @@ func (a *App) runInject(args []string) int {
 	}
 	topLessons := allLessons[:n]
 
-	// Log which lessons are being injected
+	// A user template replaces the built-in Markdown format: --template, or
+	// <base>/inject-templates/lessons.tmpl if it exists. A default template
+	// that fails to parse or render falls back to the built-in format rather
+	// than failing the hook.
+	var tmpl *template.Template
+	if format != injectFormatJSON && (templatePath != "" || format == injectFormatMarkdown) {
+		tmpl, err = a.lessonsTemplate(templatePath)
+		if err == nil && tmpl != nil {
+			_, err = renderLessonsTemplate(tmpl, topLessons, n)
+		}
+		if err != nil {
+			if templatePath != "" {
+				fmt.Fprintf(a.stderr, "error loading inject template: %v\n", err)
+				return 1
+			}
+			fmt.Fprintf(a.stderr, "warning: %v (using the built-in format)\n", err)
+			tmpl = nil
+		}
+	}
+
+	// Drop the lowest-scored lessons until the output fits the token budget.
+	// JSON output is budgeted as markdown.
+	if maxTokens > 0 {
+		var kept int
+		if tmpl != nil {
+			kept = fitTemplateBudget(tmpl, topLessons, n, maxTokens)
+		} else {
+			if format == injectFormatJSON {
+				layout = injectLayouts[injectFormatMarkdown]
+			}
+			blocks := make([]string, len(topLessons))
+			for i, l := range topLessons {
+				blocks[i] = layout.lesson(l)
+			}
+			kept = fitTokenBudget(layout.header, blocks, maxTokens)
+		}
+		if dropped := len(topLessons) - kept; dropped > 0 {
+			dlog := debuglog.New(a.stateDir, a.debugLevel)
+			dlog.LogBudgetDrop(hook, a.projectDir, "lessons", dropped, maxTokens)
+			topLessons = topLessons[:kept]
+		}
+	}
+
+	if format == injectFormatJSON {
+		a.logInjectedLessons(hook, topLessons)
+		return a.writeJSON(toInjectJSON(topLessons))
+	}
+
+	if tmpl != nil {
+		out, err := renderLessonsTemplate(tmpl, topLessons, n)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 1
+		}
+		a.logInjectedLessons(hook, topLessons)
+		fmt.Fprint(a.stdout, out)
+		return 0
+	}
+
+	a.writeInjectedLessons(hook, topLessons, format)
+	return 0
+}
+
+// injectLessonsHeader heads the lesson inject output
+const injectLessonsHeader = "## Recent Lessons\n\n"
+
+// Inject output formats
+const (
+	injectFormatMarkdown  = "markdown"
+	injectFormatCondensed = "condensed"
+	injectFormatPlain     = "plain"
+	injectFormatJSON      = "json"
+)
+
+// injectLayout is how one text inject format renders its header, level
+// group headers, and lessons
+type injectLayout struct {
+	header      string
+	levelHeader string // format string taking the capitalized level
+	lesson      func(l *models.Lesson) string
+}
+
+// injectLayouts maps each text inject format to its layout
+var injectLayouts = map[string]injectLayout{
+	injectFormatMarkdown:  {injectLessonsHeader, "### %s Lessons\n\n", formatInjectedLesson},
+	injectFormatCondensed: {"Recent Lessons:\n", "%s Lessons:\n", formatLessonCondensed},
+	injectFormatPlain:     {"Recent Lessons\n\n", "%s Lessons\n\n", formatLessonPlain},
+}
+
+// injectJSON is one lesson in inject --format json output
+type injectJSON struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Rating   string `json:"rating"`
+	Category string `json:"category"`
+}
+
+// toInjectJSON converts lessons to inject --format json output
+func toInjectJSON(lessonList []*models.Lesson) []injectJSON {
+	out := make([]injectJSON, len(lessonList))
+	for i, l := range lessonList {
+		out[i] = injectJSON{ID: l.ID, Title: l.Title, Content: l.Content, Rating: l.Rating(), Category: l.Category}
+	}
+	return out
+}
+
+// estimateTokens roughly estimates the token count of text (~4 chars per token)
+func estimateTokens(text string) int {
+	return len(text) / 4
+}
+
+// fitTokenBudget returns how many leading blocks fit, together with the
+// header, within maxTokens. Blocks are assumed to be in priority order.
+func fitTokenBudget(header string, blocks []string, maxTokens int) int {
+	total := estimateTokens(header)
+	for i, b := range blocks {
+		total += estimateTokens(b)
+		if total > maxTokens {
+			return i
+		}
+	}
+	return len(blocks)
+}
+
+// formatInjectedLesson renders one lesson in inject format
+func formatInjectedLesson(l *models.Lesson) string {
+	return fmt.Sprintf("### [%s] %s %s\n> %s\n\n", l.ID, l.Rating(), l.Title, l.Content)
+}
+
+// formatLessonCondensed renders one lesson on a single line, with its uses
+// rating as stars: "[L001|⭐⭐⭐|pattern] Title: Content"
+func formatLessonCondensed(l *models.Lesson) string {
+	stars := strings.Repeat("⭐", strings.Count(l.Stars(), "*"))
+	if stars == "" {
+		stars = "-"
+	}
+	content := strings.Join(strings.Fields(l.Content), " ")
+	return fmt.Sprintf("[%s|%s|%s] %s: %s\n", l.ID, stars, l.Category, l.Title, content)
+}
+
+// formatLessonPlain renders one lesson like inject format, without Markdown
+func formatLessonPlain(l *models.Lesson) string {
+	return fmt.Sprintf("[%s] %s %s\n%s\n\n", l.ID, l.Rating(), l.Title, l.Content)
+}
+
+// recencyScore is 1.0 for a lesson used today, decaying as 1/(1+days)
+// since it was last used
+func recencyScore(l *models.Lesson, now time.Time) float64 {
+	days := now.Sub(l.LastUsed).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return 1.0 / (1.0 + days)
+}
+
+// logInjectedLessons records which lessons a hook injected in the debug log
+func (a *App) logInjectedLessons(hook string, topLessons []*models.Lesson) {
 	dlog := debuglog.New(a.stateDir, a.debugLevel)
 	entries := make([]debuglog.LessonEntry, len(topLessons))
 	for i, l := range topLessons {
 		entries[i] = debuglog.LessonEntry{ID: l.ID, Title: l.Title}
 	}
-	dlog.LogInjection("session_start", a.projectDir, entries)
+	dlog.LogInjection(hook, a.projectDir, entries)
+}
+
+// writeInjectedLessons logs and outputs lessons in a text inject format
+func (a *App) writeInjectedLessons(hook string, topLessons []*models.Lesson, format string) {
+	a.logInjectedLessons(hook, topLessons)
 
-	// Output in inject format
 	if len(topLessons) == 0 {
 		fmt.Fprintln(a.stdout, "No lessons found.")
-		return 0
+		return
 	}
 
-	fmt.Fprintln(a.stdout, "## Recent Lessons")
-	fmt.Fprintln(a.stdout)
-
-	for _, l := range topLessons {
-		fmt.Fprintf(a.stdout, "### [%s] %s %s\n", l.ID, l.Rating(), l.Title)
-		fmt.Fprintf(a.stdout, "> %s\n\n", l.Content)
+	layout := injectLayouts[format]
+	fmt.Fprint(a.stdout, layout.header)
+	groups := groupByLevel(topLessons)
+	for _, g := range groups {
+		if len(groups) > 1 {
+			fmt.Fprintf(a.stdout, layout.levelHeader, strings.ToUpper(g.level[:1])+g.level[1:])
+		}
+		for _, l := range g.lessons {
+			fmt.Fprint(a.stdout, layout.lesson(l))
+		}
 	}
+}
 
-	return 0
+// injectLevelOrder is the order lesson levels are grouped in inject output,
+// broadest first
+var injectLevelOrder = []string{lessons.LevelWorkspace, "system", "project", lessons.LevelShared}
+
+// lessonGroup is the lessons of one level, in ranked order
+type lessonGroup struct {
+	level   string
+	lessons []*models.Lesson
+}
+
+// groupByLevel splits ranked lessons into non-empty groups in
+// injectLevelOrder, preserving rank order within each group
+func groupByLevel(ranked []*models.Lesson) []lessonGroup {
+	var groups []lessonGroup
+	for _, level := range injectLevelOrder {
+		g := lessonGroup{level: level}
+		for _, l := range ranked {
+			if l.Level == level || (l.Level == "" && level == "project") {
+				g.lessons = append(g.lessons, l)
+			}
+		}
+		if len(g.lessons) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
 }
 
 // runAdd creates a new lesson
 func (a *App) runAdd(args []string) int {
-	if len(args) < 3 {
-		fmt.Fprintln(a.stderr, "usage: recall add <category> <title> <content> [--system]")
+	// --from-file replaces the content argument, so pull it out first
+	fromFile := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--from-file" && i+1 < len(args) {
+			fromFile = args[i+1]
+			args = append(args[:i:i], args[i+2:]...)
+			break
+		}
+	}
+
+	minArgs := 3
+	if fromFile != "" {
+		minArgs = 2
+	}
+	if len(args) < minArgs {
+		fmt.Fprintln(a.stderr, "usage: recall add <category> <title> <content|--from-file PATH> [--system|--workspace] [--force] [--tag T]... [--no-git] [--confidence N] [--weight W]")
 		return 1
 	}
 
 	category := args[0]
 	title := args[1]
-	content := args[2]
+	flagStart := 3
+	var content string
+	if fromFile != "" {
+		var data []byte
+		var err error
+		if fromFile == "-" {
+			data, err = io.ReadAll(a.stdin)
+		} else {
+			data, err = os.ReadFile(fromFile)
+		}
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error reading %s: %v\n", fromFile, err)
+			return 1
+		}
+		content = strings.TrimSpace(string(data))
+		if content == "" {
+			fmt.Fprintf(a.stderr, "error: %s is empty\n", fromFile)
+			return 1
+		}
+		flagStart = 2
+	} else {
+		content = args[2]
+	}
 	level := "project"
+	force := false
+	noGit := false
+	confidence := models.DefaultConfidence
+	weight := models.DefaultWeight
+	var tags []string
 
-	// Check for --system flag
-	for i := 3; i < len(args); i++ {
-		if args[i] == "--system" {
+	// Check for flags
+	for i := flagStart; i < len(args); i++ {
+		switch args[i] {
+		case "--system":
 			level = "system"
+		case "--workspace":
+			level = lessons.LevelWorkspace
+		case "--force":
+			force = true
+		case "--no-git":
+			noGit = true
+		case "--confidence":
+			if i+1 < len(args) {
+				n, err := parseConfidence(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					return 1
+				}
+				confidence = n
+				i++
+			}
+		case "--weight":
+			if i+1 < len(args) {
+				w, err := parseWeight(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					return 1
+				}
+				weight = w
+				i++
+			}
+		case "--tag":
+			if i+1 < len(args) {
+				tags = append(tags, args[i+1])
+				i++
+			}
 		}
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
-	lesson, err := store.Add(level, category, title, content)
+	store := a.lessonStore()
+	if !noGit {
+		store.SetGitContextProvider(a.getGitProvider(), a.projectDir)
+	}
+
+	var lesson *models.Lesson
+	var err error
+	if force {
+		lesson, err = store.ForceAdd(level, category, title, content)
+	} else {
+		lesson, err = store.Add(level, category, title, content)
+	}
+
+	var dupErr *lessons.DuplicateError
+	if errors.As(err, &dupErr) {
+		fmt.Fprintf(a.stderr, "warning: lesson looks like a duplicate of %s (%.0f%% similar); use --force to add anyway\n",
+			dupErr.ID, dupErr.Similarity*100)
+		return 1
+	}
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error adding lesson: %v\n", err)
 		return 1
 	}
 
+	updates := make(map[string]interface{})
+	if len(tags) > 0 {
+		updates["tags"] = tags
+	}
+	if confidence != models.DefaultConfidence {
+		updates["confidence"] = confidence
+	}
+	if weight != models.DefaultWeight {
+		updates["weight"] = weight
+	}
+	if len(updates) > 0 {
+		if err := store.Edit(lesson.ID, updates); err != nil {
+			fmt.Fprintf(a.stderr, "error updating new lesson: %v\n", err)
+			return 1
+		}
+	}
+
 	fmt.Fprintf(a.stdout, "Added lesson %s: %s\n", lesson.ID, title)
 	return 0
 }
 
 // runCite cites one or more lessons
 func (a *App) runCite(args []string) int {
-	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall cite <id> [id...]")
+	var ids []string
+	var filePath, sessionID string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--file" && i+1 < len(args):
+			filePath = args[i+1]
+			i++
+		case args[i] == "--session" && i+1 < len(args):
+			sessionID = args[i+1]
+			i++
+		default:
+			ids = append(ids, args[i])
+		}
+	}
+
+	if len(ids) == 0 && filePath == "" {
+		fmt.Fprintln(a.stderr, "usage: recall cite <id> [id...] | --file <path> [--session <id>]")
 		return 1
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
+
+	if filePath != "" {
+		return a.runCiteFile(store, filePath, ids, sessionID)
+	}
 
-	for _, id := range args {
-		if err := store.Cite(id); err != nil {
+	for _, id := range ids {
+		if err := store.CiteInSession(id, sessionID); err != nil {
 			fmt.Fprintf(a.stderr, "error citing %s: %v\n", id, err)
 			return 1
 		}
@@ -290,14 +1080,144 @@ func (a *App) runCite(args []string) int {
 	return 0
 }
 
+// runCiteFile cites every ID listed in path (one per line; blank lines and
+// # comments are skipped) plus any extra IDs, reporting a summary instead of
+// stopping at the first missing lesson. Fails only if no citation succeeded.
+func (a *App) runCiteFile(store *lessons.Store, path string, extra []string, sessionID string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading %s: %v\n", path, err)
+		return 1
+	}
+
+	ids := extra
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+
+	cited, failed := 0, 0
+	for _, id := range ids {
+		if err := store.CiteInSession(id, sessionID); err != nil {
+			fmt.Fprintf(a.stderr, "error citing %s: %v\n", id, err)
+			failed++
+			continue
+		}
+		cited++
+	}
+
+	fmt.Fprintf(a.stdout, "Cited %d lessons (%d not found)\n", cited, failed)
+	if cited == 0 {
+		return 1
+	}
+	return 0
+}
+
 // runList lists all lessons
 func (a *App) runList(args []string) int {
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	var tag, level, sortBy, usedWithin string
+	jsonOutput := false
+	neverUsed := false
+	minConfidence := 0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--used-today":
+			usedWithin = "day"
+		case args[i] == "--used-this-week":
+			usedWithin = "week"
+		case args[i] == "--used-this-month":
+			usedWithin = "month"
+		case args[i] == "--never-used":
+			neverUsed = true
+		case args[i] == "--tag" && i+1 < len(args):
+			tag = args[i+1]
+			i++
+		case args[i] == "--sort-by" && i+1 < len(args):
+			sortBy = args[i+1]
+			i++
+		case args[i] == "--level" && i+1 < len(args):
+			level = args[i+1]
+			i++
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--min-confidence" && i+1 < len(args):
+			n, err := parseConfidence(args[i+1])
+			if err != nil {
+				fmt.Fprintf(a.stderr, "error: %v\n", err)
+				return 1
+			}
+			minConfidence = n
+			i++
+		}
+	}
+
+	store := a.lessonStore()
 	allLessons, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
 		return 1
 	}
+	if tag != "" {
+		allLessons = filterByTag(allLessons, tag)
+	}
+	if level != "" {
+		var atLevel []*models.Lesson
+		for _, l := range allLessons {
+			if l.Level == level {
+				atLevel = append(atLevel, l)
+			}
+		}
+		allLessons = atLevel
+	}
+	if minConfidence > 0 {
+		var confident []*models.Lesson
+		for _, l := range allLessons {
+			if l.Confidence >= minConfidence {
+				confident = append(confident, l)
+			}
+		}
+		allLessons = confident
+	}
+	if usedWithin != "" || neverUsed {
+		since := usedPeriodStart(usedWithin, a.clock())
+		var matching []*models.Lesson
+		for _, l := range allLessons {
+			if usedWithin != "" && l.LastUsed.Before(since) {
+				continue
+			}
+			if neverUsed && l.Uses != 0 {
+				continue
+			}
+			matching = append(matching, l)
+		}
+		allLessons = matching
+	}
+	switch sortBy {
+	case "":
+	case "preventions":
+		sort.SliceStable(allLessons, func(i, j int) bool {
+			return allLessons[i].Preventions > allLessons[j].Preventions
+		})
+	default:
+		fmt.Fprintf(a.stderr, "error: unknown sort key %q (use preventions)\n", sortBy)
+		return 1
+	}
+
+	if jsonOutput {
+		// Full lesson model plus the computed rating
+		type listedLesson struct {
+			*models.Lesson
+			Rating string `json:"rating"`
+		}
+		out := make([]listedLesson, len(allLessons))
+		for i, l := range allLessons {
+			out[i] = listedLesson{Lesson: l, Rating: l.Rating()}
+		}
+		return a.writeJSON(out)
+	}
 
 	if len(allLessons) == 0 {
 		fmt.Fprintln(a.stdout, "No lessons found.")
@@ -305,21 +1225,83 @@ func (a *App) runList(args []string) int {
 	}
 
 	for _, l := range allLessons {
-		fmt.Fprintf(a.stdout, "%s %s %s (%s)\n", l.ID, l.Rating(), l.Title, l.Category)
+		a.printLessonLine(l)
 	}
 
 	return 0
 }
 
-// runShow shows a single lesson in detail
-func (a *App) runShow(args []string) int {
-	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall show <id>")
-		return 1
+// usedPeriodStart returns the first day of the current day, week (starting
+// Monday), or month as a UTC date, comparable with lesson LastUsed dates
+func usedPeriodStart(period string, now time.Time) time.Time {
+	y, m, d := now.Date()
+	switch period {
+	case "week":
+		d -= (int(now.Weekday()) + 6) % 7
+	case "month":
+		d = 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// printLessonLine prints a lesson in the one-line list format
+func (a *App) printLessonLine(l *models.Lesson) {
+	expired := ""
+	if l.Expired {
+		expired = " [expired]"
+	}
+	fmt.Fprintf(a.stdout, "%s %s %s (%s)%s\n", l.ID, l.Rating(), l.Title, l.Category, expired)
+}
+
+// writeJSON encodes v as a single line of JSON on stdout
+func (a *App) writeJSON(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error encoding output JSON: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(a.stdout, string(data))
+	return 0
+}
+
+// filterByTag returns the lessons carrying tag
+func filterByTag(lessonList []*models.Lesson, tag string) []*models.Lesson {
+	var tagged []*models.Lesson
+	for _, l := range lessonList {
+		if l.HasTag(tag) {
+			tagged = append(tagged, l)
+		}
+	}
+	return tagged
+}
+
+// parseConfidence parses a 0-100 confidence value
+func parseConfidence(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || !models.IsValidConfidence(n) {
+		return 0, fmt.Errorf("invalid confidence %q (use 0-%d)", s, models.MaxConfidence)
+	}
+	return n, nil
+}
+
+// parseWeight parses a positive ranking weight
+func parseWeight(s string) (float64, error) {
+	w, err := strconv.ParseFloat(s, 64)
+	if err != nil || !models.IsValidWeight(w) {
+		return 0, fmt.Errorf("invalid weight %q (must be a positive number)", s)
+	}
+	return w, nil
+}
+
+// runShow shows a single lesson in detail
+func (a *App) runShow(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall show <id>")
+		return 1
 	}
 
 	id := args[0]
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
 
 	lesson, err := store.Get(id)
 	if err != nil {
@@ -327,6 +1309,12 @@ func (a *App) runShow(args []string) int {
 		return 1
 	}
 
+	a.printLessonDetail(lesson)
+	return 0
+}
+
+// printLessonDetail prints every field of a lesson, as shown by recall show
+func (a *App) printLessonDetail(lesson *models.Lesson) {
 	fmt.Fprintf(a.stdout, "ID: %s\n", lesson.ID)
 	fmt.Fprintf(a.stdout, "Title: %s\n", lesson.Title)
 	fmt.Fprintf(a.stdout, "Category: %s\n", lesson.Category)
@@ -336,17 +1324,29 @@ func (a *App) runShow(args []string) int {
 	fmt.Fprintf(a.stdout, "Learned: %s\n", lesson.Learned.Format("2006-01-02"))
 	fmt.Fprintf(a.stdout, "Last Used: %s\n", lesson.LastUsed.Format("2006-01-02"))
 	fmt.Fprintf(a.stdout, "Rating: %s\n", lesson.Rating())
+	fmt.Fprintf(a.stdout, "Confidence: %d\n", lesson.Confidence)
+	fmt.Fprintf(a.stdout, "Weight: %g\n", lesson.Weight)
+	if lesson.Preventions > 0 {
+		fmt.Fprintf(a.stdout, "Prevented: %d\n", lesson.Preventions)
+	}
+	if len(lesson.Tags) > 0 {
+		fmt.Fprintf(a.stdout, "Tags: %s\n", strings.Join(lesson.Tags, ", "))
+	}
+	if lesson.Git != nil {
+		fmt.Fprintf(a.stdout, "Git: %s\n", lesson.Git)
+	}
 	fmt.Fprintf(a.stdout, "\nContent:\n%s\n", lesson.Content)
-
-	return 0
 }
 
 // runEdit modifies an existing lesson
 func (a *App) runEdit(args []string) int {
 	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall edit <id> [--title T] [--content C] [--category C]")
+		fmt.Fprintln(a.stderr, "usage: recall edit <id> [--title T] [--content C] [--category C] [--add-tag T] [--remove-tag T] [--confidence N] [--weight W]")
 		return 1
 	}
+	if args[0] == "--bulk" {
+		return a.runEditBulk(args[1:])
+	}
 
 	id := args[0]
 	updates := make(map[string]interface{})
@@ -369,6 +1369,38 @@ func (a *App) runEdit(args []string) int {
 				updates["category"] = args[i+1]
 				i++
 			}
+		case "--add-tag":
+			if i+1 < len(args) {
+				addTags, _ := updates["add_tags"].([]string)
+				updates["add_tags"] = append(addTags, args[i+1])
+				i++
+			}
+		case "--remove-tag":
+			if i+1 < len(args) {
+				removeTags, _ := updates["remove_tags"].([]string)
+				updates["remove_tags"] = append(removeTags, args[i+1])
+				i++
+			}
+		case "--confidence":
+			if i+1 < len(args) {
+				n, err := parseConfidence(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					return 1
+				}
+				updates["confidence"] = n
+				i++
+			}
+		case "--weight":
+			if i+1 < len(args) {
+				w, err := parseWeight(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					return 1
+				}
+				updates["weight"] = w
+				i++
+			}
 		}
 	}
 
@@ -377,7 +1409,7 @@ func (a *App) runEdit(args []string) int {
 		return 1
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
 	if err := store.Edit(id, updates); err != nil {
 		fmt.Fprintf(a.stderr, "error editing lesson: %v\n", err)
 		return 1
@@ -387,6 +1419,72 @@ func (a *App) runEdit(args []string) int {
 	return 0
 }
 
+// bulkEditEntry is one element of the recall edit --bulk JSON array
+type bulkEditEntry struct {
+	ID       string  `json:"id"`
+	Title    *string `json:"title"`
+	Content  *string `json:"content"`
+	Category *string `json:"category"`
+}
+
+// runEditBulk applies a JSON array of lesson updates all at once ("-" reads
+// the array from stdin). If any ID is missing nothing is written.
+func (a *App) runEditBulk(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, `usage: recall edit --bulk '[{"id":"L001","title":"T","content":"C"},...]' (- for stdin)`)
+		return 1
+	}
+
+	data := []byte(args[0])
+	if args[0] == "-" {
+		var err error
+		if data, err = io.ReadAll(a.stdin); err != nil {
+			fmt.Fprintf(a.stderr, "error reading stdin: %v\n", err)
+			return 1
+		}
+	}
+	var entries []bulkEditEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		fmt.Fprintf(a.stderr, "error parsing bulk JSON: %v\n", err)
+		return 1
+	}
+
+	edits := make([]lessons.BulkEdit, 0, len(entries))
+	ids := make(map[string]bool)
+	for i, e := range entries {
+		if e.ID == "" {
+			fmt.Fprintf(a.stderr, "error: entry %d has no id\n", i+1)
+			return 1
+		}
+		updates := make(map[string]interface{})
+		if e.Title != nil {
+			updates["title"] = *e.Title
+		}
+		if e.Content != nil {
+			updates["content"] = *e.Content
+		}
+		if e.Category != nil {
+			updates["category"] = *e.Category
+		}
+		edits = append(edits, lessons.BulkEdit{ID: e.ID, Updates: updates})
+		ids[e.ID] = true
+	}
+
+	if err := a.lessonStore().EditBulk(edits); err != nil {
+		var notFound *lessons.NotFoundError
+		if errors.As(err, &notFound) {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			fmt.Fprintf(a.stdout, "Updated 0 lessons, %d not found\n", len(notFound.IDs))
+			return 1
+		}
+		fmt.Fprintf(a.stderr, "error editing lessons: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Updated %d lessons, 0 not found\n", len(ids))
+	return 0
+}
+
 // runDelete deletes a lesson
 func (a *App) runDelete(args []string) int {
 	if len(args) < 1 {
@@ -395,7 +1493,7 @@ func (a *App) runDelete(args []string) int {
 	}
 
 	id := args[0]
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
 
 	if err := store.Delete(id); err != nil {
 		fmt.Fprintf(a.stderr, "error deleting lesson: %v\n", err)
@@ -406,28 +1504,110 @@ func (a *App) runDelete(args []string) int {
 	return 0
 }
 
+// runPromote moves a project lesson to system level
+func (a *App) runPromote(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall promote <id>")
+		return 1
+	}
+
+	id := args[0]
+	store := a.lessonStore()
+
+	lesson, err := store.Promote(id)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error promoting lesson: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Promoted %s -> %s\n", id, lesson.ID)
+	return 0
+}
+
+// runSplit moves part of a broad lesson into a new lesson
+func (a *App) runSplit(args []string) int {
+	var id, title2, content2 string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--title2" && i+1 < len(args) {
+			title2 = args[i+1]
+			i++
+		} else if args[i] == "--content2" && i+1 < len(args) {
+			content2 = args[i+1]
+			i++
+		} else if id == "" {
+			id = args[i]
+		}
+	}
+	if id == "" || title2 == "" || content2 == "" {
+		fmt.Fprintln(a.stderr, "usage: recall split <id> --title2 <title> --content2 <content>")
+		return 1
+	}
+
+	store := a.lessonStore()
+	lesson, err := store.Split(id, title2, content2)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error splitting lesson: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Split %s -> %s: %s\n", id, lesson.ID, lesson.Title)
+	return 0
+}
+
 // runDecay runs decay cycle
 func (a *App) runDecay(args []string) int {
 	force := false
-	for _, arg := range args {
-		if arg == "--force" {
+	dryRun := false
+	ttl := lessons.TTLConfig{ExpireAction: lessons.ExpireActionWarn}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--force":
 			force = true
+		case "--dry-run":
+			dryRun = true
+		case "--ttl":
+			if i+1 < len(args) {
+				d, err := parseTTL(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: invalid --ttl %q: %v\n", args[i+1], err)
+					return 1
+				}
+				ttl.ZeroUseTTL = d
+				i++
+			}
+		case "--ttl-action":
+			if i+1 < len(args) {
+				ttl.ExpireAction = args[i+1]
+				i++
+			}
 		}
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	if ttl.ExpireAction != lessons.ExpireActionWarn && ttl.ExpireAction != lessons.ExpireActionDelete {
+		fmt.Fprintf(a.stderr, "error: unknown --ttl-action %q (use warn or delete)\n", ttl.ExpireAction)
+		return 1
+	}
+
+	store := a.lessonStore()
+
+	cfg := lessons.DecayConfig{
+		StateFile:     filepath.Join(a.stateDir, "decay_state.json"),
+		DecayInterval: 7 * 24 * time.Hour, // 7 days
+		TTL:           ttl,
+		DryRun:        dryRun,
+	}
 
 	var count int
+	var preview []lessons.DecayPreview
 	var err error
 
 	if force {
-		count, err = lessons.ForceDecay(store)
-	} else {
-		cfg := lessons.DecayConfig{
-			StateFile:     filepath.Join(a.stateDir, "decay_state.json"),
-			DecayInterval: 7 * 24 * time.Hour, // 7 days
+		count, preview, err = lessons.ForceDecay(store, cfg)
+		if err == nil && !dryRun {
+			_, err = lessons.ExpireLessons(store, ttl)
 		}
-		count, err = lessons.Decay(store, cfg)
+	} else {
+		count, preview, err = lessons.Decay(store, cfg)
 	}
 
 	if err != nil {
@@ -435,6 +1615,11 @@ func (a *App) runDecay(args []string) int {
 		return 1
 	}
 
+	if dryRun {
+		a.printDecayPreview(count, preview)
+		return 0
+	}
+
 	if count > 0 {
 		fmt.Fprintf(a.stdout, "Decayed %d lessons\n", count)
 	} else {
@@ -444,6 +1629,37 @@ func (a *App) runDecay(args []string) int {
 	return 0
 }
 
+// printDecayPreview prints the old and new velocity of each lesson a decay
+// would change
+func (a *App) printDecayPreview(count int, preview []lessons.DecayPreview) {
+	if count == 0 {
+		fmt.Fprintln(a.stdout, "No decay needed")
+		return
+	}
+	if len(preview) == 0 {
+		fmt.Fprintf(a.stdout, "Dry run: %d lessons checked, no velocity changes\n", count)
+		return
+	}
+
+	fmt.Fprintf(a.stdout, "Dry run: %d of %d lessons would change\n\n", len(preview), count)
+	fmt.Fprintf(a.stdout, "%-6s  %8s  %8s\n", "ID", "OLD", "NEW")
+	for _, p := range preview {
+		fmt.Fprintf(a.stdout, "%-6s  %8.3f  %8.3f\n", p.LessonID, p.OldVelocity, p.NewVelocity)
+	}
+}
+
+// parseTTL parses a TTL such as "90d" (days) or any time.ParseDuration value
+func parseTTL(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("expected a number of days like 90d")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
 // runHandoff dispatches to handoff subcommands
 func (a *App) runHandoff(args []string) int {
 	if len(args) < 1 {
@@ -451,16 +1667,35 @@ func (a *App) runHandoff(args []string) int {
 		fmt.Fprintln(a.stderr, "  list              - List active handoffs")
 		fmt.Fprintln(a.stderr, "  add               - Add new handoff")
 		fmt.Fprintln(a.stderr, "  update            - Update a handoff")
+		fmt.Fprintln(a.stderr, "  bulk-update       - Update every handoff matching filters")
+		fmt.Fprintln(a.stderr, "  show              - Show handoff details and notes")
 		fmt.Fprintln(a.stderr, "  tried             - Add a tried step")
+		fmt.Fprintln(a.stderr, "  note              - Append a timestamped note")
+		fmt.Fprintln(a.stderr, "  search            - Rank handoffs by BM25 text match")
+		fmt.Fprintln(a.stderr, "  milestone         - Add, complete, or list milestones")
+		fmt.Fprintln(a.stderr, "  checkpoint-history - Show next steps snapshotted at each phase change")
+		fmt.Fprintln(a.stderr, "  template          - List or save handoff templates")
 		fmt.Fprintln(a.stderr, "  complete          - Mark handoff completed")
+		fmt.Fprintln(a.stderr, "  log-time          - Log minutes spent on a handoff")
+		fmt.Fprintln(a.stderr, "  time-report       - Show total minutes logged per handoff")
+		fmt.Fprintln(a.stderr, "  clone             - Duplicate a handoff with fresh status")
 		fmt.Fprintln(a.stderr, "  archive           - Archive old completed")
 		fmt.Fprintln(a.stderr, "  inject            - Output handoffs for context injection")
+		fmt.Fprintln(a.stderr, "  inject-structured - Output handoffs as JSON for tool-use integrations")
 		fmt.Fprintln(a.stderr, "  inject-todos      - Format todos for continuation prompt")
 		fmt.Fprintln(a.stderr, "  sync-todos        - Sync TodoWrite output to handoff")
 		fmt.Fprintln(a.stderr, "  set-context       - Set structured context")
 		fmt.Fprintln(a.stderr, "  set-session       - Link session to handoff")
 		fmt.Fprintln(a.stderr, "  get-session-handoff - Lookup handoff for session")
 		fmt.Fprintln(a.stderr, "  process-transcript  - Parse transcript for handoff patterns")
+		fmt.Fprintln(a.stderr, "  timeline          - Show handoff activity chronologically")
+		fmt.Fprintln(a.stderr, "  export            - Export handoffs as CSV")
+		fmt.Fprintln(a.stderr, "  report            - Markdown status report of active handoffs")
+		fmt.Fprintln(a.stderr, "  check-deps        - Report circular blocked-by dependencies")
+		fmt.Fprintln(a.stderr, "  check-stale       - Report active handoffs not updated recently")
+		fmt.Fprintln(a.stderr, "  dependency        - Add or remove a blocked-by dependency")
+		fmt.Fprintln(a.stderr, "  graph             - Output the blocked-by dependency graph")
+		fmt.Fprintln(a.stderr, "  git-sync          - Complete handoffs referenced by merge commits")
 		return 1
 	}
 
@@ -474,14 +1709,36 @@ func (a *App) runHandoff(args []string) int {
 		return a.runHandoffAdd(subArgs)
 	case "update":
 		return a.runHandoffUpdate(subArgs)
+	case "bulk-update":
+		return a.runHandoffBulkUpdate(subArgs)
+	case "show":
+		return a.runHandoffShow(subArgs)
 	case "tried":
 		return a.runHandoffTried(subArgs)
+	case "note":
+		return a.runHandoffNote(subArgs)
+	case "log-time":
+		return a.runHandoffLogTime(subArgs)
+	case "time-report":
+		return a.runHandoffTimeReport(subArgs)
+	case "search":
+		return a.runHandoffSearch(subArgs)
+	case "milestone":
+		return a.runHandoffMilestone(subArgs)
+	case "checkpoint-history":
+		return a.runHandoffCheckpointHistory(subArgs)
+	case "template":
+		return a.runHandoffTemplate(subArgs)
 	case "complete":
 		return a.runHandoffComplete(subArgs)
+	case "clone":
+		return a.runHandoffClone(subArgs)
 	case "archive":
 		return a.runHandoffArchive(subArgs)
 	case "inject":
 		return a.runHandoffInject(subArgs)
+	case "inject-structured":
+		return a.runHandoffInjectStructured(subArgs)
 	case "inject-todos":
 		return a.runHandoffInjectTodos(subArgs)
 	case "sync-todos":
@@ -494,6 +1751,22 @@ func (a *App) runHandoff(args []string) int {
 		return a.runHandoffGetSessionHandoff(subArgs)
 	case "process-transcript":
 		return a.runHandoffProcessTranscript(subArgs)
+	case "timeline":
+		return a.runHandoffTimeline(subArgs)
+	case "export":
+		return a.runHandoffExport(subArgs)
+	case "report":
+		return a.runHandoffReport(subArgs)
+	case "check-deps":
+		return a.runHandoffCheckDeps(subArgs)
+	case "check-stale":
+		return a.runHandoffCheckStale(subArgs)
+	case "dependency":
+		return a.runHandoffDependency(subArgs)
+	case "graph":
+		return a.runHandoffGraph(subArgs)
+	case "git-sync":
+		return a.runHandoffGitSync(subArgs)
 	default:
 		fmt.Fprintf(a.stderr, "unknown handoff subcommand: %s\n", subcmd)
 		return 1
@@ -502,13 +1775,124 @@ func (a *App) runHandoff(args []string) int {
 
 // runHandoffList lists active handoffs
 func (a *App) runHandoffList(args []string) int {
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	jsonOutput := false
+	countOnly := false
+	blockedOnly := false
+	staleOnly := false
+	resolveIDs := ""
+	sortBy := ""
+	var status, phase, agent string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		case "--count":
+			countOnly = true
+		case "--blocked":
+			blockedOnly = true
+		case "--stale":
+			staleOnly = true
+		case "--resolve-ids", "--resolve-ids=true":
+			resolveIDs = "true"
+		case "--resolve-ids=false":
+			resolveIDs = "false"
+		case "--sort-by":
+			if i+1 < len(args) {
+				sortBy = args[i+1]
+				i++
+			}
+		case "--status":
+			if i+1 < len(args) {
+				status = args[i+1]
+				i++
+			}
+		case "--phase":
+			if i+1 < len(args) {
+				phase = args[i+1]
+				i++
+			}
+		case "--agent":
+			if i+1 < len(args) {
+				agent = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if sortBy != "" && sortBy != "priority" {
+		fmt.Fprintf(a.stderr, "error: unknown sort key %q (use priority)\n", sortBy)
+		return 1
+	}
+	if status != "" && !models.IsValidHandoffStatus(status) {
+		fmt.Fprintf(a.stderr, "error: invalid status %q\n", status)
+		return 1
+	}
+	if phase != "" && !models.IsValidHandoffPhase(phase) {
+		fmt.Fprintf(a.stderr, "error: invalid phase %q\n", phase)
+		return 1
+	}
+
+	store := a.handoffStore()
 
 	handoffList, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
 		return 1
 	}
+	handoffList = filterHandoffs(handoffList, status, phase, agent)
+	if blockedOnly {
+		var blocked []*models.Handoff
+		for _, h := range handoffList {
+			if h.Status == "blocked" || len(h.BlockedBy) > 0 {
+				blocked = append(blocked, h)
+			}
+		}
+		handoffList = blocked
+	}
+	if staleOnly {
+		var stale []*models.Handoff
+		for _, h := range handoffList {
+			if h.Stale {
+				stale = append(stale, h)
+			}
+		}
+		handoffList = stale
+	}
+
+	// Blockers are shown inline with --blocked or --resolve-ids; titles are
+	// looked up unless --resolve-ids=false
+	var titles map[string]string
+	showBlockers := blockedOnly || resolveIDs != ""
+	if resolveIDs == "true" || (blockedOnly && resolveIDs == "") {
+		all, err := store.ListAll()
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+			return 1
+		}
+		titles = make(map[string]string, len(all))
+		for _, h := range all {
+			titles[h.ID] = h.Title
+		}
+	}
+
+	if countOnly {
+		fmt.Fprintln(a.stdout, len(handoffList))
+		return 0
+	}
+
+	if sortBy == "priority" {
+		// Stable so equal priorities keep most-recently-updated order
+		sort.SliceStable(handoffList, func(i, j int) bool {
+			return models.PriorityRank(handoffList[i].Priority) < models.PriorityRank(handoffList[j].Priority)
+		})
+	}
+
+	if jsonOutput {
+		if handoffList == nil {
+			handoffList = []*models.Handoff{}
+		}
+		return a.writeJSON(handoffList)
+	}
 
 	if len(handoffList) == 0 {
 		fmt.Fprintln(a.stdout, "No active handoffs.")
@@ -520,7 +1904,22 @@ func (a *App) runHandoffList(args []string) int {
 		if h.Stealth {
 			stealthFlag = " [stealth]"
 		}
-		fmt.Fprintf(a.stdout, "%s [%s] %s%s\n", h.ID, h.Status, h.Title, stealthFlag)
+		if h.Stale {
+			stealthFlag += " [stale]"
+		}
+		priorityFlag := ""
+		if h.Priority != "" && h.Priority != models.DefaultHandoffPriority {
+			priorityFlag = " (" + h.Priority + ")"
+		}
+		milestoneFlag := ""
+		if done, total := h.MilestoneProgress(); total > 0 {
+			milestoneFlag = fmt.Sprintf(" [%d/%d milestones]", done, total)
+		}
+		blockerFlag := ""
+		if showBlockers && len(h.BlockedBy) > 0 {
+			blockerFlag = " [blocked by: " + formatBlockers(h.BlockedBy, titles) + "]"
+		}
+		fmt.Fprintf(a.stdout, "%s [%s] %s%s%s%s%s\n", h.ID, h.Status, h.Title, priorityFlag, milestoneFlag, stealthFlag, blockerFlag)
 		if h.Description != "" {
 			fmt.Fprintf(a.stdout, "  %s\n", h.Description)
 		}
@@ -529,19 +1928,56 @@ func (a *App) runHandoffList(args []string) int {
 	return 0
 }
 
+// formatBlockers lists blocker IDs, each followed by its title in
+// parentheses when titles is non-nil and knows the ID
+func formatBlockers(ids []string, titles map[string]string) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id
+		if title, ok := titles[id]; ok {
+			parts[i] += " (" + title + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// filterHandoffs returns the handoffs matching every non-empty filter
+func filterHandoffs(handoffList []*models.Handoff, status, phase, agent string) []*models.Handoff {
+	if status == "" && phase == "" && agent == "" {
+		return handoffList
+	}
+	var filtered []*models.Handoff
+	for _, h := range handoffList {
+		if (status == "" || h.Status == status) &&
+			(phase == "" || h.Phase == phase) &&
+			(agent == "" || h.Agent == agent) {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered
+}
+
 // runHandoffAdd adds a new handoff
 func (a *App) runHandoffAdd(args []string) int {
 	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall handoff add <title> [--desc D] [--stealth]")
+		fmt.Fprintln(a.stderr, "usage: recall handoff add <title> [--desc D] [--stealth] [--priority P]")
+		fmt.Fprintln(a.stderr, "       recall handoff add <title> --template <name>")
 		return 1
 	}
 
 	title := args[0]
 	description := ""
 	stealth := false
+	priority := models.DefaultHandoffPriority
+	template := ""
 
 	for i := 1; i < len(args); i++ {
 		switch args[i] {
+		case "--template":
+			if i+1 < len(args) {
+				template = args[i+1]
+				i++
+			}
 		case "--desc":
 			if i+1 < len(args) {
 				description = args[i+1]
@@ -549,29 +1985,136 @@ func (a *App) runHandoffAdd(args []string) int {
 			}
 		case "--stealth":
 			stealth = true
+		case "--priority":
+			if i+1 < len(args) {
+				priority = args[i+1]
+				i++
+			}
 		}
 	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	if template != "" {
+		if description != "" || stealth || priority != models.DefaultHandoffPriority {
+			fmt.Fprintln(a.stderr, "error: --template cannot be combined with --desc, --stealth, or --priority")
+			return 1
+		}
+		return a.runHandoffAddFromTemplate(template, title)
+	}
+
+	if !models.IsValidPriority(priority) {
+		fmt.Fprintf(a.stderr, "error: invalid priority %q (use critical, high, medium, or low)\n", priority)
+		return 1
+	}
+
+	store := a.handoffStore()
 	handoff, err := store.Add(title, description, stealth)
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error adding handoff: %v\n", err)
 		return 1
 	}
 
+	if priority != models.DefaultHandoffPriority {
+		if err := store.Update(handoff.ID, map[string]interface{}{"priority": priority}); err != nil {
+			fmt.Fprintf(a.stderr, "error setting priority: %v\n", err)
+			return 1
+		}
+	}
+
 	fmt.Fprintf(a.stdout, "Added handoff %s: %s\n", handoff.ID, title)
 	return 0
 }
 
+// runHandoffAddFromTemplate creates a handoff pre-populated with a template's defaults
+func (a *App) runHandoffAddFromTemplate(name, title string) int {
+	tmpl, err := handoffs.LoadTemplate(handoffs.TemplateDir(a.baseDir), name)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error loading template: %v\n", err)
+		return 1
+	}
+
+	store := a.handoffStore()
+	handoff, err := store.Add(title, tmpl.Description, false)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error adding handoff: %v\n", err)
+		return 1
+	}
+	if updates := tmpl.Updates(); updates != nil {
+		if err := store.Update(handoff.ID, updates); err != nil {
+			fmt.Fprintf(a.stderr, "error applying template: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "Added handoff %s: %s (from template %s)\n", handoff.ID, title, name)
+	return 0
+}
+
+// runHandoffTemplate dispatches handoff template list|save
+func (a *App) runHandoffTemplate(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff template list")
+		fmt.Fprintln(a.stderr, "       recall handoff template save <id> <name>")
+		return 1
+	}
+
+	dir := handoffs.TemplateDir(a.baseDir)
+	switch args[0] {
+	case "list":
+		names, err := handoffs.ListTemplates(dir)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error listing templates: %v\n", err)
+			return 1
+		}
+		if len(names) == 0 {
+			fmt.Fprintf(a.stdout, "(no templates in %s)\n", dir)
+			return 0
+		}
+		for _, name := range names {
+			tmpl, err := handoffs.LoadTemplate(dir, name)
+			if err != nil {
+				fmt.Fprintf(a.stdout, "%s (invalid: %v)\n", name, err)
+				continue
+			}
+			if tmpl.Description != "" {
+				fmt.Fprintf(a.stdout, "%s - %s\n", name, truncateContent(tmpl.Description, 60))
+			} else {
+				fmt.Fprintln(a.stdout, name)
+			}
+		}
+		return 0
+	case "save":
+		if len(args) < 3 {
+			fmt.Fprintln(a.stderr, "usage: recall handoff template save <id> <name>")
+			return 1
+		}
+		store := a.handoffStore()
+		h, err := store.Get(args[1])
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 1
+		}
+		if err := handoffs.SaveTemplate(dir, args[2], handoffs.TemplateFromHandoff(h)); err != nil {
+			fmt.Fprintf(a.stderr, "error saving template: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "Saved %s as template %s\n", h.ID, args[2])
+		return 0
+	default:
+		fmt.Fprintf(a.stderr, "unknown template action: %s (use list or save)\n", args[0])
+		return 1
+	}
+}
+
 // runHandoffUpdate updates a handoff
 func (a *App) runHandoffUpdate(args []string) int {
 	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall handoff update <id> [--status S] [--phase P] [--desc D] [--next N]")
+		fmt.Fprintln(a.stderr, "usage: recall handoff update <id> [--status S] [--phase P] [--force-phase] [--desc D] [--next N] [--blocked-by ID,ID] [--priority P]")
 		return 1
 	}
 
 	id := args[0]
 	updates := make(map[string]interface{})
+	forcePhase := false
 
 	for i := 1; i < len(args); i++ {
 		switch args[i] {
@@ -585,6 +2128,8 @@ func (a *App) runHandoffUpdate(args []string) int {
 				updates["phase"] = args[i+1]
 				i++
 			}
+		case "--force-phase":
+			forcePhase = true
 		case "--desc":
 			if i+1 < len(args) {
 				updates["description"] = args[i+1]
@@ -595,15 +2140,34 @@ func (a *App) runHandoffUpdate(args []string) int {
 				updates["next_steps"] = args[i+1]
 				i++
 			}
-		}
+		case "--priority":
+			if i+1 < len(args) {
+				updates["priority"] = args[i+1]
+				i++
+			}
+		case "--blocked-by":
+			if i+1 < len(args) {
+				blockedBy := []string{}
+				for _, id := range strings.Split(args[i+1], ",") {
+					if id = strings.TrimSpace(id); id != "" {
+						blockedBy = append(blockedBy, id)
+					}
+				}
+				updates["blocked_by"] = blockedBy
+				i++
+			}
+		}
 	}
 
 	if len(updates) == 0 {
 		fmt.Fprintln(a.stderr, "no updates specified")
 		return 1
 	}
+	if forcePhase {
+		updates["force_phase"] = true
+	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 	if err := store.Update(id, updates); err != nil {
 		fmt.Fprintf(a.stderr, "error updating handoff: %v\n", err)
 		return 1
@@ -613,25 +2177,566 @@ func (a *App) runHandoffUpdate(args []string) int {
 	return 0
 }
 
+// runHandoffBulkUpdate applies the same updates to every active handoff
+// matching the filters
+func (a *App) runHandoffBulkUpdate(args []string) int {
+	var filterStatus, filterPhase, filterAgent string
+	updates := make(map[string]interface{})
+	dryRun := false
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		if i+1 >= len(args) {
+			continue
+		}
+		switch args[i] {
+		case "--filter-status":
+			filterStatus = args[i+1]
+		case "--filter-phase":
+			filterPhase = args[i+1]
+		case "--filter-agent":
+			filterAgent = args[i+1]
+		case "--set-status", "--status":
+			updates["status"] = args[i+1]
+		case "--set-phase":
+			updates["phase"] = args[i+1]
+		case "--set-agent":
+			updates["agent"] = args[i+1]
+		case "--set-next":
+			updates["next_steps"] = args[i+1]
+		default:
+			continue
+		}
+		i++
+	}
+
+	if len(updates) == 0 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff bulk-update [--filter-status S] [--filter-phase P] [--filter-agent A]")
+		fmt.Fprintln(a.stderr, "       [--set-status S] [--set-phase P] [--set-agent A] [--set-next N] [--dry-run]")
+		return 1
+	}
+	for _, status := range []string{filterStatus, stringUpdate(updates, "status")} {
+		if status != "" && !models.IsValidHandoffStatus(status) {
+			fmt.Fprintf(a.stderr, "error: invalid status %q\n", status)
+			return 1
+		}
+	}
+	for _, phase := range []string{filterPhase, stringUpdate(updates, "phase")} {
+		if phase != "" && !models.IsValidHandoffPhase(phase) {
+			fmt.Fprintf(a.stderr, "error: invalid phase %q\n", phase)
+			return 1
+		}
+	}
+
+	store := a.handoffStore()
+	handoffList, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	matched := filterHandoffs(handoffList, filterStatus, filterPhase, filterAgent)
+
+	if dryRun {
+		for _, h := range matched {
+			fmt.Fprintf(a.stdout, "%s [%s] %s\n", h.ID, h.Status, h.Title)
+		}
+		fmt.Fprintf(a.stdout, "Would update %d handoffs\n", len(matched))
+		return 0
+	}
+
+	updated := 0
+	for _, h := range matched {
+		if err := store.Update(h.ID, updates); err != nil {
+			fmt.Fprintf(a.stderr, "error updating handoff %s: %v\n", h.ID, err)
+			continue
+		}
+		updated++
+	}
+
+	fmt.Fprintf(a.stdout, "Updated %d handoffs\n", updated)
+	if updated < len(matched) {
+		return 1
+	}
+	return 0
+}
+
+// stringUpdate returns the string value of an update key ("" if unset)
+func stringUpdate(updates map[string]interface{}, key string) string {
+	v, _ := updates[key].(string)
+	return v
+}
+
+// runHandoffDependency dispatches handoff dependency add|remove
+func (a *App) runHandoffDependency(args []string) int {
+	if len(args) != 3 || (args[0] != "add" && args[0] != "remove") {
+		fmt.Fprintln(a.stderr, "usage: recall handoff dependency add|remove <id> <dep-id>")
+		return 1
+	}
+	if args[0] == "add" {
+		return a.runHandoffDependencyAdd(args[1], args[2])
+	}
+	return a.runHandoffDependencyRemove(args[1], args[2])
+}
+
+// runHandoffDependencyAdd marks id as blocked by depID
+func (a *App) runHandoffDependencyAdd(id, depID string) int {
+	store := a.handoffStore()
+	if err := store.AddDependency(id, depID); err != nil {
+		fmt.Fprintf(a.stderr, "error adding dependency: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "%s is now blocked by %s\n", id, depID)
+	return 0
+}
+
+// runHandoffDependencyRemove drops depID from id's blockers
+func (a *App) runHandoffDependencyRemove(id, depID string) int {
+	store := a.handoffStore()
+	if err := store.RemoveDependency(id, depID); err != nil {
+		fmt.Fprintf(a.stderr, "error removing dependency: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "%s is no longer blocked by %s\n", id, depID)
+	return 0
+}
+
+// runHandoffCheckDeps reports circular blocked-by dependencies (exit 1 if any)
+func (a *App) runHandoffCheckDeps(args []string) int {
+	store := a.handoffStore()
+
+	cycles, err := handoffs.DetectCycles(store)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error checking dependencies: %v\n", err)
+		return 1
+	}
+
+	if len(cycles) == 0 {
+		fmt.Fprintln(a.stdout, "No circular dependencies found.")
+		return 0
+	}
+
+	for _, cycle := range cycles {
+		fmt.Fprintf(a.stdout, "circular dependency: %s\n", handoffs.FormatCycle(cycle))
+	}
+	return 1
+}
+
+// defaultCheckStaleDays is how long an active handoff may go without an
+// update before check-stale reports it as likely abandoned
+const defaultCheckStaleDays = 14
+
+// runHandoffCheckStale lists active handoffs not updated in --days days
+// (default defaultCheckStaleDays). --auto-flag also sets their Stale flag so
+// handoff list --stale can find them later.
+func (a *App) runHandoffCheckStale(args []string) int {
+	days := defaultCheckStaleDays
+	autoFlag := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--days" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(a.stderr, "error: invalid --days '%s'\n", args[i+1])
+				return 1
+			}
+			days = n
+			i++
+		case args[i] == "--auto-flag":
+			autoFlag = true
+		}
+	}
+
+	store := a.handoffStore()
+	now := a.clock()
+	stale, err := store.ListStale(now.AddDate(0, 0, -days))
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	if len(stale) == 0 {
+		fmt.Fprintf(a.stdout, "No stale handoffs (all updated within %d days).\n", days)
+		return 0
+	}
+
+	titleWidth := len("TITLE")
+	for _, h := range stale {
+		if len(h.Title) > titleWidth {
+			titleWidth = len(h.Title)
+		}
+	}
+	fmt.Fprintf(a.stdout, "%-12s %-*s %-16s %s\n", "ID", titleWidth, "TITLE", "STATUS", "DAYS")
+	ids := make([]string, len(stale))
+	for i, h := range stale {
+		fmt.Fprintf(a.stdout, "%-12s %-*s %-16s %d\n", h.ID, titleWidth, h.Title, h.Status, daysSince(h.Updated, now))
+		ids[i] = h.ID
+	}
+
+	if autoFlag {
+		flagged, err := store.FlagStale(ids)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error flagging stale handoffs: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "\nFlagged %d stale handoffs\n", flagged)
+	}
+	return 0
+}
+
+// runHandoffGraph prints the blocked-by dependency graph of all handoffs
+func (a *App) runHandoffGraph(args []string) int {
+	format := "dot"
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dot":
+			format = "dot"
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+	if format != "dot" && format != "json" && format != "ascii" {
+		fmt.Fprintf(a.stderr, "error: unknown format %q (use dot, json, or ascii)\n", format)
+		return 1
+	}
+
+	handoffList, err := a.handoffStore().ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	graph := handoffs.BuildGraph(handoffList)
+
+	switch format {
+	case "json":
+		return a.writeJSON(graph)
+	case "ascii":
+		fmt.Fprint(a.stdout, graph.FormatASCII())
+	default:
+		fmt.Fprint(a.stdout, graph.FormatDOT())
+	}
+	return 0
+}
+
+// runHandoffTimeline prints a handoff's recorded activity in date order
+func (a *App) runHandoffTimeline(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff timeline <id>")
+		return 1
+	}
+
+	store := a.handoffStore()
+	h, err := store.Get(args[0])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "%s: %s\n", h.ID, h.Title)
+	for _, e := range handoffs.Timeline(h) {
+		line := fmt.Sprintf("  %s  %-9s %s", e.Time.Format("2006-01-02"), e.Kind, e.Detail)
+		fmt.Fprintln(a.stdout, strings.TrimRight(line, " "))
+	}
+	return 0
+}
+
+// runHandoffShow prints a handoff's details, tried steps, and notes
+func (a *App) runHandoffShow(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff show <id>")
+		return 1
+	}
+
+	store := a.handoffStore()
+	h, err := store.Get(args[0])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "ID: %s\n", h.ID)
+	fmt.Fprintf(a.stdout, "Title: %s\n", h.Title)
+	fmt.Fprintf(a.stdout, "Status: %s\n", h.Status)
+	fmt.Fprintf(a.stdout, "Phase: %s\n", h.Phase)
+	fmt.Fprintf(a.stdout, "Priority: %s\n", h.Priority)
+	fmt.Fprintf(a.stdout, "Created: %s\n", h.Created.Format("2006-01-02"))
+	fmt.Fprintf(a.stdout, "Updated: %s\n", h.Updated.Format("2006-01-02"))
+	if h.Description != "" {
+		fmt.Fprintf(a.stdout, "Description: %s\n", h.Description)
+	}
+	if len(h.Refs) > 0 {
+		fmt.Fprintf(a.stdout, "Refs: %s\n", strings.Join(h.Refs, ", "))
+	}
+	if len(h.BlockedBy) > 0 {
+		fmt.Fprintf(a.stdout, "Blocked By: %s\n", strings.Join(h.BlockedBy, ", "))
+	}
+	if h.NextSteps != "" {
+		fmt.Fprintf(a.stdout, "Next: %s\n", h.NextSteps)
+	}
+
+	if len(h.Tried) > 0 {
+		fmt.Fprintln(a.stdout, "\nTried:")
+		for i, step := range h.Tried {
+			fmt.Fprintf(a.stdout, "  %d. [%s] %s\n", i+1, step.Outcome, step.Description)
+		}
+	}
+
+	if len(h.Milestones) > 0 {
+		done, total := h.MilestoneProgress()
+		fmt.Fprintf(a.stdout, "\nMilestones (%d/%d):\n", done, total)
+		for _, m := range h.Milestones {
+			fmt.Fprintf(a.stdout, "  %s\n", formatMilestone(m))
+		}
+	}
+
+	if len(h.TimeLog) > 0 {
+		fmt.Fprintf(a.stdout, "\nTime Logged: %s\n", formatMinutes(h.TotalMinutes()))
+	}
+
+	if len(h.Notes) > 0 {
+		notes := append([]models.HandoffNote(nil), h.Notes...)
+		sort.SliceStable(notes, func(i, j int) bool {
+			return notes[i].Timestamp.Before(notes[j].Timestamp)
+		})
+		fmt.Fprintln(a.stdout, "\nNotes:")
+		for _, n := range notes {
+			text := strings.ReplaceAll(n.Text, "\n", "\n                     ")
+			fmt.Fprintf(a.stdout, "  [%s] %s\n", n.Timestamp.Format("2006-01-02 15:04"), text)
+		}
+	}
+
+	return 0
+}
+
+// runHandoffNote appends a timestamped note to a handoff
+func (a *App) runHandoffNote(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff note <id> <text>")
+		return 1
+	}
+
+	id := args[0]
+	text := strings.Join(args[1:], " ")
+
+	store := a.handoffStore()
+	if err := store.AddNote(id, text); err != nil {
+		fmt.Fprintf(a.stderr, "error adding note: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Added note to handoff %s\n", id)
+	return 0
+}
+
+// runHandoffMilestone adds, completes, or lists a handoff's milestones
+func (a *App) runHandoffMilestone(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff milestone <id> add|complete <name>")
+		fmt.Fprintln(a.stderr, "       recall handoff milestone <id> list")
+		return 1
+	}
+
+	id := args[0]
+	action := args[1]
+	name := strings.Join(args[2:], " ")
+	store := a.handoffStore()
+
+	switch action {
+	case "add":
+		if name == "" {
+			fmt.Fprintln(a.stderr, "usage: recall handoff milestone <id> add <name>")
+			return 1
+		}
+		if err := store.AddMilestone(id, name); err != nil {
+			fmt.Fprintf(a.stderr, "error adding milestone: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "Added milestone %q to handoff %s\n", name, id)
+	case "complete":
+		if name == "" {
+			fmt.Fprintln(a.stderr, "usage: recall handoff milestone <id> complete <name>")
+			return 1
+		}
+		if err := store.CompleteMilestone(id, name); err != nil {
+			fmt.Fprintf(a.stderr, "error completing milestone: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "Completed milestone %q on handoff %s\n", name, id)
+	case "list":
+		h, err := store.Get(id)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 1
+		}
+		if len(h.Milestones) == 0 {
+			fmt.Fprintf(a.stdout, "No milestones for %s.\n", id)
+			return 0
+		}
+		done, total := h.MilestoneProgress()
+		fmt.Fprintf(a.stdout, "%s: %d/%d milestones\n", id, done, total)
+		for _, m := range h.Milestones {
+			fmt.Fprintf(a.stdout, "  %s\n", formatMilestone(m))
+		}
+	default:
+		fmt.Fprintf(a.stderr, "unknown milestone action: %s (use add, complete, or list)\n", action)
+		return 1
+	}
+	return 0
+}
+
+// runHandoffCheckpointHistory prints the next steps recorded each time a
+// handoff changed phase, oldest first
+func (a *App) runHandoffCheckpointHistory(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff checkpoint-history <id>")
+		return 1
+	}
+
+	h, err := a.handoffStore().Get(args[0])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+	if len(h.CheckpointHistory) == 0 {
+		fmt.Fprintf(a.stdout, "No checkpoint history for %s.\n", h.ID)
+		return 0
+	}
+
+	fmt.Fprintf(a.stdout, "[%s] %s\n", h.ID, h.Title)
+	for _, e := range h.CheckpointHistory {
+		notes := e.Notes
+		if notes == "" {
+			notes = "(no next steps)"
+		}
+		fmt.Fprintf(a.stdout, "  %s  -> %-12s %s\n", e.Timestamp.Format("2006-01-02 15:04"), e.Phase, notes)
+	}
+	return 0
+}
+
+// formatMilestone renders a milestone as a checkbox line
+func formatMilestone(m models.Milestone) string {
+	if !m.Completed {
+		return "[ ] " + m.Name
+	}
+	if m.CompletedAt != nil {
+		return fmt.Sprintf("[x] %s (%s)", m.Name, m.CompletedAt.Format("2006-01-02"))
+	}
+	return "[x] " + m.Name
+}
+
 // runHandoffTried adds a tried step to a handoff
 func (a *App) runHandoffTried(args []string) int {
+	for _, arg := range args {
+		if arg == "--from-transcript" {
+			return a.runHandoffTriedFromTranscript(args)
+		}
+	}
 	if len(args) < 3 {
 		fmt.Fprintln(a.stderr, "usage: recall handoff tried <id> <outcome> <description>")
+		fmt.Fprintln(a.stderr, "       recall handoff tried --from-transcript <path> [--dry-run]")
 		fmt.Fprintln(a.stderr, "  outcome: success, fail, partial")
 		return 1
 	}
 
-	id := args[0]
-	outcome := args[1]
-	description := args[2]
+	id := args[0]
+	outcome := args[1]
+	description := args[2]
+
+	store := a.handoffStore()
+	if err := store.AddTriedStep(id, outcome, description); err != nil {
+		fmt.Fprintf(a.stderr, "error adding tried step: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Added tried step [%s] to handoff %s\n", outcome, id)
+	return 0
+}
+
+// transcriptTriedStep is a tried step found in a transcript
+type transcriptTriedStep struct {
+	id          string
+	outcome     string
+	description string
+}
+
+// runHandoffTriedFromTranscript adds every "HANDOFF UPDATE <id>: tried
+// <outcome> - <desc>" line in a JSONL transcript's assistant messages as a
+// tried step
+func (a *App) runHandoffTriedFromTranscript(args []string) int {
+	var path string
+	dryRun := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--from-transcript" && i+1 < len(args):
+			path = args[i+1]
+			i++
+		case args[i] == "--dry-run":
+			dryRun = true
+		}
+	}
+	if path == "" {
+		fmt.Fprintln(a.stderr, "usage: recall handoff tried --from-transcript <path> [--dry-run]")
+		return 1
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading transcript: %v\n", err)
+		return 1
+	}
+	messages, err := transcript.Parse(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error parsing transcript: %v\n", err)
+		return 1
+	}
+
+	var steps []transcriptTriedStep
+	for _, msg := range messages {
+		for _, match := range handoffUpdatePattern.FindAllStringSubmatch(msg.Content, -1) {
+			outcome := strings.TrimSpace(match[3])
+			if strings.TrimSpace(match[2]) == "" || outcome == "" {
+				continue
+			}
+			steps = append(steps, transcriptTriedStep{id: match[1], outcome: outcome, description: strings.TrimSpace(match[4])})
+		}
+	}
+	if len(steps) == 0 {
+		fmt.Fprintf(a.stdout, "No tried steps found in %s\n", path)
+		return 0
+	}
+
+	if dryRun {
+		for _, s := range steps {
+			fmt.Fprintf(a.stdout, "Would add tried step [%s] to handoff %s: %s\n", s.outcome, s.id, s.description)
+		}
+		fmt.Fprintf(a.stdout, "Dry run: %d tried steps found, nothing written\n", len(steps))
+		return 0
+	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
-	if err := store.AddTriedStep(id, outcome, description); err != nil {
-		fmt.Fprintf(a.stderr, "error adding tried step: %v\n", err)
-		return 1
+	store := a.handoffStore()
+	added, failed := 0, 0
+	touched := make(map[string]bool)
+	for _, s := range steps {
+		if err := store.AddTriedStep(s.id, s.outcome, s.description); err != nil {
+			fmt.Fprintf(a.stderr, "warning: failed to add tried step to %s: %v\n", s.id, err)
+			failed++
+			continue
+		}
+		added++
+		touched[s.id] = true
 	}
 
-	fmt.Fprintf(a.stdout, "Added tried step [%s] to handoff %s\n", outcome, id)
+	fmt.Fprintf(a.stdout, "Added %d tried steps to %d handoffs", added, len(touched))
+	if failed > 0 {
+		fmt.Fprintf(a.stdout, " (%d failed)\n", failed)
+		return 1
+	}
+	fmt.Fprintln(a.stdout)
 	return 0
 }
 
@@ -643,7 +2748,7 @@ func (a *App) runHandoffComplete(args []string) int {
 	}
 
 	id := args[0]
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 
 	if err := store.Complete(id); err != nil {
 		fmt.Fprintf(a.stderr, "error completing handoff: %v\n", err)
@@ -654,11 +2759,67 @@ func (a *App) runHandoffComplete(args []string) int {
 	return 0
 }
 
+// runHandoffClone duplicates a handoff as a fresh not_started handoff
+func (a *App) runHandoffClone(args []string) int {
+	var id, title string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--title" && i+1 < len(args) {
+			title = args[i+1]
+			i++
+		} else if id == "" {
+			id = args[i]
+		}
+	}
+
+	if id == "" {
+		fmt.Fprintln(a.stderr, "usage: recall handoff clone <id> [--title \"New Title\"]")
+		return 1
+	}
+
+	store := a.handoffStore()
+	clone, err := store.Clone(id, title)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error cloning handoff: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Cloned %s as %s: %s\n", id, clone.ID, clone.Title)
+	return 0
+}
+
 // runHandoffArchive archives old completed handoffs
 func (a *App) runHandoffArchive(args []string) int {
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	opts := handoffs.DefaultArchiveOptions()
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--older-than":
+			if i+1 < len(args) {
+				d, err := handoffs.ParseDuration(args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					return 1
+				}
+				opts.MaxAge = d
+				i++
+			}
+		case "--keep-min":
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 0 {
+					fmt.Fprintf(a.stderr, "error: invalid --keep-min '%s'\n", args[i+1])
+					return 1
+				}
+				opts.KeepMin = n
+				i++
+			}
+		case "--stealth":
+			opts.StealthOnly = true
+		}
+	}
+
+	store := a.handoffStore()
 
-	count, err := store.Archive()
+	count, err := store.ArchiveWith(opts)
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error archiving: %v\n", err)
 		return 1
@@ -675,60 +2836,276 @@ func (a *App) runHandoffArchive(args []string) int {
 
 // runHandoffInject outputs handoffs for context injection
 func (a *App) runHandoffInject(args []string) int {
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	maxTokens := a.maxTokens
+	maxHandoffs := 0
+	compact := false
+	recencyBias := a.handoffsRecencyBias
+	stealthOnly, publicOnly := false, false
+	statuses := make(map[string]bool)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--recency-bias" && i+1 < len(args):
+			parsed, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				fmt.Fprintf(a.stderr, "error: invalid --recency-bias '%s' (use 0.0-1.0)\n", args[i+1])
+				return 1
+			}
+			recencyBias = parsed
+			i++
+		case args[i] == "--max-tokens" && i+1 < len(args):
+			parsed, err := strconv.Atoi(args[i+1])
+			if err != nil || parsed < 0 {
+				fmt.Fprintf(a.stderr, "error: invalid --max-tokens '%s'\n", args[i+1])
+				return 1
+			}
+			maxTokens = parsed
+			i++
+		case args[i] == "--max-handoffs" && i+1 < len(args):
+			parsed, err := strconv.Atoi(args[i+1])
+			if err != nil || parsed < 0 {
+				fmt.Fprintf(a.stderr, "error: invalid --max-handoffs '%s'\n", args[i+1])
+				return 1
+			}
+			maxHandoffs = parsed
+			i++
+		case args[i] == "--only-in-progress":
+			statuses["in_progress"] = true
+		case args[i] == "--only-blocked":
+			statuses["blocked"] = true
+		case args[i] == "--compact":
+			compact = true
+		case args[i] == "--stealth-only":
+			stealthOnly = true
+		case args[i] == "--public-only":
+			publicOnly = true
+		}
+	}
+	if stealthOnly && publicOnly {
+		fmt.Fprintln(a.stderr, "error: --stealth-only and --public-only are mutually exclusive")
+		return 1
+	}
+
+	store := a.handoffStore()
 
 	handoffList, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
 		return 1
 	}
+	handoffList = filterHandoffsByStealth(handoffList, stealthOnly, publicOnly)
+
+	// --only-* flags combine: --only-in-progress --only-blocked keeps both
+	if len(statuses) > 0 {
+		var matching []*models.Handoff
+		for _, h := range handoffList {
+			if statuses[h.Status] {
+				matching = append(matching, h)
+			}
+		}
+		handoffList = matching
+	}
+	handoffList = orderHandoffsByRecency(handoffList, recencyBias, a.clock())
+	if maxHandoffs > 0 && len(handoffList) > maxHandoffs {
+		handoffList = handoffList[:maxHandoffs]
+	}
 
 	if len(handoffList) == 0 {
 		fmt.Fprintln(a.stdout, "(no active handoffs)")
 		return 0
 	}
 
-	fmt.Fprintln(a.stdout, "## Active Handoffs")
-	fmt.Fprintln(a.stdout)
+	blocks := make([]string, len(handoffList))
+	for i, h := range handoffList {
+		if compact {
+			blocks[i] = formatCompactHandoff(h)
+		} else {
+			blocks[i] = formatInjectedHandoff(h)
+		}
+	}
+	if maxTokens > 0 {
+		kept := fitTokenBudget(injectHandoffsHeader, blocks, maxTokens)
+		if dropped := len(blocks) - kept; dropped > 0 {
+			dlog := debuglog.New(a.stateDir, a.debugLevel)
+			dlog.LogBudgetDrop("handoff_inject", a.projectDir, "handoffs", dropped, maxTokens)
+			blocks = blocks[:kept]
+		}
+	}
 
-	for _, h := range handoffList {
-		fmt.Fprintf(a.stdout, "### [%s] %s\n", h.ID, h.Title)
-		fmt.Fprintf(a.stdout, "- **Status**: %s | **Phase**: %s\n", h.Status, h.Phase)
+	fmt.Fprint(a.stdout, injectHandoffsHeader)
+	for _, b := range blocks {
+		fmt.Fprint(a.stdout, b)
+	}
 
-		if h.Description != "" {
-			fmt.Fprintf(a.stdout, "- **Description**: %s\n", h.Description)
-		}
+	return 0
+}
 
-		if h.Checkpoint != "" {
-			fmt.Fprintf(a.stdout, "- **Checkpoint**: %s\n", h.Checkpoint)
+// injectHandoffsHeader heads the handoff inject output
+const injectHandoffsHeader = "## Active Handoffs\n\n"
+
+// orderHandoffsByRecency reorders handoffs for injection by blending
+// priority with how recently each was updated: bias 0 keeps the list's
+// order, bias 1 orders purely by recency (1/(1+days) since updated)
+func orderHandoffsByRecency(handoffList []*models.Handoff, bias float64, now time.Time) []*models.Handoff {
+	if bias <= 0 || len(handoffList) < 2 {
+		return handoffList
+	}
+	lowest := float64(models.PriorityRank("low"))
+	score := func(h *models.Handoff) float64 {
+		// critical = 1.0 down to low = 0.0
+		priority := 1 - float64(models.PriorityRank(h.Priority))/lowest
+		days := now.Sub(h.Updated).Hours() / 24
+		if days < 0 {
+			days = 0
 		}
+		return (1-bias)*priority + bias/(1+days)
+	}
 
-		if len(h.Tried) > 0 {
-			fmt.Fprintln(a.stdout, "\n**Tried**:")
-			for i, t := range h.Tried {
-				fmt.Fprintf(a.stdout, "%d. [%s] %s\n", i+1, t.Outcome, t.Description)
-			}
+	ordered := append([]*models.Handoff(nil), handoffList...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return score(ordered[i]) > score(ordered[j])
+	})
+	return ordered
+}
+
+// formatInjectedHandoff renders one handoff in inject format
+func formatInjectedHandoff(h *models.Handoff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### [%s] %s\n", h.ID, h.Title)
+	fmt.Fprintf(&sb, "- **Status**: %s | **Phase**: %s\n", h.Status, h.Phase)
+
+	if h.Description != "" {
+		fmt.Fprintf(&sb, "- **Description**: %s\n", h.Description)
+	}
+
+	if done, total := h.MilestoneProgress(); total > 0 {
+		fmt.Fprintf(&sb, "- **Milestones**: [%d/%d milestones]\n", done, total)
+	}
+
+	if h.Checkpoint != "" {
+		fmt.Fprintf(&sb, "- **Checkpoint**: %s\n", h.Checkpoint)
+	}
+
+	if len(h.Tried) > 0 {
+		fmt.Fprintln(&sb, "\n**Tried**:")
+		for i, t := range h.Tried {
+			fmt.Fprintf(&sb, "%d. [%s] %s\n", i+1, t.Outcome, t.Description)
 		}
+	}
+
+	if h.NextSteps != "" {
+		fmt.Fprintf(&sb, "\n**Next**: %s\n", h.NextSteps)
+	}
+
+	fmt.Fprintln(&sb)
+	return sb.String()
+}
+
+// formatCompactHandoff renders one handoff as a single inject line:
+// "[hf-xxx] Title (status/phase): next_steps"
+func formatCompactHandoff(h *models.Handoff) string {
+	line := fmt.Sprintf("[%s] %s (%s/%s)", h.ID, h.Title, h.Status, h.Phase)
+	if h.NextSteps != "" {
+		line += ": " + strings.Join(strings.Fields(h.NextSteps), " ")
+	}
+	return line + "\n"
+}
 
-		if h.NextSteps != "" {
-			fmt.Fprintf(a.stdout, "\n**Next**: %s\n", h.NextSteps)
+// structuredHandoff is one handoff in handoff inject-structured output
+type structuredHandoff struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Phase       string `json:"phase"`
+	Description string `json:"description"`
+	NextSteps   string `json:"next_steps"`
+	TriedCount  int    `json:"tried_count"`
+	LastOutcome string `json:"last_outcome"` // Empty if nothing was tried
+	Priority    string `json:"priority"`
+}
+
+// runHandoffInjectStructured outputs active handoffs as a JSON array, for
+// tools that consume recall through a tool-use API rather than Markdown
+func (a *App) runHandoffInjectStructured(args []string) int {
+	stealthOnly, publicOnly := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--stealth-only":
+			stealthOnly = true
+		case "--public-only":
+			publicOnly = true
 		}
+	}
+	if stealthOnly && publicOnly {
+		fmt.Fprintln(a.stderr, "error: --stealth-only and --public-only are mutually exclusive")
+		return 1
+	}
 
-		fmt.Fprintln(a.stdout)
+	handoffList, err := a.handoffStore().List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	handoffList = filterHandoffsByStealth(handoffList, stealthOnly, publicOnly)
+
+	out := make([]structuredHandoff, len(handoffList))
+	for i, h := range handoffList {
+		out[i] = structuredHandoff{
+			ID:          h.ID,
+			Title:       h.Title,
+			Status:      h.Status,
+			Phase:       h.Phase,
+			Description: h.Description,
+			NextSteps:   h.NextSteps,
+			TriedCount:  len(h.Tried),
+			Priority:    h.Priority,
+		}
+		if len(h.Tried) > 0 {
+			out[i].LastOutcome = h.Tried[len(h.Tried)-1].Outcome
+		}
 	}
+	return a.writeJSON(out)
+}
 
-	return 0
+// filterHandoffsByStealth keeps only stealth or only public handoffs, e.g.
+// to hide stealth work while screen-sharing. With neither flag set the list
+// is returned as is.
+func filterHandoffsByStealth(list []*models.Handoff, stealthOnly, publicOnly bool) []*models.Handoff {
+	if !stealthOnly && !publicOnly {
+		return list
+	}
+	var kept []*models.Handoff
+	for _, h := range list {
+		if h.Stealth == stealthOnly {
+			kept = append(kept, h)
+		}
+	}
+	return kept
 }
 
 // runHandoffInjectTodos formats active handoff as TodoWrite continuation prompt
 func (a *App) runHandoffInjectTodos(args []string) int {
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	stealthOnly, publicOnly := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--stealth-only":
+			stealthOnly = true
+		case "--public-only":
+			publicOnly = true
+		}
+	}
+	if stealthOnly && publicOnly {
+		fmt.Fprintln(a.stderr, "error: --stealth-only and --public-only are mutually exclusive")
+		return 1
+	}
+
+	store := a.handoffStore()
 
 	handoffList, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
 		return 1
 	}
+	handoffList = filterHandoffsByStealth(handoffList, stealthOnly, publicOnly)
 
 	// Find the most recent in_progress handoff
 	var activeHandoff *models.Handoff
@@ -800,7 +3177,7 @@ func (a *App) runHandoffSyncTodos(args []string) int {
 		return 1
 	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 
 	// Determine which handoff to sync to
 	var handoffID string
@@ -890,7 +3267,7 @@ func (a *App) runHandoffSetContext(args []string) int {
 		return 1
 	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 
 	// Get handoff and update context
 	h, err := store.Get(id)
@@ -938,7 +3315,7 @@ func (a *App) runHandoffSetSession(args []string) int {
 	}
 
 	// Update handoff's sessions list
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 	h, err := store.Get(handoffID)
 	if err == nil {
 		// Add session to list if not already present
@@ -1000,32 +3377,31 @@ func (a *App) runHandoffProcessTranscript(args []string) int {
 		AssistantTexts []string `json:"assistant_texts"`
 	}
 
-	decoder := json.NewDecoder(os.Stdin)
+	decoder := json.NewDecoder(a.stdin)
 	if err := decoder.Decode(&transcriptData); err != nil {
 		fmt.Fprintf(a.stderr, "error parsing transcript JSON: %v\n", err)
 		return 1
 	}
 
-	store := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	store := a.handoffStore()
 
 	// Parse handoff operations from assistant texts
 	var results []string
 
 	for _, text := range transcriptData.AssistantTexts {
-		// HANDOFF: title
-		if matches := handoffStartPattern.FindAllStringSubmatch(text, -1); len(matches) > 0 {
-			for _, match := range matches {
-				if len(match) > 1 {
-					title := strings.TrimSpace(match[1])
-					h, err := store.Add(title, "", false)
-					if err == nil {
-						results = append(results, fmt.Sprintf("added %s", h.ID))
-						// Link to session if provided
-						if sessionID != "" {
-							a.setSessionHandoff(sessionID, h.ID, "")
-						}
-					}
-				}
+		// HANDOFF: title (optionally followed by indented desc/phase/refs lines)
+		for _, block := range handoffs.ParseStartBlocks(text) {
+			h, err := store.Add(block.Title, block.Description, false)
+			if err != nil {
+				continue
+			}
+			if updates := block.Updates(); updates != nil {
+				store.Update(h.ID, updates)
+			}
+			results = append(results, fmt.Sprintf("added %s", h.ID))
+			// Link to session if provided
+			if sessionID != "" {
+				a.setSessionHandoff(sessionID, h.ID, "")
 			}
 		}
 
@@ -1163,7 +3539,6 @@ func (a *App) getSessionHandoff(sessionID string) (string, error) {
 
 // Handoff patterns for transcript parsing
 var (
-	handoffStartPattern    = regexp.MustCompile(`(?m)^HANDOFF:\s*(.+)$`)
 	handoffUpdatePattern   = regexp.MustCompile(`(?m)^HANDOFF\s+UPDATE\s+([A-Za-z0-9-]+):\s*(tried\s+)?(success|fail|partial)?\s*[-–]?\s*(.*)$`)
 	handoffCompletePattern = regexp.MustCompile(`(?m)^HANDOFF\s+COMPLETE\s+([A-Za-z0-9-]+)`)
 )
@@ -1177,6 +3552,7 @@ func (a *App) runDebug(args []string) int {
 		fmt.Fprintln(a.stderr, "  hook-phase <h> <p> <ms>    - Log hook phase timing")
 		fmt.Fprintln(a.stderr, "  hook-end <h> <ms> [--phases json] - Log hook end")
 		fmt.Fprintln(a.stderr, "  injection-budget <t> <l> <h> <d>  - Log token budget")
+		fmt.Fprintln(a.stderr, "  citation-stats [--session <id>]   - Rank lessons by citations")
 		return 1
 	}
 
@@ -1194,6 +3570,8 @@ func (a *App) runDebug(args []string) int {
 		return a.runDebugHookEnd(subArgs)
 	case "injection-budget":
 		return a.runDebugInjectionBudget(subArgs)
+	case "citation-stats":
+		return a.runDebugCitationStats(subArgs)
 	default:
 		fmt.Fprintf(a.stderr, "unknown debug subcommand: %s\n", subcmd)
 		return 1
@@ -1398,10 +3776,39 @@ func (a *App) runDebugInjectionBudget(args []string) int {
 	return 0
 }
 
+// runDebugCitationStats ranks lessons by citations recorded in recall.log
+func (a *App) runDebugCitationStats(args []string) int {
+	var sessionID string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--session" && i+1 < len(args) {
+			sessionID = args[i+1]
+			i++
+		}
+	}
+
+	batches, err := debuglog.ReadCitationBatches(a.stateDir)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading log: %v\n", err)
+		return 1
+	}
+
+	counts := debuglog.AggregateCitations(batches, sessionID)
+	if len(counts) == 0 {
+		fmt.Fprintln(a.stdout, "No citations recorded.")
+		return 0
+	}
+
+	fmt.Fprintf(a.stdout, "%-4s  %-6s  %9s  %8s\n", "RANK", "ID", "CITATIONS", "SESSIONS")
+	for i, c := range counts {
+		fmt.Fprintf(a.stdout, "%-4d  %-6s  %9d  %8d\n", i+1, c.ID, c.Citations, c.Sessions)
+	}
+	return 0
+}
+
 // runScoreRelevance scores lessons by relevance to a query
 func (a *App) runScoreRelevance(args []string) int {
 	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall score-relevance <query> [--top N] [--min-score N] [--timeout N]")
+		fmt.Fprintln(a.stderr, "usage: recall score-relevance <query> [--top N] [--min-score N] [--timeout N] [--cache-ttl N] [--cache-only] [--json]")
 		return 1
 	}
 
@@ -1409,9 +3816,16 @@ func (a *App) runScoreRelevance(args []string) int {
 	topN := 10
 	minScore := 0
 	timeout := 30 * time.Second
+	cacheTTL := a.scoreCacheTTL
+	jsonOutput := false
+	cacheOnly := false
 
 	for i := 1; i < len(args); i++ {
 		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		case "--cache-only":
+			cacheOnly = true
 		case "--top":
 			if i+1 < len(args) {
 				if n, err := strconv.Atoi(args[i+1]); err == nil {
@@ -1433,10 +3847,17 @@ func (a *App) runScoreRelevance(args []string) int {
 				}
 				i++
 			}
+		case "--cache-ttl":
+			if i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil {
+					cacheTTL = time.Duration(n) * time.Second
+				}
+				i++
+			}
 		}
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
 	allLessons, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
@@ -1444,11 +3865,24 @@ func (a *App) runScoreRelevance(args []string) int {
 	}
 
 	if len(allLessons) == 0 {
+		if jsonOutput {
+			return a.writeScoreJSON(query, "anthropic", false, nil)
+		}
 		fmt.Fprintln(a.stdout, "No lessons found.")
 		return 0
 	}
 
-	result, err := anthropic.ScoreRelevance(allLessons, query, a.stateDir, timeout)
+	var result *anthropic.RelevanceResult
+	if cacheOnly {
+		result, err = anthropic.CachedRelevance(allLessons, query, a.stateDir, cacheTTL)
+		if errors.Is(err, anthropic.ErrNoCachedResults) {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 2
+		}
+	} else {
+		result, err = anthropic.ScoreRelevance(allLessons, query, a.stateDir, timeout,
+			anthropic.DefaultBatchSize, anthropic.DefaultMaxParallel, cacheTTL)
+	}
 	if err != nil {
 		dlog := debuglog.New(a.stateDir, a.debugLevel)
 		dlog.LogScoreRelevanceError(query, err.Error())
@@ -1464,6 +3898,7 @@ func (a *App) runScoreRelevance(args []string) int {
 
 	// Filter and limit results
 	count := 0
+	var jsonResults []scoreJSONResult
 	for _, sl := range result.ScoredLessons {
 		if sl.Score < minScore {
 			continue
@@ -1471,6 +3906,11 @@ func (a *App) runScoreRelevance(args []string) int {
 		if count >= topN {
 			break
 		}
+		if jsonOutput {
+			jsonResults = append(jsonResults, newScoreJSONResult(sl.Lesson, sl.Score))
+			count++
+			continue
+		}
 
 		// Format stars based on score
 		stars := strings.Repeat("⭐", (sl.Score+1)/2)
@@ -1500,6 +3940,10 @@ func (a *App) runScoreRelevance(args []string) int {
 	}
 	dlog.LogInjection("prompt_submit", a.projectDir, injectedEntries)
 
+	if jsonOutput {
+		return a.writeScoreJSON(query, "anthropic", result.CacheHit, jsonResults)
+	}
+
 	if count == 0 {
 		fmt.Fprintln(a.stdout, "No relevant lessons found.")
 	}
@@ -1513,19 +3957,25 @@ func (a *App) runScoreRelevance(args []string) int {
 	return 0
 }
 
-// runScoreLocal scores lessons locally using BM25 (no API key required)
+// runScoreLocal scores lessons locally using BM25 or TF-IDF (no API key required)
 func (a *App) runScoreLocal(args []string) int {
 	if len(args) < 1 {
-		fmt.Fprintln(a.stderr, "usage: recall score-local <query> [--top N] [--min-score N]")
+		fmt.Fprintln(a.stderr, "usage: recall score-local <query> [--top N] [--min-score N] [--algo bm25|tfidf] [--k1 F] [--b F] [--cache] [--json]")
 		return 1
 	}
 
 	query := args[0]
 	topN := 5
 	minScore := 1
+	algo := "bm25"
+	useCache := false
+	jsonOutput := false
+	var opts []scoring.BM25Option
 
 	for i := 1; i < len(args); i++ {
 		switch args[i] {
+		case "--json":
+			jsonOutput = true
 		case "--top":
 			if i+1 < len(args) {
 				if n, err := strconv.Atoi(args[i+1]); err == nil {
@@ -1540,26 +3990,83 @@ func (a *App) runScoreLocal(args []string) int {
 				}
 				i++
 			}
+		case "--algo":
+			if i+1 < len(args) {
+				algo = args[i+1]
+				i++
+			}
+		case "--k1":
+			if i+1 < len(args) {
+				if v, err := strconv.ParseFloat(args[i+1], 64); err == nil {
+					opts = append(opts, scoring.WithK1(v))
+				}
+				i++
+			}
+		case "--b":
+			if i+1 < len(args) {
+				if v, err := strconv.ParseFloat(args[i+1], 64); err == nil {
+					opts = append(opts, scoring.WithB(v))
+				}
+				i++
+			}
+		case "--cache":
+			useCache = true
 		}
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
-	allLessons, err := store.List()
-	if err != nil {
-		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+	if algo != "bm25" && algo != "tfidf" {
+		fmt.Fprintf(a.stderr, "unknown algorithm: %s (use bm25 or tfidf)\n", algo)
 		return 1
 	}
-
-	if len(allLessons) == 0 {
-		fmt.Fprintln(a.stdout, "No lessons found.")
-		return 0
+	if useCache && algo != "bm25" {
+		fmt.Fprintln(a.stderr, "--cache is only supported with --algo bm25")
+		return 1
 	}
 
-	scorer := scoring.NewBM25Scorer(allLessons)
+	var scorer scoring.Scorer
+	label := "BM25"
+	if useCache {
+		bm25, err := a.cachedBM25Scorer(opts)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error loading BM25 index: %v\n", err)
+			return 1
+		}
+		if bm25.Len() == 0 {
+			if jsonOutput {
+				return a.writeScoreJSON(query, algo, false, nil)
+			}
+			fmt.Fprintln(a.stdout, "No lessons found.")
+			return 0
+		}
+		scorer = bm25
+	} else {
+		store := a.lessonStore()
+		allLessons, err := store.List()
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+			return 1
+		}
+
+		if len(allLessons) == 0 {
+			if jsonOutput {
+				return a.writeScoreJSON(query, algo, false, nil)
+			}
+			fmt.Fprintln(a.stdout, "No lessons found.")
+			return 0
+		}
+
+		if algo == "tfidf" {
+			scorer = scoring.NewTFIDFScorer(allLessons)
+			label = "TF-IDF"
+		} else {
+			scorer = scoring.NewBM25Scorer(allLessons, opts...)
+		}
+	}
 	results := scorer.Score(query)
 
 	// Filter and limit results
 	count := 0
+	var jsonResults []scoreJSONResult
 	for _, sl := range results {
 		if sl.Score < minScore {
 			continue
@@ -1567,6 +4074,11 @@ func (a *App) runScoreLocal(args []string) int {
 		if count >= topN {
 			break
 		}
+		if jsonOutput {
+			jsonResults = append(jsonResults, newScoreJSONResult(sl.Lesson, sl.Score))
+			count++
+			continue
+		}
 
 		// Format stars based on score (same as score-relevance)
 		stars := strings.Repeat("\u2b50", (sl.Score+1)/2)
@@ -1579,15 +4091,77 @@ func (a *App) runScoreLocal(args []string) int {
 		count++
 	}
 
+	if jsonOutput {
+		return a.writeScoreJSON(query, algo, false, jsonResults)
+	}
+
 	if count == 0 {
 		fmt.Fprintln(a.stdout, "No relevant lessons found.")
 	}
 
-	fmt.Fprintf(a.stderr, "\nShowing %d results (local BM25)\n", count)
+	fmt.Fprintf(a.stderr, "\nShowing %d results (local %s)\n", count, label)
 
 	return 0
 }
 
+// scoreJSONResult is one scored lesson in score-local and score-relevance
+// --json output. Both commands share the schema so tools can swap scorers.
+type scoreJSONResult struct {
+	ID       string  `json:"id"`
+	Score    int     `json:"score"`
+	Title    string  `json:"title"`
+	Content  string  `json:"content"`
+	Uses     int     `json:"uses"`
+	Velocity float64 `json:"velocity"`
+}
+
+// newScoreJSONResult builds a scoreJSONResult for a lesson and its score
+func newScoreJSONResult(l *models.Lesson, score int) scoreJSONResult {
+	return scoreJSONResult{ID: l.ID, Score: score, Title: l.Title, Content: l.Content, Uses: l.Uses, Velocity: l.Velocity}
+}
+
+// writeScoreJSON writes scored results (highest score first) as JSON
+func (a *App) writeScoreJSON(query, algo string, cacheHit bool, results []scoreJSONResult) int {
+	if results == nil {
+		results = []scoreJSONResult{}
+	}
+	return a.writeJSON(struct {
+		Query    string            `json:"query"`
+		Results  []scoreJSONResult `json:"results"`
+		CacheHit bool              `json:"cache_hit"`
+		Algo     string            `json:"algo"`
+	}{query, results, cacheHit, algo})
+}
+
+// cachedBM25Scorer loads the BM25 index from the state directory, rebuilding
+// and saving it when any lessons file the store reads has changed since it
+// was written
+func (a *App) cachedBM25Scorer(opts []scoring.BM25Option) (*scoring.BM25Scorer, error) {
+	checksum, err := scoring.ChecksumFiles(a.lessonPaths().Files()...)
+	if err != nil {
+		return nil, err
+	}
+
+	indexPath := filepath.Join(a.stateDir, "bm25-index.json")
+	index := &scoring.BM25Index{Checksum: checksum}
+	if scorer, err := index.Load(indexPath, opts...); err == nil {
+		return scorer, nil
+	}
+
+	store := a.lessonStore()
+	allLessons, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	index.Scorer = scoring.NewBM25Scorer(allLessons, opts...)
+	if err := index.Save(indexPath); err != nil {
+		// The index is only an optimization; score without it
+		fmt.Fprintf(a.stderr, "warning: could not save BM25 index: %v\n", err)
+	}
+	return index.Scorer, nil
+}
+
 // runExtractContext extracts handoff context from a transcript
 func (a *App) runExtractContext(args []string) int {
 	if len(args) < 1 {
@@ -1674,7 +4248,7 @@ func (a *App) runPrescoreCache(args []string) int {
 		return 1
 	}
 
-	store := lessons.NewStore(a.projectPath, a.systemPath)
+	store := a.lessonStore()
 	allLessons, err := store.List()
 	if err != nil {
 		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
@@ -1692,7 +4266,8 @@ func (a *App) runPrescoreCache(args []string) int {
 			continue
 		}
 
-		_, err := anthropic.ScoreRelevance(allLessons, query, a.stateDir, 30*time.Second)
+		_, err := anthropic.ScoreRelevance(allLessons, query, a.stateDir, 30*time.Second,
+			anthropic.DefaultBatchSize, anthropic.DefaultMaxParallel, a.scoreCacheTTL)
 		if err == nil {
 			prescored++
 			fmt.Fprintf(a.stdout, "Pre-scored: %s\n", truncateContent(query, 50))
@@ -1713,6 +4288,33 @@ func truncateContent(content string, maxLen int) string {
 }
 
 func (a *App) readTranscriptTexts(path string) ([]string, error) {
+	t, err := a.readTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.Texts, nil
+}
+
+// toolUseEvent is a tool call made by the assistant in a transcript
+type toolUseEvent struct {
+	Name     string // Tool name, e.g. "Edit"
+	FilePath string // file_path (or notebook_path) input, if any
+}
+
+// transcriptData is what readTranscript extracts from a JSONL transcript
+type transcriptData struct {
+	Texts             []string       // Assistant text blocks
+	ToolUses          []toolUseEvent // Assistant tool_use blocks
+	UserMessages      int
+	AssistantMessages int
+	TextChars         int // Characters of user and assistant text
+	InputTokens       int // Reported usage, when the transcript includes it
+	OutputTokens      int
+}
+
+// readTranscript reads messages, assistant text, tool uses, and token usage
+// from a JSONL transcript
+func (a *App) readTranscript(path string) (*transcriptData, error) {
 	// Expand tilde
 	if strings.HasPrefix(path, "~/") {
 		homeDir, _ := os.UserHomeDir()
@@ -1725,7 +4327,7 @@ func (a *App) readTranscriptTexts(path string) ([]string, error) {
 	}
 	defer file.Close()
 
-	var texts []string
+	data := &transcriptData{}
 	decoder := json.NewDecoder(file)
 
 	for {
@@ -1734,25 +4336,68 @@ func (a *App) readTranscriptTexts(path string) ([]string, error) {
 			break
 		}
 
-		// Look for assistant messages
-		if msg, ok := entry["message"].(map[string]interface{}); ok {
-			if role, ok := msg["role"].(string); ok && role == "assistant" {
-				if content, ok := msg["content"].([]interface{}); ok {
-					for _, block := range content {
-						if b, ok := block.(map[string]interface{}); ok {
-							if t, ok := b["type"].(string); ok && t == "text" {
-								if text, ok := b["text"].(string); ok {
-									texts = append(texts, text)
-								}
-							}
-						}
+		msg, ok := entry["message"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		role, _ := msg["role"].(string)
+		switch role {
+		case "user":
+			data.UserMessages++
+		case "assistant":
+			data.AssistantMessages++
+		default:
+			continue
+		}
+
+		if usage, ok := msg["usage"].(map[string]interface{}); ok {
+			if n, ok := usage["input_tokens"].(float64); ok {
+				data.InputTokens += int(n)
+			}
+			if n, ok := usage["output_tokens"].(float64); ok {
+				data.OutputTokens += int(n)
+			}
+		}
+
+		// User prompts are often plain strings rather than content blocks
+		if text, ok := msg["content"].(string); ok {
+			data.TextChars += len(text)
+			continue
+		}
+
+		content, _ := msg["content"].([]interface{})
+		for _, block := range content {
+			b, ok := block.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch b["type"] {
+			case "text":
+				text, _ := b["text"].(string)
+				data.TextChars += len(text)
+				if role == "assistant" {
+					data.Texts = append(data.Texts, text)
+				}
+			case "tool_use":
+				if role != "assistant" {
+					continue
+				}
+				ev := toolUseEvent{}
+				ev.Name, _ = b["name"].(string)
+				if input, ok := b["input"].(map[string]interface{}); ok {
+					if fp, ok := input["file_path"].(string); ok {
+						ev.FilePath = fp
+					} else if fp, ok := input["notebook_path"].(string); ok {
+						ev.FilePath = fp
 					}
 				}
+				data.ToolUses = append(data.ToolUses, ev)
 			}
 		}
 	}
 
-	return texts, nil
+	return data, nil
 }
 
 func (a *App) readTranscriptQueries(path string, maxQueries int) ([]string, error) {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+// runAudit dispatches to audit subcommands
+func (a *App) runAudit(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall audit <subcommand> [args...]")
+		fmt.Fprintln(a.stderr, "  list            - Show recorded lesson changes")
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		return a.runAuditList(args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "unknown audit subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// runAuditList prints audit log events, optionally filtered by lesson and date
+func (a *App) runAuditList(args []string) int {
+	var lessonID string
+	var since time.Time
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--lesson":
+			if i+1 < len(args) {
+				lessonID = args[i+1]
+				i++
+			}
+		case "--since":
+			if i+1 < len(args) {
+				t, err := time.ParseInLocation("2006-01-02", args[i+1], time.Local)
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: invalid --since date %q (use YYYY-MM-DD)\n", args[i+1])
+					return 1
+				}
+				since = t
+				i++
+			}
+		}
+	}
+
+	events, err := lessons.NewAuditLog(a.stateDir).Read()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading audit log: %v\n", err)
+		return 1
+	}
+
+	shown := 0
+	for _, e := range events {
+		if lessonID != "" && e.LessonID != lessonID {
+			continue
+		}
+		if !since.IsZero() && e.Timestamp.Before(since) {
+			continue
+		}
+		fmt.Fprintf(a.stdout, "%s  %-6s  %-5s %s: %q -> %q\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Event, e.LessonID, e.Field,
+			truncateContent(e.OldValue, 40), truncateContent(e.NewValue, 40))
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Fprintln(a.stdout, "No audit events found.")
+	}
+	return 0
+}
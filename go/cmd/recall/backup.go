@@ -0,0 +1,295 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+)
+
+// backupManifestName is the archive entry holding file checksums
+const backupManifestName = "manifest.json"
+
+// backupManifestVersion is bumped whenever the archive layout changes
+const backupManifestVersion = 1
+
+// backupTarget maps an archive entry name to its configured path. Locked
+// files are owned by a store and guarded by <path>.lock.
+type backupTarget struct {
+	Name   string
+	Path   string
+	Locked bool
+}
+
+// backupManifest lists the files in a backup archive with their checksums
+type backupManifest struct {
+	Version int                   `json:"version"`
+	Created time.Time             `json:"created"`
+	Files   []backupManifestEntry `json:"files"`
+}
+
+// backupManifestEntry describes a single backed-up file
+type backupManifestEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// backupTargets returns every file covered by backup and restore
+func (a *App) backupTargets() []backupTarget {
+	targets := []backupTarget{
+		{Name: "project/LESSONS.md", Path: a.projectPath, Locked: true},
+		{Name: "system/LESSONS.md", Path: a.systemPath, Locked: true},
+		{Name: "project/HANDOFFS.md", Path: a.handoffsPath, Locked: true},
+		{Name: "project/HANDOFFS_LOCAL.md", Path: a.stealthPath, Locked: true},
+		{Name: "state/session-handoffs.json", Path: a.getSessionHandoffsPath()},
+		{Name: "state/recall.log", Path: filepath.Join(a.stateDir, "recall.log")},
+	}
+	if a.workspacePath != "" {
+		targets = append(targets, backupTarget{Name: "workspace/LESSONS.md", Path: a.workspacePath, Locked: true})
+	}
+	return targets
+}
+
+// runBackup writes a tar.gz archive of all lessons, handoffs, and state files
+func (a *App) runBackup(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall backup <dest>")
+		fmt.Fprintln(a.stderr, "  dest: archive path, or directory for a timestamped archive")
+		return 1
+	}
+
+	dest := args[0]
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, "recall-backup-"+time.Now().Format("20060102-150405")+".tar.gz")
+	}
+
+	manifest := backupManifest{Version: backupManifestVersion, Created: time.Now()}
+	contents := make(map[string][]byte)
+
+	for _, t := range a.backupTargets() {
+		data, err := os.ReadFile(t.Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error reading %s: %v\n", t.Path, err)
+			return 1
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, backupManifestEntry{
+			Name:   t.Name,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(data)),
+		})
+		contents[t.Name] = data
+	}
+
+	if err := writeBackupArchive(dest, manifest, contents); err != nil {
+		fmt.Fprintf(a.stderr, "error writing backup: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Backed up %d files to %s\n", len(manifest.Files), dest)
+	return 0
+}
+
+// writeBackupArchive writes the manifest followed by each file into a tar.gz
+func writeBackupArchive(path string, manifest backupManifest, contents map[string][]byte) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	writeEntry := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: manifest.Created,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeEntry(backupManifestName, manifestData); err != nil {
+		return err
+	}
+	for _, f := range manifest.Files {
+		if err := writeEntry(f.Name, contents[f.Name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// runRestore extracts a backup archive to the configured paths after
+// verifying every file against the embedded manifest
+func (a *App) runRestore(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall restore <src> [--dry-run]")
+		return 1
+	}
+
+	src := args[0]
+	dryRun := false
+	for _, arg := range args[1:] {
+		if arg == "--dry-run" {
+			dryRun = true
+		}
+	}
+
+	manifest, contents, err := readBackupArchive(src)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading backup: %v\n", err)
+		return 1
+	}
+
+	// Verify everything before writing anything
+	for _, f := range manifest.Files {
+		data, ok := contents[f.Name]
+		if !ok {
+			fmt.Fprintf(a.stderr, "error: %s listed in manifest but missing from archive\n", f.Name)
+			return 1
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			fmt.Fprintf(a.stderr, "error: checksum mismatch for %s\n", f.Name)
+			return 1
+		}
+	}
+
+	targets := make(map[string]backupTarget)
+	for _, t := range a.backupTargets() {
+		targets[t.Name] = t
+	}
+
+	for _, f := range manifest.Files {
+		target, ok := targets[f.Name]
+		if !ok {
+			fmt.Fprintf(a.stderr, "warning: skipping unknown archive entry %s\n", f.Name)
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(a.stdout, "Would restore %s -> %s (%d bytes)\n", f.Name, target.Path, f.Size)
+			continue
+		}
+
+		if err := restoreBackupFile(target, contents[f.Name]); err != nil {
+			fmt.Fprintf(a.stderr, "error restoring %s: %v\n", target.Path, err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "Restored %s -> %s\n", f.Name, target.Path)
+	}
+
+	return 0
+}
+
+// restoreBackupFile writes a restored file atomically, holding the owning
+// store's lock for locked targets so it can't interleave with a store write
+func restoreBackupFile(t backupTarget, data []byte) error {
+	if !t.Locked {
+		return writeFileAtomic(t.Path, data)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0755); err != nil {
+		return err
+	}
+	fl, err := lock.Acquire(t.Path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer fl.Release()
+	return writeFileAtomic(t.Path, data)
+}
+
+// readBackupArchive loads the manifest and all file entries from a tar.gz
+func readBackupArchive(path string) (*backupManifest, map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+
+	var manifest *backupManifest
+	contents := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if hdr.Name == backupManifestName {
+			manifest = &backupManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+		contents[hdr.Name] = data
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("archive has no %s", backupManifestName)
+	}
+	if manifest.Version > backupManifestVersion {
+		return nil, nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	return manifest, contents, nil
+}
+
+// writeFileAtomic writes data to path via a temp file and rename
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
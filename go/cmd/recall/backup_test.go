@@ -0,0 +1,184 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/lock"
+)
+
+func Test_BackupRestore_RoundTrip(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "pattern", "Project lesson", "Project content")
+	store.Add("system", "gotcha", "System lesson", "System content")
+	hstore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	hstore.Add("Visible work", "desc", false)
+	hstore.Add("Hidden work", "desc", true)
+	os.WriteFile(app.getSessionHandoffsPath(), []byte(`{"sess-1":{"handoff_id":"hf-1234567"}}`), 0644)
+	os.WriteFile(filepath.Join(app.stateDir, "recall.log"), []byte("{\"event\":\"test\"}\n"), 0644)
+
+	originals := make(map[string][]byte)
+	for _, target := range app.backupTargets() {
+		data, err := os.ReadFile(target.Path)
+		if err != nil {
+			t.Fatalf("fixture missing %s: %v", target.Path, err)
+		}
+		originals[target.Path] = data
+	}
+
+	// Backing up into a directory produces a timestamped archive
+	backupDir := t.TempDir()
+	if exitCode := app.Run([]string{"recall", "backup", backupDir}); exitCode != 0 {
+		t.Fatalf("backup failed with %d: %s", exitCode, stderr.String())
+	}
+	matches, _ := filepath.Glob(filepath.Join(backupDir, "recall-backup-*.tar.gz"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one timestamped archive, got %v", matches)
+	}
+	archive := matches[0]
+	if !strings.Contains(stdout.String(), "Backed up 6 files") {
+		t.Errorf("unexpected backup output: %s", stdout.String())
+	}
+
+	for path := range originals {
+		os.Remove(path)
+	}
+
+	// Dry run lists files without writing them
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "restore", archive, "--dry-run"}); exitCode != 0 {
+		t.Fatalf("dry run failed with %d: %s", exitCode, stderr.String())
+	}
+	if strings.Count(stdout.String(), "Would restore") != 6 {
+		t.Errorf("expected 6 dry-run entries, got: %s", stdout.String())
+	}
+	if _, err := os.Stat(app.projectPath); !os.IsNotExist(err) {
+		t.Error("dry run should not write files")
+	}
+
+	if exitCode := app.Run([]string{"recall", "restore", archive}); exitCode != 0 {
+		t.Fatalf("restore failed with %d: %s", exitCode, stderr.String())
+	}
+	for path, want := range originals {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("expected %s restored: %v", path, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("content mismatch for %s", path)
+		}
+	}
+}
+
+func Test_Restore_RejectsChecksumMismatch(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Project lesson", "Project content")
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if exitCode := app.Run([]string{"recall", "backup", archive}); exitCode != 0 {
+		t.Fatalf("backup failed: %s", stderr.String())
+	}
+
+	// Rewrite the archive with tampered lesson content but the original manifest
+	tampered := rewriteArchive(t, archive, func(name string, data []byte) []byte {
+		if name == "project/LESSONS.md" {
+			return append(data, []byte("tampered\n")...)
+		}
+		return data
+	})
+	os.WriteFile(archive, tampered, 0644)
+	os.Remove(app.projectPath)
+
+	if exitCode := app.Run([]string{"recall", "restore", archive}); exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "checksum mismatch for project/LESSONS.md") {
+		t.Errorf("expected checksum error, got: %s", stderr.String())
+	}
+	if _, err := os.Stat(app.projectPath); !os.IsNotExist(err) {
+		t.Error("expected nothing restored after a failed verification")
+	}
+}
+
+func Test_BackupRestore_WorkspaceLessonsUnderLock(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+	app.workspacePath = filepath.Join(t.TempDir(), "LESSONS.md")
+	app.lessonStore().Add(lessons.LevelWorkspace, "pattern", "Team lesson", "Team content")
+	original, err := os.ReadFile(app.workspacePath)
+	if err != nil {
+		t.Fatalf("fixture missing workspace lessons: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if exitCode := app.Run([]string{"recall", "backup", archive}); exitCode != 0 {
+		t.Fatalf("backup failed: %s", stderr.String())
+	}
+	os.Remove(app.workspacePath)
+
+	// Restore must wait for a store holding the workspace file's lock
+	fl, err := lock.Acquire(app.workspacePath + ".lock")
+	if err != nil {
+		t.Fatalf("acquiring lock: %v", err)
+	}
+	done := make(chan int)
+	go func() { done <- app.Run([]string{"recall", "restore", archive}) }()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := os.Stat(app.workspacePath); !os.IsNotExist(err) {
+		t.Error("restore wrote the workspace file while its lock was held")
+	}
+	fl.Release()
+
+	if exitCode := <-done; exitCode != 0 {
+		t.Fatalf("restore failed: %s", stderr.String())
+	}
+	if got, _ := os.ReadFile(app.workspacePath); !bytes.Equal(got, original) {
+		t.Errorf("workspace lessons not restored, got %q", got)
+	}
+}
+
+// rewriteArchive copies a tar.gz, transforming each entry's content
+func rewriteArchive(t *testing.T, path string, transform func(string, []byte) []byte) []byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		data = transform(hdr.Name, data)
+		hdr.Size = int64(len(data))
+		tw.WriteHeader(hdr)
+		tw.Write(data)
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/anthropic"
+)
+
+// runCache dispatches to relevance cache subcommands
+func (a *App) runCache(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall cache <subcommand>")
+		fmt.Fprintln(a.stderr, "  clear           - Delete cached relevance scores")
+		fmt.Fprintln(a.stderr, "  stats           - Show cache hits, misses, and oldest entry age")
+		fmt.Fprintln(a.stderr, "  list            - List cached queries with timestamps and sizes")
+		fmt.Fprintln(a.stderr, "  delete <query>  - Delete the cached scores for one query")
+		return 1
+	}
+
+	switch args[0] {
+	case "clear":
+		return a.runCacheClear()
+	case "stats":
+		return a.runCacheStats()
+	case "list":
+		return a.runCacheList()
+	case "delete":
+		return a.runCacheDelete(args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "unknown cache subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// runCacheClear deletes the relevance score cache in the state directory
+func (a *App) runCacheClear() int {
+	n, err := anthropic.ClearCache(a.stateDir)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error clearing cache: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "Cleared %d cached relevance entries\n", n)
+	return 0
+}
+
+// runCacheStats prints relevance cache counters and the oldest entry age
+func (a *App) runCacheStats() int {
+	stats := anthropic.GetCacheStats(a.stateDir)
+	fmt.Fprintf(a.stdout, "Entries: %d\n", stats.Entries)
+	fmt.Fprintf(a.stdout, "Hits:    %d\n", stats.Hits)
+	fmt.Fprintf(a.stdout, "Misses:  %d\n", stats.Misses)
+	if stats.Entries == 0 {
+		fmt.Fprintln(a.stdout, "Oldest:  -")
+	} else {
+		fmt.Fprintf(a.stdout, "Oldest:  %s\n", stats.OldestAge.Truncate(time.Second))
+	}
+	return 0
+}
+
+// runCacheList prints each cached query with its timestamp and score count,
+// newest first
+func (a *App) runCacheList() int {
+	entries := anthropic.ListCache(a.stateDir)
+	if len(entries) == 0 {
+		fmt.Fprintln(a.stdout, "No cached relevance entries.")
+		return 0
+	}
+	for _, e := range entries {
+		fmt.Fprintf(a.stdout, "%s  %3d scores  %s\n", e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Scores, e.Query)
+	}
+	return 0
+}
+
+// runCacheDelete removes the cached scores for one query
+func (a *App) runCacheDelete(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall cache delete <query>")
+		return 1
+	}
+	query := strings.Join(args, " ")
+
+	found, err := anthropic.DeleteCacheEntry(a.stateDir, query)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error deleting cache entry: %v\n", err)
+		return 1
+	}
+	if !found {
+		fmt.Fprintf(a.stderr, "error: no cached results for query %q\n", query)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "Deleted cached relevance entry for %q\n", query)
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+// runCategory dispatches to category subcommands
+func (a *App) runCategory(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall category <subcommand> [args...]")
+		fmt.Fprintln(a.stderr, "  rename          - Rename a category across all lessons")
+		return 1
+	}
+
+	switch args[0] {
+	case "rename":
+		return a.runCategoryRename(args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "unknown category subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// runCategoryRename renames a category in every project and system lesson
+func (a *App) runCategoryRename(args []string) int {
+	var names []string
+	dryRun := false
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+		} else {
+			names = append(names, arg)
+		}
+	}
+
+	if len(names) != 2 {
+		fmt.Fprintln(a.stderr, "usage: recall category rename <old> <new> [--dry-run]")
+		return 1
+	}
+	oldName, newName := names[0], names[1]
+	if err := lessons.ValidateCategory(newName); err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	store := a.localLessonStore()
+
+	if dryRun {
+		all, err := store.List()
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+			return 1
+		}
+		count := 0
+		for _, l := range all {
+			if l.Category == oldName {
+				fmt.Fprintf(a.stdout, "  [%s] %s\n", l.ID, l.Title)
+				count++
+			}
+		}
+		fmt.Fprintf(a.stdout, "Would rename %d lessons from '%s' to '%s'\n", count, oldName, newName)
+		return 0
+	}
+
+	count, err := store.RenameCategory(oldName, newName)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error renaming category: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Renamed %d lessons from '%s' to '%s'\n", count, oldName, newName)
+	return 0
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/config"
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+// Check severities reported by config validate
+const (
+	checkOK    = "OK"
+	checkWarn  = "WARN"
+	checkError = "ERROR"
+)
+
+// configCheck is a single line of the config validation report
+type configCheck struct {
+	Level   string
+	Message string
+}
+
+// runConfig dispatches to config subcommands
+func (a *App) runConfig(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall config <subcommand> [args...]")
+		fmt.Fprintln(a.stderr, "  validate        - Check config file, paths, and API key")
+		return 1
+	}
+
+	switch args[0] {
+	case "validate":
+		return a.runConfigValidate(args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "unknown config subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// runConfigValidate reports on the config file, configured paths, lesson
+// file format, and API key. Exits 1 if any check is an ERROR, or with
+// --strict if any is a WARN.
+func (a *App) runConfigValidate(args []string) int {
+	strict := false
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--config" && i+1 < len(args) {
+			a.configPath = args[i+1]
+			i++
+		} else if args[i] == "--strict" {
+			strict = true
+		}
+	}
+
+	checks := a.validateConfigFile()
+
+	if err := a.initPaths(); err != nil {
+		checks = append(checks, configCheck{checkError, fmt.Sprintf("cannot resolve paths: %v", err)})
+	} else {
+		checks = append(checks, checkPath("project lessons", a.projectPath, false))
+		checks = append(checks, checkPath("system lessons", a.systemPath, false))
+		checks = append(checks, checkPath("handoffs", a.handoffsPath, false))
+		checks = append(checks, checkPath("stealth handoffs", a.stealthPath, false))
+		checks = append(checks, checkPath("state dir", a.stateDir, true))
+		checks = append(checks, checkLessonsFile("project lessons", a.projectPath))
+		checks = append(checks, checkLessonsFile("system lessons", a.systemPath))
+	}
+
+	if os.Getenv("ANTHROPIC_API_KEY") != "" {
+		checks = append(checks, configCheck{checkOK, "ANTHROPIC_API_KEY is set"})
+	} else {
+		checks = append(checks, configCheck{checkWarn, "ANTHROPIC_API_KEY is not set (score-relevance unavailable)"})
+	}
+
+	failed := false
+	for _, c := range checks {
+		fmt.Fprintf(a.stdout, "[%s] %s\n", c.Level, c.Message)
+		if c.Level == checkError || (strict && c.Level == checkWarn) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// validateConfigFile checks that the config file exists and is valid JSON
+func (a *App) validateConfigFile() []configCheck {
+	path := a.getConfigPath()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []configCheck{{checkWarn, fmt.Sprintf("config file %s not found (using defaults)", path)}}
+	}
+	if err != nil {
+		return []configCheck{{checkError, fmt.Sprintf("config file %s unreadable: %v", path, err)}}
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return []configCheck{{checkError, fmt.Sprintf("config file %s is not valid JSON: %v", path, err)}}
+	}
+
+	var schemaErr *config.SchemaError
+	if err := config.ValidateSchema(data); errors.As(err, &schemaErr) {
+		var checks []configCheck
+		for _, p := range schemaErr.Problems {
+			checks = append(checks, configCheck{checkError, fmt.Sprintf("config file %s: %s", path, p)})
+		}
+		for _, k := range schemaErr.UnknownKeys {
+			checks = append(checks, configCheck{checkWarn, fmt.Sprintf("config file %s: %s", path, k)})
+		}
+		return checks
+	}
+
+	return []configCheck{{checkOK, fmt.Sprintf("config file %s is valid JSON", path)}}
+}
+
+// checkPath reports whether path exists with the expected kind, or can be created
+func checkPath(label, path string, isDir bool) configCheck {
+	if path == "" {
+		return configCheck{checkError, fmt.Sprintf("%s path is empty", label)}
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() != isDir {
+			kind := "file"
+			if isDir {
+				kind = "directory"
+			}
+			return configCheck{checkError, fmt.Sprintf("%s %s exists but is not a %s", label, path, kind)}
+		}
+		return configCheck{checkOK, fmt.Sprintf("%s %s exists", label, path)}
+	}
+
+	// Walk up to the nearest existing ancestor and check it can hold new entries
+	dir := path
+	if !isDir {
+		dir = filepath.Dir(path)
+	}
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return configCheck{checkError, fmt.Sprintf("%s %s cannot be created: %s is not a directory", label, path, dir)}
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	probe, err := os.CreateTemp(dir, ".recall-validate-*")
+	if err != nil {
+		return configCheck{checkError, fmt.Sprintf("%s %s cannot be created: %v", label, path, err)}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return configCheck{checkOK, fmt.Sprintf("%s %s does not exist yet (creatable)", label, path)}
+}
+
+// checkLessonsFile verifies every lesson header in a LESSONS.md parses
+func checkLessonsFile(label, path string) configCheck {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return configCheck{checkOK, fmt.Sprintf("%s file not created yet", label)}
+	}
+	if err != nil {
+		return configCheck{checkError, fmt.Sprintf("%s %s unreadable: %v", label, path, err)}
+	}
+	defer f.Close()
+
+	// Count anything that looks like a lesson header, parsed or not
+	headers := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "### [") {
+			headers++
+		}
+	}
+
+	parsed, err := lessons.ParseFile(path)
+	if err != nil {
+		return configCheck{checkError, fmt.Sprintf("%s %s failed to parse: %v", label, path, err)}
+	}
+	if len(parsed) != headers {
+		return configCheck{checkError, fmt.Sprintf("%s %s has %d malformed lesson header(s)", label, path, headers-len(parsed))}
+	}
+
+	return configCheck{checkOK, fmt.Sprintf("%s %s parses (%d lessons)", label, path, len(parsed))}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ConfigValidate_ReportsErrors(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	dir := t.TempDir()
+	badConfig := filepath.Join(dir, "config.json")
+	os.WriteFile(badConfig, []byte(`{"state_dir": `), 0644)
+
+	// Lesson header without a valid rating block
+	os.WriteFile(app.projectPath, []byte(`# LESSONS.md - Project Level
+
+### [L001] [*----|-----] Good lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-01 | **Category**: pattern
+> Fine
+
+### [L002] broken header
+> Missing rating
+`), 0644)
+
+	// State dir path is occupied by a regular file
+	blocker := filepath.Join(dir, "blocker")
+	os.WriteFile(blocker, []byte("x"), 0644)
+	app.stateDir = filepath.Join(blocker, "state")
+
+	exitCode := app.Run([]string{"recall", "config", "validate", "--config", badConfig})
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+
+	output := stdout.String()
+	for _, want := range []string{
+		"[ERROR] config file " + badConfig + " is not valid JSON",
+		"[ERROR] project lessons " + app.projectPath + " has 1 malformed lesson header(s)",
+		"[ERROR] state dir " + app.stateDir + " cannot be created: " + blocker + " is not a directory",
+		"[WARN] ANTHROPIC_API_KEY is not set",
+		"[OK] handoffs " + app.handoffsPath + " does not exist yet (creatable)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func Test_ConfigValidate_CleanSetup(t *testing.T) {
+	app, store, stdout, _ := newTestApp(t)
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	store.Add("project", "pattern", "Lesson", "Content")
+
+	goodConfig := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(goodConfig, []byte(`{"debug_level": 1}`), 0644)
+
+	if exitCode := app.Run([]string{"recall", "config", "validate", "--config", goodConfig}); exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d. output:\n%s", exitCode, stdout.String())
+	}
+	if strings.Contains(stdout.String(), "[ERROR]") {
+		t.Errorf("expected no errors, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "parses (1 lessons)") {
+		t.Errorf("expected lesson parse check, got:\n%s", stdout.String())
+	}
+}
+
+func Test_ConfigValidate_SchemaAndStrict(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(configPath, []byte(`{"debug_levl": 1, "max_tokens": "lots"}`), 0644)
+	if code := app.Run([]string{"recall", "config", "validate", "--config", configPath}); code != 1 {
+		t.Errorf("expected exit code 1 for schema errors, got %d", code)
+	}
+	for _, want := range []string{
+		`[WARN] config file ` + configPath + `: unknown key "debug_levl"`,
+		"[ERROR] config file " + configPath + ": max_tokens: expected integer, got string",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout.String())
+		}
+	}
+
+	// A missing API key is only a warning unless --strict
+	os.WriteFile(configPath, []byte(`{"debug_level": 1}`), 0644)
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "config", "validate", "--config", configPath}); code != 0 {
+		t.Errorf("expected exit code 0 with only warnings, got %d:\n%s", code, stdout.String())
+	}
+	if code := app.Run([]string{"recall", "config", "validate", "--config", configPath, "--strict"}); code != 1 {
+		t.Errorf("expected exit code 1 for warnings with --strict, got %d", code)
+	}
+}
+
+func Test_InitPaths_ReloadsConfigAfterInterval(t *testing.T) {
+	for _, v := range []string{"CLAUDE_RECALL_BASE", "RECALL_BASE", "LESSONS_BASE", "CLAUDE_RECALL_STATE", "PROJECT_DIR"} {
+		t.Setenv(v, "")
+	}
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	writeConfig := func(name string) {
+		os.WriteFile(configPath, []byte(`{"project_dir": "`+filepath.Join(dir, name)+`", "state_dir": "`+filepath.Join(dir, name+"-state")+`"}`), 0644)
+	}
+	writeConfig("a")
+
+	for _, noReload := range []bool{false, true} {
+		writeConfig("a")
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		app := NewApp()
+		app.configPath = configPath
+		app.noReload = noReload
+		app.now = func() time.Time { return now }
+
+		if err := app.initPaths(); err != nil {
+			t.Fatalf("initPaths failed: %v", err)
+		}
+		if app.stateDir != filepath.Join(dir, "a-state") {
+			t.Fatalf("expected initial state dir a-state, got %s", app.stateDir)
+		}
+
+		writeConfig("b")
+		now = now.Add(ConfigReloadInterval - time.Second)
+		app.initPaths()
+		if app.stateDir != filepath.Join(dir, "a-state") {
+			t.Errorf("noReload=%v: config reloaded before interval: %s", noReload, app.stateDir)
+		}
+
+		now = now.Add(2 * time.Second)
+		app.initPaths()
+		wantDir := "b"
+		if noReload {
+			wantDir = "a"
+		}
+		if app.stateDir != filepath.Join(dir, wantDir+"-state") {
+			t.Errorf("noReload=%v: expected state dir %s-state, got %s", noReload, wantDir, app.stateDir)
+		}
+		if app.projectPath != filepath.Join(dir, wantDir, ".claude-recall", "LESSONS.md") {
+			t.Errorf("noReload=%v: unexpected project path %s", noReload, app.projectPath)
+		}
+	}
+}
+
+func Test_InitPaths_ExplicitPathsSurviveReload(t *testing.T) {
+	t.Setenv("CLAUDE_RECALL_STATE", "")
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	os.WriteFile(configPath, []byte(`{"state_dir": "`+filepath.Join(dir, "state")+`"}`), 0644)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	app := NewApp()
+	app.configPath = configPath
+	app.now = func() time.Time { return now }
+	app.projectPath = filepath.Join(dir, "explicit", "LESSONS.md")
+
+	app.initPaths()
+	now = now.Add(ConfigReloadInterval)
+	app.initPaths()
+
+	if app.projectPath != filepath.Join(dir, "explicit", "LESSONS.md") {
+		t.Errorf("explicit project path overwritten by reload: %s", app.projectPath)
+	}
+	if app.systemPath != filepath.Join(dir, "state", "LESSONS.md") {
+		t.Errorf("expected system path from config, got %s", app.systemPath)
+	}
+}
+
+func Test_Run_NoReloadFlag(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	if code := app.Run([]string{"recall", "--no-reload", "list"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !app.noReload {
+		t.Error("expected --no-reload to set noReload")
+	}
+}
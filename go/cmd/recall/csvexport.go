@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// lessonCSVColumns are the columns of `recall export --csv`, in default order
+var lessonCSVColumns = []string{"id", "title", "category", "level", "uses", "velocity", "confidence", "learned", "last_used", "content"}
+
+// handoffCSVColumns are the columns of `recall handoff export --csv`, in default order
+var handoffCSVColumns = []string{"id", "title", "status", "phase", "priority", "created", "updated", "tried_count", "next_steps"}
+
+// numericCSVColumns sort numerically rather than as text
+var numericCSVColumns = map[string]bool{"uses": true, "velocity": true, "confidence": true, "tried_count": true}
+
+// csvOptions holds the flags shared by lesson and handoff CSV export
+type csvOptions struct {
+	fields     []string
+	sortBy     string
+	outputPath string
+}
+
+// parseCSVOptions parses --fields, --sort-by and --output against columns.
+// Unrecognised arguments (such as --csv itself) are ignored.
+func parseCSVOptions(args []string, columns []string) (csvOptions, error) {
+	opts := csvOptions{fields: columns}
+	valid := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		valid[c] = true
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--fields":
+			if i+1 < len(args) {
+				opts.fields = nil
+				for _, f := range strings.Split(args[i+1], ",") {
+					f = strings.TrimSpace(f)
+					if !valid[f] {
+						return opts, fmt.Errorf("unknown field %q (use %s)", f, strings.Join(columns, ", "))
+					}
+					opts.fields = append(opts.fields, f)
+				}
+				i++
+			}
+		case "--sort-by":
+			if i+1 < len(args) {
+				if !valid[args[i+1]] {
+					return opts, fmt.Errorf("unknown sort field %q (use %s)", args[i+1], strings.Join(columns, ", "))
+				}
+				opts.sortBy = args[i+1]
+				i++
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				opts.outputPath = args[i+1]
+				i++
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// runExportCSV writes lessons as CSV for spreadsheet analysis
+func (a *App) runExportCSV(args []string) int {
+	opts, err := parseCSVOptions(args, lessonCSVColumns)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	store := a.lessonStore()
+	allLessons, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	rows := make([]map[string]string, len(allLessons))
+	for i, l := range allLessons {
+		rows[i] = lessonCSVRow(l)
+	}
+	return a.writeCSVExport(rows, opts, "lessons")
+}
+
+// runHandoffExport writes handoffs as CSV (the only supported format)
+func (a *App) runHandoffExport(args []string) int {
+	csvFlag := false
+	for _, arg := range args {
+		if arg == "--csv" {
+			csvFlag = true
+		}
+	}
+	if !csvFlag {
+		fmt.Fprintln(a.stderr, "usage: recall handoff export --csv [--fields a,b] [--sort-by field] [-o path]")
+		return 1
+	}
+
+	opts, err := parseCSVOptions(args, handoffCSVColumns)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	store := a.handoffStore()
+	allHandoffs, err := store.ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+
+	rows := make([]map[string]string, len(allHandoffs))
+	for i, h := range allHandoffs {
+		rows[i] = handoffCSVRow(h)
+	}
+	return a.writeCSVExport(rows, opts, "handoffs")
+}
+
+func lessonCSVRow(l *models.Lesson) map[string]string {
+	return map[string]string{
+		"id":         l.ID,
+		"title":      l.Title,
+		"category":   l.Category,
+		"level":      l.Level,
+		"uses":       strconv.Itoa(l.Uses),
+		"velocity":   strconv.FormatFloat(l.Velocity, 'g', -1, 64),
+		"confidence": strconv.Itoa(l.Confidence),
+		"learned":    l.Learned.Format("2006-01-02"),
+		"last_used":  l.LastUsed.Format("2006-01-02"),
+		"content":    l.Content,
+	}
+}
+
+func handoffCSVRow(h *models.Handoff) map[string]string {
+	return map[string]string{
+		"id":          h.ID,
+		"title":       h.Title,
+		"status":      h.Status,
+		"phase":       h.Phase,
+		"priority":    h.Priority,
+		"created":     h.Created.Format("2006-01-02"),
+		"updated":     h.Updated.Format("2006-01-02"),
+		"tried_count": strconv.Itoa(len(h.Tried)),
+		"next_steps":  h.NextSteps,
+	}
+}
+
+// writeCSVExport sorts rows and writes the selected fields with a header row
+// to stdout or opts.outputPath
+func (a *App) writeCSVExport(rows []map[string]string, opts csvOptions, noun string) int {
+	if opts.sortBy != "" {
+		sortCSVRows(rows, opts.sortBy)
+	}
+
+	var out io.Writer = a.stdout
+	if opts.outputPath != "" {
+		f, err := os.Create(opts.outputPath)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error creating %s: %v\n", opts.outputPath, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := csv.NewWriter(out)
+	w.Write(opts.fields)
+	for _, row := range rows {
+		record := make([]string, len(opts.fields))
+		for i, f := range opts.fields {
+			record[i] = row[f]
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(a.stderr, "error writing CSV: %v\n", err)
+		return 1
+	}
+
+	if opts.outputPath != "" {
+		fmt.Fprintf(a.stdout, "Exported %d %s to %s\n", len(rows), noun, opts.outputPath)
+	}
+	return 0
+}
+
+// sortCSVRows sorts rows ascending by field, numerically for numeric columns
+func sortCSVRows(rows []map[string]string, field string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if numericCSVColumns[field] {
+			x, _ := strconv.ParseFloat(rows[i][field], 64)
+			y, _ := strconv.ParseFloat(rows[j][field], 64)
+			return x < y
+		}
+		return rows[i][field] < rows[j][field]
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+func Test_ExportCSV_Lessons(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.ForceAdd("project", "gotcha", "Quote handling", `Use "quotes", commas, and`+"\nnewlines safely")
+	store.ForceAdd("project", "pattern", "Second lesson", "Plain content")
+	store.Cite("L002")
+	store.Cite("L002")
+
+	if exitCode := app.Run([]string{"recall", "export", "--csv"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(stdout.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, stdout.String())
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d", len(records))
+	}
+	if strings.Join(records[0], ",") != "id,title,category,level,uses,velocity,confidence,learned,last_used,content" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if records[1][0] != "L001" || records[1][9] != `Use "quotes", commas, and`+"\nnewlines safely" {
+		t.Errorf("content not escaped correctly: %q", records[1])
+	}
+	if records[2][4] != "2" || records[2][6] != "50" {
+		t.Errorf("expected uses 2 and confidence 50 for L002, got %q", records[2])
+	}
+}
+
+func Test_ExportCSV_FieldsAndSort(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.ForceAdd("project", "pattern", "Often used", "a")
+	store.ForceAdd("project", "pattern", "Rarely used", "b")
+	for i := 0; i < 10; i++ {
+		store.Cite("L001")
+	}
+	store.Cite("L002")
+
+	if exitCode := app.Run([]string{"recall", "export", "--csv", "--fields", "id,uses", "--sort-by", "uses"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(stdout.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	// Numeric sort puts 1 before 10
+	want := [][]string{{"id", "uses"}, {"L002", "1"}, {"L001", "10"}}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %v", len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("record %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+
+	if exitCode := app.Run([]string{"recall", "export", "--csv", "--fields", "id,bogus"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for unknown field, got %d", exitCode)
+	}
+}
+
+func Test_HandoffExportCSV(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	store := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	first, _ := store.Add("Build parser", "", false)
+	store.Add("Write docs", "", false)
+	store.Update(first.ID, map[string]interface{}{"status": "in_progress", "next_steps": "Handle edge cases, then ship", "priority": "high"})
+	store.AddTriedStep(first.ID, "fail", "Regex approach")
+	store.AddTriedStep(first.ID, "success", "Hand-written lexer")
+
+	outPath := filepath.Join(t.TempDir(), "handoffs.csv")
+	if exitCode := app.Run([]string{"recall", "handoff", "export", "--csv", "--sort-by", "title", "-o", outPath}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Exported 2 handoffs") {
+		t.Errorf("expected export summary, got: %s", stdout.String())
+	}
+
+	f, err := os.Open(outPath)
+	if err != nil {
+		t.Fatalf("open export: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d", len(records))
+	}
+	if strings.Join(records[0], ",") != "id,title,status,phase,priority,created,updated,tried_count,next_steps" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	row := records[1]
+	if row[0] != first.ID || row[2] != "in_progress" || row[4] != "high" || row[7] != "2" || row[8] != "Handle edge cases, then ship" {
+		t.Errorf("unexpected row for %s: %q", first.ID, row)
+	}
+
+	if exitCode := app.Run([]string{"recall", "handoff", "export"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 without --csv, got %d", exitCode)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultGitSyncDays is how far back git-sync looks for merge commits
+const defaultGitSyncDays = 7
+
+// mergeHandoffPattern matches handoff IDs mentioned in commit messages
+var mergeHandoffPattern = regexp.MustCompile(`\bhf-[0-9a-f]{7}\b`)
+
+// runCommand runs an external command in dir and returns its stdout
+func (a *App) runCommand(dir, name string, args ...string) ([]byte, error) {
+	if a.execCommand != nil {
+		return a.execCommand(dir, name, args...)
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.Output()
+}
+
+// runHandoffGitSync completes handoffs whose IDs appear in recent merge commits
+func (a *App) runHandoffGitSync(args []string) int {
+	days := defaultGitSyncDays
+	dryRun := false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--since":
+			if i+1 >= len(args) {
+				fmt.Fprintln(a.stderr, "usage: recall handoff git-sync [--since N] [--dry-run]")
+				return 1
+			}
+			n, err := strconv.Atoi(strings.TrimSuffix(args[i+1], "d"))
+			if err != nil || n <= 0 {
+				fmt.Fprintf(a.stderr, "error: invalid --since %q (use a number of days)\n", args[i+1])
+				return 1
+			}
+			days = n
+			i++
+		case "--dry-run":
+			dryRun = true
+		}
+	}
+
+	output, err := a.runCommand(a.projectDir, "git", "log", "--merges",
+		fmt.Sprintf("--since=%d.days.ago", days), "--format=%B")
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading git log: %v\n", err)
+		return 1
+	}
+
+	ids := extractMergedHandoffIDs(string(output))
+	if len(ids) == 0 {
+		fmt.Fprintf(a.stdout, "No handoffs referenced in merge commits from the last %d days.\n", days)
+		return 0
+	}
+
+	store := a.handoffStore()
+	completed := 0
+	for _, id := range ids {
+		h, err := store.Get(id)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "warning: merge commit references unknown handoff %s\n", id)
+			continue
+		}
+		if h.Status == "completed" {
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(a.stdout, "Would complete handoff %s: %s\n", h.ID, h.Title)
+			completed++
+			continue
+		}
+
+		if err := store.Complete(id); err != nil {
+			fmt.Fprintf(a.stderr, "error completing handoff %s: %v\n", id, err)
+			return 1
+		}
+		fmt.Fprintf(a.stdout, "Completed handoff %s: %s\n", h.ID, h.Title)
+		completed++
+	}
+
+	if completed == 0 {
+		fmt.Fprintln(a.stdout, "All referenced handoffs are already completed.")
+	}
+	return 0
+}
+
+// extractMergedHandoffIDs returns the unique handoff IDs in git log output,
+// in order of first appearance
+func extractMergedHandoffIDs(log string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range mergeHandoffPattern.FindAllString(log, -1) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+// stubGitLog returns an execCommand that answers git log with output and
+// records the arguments it was called with
+func stubGitLog(output string, calls *[][]string) func(dir, name string, args ...string) ([]byte, error) {
+	return func(dir, name string, args ...string) ([]byte, error) {
+		*calls = append(*calls, append([]string{name}, args...))
+		return []byte(output), nil
+	}
+}
+
+func Test_HandoffGitSync_CompletesMergedHandoffs(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	store := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	merged, _ := store.Add("Login flow", "", false)
+	open, _ := store.Add("Unrelated work", "", false)
+
+	var calls [][]string
+	app.execCommand = stubGitLog("Merge pull request #12 from me/login\n\nFinishes "+merged.ID+"\n\nMerge branch 'hf-0000000'\n", &calls)
+
+	if exitCode := app.Run([]string{"recall", "handoff", "git-sync"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	if len(calls) != 1 || strings.Join(calls[0], " ") != "git log --merges --since=7.days.ago --format=%B" {
+		t.Errorf("unexpected git invocation: %v", calls)
+	}
+
+	h, _ := store.Get(merged.ID)
+	if h.Status != "completed" {
+		t.Errorf("expected %s to be completed, got %s", merged.ID, h.Status)
+	}
+	h, _ = store.Get(open.ID)
+	if h.Status == "completed" {
+		t.Errorf("expected %s to stay open", open.ID)
+	}
+	if !strings.Contains(stdout.String(), "Completed handoff "+merged.ID) {
+		t.Errorf("expected completion message, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "unknown handoff hf-0000000") {
+		t.Errorf("expected warning for unknown handoff, got: %s", stderr.String())
+	}
+}
+
+func Test_HandoffGitSync_DryRunLeavesHandoffsOpen(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	store := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	merged, _ := store.Add("Login flow", "", false)
+
+	var calls [][]string
+	app.execCommand = stubGitLog("Merge branch 'feature/"+merged.ID+"'\n", &calls)
+
+	if exitCode := app.Run([]string{"recall", "handoff", "git-sync", "--since", "30", "--dry-run"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	if len(calls) != 1 || !strings.Contains(strings.Join(calls[0], " "), "--since=30.days.ago") {
+		t.Errorf("expected --since to be passed to git log, got %v", calls)
+	}
+	if !strings.Contains(stdout.String(), "Would complete handoff "+merged.ID) {
+		t.Errorf("expected dry-run message, got: %s", stdout.String())
+	}
+	h, _ := store.Get(merged.ID)
+	if h.Status == "completed" {
+		t.Error("expected dry run to leave handoff open")
+	}
+}
+
+func Test_HandoffGitSync_GitError(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+	app.execCommand = func(dir, name string, args ...string) ([]byte, error) {
+		return nil, errors.New("not a git repository")
+	}
+
+	if exitCode := app.Run([]string{"recall", "handoff", "git-sync"}); exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "not a git repository") {
+		t.Errorf("expected git error, got: %s", stderr.String())
+	}
+}
+
+func Test_ExtractMergedHandoffIDs_Dedupes(t *testing.T) {
+	ids := extractMergedHandoffIDs("hf-abc1234 and hf-abc1234 then hf-1234567; not hf-xyz or xhf-1111111")
+	if strings.Join(ids, ",") != "hf-abc1234,hf-1234567" {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
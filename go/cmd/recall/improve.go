@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/anthropic"
+)
+
+// improveTimeout bounds the API call for a lesson rewrite
+const improveTimeout = 30 * time.Second
+
+// runImprove asks the API for a clearer version of a lesson's content and
+// saves it after confirmation (or immediately with --auto-accept)
+func (a *App) runImprove(args []string) int {
+	var id, model string
+	autoAccept := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--auto-accept":
+			autoAccept = true
+		case "--model":
+			if i+1 < len(args) {
+				model = args[i+1]
+				i++
+			}
+		default:
+			if id == "" {
+				id = args[i]
+			}
+		}
+	}
+	if id == "" {
+		fmt.Fprintln(a.stderr, "usage: recall improve <id> [--auto-accept] [--model M]")
+		return 1
+	}
+
+	store := a.lessonStore()
+	lesson, err := store.Get(id)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	improve := a.improveLesson
+	if improve == nil {
+		improve = func(title, content, model string) (string, error) {
+			return anthropic.ImproveLesson(title, content, model, improveTimeout)
+		}
+	}
+	suggestion, err := improve(lesson.Title, lesson.Content, model)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error improving lesson: %v\n", err)
+		return 1
+	}
+	if suggestion == lesson.Content {
+		fmt.Fprintf(a.stdout, "No changes suggested for %s.\n", id)
+		return 0
+	}
+
+	fmt.Fprintf(a.stdout, "[%s] %s\n", lesson.ID, lesson.Title)
+	fmt.Fprintf(a.stdout, "Current:   %s\n", lesson.Content)
+	fmt.Fprintf(a.stdout, "Suggested: %s\n", suggestion)
+
+	if !autoAccept {
+		fmt.Fprint(a.stdout, "Apply this change? [y/N] ")
+		answer, _ := bufio.NewReader(a.stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(a.stdout, "Kept original lesson.")
+			return 0
+		}
+	}
+
+	if err := store.Edit(id, map[string]interface{}{"content": suggestion}); err != nil {
+		fmt.Fprintf(a.stderr, "error updating lesson: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "Updated %s\n", id)
+	return 0
+}
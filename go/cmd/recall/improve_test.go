@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubImprove returns an improveLesson stub answering with suggestion and
+// recording the requested model
+func stubImprove(suggestion string, model *string) func(title, content, m string) (string, error) {
+	return func(title, content, m string) (string, error) {
+		*model = m
+		return suggestion, nil
+	}
+}
+
+func Test_Improve_AutoAccept(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Errors", "handle errors better")
+
+	var model string
+	app.improveLesson = stubImprove("Wrap returned errors with fmt.Errorf and %w so callers can inspect them.", &model)
+
+	if code := app.Run([]string{"recall", "improve", "L001", "--auto-accept", "--model", "claude-haiku-3"}); code != 0 {
+		t.Fatalf("improve failed: %s", stderr.String())
+	}
+	if model != "claude-haiku-3" {
+		t.Errorf("expected --model passed through, got %q", model)
+	}
+	lesson, _ := store.Get("L001")
+	if !strings.HasPrefix(lesson.Content, "Wrap returned errors") {
+		t.Errorf("expected improved content saved, got %q", lesson.Content)
+	}
+	if !strings.Contains(stdout.String(), "Updated L001") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+}
+
+func Test_Improve_ConfirmAndReject(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Errors", "handle errors better")
+
+	var model string
+	app.improveLesson = stubImprove("Wrap errors with context.", &model)
+
+	app.stdin = strings.NewReader("n\n")
+	if code := app.Run([]string{"recall", "improve", "L001"}); code != 0 {
+		t.Fatalf("improve failed: %s", stderr.String())
+	}
+	lesson, _ := store.Get("L001")
+	if lesson.Content != "handle errors better" {
+		t.Errorf("expected content unchanged after rejection, got %q", lesson.Content)
+	}
+	if !strings.Contains(stdout.String(), "Suggested: Wrap errors with context.") || !strings.Contains(stdout.String(), "Kept original lesson.") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	app.stdin = strings.NewReader("y\n")
+	if code := app.Run([]string{"recall", "improve", "L001"}); code != 0 {
+		t.Fatalf("improve failed: %s", stderr.String())
+	}
+	lesson, _ = store.Get("L001")
+	if lesson.Content != "Wrap errors with context." {
+		t.Errorf("expected content updated after confirmation, got %q", lesson.Content)
+	}
+}
+
+func Test_Improve_Errors(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Errors", "handle errors better")
+
+	app.improveLesson = func(title, content, model string) (string, error) {
+		return "", errors.New("API error: overloaded")
+	}
+	if code := app.Run([]string{"recall", "improve", "L001", "--auto-accept"}); code != 1 {
+		t.Errorf("expected exit code 1 on API error, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "overloaded") {
+		t.Errorf("expected API error reported, got: %s", stderr.String())
+	}
+
+	if code := app.Run([]string{"recall", "improve", "L999"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown lesson, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "improve"}); code != 1 {
+		t.Errorf("expected exit code 1 without id, got %d", code)
+	}
+}
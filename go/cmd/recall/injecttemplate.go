@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// lessonsTemplateName is the user's lesson inject template under
+// <base>/inject-templates
+const lessonsTemplateName = "lessons.tmpl"
+
+// LessonsTemplateData is what a lesson inject template is executed with
+type LessonsTemplateData struct {
+	Lessons []*models.Lesson // Lessons to inject, best first
+	TopN    int              // Number of lessons requested
+}
+
+// injectTemplateFuncs are available to inject templates in addition to
+// the text/template builtins, e.g. {{inc $i}} for 1-based numbering
+var injectTemplateFuncs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// injectTemplateDir returns the inject template directory under the base
+// directory
+func injectTemplateDir(base string) string {
+	return filepath.Join(base, "inject-templates")
+}
+
+// lessonsTemplate loads the lesson inject template at path, or, when path
+// is empty, <base>/inject-templates/lessons.tmpl. It returns nil when no
+// path was given and the default template doesn't exist, meaning the
+// built-in format should be used.
+func (a *App) lessonsTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		if a.baseDir == "" {
+			return nil, nil
+		}
+		path = filepath.Join(injectTemplateDir(a.baseDir), lessonsTemplateName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(injectTemplateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing inject template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderLessonsTemplate executes an inject template over lessons
+func renderLessonsTemplate(tmpl *template.Template, lessonList []*models.Lesson, topN int) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, LessonsTemplateData{Lessons: lessonList, TopN: topN}); err != nil {
+		return "", fmt.Errorf("executing inject template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// fitTemplateBudget returns how many leading lessons can be rendered with
+// tmpl within maxTokens. Lessons are assumed to be in priority order. A
+// render error keeps every lesson, leaving the error to the final render.
+func fitTemplateBudget(tmpl *template.Template, lessonList []*models.Lesson, topN, maxTokens int) int {
+	for kept := len(lessonList); kept > 0; kept-- {
+		out, err := renderLessonsTemplate(tmpl, lessonList[:kept], topN)
+		if err != nil {
+			return len(lessonList)
+		}
+		if estimateTokens(out) <= maxTokens {
+			return kept
+		}
+	}
+	return 0
+}
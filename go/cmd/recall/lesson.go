@@ -0,0 +1,339 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/scoring"
+)
+
+// smartInjectAlpha is the weight given to context relevance over usage
+const smartInjectAlpha = 0.6
+
+// runLesson dispatches to lesson subcommands
+func (a *App) runLesson(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall lesson <subcommand> [args...]")
+		fmt.Fprintln(a.stderr, "  score-local     - Score lessons locally using BM25 (supports err* wildcards)")
+		fmt.Fprintln(a.stderr, "  smart-inject    - Inject lessons reranked by a context summary")
+		fmt.Fprintln(a.stderr, "  find-by-triggers - Find lessons whose triggers appear in text")
+		fmt.Fprintln(a.stderr, "  diff            - Compare two lessons' content and metadata")
+		fmt.Fprintln(a.stderr, "  report          - Detailed analytics for one lesson")
+		return 1
+	}
+
+	subcmd := args[0]
+	subArgs := args[1:]
+
+	switch subcmd {
+	case "score-local":
+		return a.runScoreLocal(subArgs)
+	case "smart-inject":
+		return a.runSmartInject(subArgs)
+	case "find-by-triggers":
+		return a.runFindByTriggers(subArgs)
+	case "diff":
+		return a.runLessonDiff(subArgs)
+	case "report":
+		return a.runLessonReport(subArgs)
+	default:
+		fmt.Fprintf(a.stderr, "unknown lesson subcommand: %s\n", subcmd)
+		return 1
+	}
+}
+
+// runSmartInject outputs top n lessons, reranked by relevance to a context summary.
+// Lessons whose triggers appear in the summary come first, followed by the
+// hybrid (BM25 + usage) ranking. Without a summary it behaves like inject.
+func (a *App) runSmartInject(args []string) int {
+	n := 5
+	var contextSummary string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--context-summary":
+			if i+1 < len(args) {
+				contextSummary = args[i+1]
+				i++
+			}
+		default:
+			if parsed, err := strconv.Atoi(args[i]); err == nil {
+				n = parsed
+			}
+		}
+	}
+
+	if contextSummary == "" {
+		return a.runInject([]string{strconv.Itoa(n)})
+	}
+
+	store := a.lessonStore()
+	allLessons, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	triggered, err := store.FindByTriggers(contextSummary)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error matching triggers: %v\n", err)
+		return 1
+	}
+
+	ranked := scoring.NewHybridScorer(allLessons, smartInjectAlpha).Score(contextSummary)
+
+	// Union-merge: trigger matches first, then hybrid ranking
+	seen := make(map[string]bool)
+	var merged []*models.Lesson
+	for _, l := range triggered {
+		if !seen[l.ID] {
+			seen[l.ID] = true
+			merged = append(merged, l)
+		}
+	}
+	for _, sl := range ranked {
+		if !seen[sl.Lesson.ID] {
+			seen[sl.Lesson.ID] = true
+			merged = append(merged, sl.Lesson)
+		}
+	}
+
+	if n > len(merged) {
+		n = len(merged)
+	}
+
+	a.writeInjectedLessons("smart_inject", merged[:n], injectFormatMarkdown)
+	return 0
+}
+
+// lessonJSON is the JSON representation of a lesson for --format json output
+type lessonJSON struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Category string   `json:"category"`
+	Level    string   `json:"level"`
+	Uses     int      `json:"uses"`
+	Velocity float64  `json:"velocity"`
+	Triggers []string `json:"triggers"`
+	Tags     []string `json:"tags"`
+}
+
+// toLessonJSON converts lessons to their JSON representation
+func toLessonJSON(lessonList []*models.Lesson) []lessonJSON {
+	out := make([]lessonJSON, len(lessonList))
+	for i, l := range lessonList {
+		out[i] = lessonJSON{
+			ID:       l.ID,
+			Title:    l.Title,
+			Content:  l.Content,
+			Category: l.Category,
+			Level:    l.Level,
+			Uses:     l.Uses,
+			Velocity: l.Velocity,
+			Triggers: l.Triggers,
+			Tags:     l.Tags,
+		}
+	}
+	return out
+}
+
+// runFindByTriggers lists lessons whose trigger keywords appear in text
+func (a *App) runFindByTriggers(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall lesson find-by-triggers <text> [--format text|json]")
+		return 1
+	}
+
+	text := args[0]
+	format := "text"
+
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--format" && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		}
+	}
+
+	if format != "text" && format != "json" {
+		fmt.Fprintf(a.stderr, "error: unknown format %q (use text or json)\n", format)
+		return 1
+	}
+
+	store := a.lessonStore()
+	matched, err := store.FindByTriggers(text)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error matching triggers: %v\n", err)
+		return 1
+	}
+
+	if format == "json" {
+		data, err := json.Marshal(toLessonJSON(matched))
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error encoding output JSON: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(a.stdout, string(data))
+		return 0
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintln(a.stdout, "No lessons matched.")
+		return 0
+	}
+
+	for _, l := range matched {
+		fmt.Fprintf(a.stdout, "%s %s %s (triggers: %s)\n", l.ID, l.Rating(), l.Title, strings.Join(l.Triggers, ", "))
+	}
+
+	return 0
+}
+
+// runLessonGraph lists the lessons most often cited alongside a lesson
+func (a *App) runLessonGraph(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall lesson-graph <id>")
+		return 1
+	}
+	id := args[0]
+
+	graph, err := lessons.LoadCoGraph(lessons.CoGraphPath(a.stateDir))
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error loading co-citation graph: %v\n", err)
+		return 1
+	}
+	related := graph.Related(id)
+	if len(related) == 0 {
+		fmt.Fprintf(a.stdout, "No lessons co-cited with %s.\n", id)
+		return 0
+	}
+
+	store := a.lessonStore()
+	fmt.Fprintf(a.stdout, "Lessons co-cited with %s:\n", id)
+	for _, c := range related {
+		title := "(not found)"
+		if l, err := store.Get(c.ID); err == nil {
+			title = l.Title
+		}
+		fmt.Fprintf(a.stdout, "  %s  %3dx  %s\n", c.ID, c.Count, title)
+	}
+	return 0
+}
+
+// runLessonPrevented records that a lesson prevented a mistake, which
+// raises its inject ranking by the configured prevention weight
+func (a *App) runLessonPrevented(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall lesson-prevented <id> [--desc \"description\"]")
+		return 1
+	}
+	id := args[0]
+	var desc string
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--desc" && i+1 < len(args) {
+			desc = args[i+1]
+			i++
+		}
+	}
+
+	store := a.lessonStore()
+	if err := store.RecordPreventionNote(id, desc); err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+	lesson, err := store.Get(id)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "Recorded prevention for %s (%d total)\n", id, lesson.Preventions)
+	return 0
+}
+
+// lessonFieldDiff holds one metadata field's values in both lessons
+type lessonFieldDiff struct {
+	ID1 interface{} `json:"id1"`
+	ID2 interface{} `json:"id2"`
+}
+
+// lessonDiffOutput is the --json output of lesson diff. MetadataDiff only
+// has the fields that differ.
+type lessonDiffOutput struct {
+	ID1          string                     `json:"id1"`
+	ID2          string                     `json:"id2"`
+	ContentDiff  string                     `json:"content_diff"`
+	MetadataDiff map[string]lessonFieldDiff `json:"metadata_diff"`
+}
+
+// runLessonDiff compares two lessons, typically before merging them: a
+// unified diff of their content plus their title, category, uses, and
+// velocity, with differing fields marked
+func (a *App) runLessonDiff(args []string) int {
+	jsonOutput := false
+	var ids []string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		ids = append(ids, arg)
+	}
+	if len(ids) != 2 {
+		fmt.Fprintln(a.stderr, "usage: recall lesson diff <id1> <id2> [--json]")
+		return 1
+	}
+
+	store := a.lessonStore()
+	l1, err := store.Get(ids[0])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+	l2, err := store.Get(ids[1])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fields := []struct {
+		name     string
+		v1, v2   interface{}
+		from, to string
+	}{
+		{"title", l1.Title, l2.Title, l1.Title, l2.Title},
+		{"category", l1.Category, l2.Category, l1.Category, l2.Category},
+		{"uses", l1.Uses, l2.Uses, strconv.Itoa(l1.Uses), strconv.Itoa(l2.Uses)},
+		{"velocity", l1.Velocity, l2.Velocity, fmt.Sprintf("%.2f", l1.Velocity), fmt.Sprintf("%.2f", l2.Velocity)},
+	}
+	contentDiff := lessons.UnifiedDiff(l1.Content, l2.Content, l1.ID, l2.ID)
+
+	if jsonOutput {
+		out := lessonDiffOutput{ID1: l1.ID, ID2: l2.ID, ContentDiff: contentDiff, MetadataDiff: map[string]lessonFieldDiff{}}
+		for _, f := range fields {
+			if f.v1 != f.v2 {
+				out.MetadataDiff[f.name] = lessonFieldDiff{ID1: f.v1, ID2: f.v2}
+			}
+		}
+		return a.writeJSON(out)
+	}
+
+	fmt.Fprintf(a.stdout, "Comparing %s and %s\n\n", l1.ID, l2.ID)
+	for _, f := range fields {
+		marker := " "
+		if f.v1 != f.v2 {
+			marker = "*"
+		}
+		fmt.Fprintf(a.stdout, "%s %-9s %s | %s\n", marker, f.name, f.from, f.to)
+	}
+	fmt.Fprintln(a.stdout)
+	if contentDiff == "" {
+		fmt.Fprintln(a.stdout, "Content is identical.")
+		return 0
+	}
+	fmt.Fprint(a.stdout, contentDiff)
+	return 0
+}
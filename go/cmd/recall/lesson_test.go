@@ -0,0 +1,1204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/scoring"
+)
+
+// newTestApp creates an App with all paths pointed at a temp dir
+func newTestApp(t *testing.T) (*App, *lessons.Store, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
+	systemDir := filepath.Join(tmpDir, "system")
+	stateDir := filepath.Join(tmpDir, "state")
+	os.MkdirAll(projectDir, 0755)
+	os.MkdirAll(systemDir, 0755)
+	os.MkdirAll(stateDir, 0755)
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.projectPath = filepath.Join(projectDir, "LESSONS.md")
+	app.systemPath = filepath.Join(systemDir, "LESSONS.md")
+	app.handoffsPath = filepath.Join(projectDir, "HANDOFFS.md")
+	app.stealthPath = filepath.Join(projectDir, "HANDOFFS_LOCAL.md")
+	app.stateDir = stateDir
+	app.projectDir = filepath.Dir(projectDir)
+
+	return app, lessons.NewStore(app.projectPath, app.systemPath), &stdout, &stderr
+}
+
+func Test_LessonScoreLocal_Wildcard(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "pattern", "Pass context.Context first", "IO functions take ctx first")
+	store.Add("project", "gotcha", "Cancel derived contexts", "Always defer cancel after context.WithCancel")
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+
+	exitCode := app.Run([]string{"recall", "lesson", "score-local", "context*", "--top", "5"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "[L001]") || !strings.Contains(output, "[L002]") {
+		t.Errorf("expected both context lessons in output, got: %s", output)
+	}
+	if strings.Contains(output, "[L003]") {
+		t.Errorf("expected docker lesson to be filtered out, got: %s", output)
+	}
+}
+
+func Test_ScoreLocal_TFIDF(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "gotcha", "Goroutine leaks", "Cancel the context so the goroutine exits")
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+
+	exitCode := app.Run([]string{"recall", "score-local", "goroutine context", "--algo", "tfidf"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L001]") || strings.Contains(stdout.String(), "[L002]") {
+		t.Errorf("expected only goroutine lesson, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "local TF-IDF") {
+		t.Errorf("expected TF-IDF label, got: %s", stderr.String())
+	}
+
+	exitCode = app.Run([]string{"recall", "score-local", "goroutine", "--algo", "bogus"})
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1 for unknown algorithm, got %d", exitCode)
+	}
+}
+
+func Test_ScoreCommands_JSONSchema(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	store.Add("project", "gotcha", "Goroutine leaks", "Cancel the context so the goroutine exits goroutine")
+	store.Add("project", "pattern", "Context first", "Pass context as the first goroutine argument")
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+
+	// Seed the relevance cache so score-relevance needs no API call
+	cache := fmt.Sprintf(`{"entries": {"seed": {"normalized_query": "goroutine context", "scores": {"L001": 9, "L002": 6, "L003": 1}, "timestamp": %d}}}`,
+		time.Now().Unix())
+	os.WriteFile(filepath.Join(app.stateDir, "relevance-cache.json"), []byte(cache), 0644)
+
+	type result struct {
+		ID       *string  `json:"id"`
+		Score    *int     `json:"score"`
+		Title    *string  `json:"title"`
+		Content  *string  `json:"content"`
+		Uses     *int     `json:"uses"`
+		Velocity *float64 `json:"velocity"`
+	}
+	type output struct {
+		Query    string   `json:"query"`
+		Results  []result `json:"results"`
+		CacheHit *bool    `json:"cache_hit"`
+		Algo     string   `json:"algo"`
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		algo     string
+		cacheHit bool
+	}{
+		{[]string{"score-local", "goroutine context", "--json"}, "bm25", false},
+		{[]string{"score-local", "goroutine context", "--algo", "tfidf", "--json"}, "tfidf", false},
+		{[]string{"score-relevance", "goroutine context", "--json"}, "anthropic", true},
+	} {
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall"}, tc.args...)); code != 0 {
+			t.Fatalf("%v failed: %s", tc.args, stderr.String())
+		}
+		var out output
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("%v: invalid JSON %q: %v", tc.args, stdout.String(), err)
+		}
+		if out.Query != "goroutine context" || out.Algo != tc.algo || out.CacheHit == nil || *out.CacheHit != tc.cacheHit {
+			t.Errorf("%v: unexpected envelope %+v", tc.args, out)
+		}
+		if len(out.Results) < 2 {
+			t.Fatalf("%v: expected at least 2 results, got %d", tc.args, len(out.Results))
+		}
+		for i, r := range out.Results {
+			if r.ID == nil || r.Score == nil || r.Title == nil || r.Content == nil || r.Uses == nil || r.Velocity == nil {
+				t.Errorf("%v: result %d missing fields: %s", tc.args, i, stdout.String())
+				continue
+			}
+			if i > 0 && *out.Results[i-1].Score < *r.Score {
+				t.Errorf("%v: results not sorted by score descending: %s", tc.args, stdout.String())
+			}
+		}
+		if *out.Results[0].ID != "L001" {
+			t.Errorf("%v: expected L001 first, got %s", tc.args, *out.Results[0].ID)
+		}
+	}
+}
+
+func Test_ScoreLocal_CacheRebuildsWhenLessonsChange(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "gotcha", "Goroutine leaks", "Cancel the context so the goroutine exits")
+
+	indexPath := filepath.Join(app.stateDir, "bm25-index.json")
+	if exitCode := app.Run([]string{"recall", "score-local", "goroutine", "--cache"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Fatalf("expected index to be saved: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "[L001]") {
+		t.Errorf("expected L001 from fresh index, got: %s", stdout.String())
+	}
+
+	// Adding a lesson changes the checksum, so the next run must rebuild
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "score-local", "docker bridge", "--cache"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L002]") {
+		t.Errorf("expected rebuilt index to include L002, got: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "score-local", "docker", "--cache", "--algo", "tfidf"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for --cache with tfidf, got %d", exitCode)
+	}
+}
+
+func Test_ScoreLocal_CacheRebuildsWhenWorkspaceLessonsChange(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	app.workspacePath = filepath.Join(t.TempDir(), "LESSONS.md")
+	store := app.lessonStore()
+	store.Add("project", "gotcha", "Goroutine leaks", "Cancel the context so the goroutine exits")
+
+	if exitCode := app.Run([]string{"recall", "score-local", "goroutine", "--cache"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	// Only the workspace file changes; the index must still be rebuilt
+	store.Add(lessons.LevelWorkspace, "pattern", "Docker networking", "Containers use bridge networks")
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "score-local", "docker bridge", "--cache"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[W001]") {
+		t.Errorf("expected rebuilt index to include W001, got: %s", stdout.String())
+	}
+}
+
+func Test_LessonCommand_UnknownSubcommand(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	exitCode := app.Run([]string{"recall", "lesson", "bogus"})
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "unknown lesson subcommand") {
+		t.Errorf("expected unknown subcommand error, got: %s", stderr.String())
+	}
+}
+
+func Test_LessonSmartInject_ReranksByContext(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	// Heavily-cited lesson dominates plain inject
+	popular, _ := store.Add("project", "pattern", "Git commit hooks", "Run pre-commit hooks before pushing")
+	for i := 0; i < 10; i++ {
+		store.Cite(popular.ID)
+	}
+	store.Add("project", "gotcha", "Goroutine leaks", "Give every goroutine a cancellation path")
+
+	exitCode := app.Run([]string{"recall", "inject", "1"})
+	if exitCode != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Git commit hooks") {
+		t.Fatalf("expected plain inject to pick the popular lesson, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	exitCode = app.Run([]string{"recall", "lesson", "smart-inject", "1", "--context-summary", "fix goroutine cancellation leak"})
+	if exitCode != 0 {
+		t.Fatalf("smart-inject failed: %s", stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "Goroutine leaks") {
+		t.Errorf("expected context-relevant lesson, got: %s", output)
+	}
+	if strings.Contains(output, "Git commit hooks") {
+		t.Errorf("expected popular lesson to be displaced, got: %s", output)
+	}
+}
+
+func Test_Inject_Randomize(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	for i, title := range []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo", "Foxtrot"} {
+		l, _ := store.Add("project", "pattern", title, "Distinct content "+title)
+		for c := 0; c <= i; c++ {
+			store.Cite(l.ID)
+		}
+	}
+
+	inject := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "inject", "3", "--randomize"}, args...)); code != 0 {
+			t.Fatalf("inject failed: %s", stderr.String())
+		}
+		return stdout.String()
+	}
+
+	first := inject("--seed", "1")
+	if got := strings.Count(first, "### ["); got != 3 {
+		t.Fatalf("expected 3 lessons, got %d:\n%s", got, first)
+	}
+	if again := inject("--seed", "1"); again != first {
+		t.Errorf("expected the same seed to be deterministic:\n%s\nvs\n%s", first, again)
+	}
+	varied := false
+	for seed := 2; seed <= 10 && !varied; seed++ {
+		varied = inject("--seed", strconv.Itoa(seed)) != first
+	}
+	if !varied {
+		t.Error("expected different seeds to vary the injected lessons")
+	}
+
+	if code := app.Run([]string{"recall", "inject", "--randomize", "--query", "alpha"}); code != 1 {
+		t.Errorf("expected exit code 1 for --randomize with --query, got %d", code)
+	}
+}
+
+func Test_LessonSmartInject_TriggersIncluded(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "pattern", "Alpha", "Unrelated content one")
+	l2, _ := store.Add("project", "pattern", "Beta", "Unrelated content two")
+	store.Edit(l2.ID, map[string]interface{}{"triggers": []string{"deploy"}})
+
+	exitCode := app.Run([]string{"recall", "lesson", "smart-inject", "1", "--context-summary", "time to deploy"})
+	if exitCode != 0 {
+		t.Fatalf("smart-inject failed: %s", stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "[L002]") {
+		t.Errorf("expected trigger-matched L002, got: %s", stdout.String())
+	}
+}
+
+func Test_LessonFindByTriggers_JSON(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	l1, _ := store.Add("project", "gotcha", "Goroutine leaks", "Give every goroutine an exit")
+	store.Edit(l1.ID, map[string]interface{}{"triggers": []string{"goroutine"}})
+	store.Add("project", "pattern", "Unrelated", "Nothing to see")
+
+	exitCode := app.Run([]string{"recall", "lesson", "find-by-triggers", "goroutine leak in worker", "--format", "json"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	var result []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output JSON: %v. output: %s", err, stdout.String())
+	}
+	if len(result) != 1 || result[0]["id"] != "L001" {
+		t.Errorf("expected only L001, got %v", result)
+	}
+}
+
+func Test_LessonFindByTriggers_NoMatch(t *testing.T) {
+	app, store, stdout, _ := newTestApp(t)
+
+	store.Add("project", "pattern", "Unrelated", "Nothing to see")
+
+	exitCode := app.Run([]string{"recall", "lesson", "find-by-triggers", "goroutine"})
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "No lessons matched.") {
+		t.Errorf("expected no-match message, got: %s", stdout.String())
+	}
+}
+
+func Test_LessonTags_AddEditListInject(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	if exitCode := app.Run([]string{"recall", "add", "pattern", "Close response bodies", "Defer resp.Body.Close()", "--tag", "Go", "--tag", "http"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+
+	l, _ := store.Get("L001")
+	if strings.Join(l.Tags, ",") != "go,http" {
+		t.Errorf("expected tags [go http], got %v", l.Tags)
+	}
+
+	if exitCode := app.Run([]string{"recall", "edit", "L001", "--add-tag", "networking", "--remove-tag", "http"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	l, _ = store.Get("L001")
+	if strings.Join(l.Tags, ",") != "go,networking" {
+		t.Errorf("expected tags [go networking], got %v", l.Tags)
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "list", "--tag", "go"})
+	if !strings.Contains(stdout.String(), "L001") || strings.Contains(stdout.String(), "L002") {
+		t.Errorf("expected only L001 in tagged list, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "inject", "5", "--tag", "networking"})
+	if !strings.Contains(stdout.String(), "[L001]") || strings.Contains(stdout.String(), "[L002]") {
+		t.Errorf("expected only L001 injected, got: %s", stdout.String())
+	}
+}
+
+// stubGitProvider returns a fixed git context for add tests
+type stubGitProvider struct {
+	ctx *models.GitContext
+}
+
+func (p stubGitProvider) GetGitContext(dir string) (*models.GitContext, error) {
+	return p.ctx, nil
+}
+
+func Test_AddCommand_RecordsGitContext(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+	app.gitProvider = stubGitProvider{ctx: &models.GitContext{Branch: "main", ShortSHA: "abc1234"}}
+
+	if exitCode := app.Run([]string{"recall", "add", "pattern", "With git", "Records the commit"}); exitCode != 0 {
+		t.Fatalf("add failed: %s", stderr.String())
+	}
+	if exitCode := app.Run([]string{"recall", "add", "pattern", "Without git", "Skips the commit lookup", "--no-git"}); exitCode != 0 {
+		t.Fatalf("add --no-git failed: %s", stderr.String())
+	}
+
+	data, err := os.ReadFile(app.projectPath)
+	if err != nil {
+		t.Fatalf("read lessons: %v", err)
+	}
+	if !strings.Contains(string(data), "**Git**: main@abc1234") {
+		t.Errorf("expected Git metadata in LESSONS.md, got:\n%s", data)
+	}
+
+	withoutGit, err := store.Get("L002")
+	if err != nil {
+		t.Fatalf("get L002: %v", err)
+	}
+	if withoutGit.Git != nil {
+		t.Errorf("expected --no-git lesson to have no git context, got %v", withoutGit.Git)
+	}
+}
+
+func Test_LessonConfidence_RanksInjectAndFiltersList(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	// Two otherwise identical lessons differing only in confidence
+	fixture := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [**---|-----] Guessed approach
+- **Uses**: 5 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern | **Confidence**: 20
+> Same content
+
+### [L002] [**---|-----] Verified approach
+- **Uses**: 5 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern | **Confidence**: 90
+> Same content
+`
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	if exitCode := app.Run([]string{"recall", "inject", "1"}); exitCode != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L002]") || strings.Contains(stdout.String(), "[L001]") {
+		t.Errorf("expected high-confidence L002 to rank first, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "list", "--min-confidence", "50"}); exitCode != 0 {
+		t.Fatalf("list failed: %s", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "L001") || !strings.Contains(stdout.String(), "L002") {
+		t.Errorf("expected only L002 at min confidence 50, got: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "edit", "L001", "--confidence", "95"}); exitCode != 0 {
+		t.Fatalf("edit failed: %s", stderr.String())
+	}
+	edited, _ := store.Get("L001")
+	if edited.Confidence != 95 {
+		t.Errorf("expected confidence 95 after edit, got %d", edited.Confidence)
+	}
+
+	if exitCode := app.Run([]string{"recall", "add", "pattern", "Sure thing", "Checked twice", "--no-git", "--confidence", "80"}); exitCode != 0 {
+		t.Fatalf("add failed: %s", stderr.String())
+	}
+	added, _ := store.Get("L003")
+	if added.Confidence != 80 {
+		t.Errorf("expected confidence 80 on new lesson, got %d", added.Confidence)
+	}
+
+	if exitCode := app.Run([]string{"recall", "edit", "L001", "--confidence", "101"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for out-of-range confidence, got %d", exitCode)
+	}
+}
+
+func Test_LessonWeight_RanksInject(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	// L001 is rarely used but critical; L002 is used moderately
+	fixture := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Never force-push main
+- **Uses**: 2 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: gotcha | **Weight**: 5
+> Critical but rare
+
+### [L002] [**---|-----] Prefer table tests
+- **Uses**: 6 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern | **Weight**: 0.5
+> Common but minor
+`
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	if exitCode := app.Run([]string{"recall", "inject", "1"}); exitCode != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L001]") || strings.Contains(stdout.String(), "[L002]") {
+		t.Errorf("expected high-weight L001 to rank first, got: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "edit", "L001", "--weight", "1"}); exitCode != 0 {
+		t.Fatalf("edit failed: %s", stderr.String())
+	}
+	if exitCode := app.Run([]string{"recall", "edit", "L002", "--weight", "1"}); exitCode != 0 {
+		t.Fatalf("edit failed: %s", stderr.String())
+	}
+	edited, _ := store.Get("L001")
+	if edited.Weight != 1 {
+		t.Errorf("expected weight 1 after edit, got %g", edited.Weight)
+	}
+
+	// With default weights the ordering falls back to uses + velocity
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "inject", "1"}); exitCode != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L002]") || strings.Contains(stdout.String(), "[L001]") {
+		t.Errorf("expected higher-use L002 to rank first at default weight, got: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "add", "pattern", "Heavy", "Matters a lot", "--no-git", "--weight", "2.5"}); exitCode != 0 {
+		t.Fatalf("add failed: %s", stderr.String())
+	}
+	added, _ := store.Get("L003")
+	if added.Weight != 2.5 {
+		t.Errorf("expected weight 2.5 on new lesson, got %g", added.Weight)
+	}
+
+	for _, bad := range []string{"0", "-1", "abc"} {
+		if exitCode := app.Run([]string{"recall", "edit", "L001", "--weight", bad}); exitCode != 1 {
+			t.Errorf("expected exit code 1 for weight %q, got %d", bad, exitCode)
+		}
+	}
+}
+
+func Test_InjectScore_DefaultWeightPreservesOrdering(t *testing.T) {
+	var all []*models.Lesson
+	for i, uses := range []int{3, 9, 1, 9, 5} {
+		l := models.NewLesson(fmt.Sprintf("L%03d", i+1), "t", "c")
+		l.Uses = uses
+		l.Velocity = float64(i) * 0.3
+		all = append(all, l)
+	}
+
+	weighted := append([]*models.Lesson(nil), all...)
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return scoring.InjectScore(weighted[i], 0) > scoring.InjectScore(weighted[j], 0)
+	})
+	unweighted := append([]*models.Lesson(nil), all...)
+	sort.SliceStable(unweighted, func(i, j int) bool {
+		return float64(unweighted[i].Uses)+unweighted[i].Velocity > float64(unweighted[j].Uses)+unweighted[j].Velocity
+	})
+
+	for i := range weighted {
+		if weighted[i].ID != unweighted[i].ID {
+			t.Fatalf("ordering differs at %d: %s vs %s", i, weighted[i].ID, unweighted[i].ID)
+		}
+	}
+}
+
+func Test_Inject_SourceShared(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Local lesson", "Only in this project")
+
+	sharedPath := filepath.Join(t.TempDir(), "LESSONS.md")
+	os.WriteFile(sharedPath, []byte(`# LESSONS.md - Project Level
+
+### [L050] [*----|-----] Team lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-01 | **Category**: pattern
+> Shared across the team
+`), 0644)
+	app.sharedPaths = []string{sharedPath}
+
+	if code := app.Run([]string{"recall", "inject", "--source", "shared"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Team lesson") || strings.Contains(stdout.String(), "Local lesson") {
+		t.Errorf("expected only shared lessons, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "inject"})
+	if !strings.Contains(stdout.String(), "Team lesson") || !strings.Contains(stdout.String(), "Local lesson") {
+		t.Errorf("expected local and shared lessons, got:\n%s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "inject", "--source", "bogus"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid source, got %d", code)
+	}
+}
+
+func Test_WorkspaceLevel_AddListInject(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	app.workspacePath = filepath.Join(t.TempDir(), "LESSONS.md")
+
+	for _, args := range [][]string{
+		{"pattern", "Project habit", "Only here"},
+		{"pattern", "System habit", "Everywhere for me", "--system"},
+		{"decision", "Team habit", "Everywhere for the team", "--workspace"},
+	} {
+		if code := app.Run(append([]string{"recall", "add"}, args...)); code != 0 {
+			t.Fatalf("add %v failed: %s", args, stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "list", "--level", "workspace"}); code != 0 {
+		t.Fatalf("list failed: %s", stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "W001") || strings.Contains(out, "L001") || strings.Contains(out, "S001") {
+		t.Errorf("expected only W001 at workspace level, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	sections := []string{"### Workspace Lessons", "[W001]", "### System Lessons", "[S001]", "### Project Lessons", "[L001]"}
+	last := -1
+	for _, section := range sections {
+		i := strings.Index(out, section)
+		if i <= last {
+			t.Fatalf("expected %q after previous sections, got:\n%s", section, out)
+		}
+		last = i
+	}
+}
+
+func Test_LessonGraph_AndInjectCoCited(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Anchor lesson", "Cited with friends")
+	store.Add("project", "pattern", "Close friend", "Cited together often")
+	store.Add("project", "pattern", "Acquaintance", "Cited together once")
+	store.Add("project", "pattern", "Stranger", "Never cited together")
+
+	for _, batch := range [][]string{{"L001", "L002"}, {"L001", "L002", "L003"}, {"L002", "L001"}} {
+		if err := lessons.RecordCoCitations(app.stateDir, batch); err != nil {
+			t.Fatalf("RecordCoCitations failed: %v", err)
+		}
+	}
+
+	if code := app.Run([]string{"recall", "lesson-graph", "L001"}); code != 0 {
+		t.Fatalf("lesson-graph failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "L002    3x  Close friend") || !strings.Contains(out, "L003    1x  Acquaintance") {
+		t.Errorf("unexpected lesson-graph output:\n%s", out)
+	}
+	if strings.Index(out, "L002") > strings.Index(out, "L003") || strings.Contains(out, "L004") {
+		t.Errorf("expected L002 before L003 and no L004:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "--co-cited", "L001"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	out = stdout.String()
+	if strings.Count(out, "### [") != 2 || strings.Index(out, "[L002]") > strings.Index(out, "[L003]") {
+		t.Errorf("expected L002 then L003 only, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "lesson-graph", "L004"})
+	if !strings.Contains(stdout.String(), "No lessons co-cited with L004") {
+		t.Errorf("expected empty graph message, got: %s", stdout.String())
+	}
+}
+
+func Test_Inject_QueryRanksByRelevance(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	// L001 is by far the most used; L002 is the one about the query topic
+	fixture := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [****-|-----] Keep commits small
+- **Uses**: 40 | **Velocity**: 5 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> One logical change per commit
+
+### [L002] [*----|-----] Postgres migrations need locks
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: gotcha
+> Run postgres schema migrations with a lock timeout
+
+### [L003] [**---|-----] Prefer table tests
+- **Uses**: 10 | **Velocity**: 1 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> Table-driven tests keep cases readable
+`
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	if code := app.Run([]string{"recall", "inject", "1"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[L001]") {
+		t.Errorf("expected most-used L001 without a query, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "2", "--query", "postgres migration lock"}); code != 0 {
+		t.Fatalf("inject --query failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	first := strings.Index(out, "### [")
+	if first < 0 || !strings.HasPrefix(out[first:], "### [L002]") {
+		t.Errorf("expected relevant L002 first, got:\n%s", out)
+	}
+	if got := strings.Count(out, "### ["); got != 2 {
+		t.Errorf("expected 2 lessons, got %d", got)
+	}
+}
+
+func Test_Inject_MaxTokens(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	for i := 0; i < 5; i++ {
+		store.Add("project", "pattern", fmt.Sprintf("Lesson %d", i), strings.Repeat("padding ", 20))
+	}
+	app.debugLevel = 1
+
+	if code := app.Run([]string{"recall", "inject", "--max-tokens", "60"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	injected := strings.Count(stdout.String(), "### [")
+	if injected == 0 || injected >= 5 {
+		t.Errorf("expected budget to keep some but not all of 5 lessons, got %d:\n%s", injected, stdout.String())
+	}
+	if tokens := estimateTokens(stdout.String()); tokens > 60 {
+		t.Errorf("expected output within 60 tokens, got %d", tokens)
+	}
+
+	logData, _ := os.ReadFile(filepath.Join(app.stateDir, "recall.log"))
+	if !strings.Contains(string(logData), `"event":"injection_budget_dropped"`) ||
+		!strings.Contains(string(logData), fmt.Sprintf(`"dropped":%d`, 5-injected)) {
+		t.Errorf("expected budget drop in debug log, got: %s", logData)
+	}
+
+	// The config budget applies when no flag is given; 0 means unlimited
+	stdout.Reset()
+	app.maxTokens = 60
+	app.Run([]string{"recall", "inject"})
+	if got := strings.Count(stdout.String(), "### ["); got != injected {
+		t.Errorf("expected config budget to keep %d lessons, got %d", injected, got)
+	}
+	stdout.Reset()
+	app.Run([]string{"recall", "inject", "--max-tokens", "0"})
+	if got := strings.Count(stdout.String(), "### ["); got != 5 {
+		t.Errorf("expected --max-tokens 0 to keep all 5 lessons, got %d", got)
+	}
+
+	if code := app.Run([]string{"recall", "inject", "--max-tokens", "lots"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid --max-tokens, got %d", code)
+	}
+}
+
+func Test_Inject_RecencyWeight(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	recent := time.Now().Format("2006-01-02")
+	fixture := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*****|*****] Old favourite
+- **Uses**: 40 | **Velocity**: 3 | **Learned**: 2024-01-01 | **Last**: 2024-02-01 | **Category**: pattern
+> Cited a lot, long ago
+
+### [L002] [*----|-----] Fresh lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: ` + recent + ` | **Last**: ` + recent + ` | **Category**: pattern
+> Cited once, today
+`
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	inject := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "inject", "2"}, args...)); code != 0 {
+			t.Fatalf("inject failed: %s", stderr.String())
+		}
+		return stdout.String()
+	}
+
+	if out := inject(); strings.Index(out, "[L001]") > strings.Index(out, "[L002]") {
+		t.Errorf("expected high-use L001 first without recency weight, got:\n%s", out)
+	}
+	if out := inject("--recency-weight", "1.0"); strings.Index(out, "[L002]") > strings.Index(out, "[L001]") {
+		t.Errorf("expected recent L002 first with --recency-weight 1.0, got:\n%s", out)
+	}
+
+	// The config weight applies when no flag is given
+	app.recencyWeight = 1.0
+	if out := inject(); strings.Index(out, "[L002]") > strings.Index(out, "[L001]") {
+		t.Errorf("expected config recency weight to rank L002 first, got:\n%s", out)
+	}
+
+	for _, bad := range []string{"1.5", "-0.1", "recent"} {
+		if code := app.Run([]string{"recall", "inject", "--recency-weight", bad}); code != 1 {
+			t.Errorf("expected exit code 1 for --recency-weight %s, got %d", bad, code)
+		}
+	}
+}
+
+func Test_Cite_FromFile(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	l1, _ := store.Add("project", "pattern", "First lesson", "Alpha content")
+	l2, _ := store.Add("project", "gotcha", "Second lesson", "Beta material")
+
+	idFile := filepath.Join(t.TempDir(), "ids.txt")
+	os.WriteFile(idFile, []byte("# lessons used this session\n"+l1.ID+"\n\nL999\n"+l2.ID+"\nS999\n"), 0644)
+
+	if code := app.Run([]string{"recall", "cite", "--file", idFile}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (%s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Cited 2 lessons (2 not found)") {
+		t.Errorf("unexpected summary: %s", stdout.String())
+	}
+	for _, id := range []string{l1.ID, l2.ID} {
+		if l, _ := store.Get(id); l.Uses != 1 {
+			t.Errorf("expected %s uses 1, got %d", id, l.Uses)
+		}
+	}
+
+	// Every citation failing is an error
+	os.WriteFile(idFile, []byte("L998\nL999\n"), 0644)
+	if code := app.Run([]string{"recall", "cite", "--file", idFile}); code != 1 {
+		t.Errorf("expected exit code 1 when all citations fail, got %d", code)
+	}
+}
+
+func Test_Cite_SessionAttribution(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+	store.Add("project", "pattern", "First lesson", "Alpha content")
+	store.Add("project", "gotcha", "Second lesson", "Beta material")
+
+	if code := app.Run([]string{"recall", "cite", "L001", "--session", "abc123"}); code != 0 {
+		t.Fatalf("cite with --session failed: %s", stderr.String())
+	}
+	// Omitting --session still cites
+	if code := app.Run([]string{"recall", "cite", "L002"}); code != 0 {
+		t.Fatalf("cite without --session failed: %s", stderr.String())
+	}
+
+	events, err := lessons.NewAuditLog(app.stateDir).Read()
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	sessions := map[string]string{}
+	for _, e := range events {
+		if e.Event == lessons.AuditCite {
+			sessions[e.LessonID] = e.SessionID
+		}
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 cite events, got %+v", events)
+	}
+	if sessions["L001"] != "abc123" {
+		t.Errorf("expected L001 cite from session abc123, got %q", sessions["L001"])
+	}
+	if sessions["L002"] != "" {
+		t.Errorf("expected no session for L002 cite, got %q", sessions["L002"])
+	}
+
+	data, _ := os.ReadFile(filepath.Join(app.stateDir, lessons.AuditLogFile))
+	if !strings.Contains(string(data), `"session_id":"abc123"`) {
+		t.Errorf("expected session_id in audit log:\n%s", data)
+	}
+	if l, _ := store.Get("L001"); l.Uses != 1 {
+		t.Errorf("expected L001 uses 1, got %d", l.Uses)
+	}
+}
+
+func Test_EditBulk(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	for _, title := range []string{"One", "Two", "Three", "Four", "Five"} {
+		store.Add("project", "pattern", title, "Original "+title)
+	}
+
+	bulk := `[{"id":"L001","title":"One renamed"},
+		{"id":"L003","content":"Rewritten three"},
+		{"id":"L005","title":"Five renamed","content":"Rewritten five"}]`
+	if code := app.Run([]string{"recall", "edit", "--bulk", bulk}); code != 0 {
+		t.Fatalf("bulk edit failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Updated 3 lessons, 0 not found") {
+		t.Errorf("unexpected summary: %s", stdout.String())
+	}
+
+	want := map[string][2]string{
+		"L001": {"One renamed", "Original One"},
+		"L002": {"Two", "Original Two"},
+		"L003": {"Three", "Rewritten three"},
+		"L004": {"Four", "Original Four"},
+		"L005": {"Five renamed", "Rewritten five"},
+	}
+	for id, w := range want {
+		l, _ := store.Get(id)
+		if l.Title != w[0] || l.Content != w[1] {
+			t.Errorf("%s = %q / %q, want %q / %q", id, l.Title, l.Content, w[0], w[1])
+		}
+	}
+
+	// One bad ID fails the whole bulk with nothing written
+	stdout.Reset()
+	bulk = `[{"id":"L002","title":"Two renamed"},{"id":"L999","title":"Missing"}]`
+	if code := app.Run([]string{"recall", "edit", "--bulk", bulk}); code != 1 {
+		t.Fatalf("expected exit code 1 with a missing ID, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "Updated 0 lessons, 1 not found") || !strings.Contains(stderr.String(), "L999") {
+		t.Errorf("unexpected output: %s / %s", stdout.String(), stderr.String())
+	}
+	if l, _ := store.Get("L002"); l.Title != "Two" {
+		t.Errorf("expected no partial write, got L002 title %q", l.Title)
+	}
+
+	if code := app.Run([]string{"recall", "edit", "--bulk", "not json"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid JSON, got %d", code)
+	}
+}
+
+func Test_CategoryRename_DryRun(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	l, _ := store.Add("project", "gotcha", "Watch the nil map", "Writing to a nil map panics")
+
+	if code := app.Run([]string{"recall", "category", "rename", "gotcha", "pitfall", "--dry-run"}); code != 0 {
+		t.Fatalf("dry run failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Would rename 1 lessons") {
+		t.Errorf("unexpected dry-run output: %s", stdout.String())
+	}
+	if got, _ := store.Get(l.ID); got.Category != "gotcha" {
+		t.Errorf("dry run changed category to %q", got.Category)
+	}
+
+	if code := app.Run([]string{"recall", "category", "rename", "gotcha", "pitfall"}); code != 0 {
+		t.Fatalf("rename failed: %s", stderr.String())
+	}
+	if got, _ := store.Get(l.ID); got.Category != "pitfall" {
+		t.Errorf("expected category pitfall, got %q", got.Category)
+	}
+
+	if code := app.Run([]string{"recall", "category", "rename", "pitfall", "pit fall", "--dry-run"}); code != 1 {
+		t.Errorf("expected exit code 1 for an invalid name, got %d", code)
+	}
+}
+
+func Test_AuditList_FiltersByLesson(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	app.Run([]string{"recall", "add", "pattern", "First lesson", "Alpha content", "--no-git"})
+	app.Run([]string{"recall", "add", "gotcha", "Second lesson", "Beta material", "--no-git"})
+	app.Run([]string{"recall", "cite", "L002"})
+	stdout.Reset()
+
+	if code := app.Run([]string{"recall", "audit", "list", "--lesson", "L002"}); code != 0 {
+		t.Fatalf("audit list failed: %s", stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, `add     L002  title: "" -> "Second lesson"`) ||
+		!strings.Contains(output, `cite    L002  uses: "0" -> "1"`) {
+		t.Errorf("unexpected audit output:\n%s", output)
+	}
+	if strings.Contains(output, "L001") {
+		t.Errorf("expected L001 filtered out:\n%s", output)
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "audit", "list", "--since", "2999-01-01"})
+	if !strings.Contains(stdout.String(), "No audit events found.") {
+		t.Errorf("expected no events after future date, got:\n%s", stdout.String())
+	}
+}
+
+func Test_LessonPrevented_ChangesSortOrder(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Often cited", "Cited a lot")
+	store.Add("project", "pattern", "Quiet guard", "Rarely cited but catches bugs")
+	store.Add("project", "pattern", "Occasional guard", "Sometimes catches bugs")
+	for i := 0; i < 2; i++ {
+		store.Cite("L001")
+	}
+
+	if code := app.Run([]string{"recall", "inject", "3"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if out := stdout.String(); strings.Index(out, "[L001]") > strings.Index(out, "[L002]") {
+		t.Fatalf("expected L001 first before any preventions:\n%s", out)
+	}
+
+	for _, args := range [][]string{
+		{"L002", "--desc", "stopped a nil map write"},
+		{"L002"},
+		{"L002"},
+		{"L003"},
+	} {
+		if code := app.Run(append([]string{"recall", "lesson-prevented"}, args...)); code != 0 {
+			t.Fatalf("lesson-prevented %v failed: %s", args, stderr.String())
+		}
+	}
+	if !strings.Contains(stdout.String(), "Recorded prevention for L002 (3 total)") {
+		t.Errorf("unexpected lesson-prevented output:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "list", "--sort-by", "preventions"}); code != 0 {
+		t.Fatalf("list failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !(strings.Index(out, "L002") < strings.Index(out, "L003") && strings.Index(out, "L003") < strings.Index(out, "L001")) {
+		t.Errorf("expected L002, L003, L001 by preventions:\n%s", out)
+	}
+
+	// 3 preventions at weight 2 (6) outrank 2 uses + 2 velocity (4)
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "3"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if out := stdout.String(); strings.Index(out, "[L002]") > strings.Index(out, "[L001]") {
+		t.Errorf("expected L002 to outrank L001 after preventions:\n%s", out)
+	}
+
+	if code := app.Run([]string{"recall", "list", "--sort-by", "bogus"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown sort key, got %d", code)
+	}
+}
+
+func Test_LessonDiff(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping\nKeep messages lowercase")
+	store.Add("project", "gotcha", "Error messages", "Use %w when wrapping\nStart messages with the operation")
+
+	if code := app.Run([]string{"recall", "lesson", "diff", "L001", "L002"}); code != 0 {
+		t.Fatalf("lesson diff failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"* title     Wrap errors | Error messages\n",
+		"* category  pattern | gotcha\n",
+		"  uses      0 | 0\n",
+		"--- L001\n+++ L002\n",
+		" Use %w when wrapping\n-Keep messages lowercase\n+Start messages with the operation\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "lesson", "diff", "L001", "L002", "--json"}); code != 0 {
+		t.Fatalf("lesson diff --json failed: %s", stderr.String())
+	}
+	var got lessonDiffOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if got.ID1 != "L001" || got.ID2 != "L002" || !strings.Contains(got.ContentDiff, "+Start messages with the operation") {
+		t.Errorf("unexpected diff output: %+v", got)
+	}
+	if len(got.MetadataDiff) != 2 || got.MetadataDiff["category"].ID2 != "gotcha" || got.MetadataDiff["title"].ID1 != "Wrap errors" {
+		t.Errorf("expected only title and category to differ, got %+v", got.MetadataDiff)
+	}
+
+	if code := app.Run([]string{"recall", "lesson", "diff", "L001", "L999"}); code != 1 {
+		t.Errorf("expected exit code 1 for missing lesson, got %d", code)
+	}
+}
+
+func Test_Inject_Formats(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	for i := 0; i < 10; i++ {
+		store.Cite("L001")
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"condensed", "Recent Lessons:\n[L001|⭐⭐⭐|pattern] Wrap errors: Use %w when wrapping\n"},
+		{"plain", "Recent Lessons\n\n[L001] [***--|"},
+		{"markdown", "## Recent Lessons\n\n### [L001] [***--|"},
+	}
+	for _, tt := range tests {
+		stdout.Reset()
+		if code := app.Run([]string{"recall", "inject", "--format", tt.format}); code != 0 {
+			t.Fatalf("inject --format %s failed: %s", tt.format, stderr.String())
+		}
+		out := stdout.String()
+		if !strings.HasPrefix(out, tt.want) {
+			t.Errorf("--format %s: expected output to start with %q, got:\n%s", tt.format, tt.want, out)
+		}
+		if tt.format == "plain" && (strings.Contains(out, "#") || strings.Contains(out, ">")) {
+			t.Errorf("--format plain output contains Markdown:\n%s", out)
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "--format", "json"}); code != 0 {
+		t.Fatalf("inject --format json failed: %s", stderr.String())
+	}
+	var got []injectJSON
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(got) != 1 || got[0].ID != "L001" || got[0].Title != "Wrap errors" || got[0].Content != "Use %w when wrapping" ||
+		got[0].Category != "pattern" || !strings.HasPrefix(got[0].Rating, "[***--|") {
+		t.Errorf("unexpected JSON output: %+v", got)
+	}
+
+	if code := app.Run([]string{"recall", "inject", "--format", "html"}); code != 1 {
+		t.Errorf("expected invalid format to fail, got %d", code)
+	}
+}
+
+func Test_List_UsedPeriodFilters(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	// Wednesday
+	app.now = func() time.Time { return time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local) }
+	lesson := func(id, title, last string, uses int) string {
+		return fmt.Sprintf("### [%s] [*----|-----] %s\n- **Uses**: %d | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: %s | **Category**: pattern\n> Content\n\n",
+			id, title, uses, last)
+	}
+	fixture := "# LESSONS.md - Project Level\n\n## Active Lessons\n\n" +
+		lesson("L001", "Used today", "2026-03-18", 3) +
+		lesson("L002", "Used Monday", "2026-03-16", 2) +
+		lesson("L003", "Used this month", "2026-03-02", 1) +
+		lesson("L004", "Used last month", "2026-02-10", 0)
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	for _, tt := range []struct {
+		flag string
+		want []string
+	}{
+		{"--used-today", []string{"L001"}},
+		{"--used-this-week", []string{"L001", "L002"}},
+		{"--used-this-month", []string{"L001", "L002", "L003"}},
+		{"--never-used", []string{"L004"}},
+	} {
+		stdout.Reset()
+		if code := app.Run([]string{"recall", "list", tt.flag}); code != 0 {
+			t.Fatalf("list %s failed: %s", tt.flag, stderr.String())
+		}
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+			got = append(got, strings.Fields(line)[0])
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("list %s = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func Test_Inject_Template(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	app.baseDir = t.TempDir()
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	store.Add("project", "gotcha", "Close bodies", "Always defer resp.Body.Close()")
+
+	tmplDir := injectTemplateDir(app.baseDir)
+	os.MkdirAll(tmplDir, 0755)
+	os.WriteFile(filepath.Join(tmplDir, lessonsTemplateName),
+		[]byte("Top {{.TopN}}:\n{{range $i, $l := .Lessons}}{{inc $i}}. {{$l.Title}} ({{$l.ID}})\n{{end}}"), 0644)
+
+	if code := app.Run([]string{"recall", "inject", "2"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.HasPrefix(out, "Top 2:\n1. ") || !strings.Contains(out, "2. ") || strings.Contains(out, "## Recent Lessons") {
+		t.Errorf("expected numbered template output, got:\n%s", out)
+	}
+
+	// --template overrides the base template
+	custom := filepath.Join(t.TempDir(), "ids.tmpl")
+	os.WriteFile(custom, []byte("{{range .Lessons}}{{.ID}};{{end}}"), 0644)
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "--template", custom}); code != 0 {
+		t.Fatalf("inject --template failed: %s", stderr.String())
+	}
+	if got := stdout.String(); got != "L001;L002;" && got != "L002;L001;" {
+		t.Errorf("expected --template output, got %q", got)
+	}
+
+	// --max-tokens budgets the template's output: each line here is ~25 tokens
+	padded := filepath.Join(t.TempDir(), "padded.tmpl")
+	os.WriteFile(padded, []byte(`{{range .Lessons}}{{printf "%-96s" .ID}}{{"\n"}}{{end}}`), 0644)
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "inject", "--template", padded, "--max-tokens", "30"}); code != 0 {
+		t.Fatalf("inject --max-tokens failed: %s", stderr.String())
+	}
+	if lines := strings.Count(stdout.String(), "\n"); lines != 1 {
+		t.Errorf("expected 1 lesson within the budget, got %d:\n%s", lines, stdout.String())
+	}
+
+	// A default template that fails to parse falls back to the built-in format
+	os.WriteFile(filepath.Join(tmplDir, lessonsTemplateName), []byte("{{range .Lessons}"), 0644)
+	stdout.Reset()
+	stderr.Reset()
+	if code := app.Run([]string{"recall", "inject"}); code != 0 {
+		t.Fatalf("inject with a broken default template failed: %s", stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), injectLessonsHeader) || !strings.Contains(stderr.String(), "warning:") {
+		t.Errorf("expected a warning and the built-in format, got stdout:\n%s\nstderr:\n%s", stdout.String(), stderr.String())
+	}
+
+	// Without the base template, the built-in format is used
+	os.Remove(filepath.Join(tmplDir, lessonsTemplateName))
+	stdout.Reset()
+	app.Run([]string{"recall", "inject"})
+	if !strings.HasPrefix(stdout.String(), injectLessonsHeader) {
+		t.Errorf("expected built-in format, got:\n%s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "inject", "--template", filepath.Join(tmplDir, "missing.tmpl")}); code != 1 {
+		t.Errorf("expected exit code 1 for a missing --template, got %d", code)
+	}
+}
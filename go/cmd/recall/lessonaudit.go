@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/anthropic"
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// lessonAuditProgressFile records where a batched lesson audit left off
+const lessonAuditProgressFile = "lesson-audit-progress.json"
+
+// lessonAuditTimeout bounds each --auto-audit scoring request
+const lessonAuditTimeout = 60 * time.Second
+
+// defaultAuditMinScore is the usefulness score (0-10) below which
+// --auto-audit flags a lesson for deletion
+const defaultAuditMinScore = 4
+
+// lessonAuditProgress is the saved position of a batched audit
+type lessonAuditProgress struct {
+	LastID string `json:"last_id"` // Last lesson reviewed; the next batch starts after it
+}
+
+// runLessonAudit walks lessons (optionally only stale ones) asking whether
+// to keep, edit, or delete each. With --batch-size N only N lessons are
+// reviewed per run and the position is saved in the state dir so the next
+// run picks up where this one stopped. --auto-audit scores the lessons via
+// the API instead of prompting and only reports low scorers.
+func (a *App) runLessonAudit(args []string) int {
+	var lastUsedBefore time.Time
+	minAge, batchSize := 0, 0
+	minScore := defaultAuditMinScore
+	auto := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--last-used-before" && i+1 < len(args):
+			t, err := time.Parse("2006-01-02", args[i+1])
+			if err != nil {
+				fmt.Fprintf(a.stderr, "error: invalid --last-used-before date %q (use YYYY-MM-DD)\n", args[i+1])
+				return 1
+			}
+			lastUsedBefore = t
+			i++
+		case args[i] == "--min-age" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				fmt.Fprintf(a.stderr, "error: invalid --min-age '%s'\n", args[i+1])
+				return 1
+			}
+			minAge = n
+			i++
+		case args[i] == "--batch-size" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(a.stderr, "error: invalid --batch-size '%s'\n", args[i+1])
+				return 1
+			}
+			batchSize = n
+			i++
+		case args[i] == "--min-score" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 || n > 10 {
+				fmt.Fprintf(a.stderr, "error: invalid --min-score '%s' (use 0-10)\n", args[i+1])
+				return 1
+			}
+			minScore = n
+			i++
+		case args[i] == "--auto-audit":
+			auto = true
+		}
+	}
+
+	store := a.lessonStore()
+	all, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	now := a.clock()
+	var candidates []*models.Lesson
+	for _, l := range all {
+		// Shared lessons belong to their own repos and can't be edited here
+		if l.Level == lessons.LevelShared {
+			continue
+		}
+		if !lastUsedBefore.IsZero() && !l.LastUsed.Before(lastUsedBefore) {
+			continue
+		}
+		if minAge > 0 && now.Sub(l.Learned) < time.Duration(minAge)*24*time.Hour {
+			continue
+		}
+		candidates = append(candidates, l)
+	}
+
+	// List is sorted by ID, so a batch resumes after the last ID reviewed
+	var progress lessonAuditProgress
+	if batchSize > 0 {
+		progress = a.loadLessonAuditProgress()
+		start := 0
+		for start < len(candidates) && candidates[start].ID <= progress.LastID {
+			start++
+		}
+		candidates = candidates[start:]
+		if len(candidates) == 0 && progress.LastID != "" {
+			a.saveLessonAuditProgress(lessonAuditProgress{})
+			fmt.Fprintln(a.stdout, "Audit complete: every lesson has been reviewed. The next run starts over.")
+			return 0
+		}
+		if len(candidates) > batchSize {
+			candidates = candidates[:batchSize]
+		}
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(a.stdout, "No lessons to audit.")
+		return 0
+	}
+
+	var reviewed int
+	if auto {
+		reviewed, err = a.autoAuditLessons(candidates, minScore)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error scoring lessons: %v\n", err)
+			return 1
+		}
+	} else {
+		reviewed, err = a.reviewLessons(store, candidates)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 1
+		}
+	}
+
+	if batchSize > 0 && reviewed > 0 {
+		progress.LastID = candidates[reviewed-1].ID
+		if err := a.saveLessonAuditProgress(progress); err != nil {
+			fmt.Fprintf(a.stderr, "warning: failed to save audit progress: %v\n", err)
+		}
+	}
+	return 0
+}
+
+// reviewLessons prompts on stdin to keep, edit, or delete each lesson and
+// returns how many were reviewed before the user quit or input ran out
+func (a *App) reviewLessons(store *lessons.Store, candidates []*models.Lesson) (int, error) {
+	in := bufio.NewReader(a.stdin)
+	kept, edited, deleted := 0, 0, 0
+	reviewed := 0
+
+review:
+	for _, l := range candidates {
+		fmt.Fprintf(a.stdout, "\n[%s] %s %s (%s)\n", l.ID, l.Rating(), l.Title, l.Category)
+		fmt.Fprintf(a.stdout, "> %s\n", l.Content)
+		fmt.Fprintf(a.stdout, "Learned: %s | Last used: %s | Uses: %d\n",
+			l.Learned.Format("2006-01-02"), l.LastUsed.Format("2006-01-02"), l.Uses)
+
+		for {
+			fmt.Fprint(a.stdout, "[k]eep, [e]dit, [d]elete, [q]uit? ")
+			answer, err := in.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if err != nil && answer == "" {
+				break review // Out of input: stop like quit
+			}
+
+			switch answer {
+			case "k", "keep", "":
+				kept++
+			case "e", "edit":
+				fmt.Fprint(a.stdout, "New content (blank to keep): ")
+				content, _ := in.ReadString('\n')
+				content = strings.TrimSpace(content)
+				if content == "" || content == l.Content {
+					kept++
+					break
+				}
+				if err := store.Edit(l.ID, map[string]interface{}{"content": content}); err != nil {
+					return reviewed, fmt.Errorf("editing %s: %w", l.ID, err)
+				}
+				edited++
+			case "d", "delete":
+				if err := store.Delete(l.ID); err != nil {
+					return reviewed, fmt.Errorf("deleting %s: %w", l.ID, err)
+				}
+				deleted++
+			case "q", "quit":
+				break review
+			default:
+				continue
+			}
+			break
+		}
+		reviewed++
+	}
+
+	fmt.Fprintf(a.stdout, "\nReviewed %d lessons: %d kept, %d edited, %d deleted\n", reviewed, kept, edited, deleted)
+	return reviewed, nil
+}
+
+// autoAuditLessons scores candidates via the API and reports each score,
+// flagging those below minScore. Nothing is changed.
+func (a *App) autoAuditLessons(candidates []*models.Lesson, minScore int) (int, error) {
+	score := a.scoreUsefulness
+	if score == nil {
+		score = func(l []*models.Lesson) (map[string]int, error) {
+			return anthropic.ScoreUsefulness(l, lessonAuditTimeout)
+		}
+	}
+	scores, err := score(candidates)
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for _, l := range candidates {
+		s, ok := scores[l.ID]
+		if !ok {
+			fmt.Fprintf(a.stdout, "[%s]  ?/10 %s\n", l.ID, l.Title)
+			continue
+		}
+		note := ""
+		if s < minScore {
+			note = " (consider deleting)"
+			flagged++
+		}
+		fmt.Fprintf(a.stdout, "[%s] %2d/10 %s%s\n", l.ID, s, l.Title, note)
+	}
+	fmt.Fprintf(a.stdout, "\n%d of %d lessons scored below %d\n", flagged, len(candidates), minScore)
+	return len(candidates), nil
+}
+
+// loadLessonAuditProgress reads the saved audit position (zero if none)
+func (a *App) loadLessonAuditProgress() lessonAuditProgress {
+	var progress lessonAuditProgress
+	data, err := os.ReadFile(filepath.Join(a.stateDir, lessonAuditProgressFile))
+	if err == nil {
+		json.Unmarshal(data, &progress)
+	}
+	return progress
+}
+
+// saveLessonAuditProgress writes the audit position to the state dir
+func (a *App) saveLessonAuditProgress(progress lessonAuditProgress) error {
+	if err := os.MkdirAll(a.stateDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(a.stateDir, lessonAuditProgressFile), data, 0644)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func Test_LessonAudit_KeepEditDelete(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	store.Add("project", "gotcha", "Old build flag", "Pass --legacy to the build")
+	store.Add("project", "decision", "Use sqlite", "Local state lives in sqlite")
+
+	// Unknown answers re-prompt; edit reads the new content on the next line
+	app.stdin = strings.NewReader("k\nx\ne\nPass --modern to the build\nd\n")
+	if code := app.Run([]string{"recall", "lesson-audit"}); code != 0 {
+		t.Fatalf("lesson-audit failed: %s", stderr.String())
+	}
+
+	if l, err := store.Get("L001"); err != nil || l.Content != "Use %w when wrapping" {
+		t.Errorf("expected L001 kept unchanged, got %+v (%v)", l, err)
+	}
+	if l, _ := store.Get("L002"); l == nil || l.Content != "Pass --modern to the build" {
+		t.Errorf("expected L002 content edited, got %+v", l)
+	}
+	if _, err := store.Get("L003"); err == nil {
+		t.Error("expected L003 deleted")
+	}
+	if !strings.Contains(stdout.String(), "Reviewed 3 lessons: 1 kept, 1 edited, 1 deleted") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+}
+
+func Test_LessonAudit_QuitStopsReview(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	store.Add("project", "gotcha", "Old build flag", "Pass --legacy to the build")
+
+	app.stdin = strings.NewReader("q\n")
+	if code := app.Run([]string{"recall", "lesson-audit"}); code != 0 {
+		t.Fatalf("lesson-audit failed: %s", stderr.String())
+	}
+	if all, _ := store.List(); len(all) != 2 {
+		t.Errorf("expected both lessons untouched, got %d", len(all))
+	}
+	if !strings.Contains(stdout.String(), "Reviewed 0 lessons") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+}
+
+func Test_LessonAudit_SkipsSharedLessons(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+
+	sharedPath := filepath.Join(t.TempDir(), "LESSONS.md")
+	os.WriteFile(sharedPath, []byte(`# LESSONS.md - Project Level
+
+### [L050] [*----|-----] Team lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-01 | **Category**: pattern
+> Shared across the team
+`), 0644)
+	app.sharedPaths = []string{sharedPath}
+
+	app.stdin = strings.NewReader("k\nd\n")
+	if code := app.Run([]string{"recall", "lesson-audit"}); code != 0 {
+		t.Fatalf("lesson-audit failed: %s", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "L050") {
+		t.Errorf("shared lesson should not be offered for review:\n%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Reviewed 1 lessons: 1 kept") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+}
+
+func Test_LessonAudit_Filters(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		app.stdin = strings.NewReader("k\n")
+		if code := app.Run(append([]string{"recall", "lesson-audit"}, args...)); code != 0 {
+			t.Fatalf("lesson-audit %v failed: %s", args, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	if out := run("--last-used-before", "2000-01-01"); !strings.Contains(out, "No lessons to audit.") {
+		t.Errorf("expected recently used lesson filtered out:\n%s", out)
+	}
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	if out := run("--last-used-before", tomorrow); !strings.Contains(out, "[L001]") {
+		t.Errorf("expected lesson included:\n%s", out)
+	}
+
+	app.now = func() time.Time { return time.Now().AddDate(0, 0, 10) }
+	if out := run("--min-age", "30"); !strings.Contains(out, "No lessons to audit.") {
+		t.Errorf("expected 10-day-old lesson filtered out:\n%s", out)
+	}
+	app.now = func() time.Time { return time.Now().AddDate(0, 0, 40) }
+	if out := run("--min-age", "30"); !strings.Contains(out, "[L001]") {
+		t.Errorf("expected 40-day-old lesson included:\n%s", out)
+	}
+
+	if code := app.Run([]string{"recall", "lesson-audit", "--last-used-before", "soon"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid date, got %d", code)
+	}
+}
+
+func Test_LessonAudit_BatchResumes(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	store.Add("project", "gotcha", "Old build flag", "Pass --legacy to the build")
+	store.Add("project", "decision", "Use sqlite", "Local state lives in sqlite")
+
+	run := func() string {
+		t.Helper()
+		stdout.Reset()
+		app.stdin = strings.NewReader("k\nk\n")
+		if code := app.Run([]string{"recall", "lesson-audit", "--batch-size", "2"}); code != 0 {
+			t.Fatalf("lesson-audit failed: %s", stderr.String())
+		}
+		return stdout.String()
+	}
+
+	out := run()
+	if !strings.Contains(out, "[L001]") || !strings.Contains(out, "[L002]") || strings.Contains(out, "[L003]") {
+		t.Errorf("expected first batch L001-L002:\n%s", out)
+	}
+	out = run()
+	if strings.Contains(out, "[L002]") || !strings.Contains(out, "[L003]") {
+		t.Errorf("expected second batch to resume at L003:\n%s", out)
+	}
+	if out = run(); !strings.Contains(out, "Audit complete") {
+		t.Errorf("expected audit complete:\n%s", out)
+	}
+	if out = run(); !strings.Contains(out, "[L001]") {
+		t.Errorf("expected a new cycle to start over:\n%s", out)
+	}
+}
+
+func Test_LessonAudit_AutoAudit(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+	store.Add("project", "gotcha", "Old build flag", "Pass --legacy to the build")
+
+	var scored []string
+	app.scoreUsefulness = func(lessons []*models.Lesson) (map[string]int, error) {
+		for _, l := range lessons {
+			scored = append(scored, l.ID)
+		}
+		return map[string]int{"L001": 9, "L002": 2}, nil
+	}
+
+	if code := app.Run([]string{"recall", "lesson-audit", "--auto-audit"}); code != 0 {
+		t.Fatalf("auto audit failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "[L001]  9/10 Wrap errors\n") ||
+		!strings.Contains(out, "[L002]  2/10 Old build flag (consider deleting)") ||
+		!strings.Contains(out, "1 of 2 lessons scored below 4") {
+		t.Errorf("unexpected auto audit output:\n%s", out)
+	}
+	if len(scored) != 2 {
+		t.Errorf("expected both lessons scored, got %v", scored)
+	}
+	if all, _ := store.List(); len(all) != 2 {
+		t.Errorf("expected auto audit to change nothing, got %d lessons", len(all))
+	}
+}
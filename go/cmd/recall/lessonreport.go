@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/scoring"
+)
+
+// lessonReportDecayCycles is how many recent decays the velocity trend shows
+const lessonReportDecayCycles = 4
+
+// lessonReportSimilarLimit caps the similar lessons listed in a report
+const lessonReportSimilarLimit = 5
+
+// weeklyCitations is the number of citations in the week starting Week
+type weeklyCitations struct {
+	Week  string `json:"week"` // Monday, YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// velocityChange is one decay's effect on a lesson's velocity
+type velocityChange struct {
+	Timestamp time.Time `json:"timestamp"`
+	From      float64   `json:"from"`
+	To        float64   `json:"to"`
+}
+
+// relatedLesson is a co-cited or similar lesson. Count is the number of
+// co-citations; Score is the BM25 similarity (0-10).
+type relatedLesson struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Count int    `json:"count,omitempty"`
+	Score int    `json:"score,omitempty"`
+}
+
+// lessonReport is the --json output of lesson report
+type lessonReport struct {
+	lessonJSON
+	Rating          string            `json:"rating"`
+	Learned         string            `json:"learned"`
+	LastUsed        string            `json:"last_used"`
+	Confidence      int               `json:"confidence"`
+	Weight          float64           `json:"weight"`
+	Preventions     int               `json:"preventions"`
+	TotalCitations  int               `json:"total_citations"`
+	CitationsByWeek []weeklyCitations `json:"citations_by_week"`
+	Sessions        []string          `json:"sessions"`
+	CoCitations     []relatedLesson   `json:"co_citations"`
+	VelocityTrend   []velocityChange  `json:"velocity_trend"`
+	Similar         []relatedLesson   `json:"similar"`
+}
+
+// runLessonReport prints everything known about one lesson: its fields,
+// citations per week and the sessions they came from (from the audit log),
+// co-cited lessons, velocity over the last few decays, and the most similar
+// lessons by BM25. --json gives the same data structured.
+func (a *App) runLessonReport(args []string) int {
+	jsonOutput := false
+	var id string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		id = arg
+	}
+	if id == "" {
+		fmt.Fprintln(a.stderr, "usage: recall lesson report <id> [--json]")
+		return 1
+	}
+
+	store := a.lessonStore()
+	lesson, err := store.Get(id)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+	all, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+	events, err := lessons.NewAuditLog(a.stateDir).Read()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading audit log: %v\n", err)
+		return 1
+	}
+	graph, err := lessons.LoadCoGraph(lessons.CoGraphPath(a.stateDir))
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error loading co-citation graph: %v\n", err)
+		return 1
+	}
+
+	report := buildLessonReport(lesson, all, events, graph)
+	if jsonOutput {
+		return a.writeJSON(report)
+	}
+	a.printLessonReport(report, lesson.Content)
+	return 0
+}
+
+// buildLessonReport gathers a lesson's report from the audit log, the
+// co-citation graph, and the other lessons
+func buildLessonReport(lesson *models.Lesson, all []*models.Lesson, events []lessons.AuditEvent, graph lessons.CoGraph) lessonReport {
+	report := lessonReport{
+		lessonJSON:      toLessonJSON([]*models.Lesson{lesson})[0],
+		Rating:          lesson.Rating(),
+		Learned:         lesson.Learned.Format("2006-01-02"),
+		LastUsed:        lesson.LastUsed.Format("2006-01-02"),
+		Confidence:      lesson.Confidence,
+		Weight:          lesson.Weight,
+		Preventions:     lesson.Preventions,
+		CitationsByWeek: []weeklyCitations{},
+		Sessions:        []string{},
+		CoCitations:     []relatedLesson{},
+		VelocityTrend:   []velocityChange{},
+		Similar:         []relatedLesson{},
+	}
+
+	titles := make(map[string]string, len(all))
+	var others []*models.Lesson
+	for _, l := range all {
+		titles[l.ID] = l.Title
+		if l.ID != lesson.ID {
+			others = append(others, l)
+		}
+	}
+
+	weekCounts := make(map[string]int)
+	seenSessions := make(map[string]bool)
+	for _, e := range events {
+		if e.LessonID != lesson.ID {
+			continue
+		}
+		switch e.Event {
+		case lessons.AuditCite:
+			report.TotalCitations++
+			weekCounts[usedPeriodStart("week", e.Timestamp).Format("2006-01-02")]++
+			if e.SessionID != "" && !seenSessions[e.SessionID] {
+				seenSessions[e.SessionID] = true
+				report.Sessions = append(report.Sessions, e.SessionID)
+			}
+		case lessons.AuditDecay:
+			from, _ := strconv.ParseFloat(e.OldValue, 64)
+			to, _ := strconv.ParseFloat(e.NewValue, 64)
+			report.VelocityTrend = append(report.VelocityTrend, velocityChange{Timestamp: e.Timestamp, From: from, To: to})
+		}
+	}
+	for week, count := range weekCounts {
+		report.CitationsByWeek = append(report.CitationsByWeek, weeklyCitations{Week: week, Count: count})
+	}
+	sort.Slice(report.CitationsByWeek, func(i, j int) bool {
+		return report.CitationsByWeek[i].Week < report.CitationsByWeek[j].Week
+	})
+	if n := len(report.VelocityTrend); n > lessonReportDecayCycles {
+		report.VelocityTrend = report.VelocityTrend[n-lessonReportDecayCycles:]
+	}
+
+	for _, c := range graph.Related(lesson.ID) {
+		title := titles[c.ID]
+		if title == "" {
+			title = "(not found)"
+		}
+		report.CoCitations = append(report.CoCitations, relatedLesson{ID: c.ID, Title: title, Count: c.Count})
+	}
+
+	for _, sl := range scoring.NewBM25Scorer(others).Score(lesson.Title + " " + lesson.Content) {
+		if sl.Score <= 0 || len(report.Similar) >= lessonReportSimilarLimit {
+			break
+		}
+		report.Similar = append(report.Similar, relatedLesson{ID: sl.Lesson.ID, Title: sl.Lesson.Title, Score: sl.Score})
+	}
+
+	return report
+}
+
+// printLessonReport writes a lesson report as Markdown
+func (a *App) printLessonReport(r lessonReport, content string) {
+	fmt.Fprintf(a.stdout, "# Lesson Report: [%s] %s\n\n", r.ID, r.Title)
+
+	fmt.Fprintln(a.stdout, "## Details")
+	fmt.Fprintf(a.stdout, "- **Category**: %s\n", r.Category)
+	fmt.Fprintf(a.stdout, "- **Level**: %s\n", r.Level)
+	fmt.Fprintf(a.stdout, "- **Rating**: %s\n", r.Rating)
+	fmt.Fprintf(a.stdout, "- **Uses**: %d\n", r.Uses)
+	fmt.Fprintf(a.stdout, "- **Velocity**: %.2f\n", r.Velocity)
+	fmt.Fprintf(a.stdout, "- **Learned**: %s\n", r.Learned)
+	fmt.Fprintf(a.stdout, "- **Last Used**: %s\n", r.LastUsed)
+	fmt.Fprintf(a.stdout, "- **Confidence**: %d\n", r.Confidence)
+	fmt.Fprintf(a.stdout, "- **Weight**: %g\n", r.Weight)
+	if r.Preventions > 0 {
+		fmt.Fprintf(a.stdout, "- **Prevented**: %d\n", r.Preventions)
+	}
+	if len(r.Triggers) > 0 {
+		fmt.Fprintf(a.stdout, "- **Triggers**: %s\n", strings.Join(r.Triggers, ", "))
+	}
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(a.stdout, "- **Tags**: %s\n", strings.Join(r.Tags, ", "))
+	}
+	fmt.Fprintf(a.stdout, "\n> %s\n", strings.ReplaceAll(content, "\n", "\n> "))
+
+	fmt.Fprintln(a.stdout, "\n## Citations")
+	fmt.Fprintf(a.stdout, "%d citations in the audit log.\n", r.TotalCitations)
+	for _, w := range r.CitationsByWeek {
+		fmt.Fprintf(a.stdout, "- Week of %s: %d\n", w.Week, w.Count)
+	}
+
+	fmt.Fprintln(a.stdout, "\n## Sessions")
+	if len(r.Sessions) == 0 {
+		fmt.Fprintln(a.stdout, "No citing sessions recorded.")
+	}
+	for _, s := range r.Sessions {
+		fmt.Fprintf(a.stdout, "- %s\n", s)
+	}
+
+	fmt.Fprintln(a.stdout, "\n## Co-cited With")
+	if len(r.CoCitations) == 0 {
+		fmt.Fprintln(a.stdout, "No co-citations recorded.")
+	}
+	for _, c := range r.CoCitations {
+		fmt.Fprintf(a.stdout, "- [%s] %s (%dx)\n", c.ID, c.Title, c.Count)
+	}
+
+	fmt.Fprintln(a.stdout, "\n## Velocity Trend")
+	if len(r.VelocityTrend) == 0 {
+		fmt.Fprintln(a.stdout, "No decays recorded.")
+	}
+	for _, v := range r.VelocityTrend {
+		fmt.Fprintf(a.stdout, "- %s: %.2f -> %.2f\n", v.Timestamp.Format("2006-01-02"), v.From, v.To)
+	}
+
+	fmt.Fprintln(a.stdout, "\n## Similar Lessons")
+	if len(r.Similar) == 0 {
+		fmt.Fprintln(a.stdout, "No similar lessons.")
+	}
+	for _, s := range r.Similar {
+		fmt.Fprintf(a.stdout, "- [%s] %s (score %d)\n", s.ID, s.Title, s.Score)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+)
+
+func Test_LessonReport(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors with context", "Use fmt.Errorf with %w when wrapping errors")
+	store.Add("project", "gotcha", "Wrap errors in handlers", "Handlers should wrap errors before logging")
+	store.Add("project", "decision", "Docker networking", "Containers talk over bridge networks")
+
+	audit := lessons.NewAuditLog(app.stateDir)
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	for _, e := range []lessons.AuditEvent{
+		{Timestamp: day(13), Event: lessons.AuditCite, LessonID: "L001", SessionID: "sess-a"},
+		{Timestamp: day(14), Event: lessons.AuditCite, LessonID: "L001", SessionID: "sess-b"},
+		{Timestamp: day(14), Event: lessons.AuditCite, LessonID: "L002", SessionID: "sess-c"},
+		{Timestamp: day(21), Event: lessons.AuditCite, LessonID: "L001", SessionID: "sess-a"},
+		{Timestamp: day(5), Event: lessons.AuditDecay, LessonID: "L001", OldValue: "4", NewValue: "2"},
+		{Timestamp: day(12), Event: lessons.AuditDecay, LessonID: "L001", OldValue: "2", NewValue: "1"},
+		{Timestamp: day(19), Event: lessons.AuditDecay, LessonID: "L001", OldValue: "1", NewValue: "0.5"},
+		{Timestamp: day(26), Event: lessons.AuditDecay, LessonID: "L001", OldValue: "0.5", NewValue: "0.25"},
+		{Timestamp: day(28), Event: lessons.AuditDecay, LessonID: "L001", OldValue: "0.25", NewValue: "0.125"},
+	} {
+		if err := audit.Log(e); err != nil {
+			t.Fatalf("writing audit log: %v", err)
+		}
+	}
+
+	graph := lessons.CoGraph{}
+	graph.Record([]string{"L001", "L002"})
+	graph.Record([]string{"L001", "L002", "L003"})
+	if err := graph.Save(lessons.CoGraphPath(app.stateDir)); err != nil {
+		t.Fatalf("saving co-citation graph: %v", err)
+	}
+
+	if code := app.Run([]string{"recall", "lesson", "report", "L001", "--json"}); code != 0 {
+		t.Fatalf("lesson report --json failed: %s", stderr.String())
+	}
+	var report lessonReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+
+	if report.ID != "L001" || report.Category != "pattern" || report.TotalCitations != 3 {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+	wantWeeks := []weeklyCitations{{Week: "2026-01-12", Count: 2}, {Week: "2026-01-19", Count: 1}}
+	if len(report.CitationsByWeek) != len(wantWeeks) {
+		t.Fatalf("citations by week = %+v, want %+v", report.CitationsByWeek, wantWeeks)
+	}
+	for i, w := range wantWeeks {
+		if report.CitationsByWeek[i] != w {
+			t.Errorf("citations by week[%d] = %+v, want %+v", i, report.CitationsByWeek[i], w)
+		}
+	}
+	if strings.Join(report.Sessions, ",") != "sess-a,sess-b" {
+		t.Errorf("sessions = %v, want [sess-a sess-b]", report.Sessions)
+	}
+	if len(report.CoCitations) != 2 || report.CoCitations[0].ID != "L002" || report.CoCitations[0].Count != 2 {
+		t.Errorf("unexpected co-citations: %+v", report.CoCitations)
+	}
+	if len(report.VelocityTrend) != 4 || report.VelocityTrend[0].From != 2 || report.VelocityTrend[3].To != 0.125 {
+		t.Errorf("expected the last 4 decays, got %+v", report.VelocityTrend)
+	}
+	if len(report.Similar) != 1 || report.Similar[0].ID != "L002" {
+		t.Errorf("expected L002 as the only similar lesson, got %+v", report.Similar)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "lesson", "report", "L001"}); code != 0 {
+		t.Fatalf("lesson report failed: %s", stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"# Lesson Report: [L001] Wrap errors with context",
+		"## Details\n- **Category**: pattern",
+		"## Citations\n3 citations in the audit log.\n- Week of 2026-01-12: 2\n- Week of 2026-01-19: 1\n",
+		"## Sessions\n- sess-a\n- sess-b\n",
+		"## Co-cited With\n- [L002] Wrap errors in handlers (2x)\n- [L003] Docker networking (1x)\n",
+		"## Velocity Trend\n- 2026-01-12: 2.00 -> 1.00\n",
+		"- 2026-01-28: 0.25 -> 0.12\n",
+		"## Similar Lessons\n- [L002] Wrap errors in handlers (score 10)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in report:\n%s", want, output)
+		}
+	}
+
+	if code := app.Run([]string{"recall", "lesson", "report", "L999"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown lesson, got %d", code)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// runLint validates the lesson and handoff files, printing one issue per
+// line. Exits 1 if any issue is found.
+func (a *App) runLint(args []string) int {
+	jsonOutput := false
+	verifyChecksums := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		case "--verify-checksums":
+			verifyChecksums = true
+		}
+	}
+
+	store := a.localLessonStore()
+	issues, err := lessons.Lint(store)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error linting lessons: %v\n", err)
+		return 1
+	}
+	handoffIssues, err := handoffs.Lint(a.handoffStore())
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error linting handoffs: %v\n", err)
+		return 1
+	}
+	issues = append(issues, handoffIssues...)
+
+	if verifyChecksums {
+		lessonSums, err := lessons.LintChecksums(store)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error verifying lesson checksums: %v\n", err)
+			return 1
+		}
+		handoffSums, err := handoffs.LintChecksums(a.handoffStore())
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error verifying handoff checksums: %v\n", err)
+			return 1
+		}
+		issues = append(issues, lessonSums...)
+		issues = append(issues, handoffSums...)
+	}
+
+	if jsonOutput {
+		if issues == nil {
+			issues = []models.LintIssue{}
+		}
+		if code := a.writeJSON(issues); code != 0 {
+			return code
+		}
+	} else if len(issues) == 0 {
+		fmt.Fprintln(a.stdout, "No issues found.")
+	} else {
+		for _, issue := range issues {
+			fmt.Fprintln(a.stdout, issue)
+		}
+	}
+
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
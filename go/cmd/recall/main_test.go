@@ -2,6 +2,10 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -138,6 +142,68 @@ func Test_AddCommand_CreatesLesson(t *testing.T) {
 	}
 }
 
+func Test_AddCommand_FromFile(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+
+	content := "First line with \"quotes\" and $VARS\n\n- step one\n- step two"
+	contentPath := filepath.Join(t.TempDir(), "content.md")
+	os.WriteFile(contentPath, []byte("\n  "+content+"\n\n"), 0644)
+
+	exitCode := app.Run([]string{"recall", "add", "pattern", "Long Lesson", "--from-file", contentPath, "--no-git"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stderr.String())
+	}
+
+	lessonList, err := store.List()
+	if err != nil {
+		t.Fatalf("failed to list lessons: %v", err)
+	}
+	if len(lessonList) != 1 {
+		t.Fatalf("expected 1 lesson, got %d", len(lessonList))
+	}
+	if lessonList[0].Title != "Long Lesson" {
+		t.Errorf("expected title 'Long Lesson', got '%s'", lessonList[0].Title)
+	}
+	if lessonList[0].Content != content {
+		t.Errorf("expected content %q, got %q", content, lessonList[0].Content)
+	}
+}
+
+func Test_AddCommand_FromStdin(t *testing.T) {
+	app, store, _, stderr := newTestApp(t)
+	app.stdin = strings.NewReader("Read from stdin\nsecond line\n")
+
+	exitCode := app.Run([]string{"recall", "add", "gotcha", "Stdin Lesson", "--from-file", "-", "--system", "--no-git"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stderr.String())
+	}
+
+	lessonList, _ := store.List()
+	if len(lessonList) != 1 || lessonList[0].Level != "system" {
+		t.Fatalf("expected 1 system lesson, got %+v", lessonList)
+	}
+	if lessonList[0].Content != "Read from stdin\nsecond line" {
+		t.Errorf("unexpected content %q", lessonList[0].Content)
+	}
+}
+
+func Test_AddCommand_FromFileErrors(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	if code := app.Run([]string{"recall", "add", "pattern", "Title", "--from-file", filepath.Join(t.TempDir(), "missing.md")}); code != 1 {
+		t.Errorf("expected exit code 1 for missing file, got %d", code)
+	}
+
+	emptyPath := filepath.Join(t.TempDir(), "empty.md")
+	os.WriteFile(emptyPath, []byte("  \n"), 0644)
+	if code := app.Run([]string{"recall", "add", "pattern", "Title", "--from-file", emptyPath}); code != 1 {
+		t.Errorf("expected exit code 1 for empty file, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "is empty") {
+		t.Errorf("expected empty-file error, got: %s", stderr.String())
+	}
+}
+
 func Test_AddCommand_SystemLevel(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -448,6 +514,129 @@ func Test_DecayCommand_RunsDecay(t *testing.T) {
 	}
 }
 
+func Test_DecayCommand_TTLWarnMarksExpired(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	old := time.Now().AddDate(0, 0, -120).Format("2006-01-02")
+	os.WriteFile(app.projectPath, []byte(`# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [-----|-----] Forgotten lesson
+- **Uses**: 0 | **Velocity**: 0 | **Learned**: `+old+` | **Last**: `+old+` | **Category**: pattern
+> Never cited
+`), 0644)
+
+	if exitCode := app.Run([]string{"recall", "decay", "--force", "--ttl", "90d", "--ttl-action", "warn"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "list"})
+	if !strings.Contains(stdout.String(), "Forgotten lesson (pattern) [expired]") {
+		t.Errorf("expected [expired] marker, got: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "decay", "--ttl", "soon"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for invalid ttl, got %d", exitCode)
+	}
+}
+
+func Test_DecayCommand_DryRunPrintsTableWithoutWriting(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	fixture := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [***--|*****] Busy lesson
+- **Uses**: 20 | **Velocity**: 4 | **Learned**: 2024-01-01 | **Last**: 2024-01-15 | **Category**: pattern
+> Cited often
+`
+	os.WriteFile(app.projectPath, []byte(fixture), 0644)
+
+	if exitCode := app.Run([]string{"recall", "decay", "--force", "--dry-run"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "Dry run: 1 of 1 lessons would change") {
+		t.Errorf("expected dry-run summary, got: %s", out)
+	}
+	if !strings.Contains(out, "L001       4.000     2.000") {
+		t.Errorf("expected velocity row for L001, got: %s", out)
+	}
+
+	data, _ := os.ReadFile(app.projectPath)
+	if string(data) != fixture {
+		t.Errorf("expected LESSONS.md unchanged, got:\n%s", data)
+	}
+}
+
+func Test_DebugCitationStats_RanksLessons(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	log := `{"event":"citation_batch","session_id":"s1","lesson_ids":["L001","L002"]}
+{"event":"citation_batch","session_id":"s2","lesson_ids":["L002"]}
+`
+	os.WriteFile(filepath.Join(app.stateDir, "recall.log"), []byte(log), 0644)
+
+	if exitCode := app.Run([]string{"recall", "debug", "citation-stats"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "1     L002") || !strings.HasPrefix(lines[2], "2     L001") {
+		t.Errorf("unexpected ranking:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "debug", "citation-stats", "--session", "s2"})
+	if strings.Contains(stdout.String(), "L001") {
+		t.Errorf("expected only session s2 citations, got:\n%s", stdout.String())
+	}
+}
+
+func Test_HandoffTimelineCommand(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	os.WriteFile(app.handoffsPath, []byte(`# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-a1b2c3d] Build parser
+- **Status**: completed | **Phase**: review | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-20
+
+**Tried**:
+1. [success 2026-01-15] Hand-written lexer
+2. [fail 2026-01-12] Regex approach
+3. [partial] Undated step
+
+**Next**: Done.
+
+---
+`), 0644)
+
+	if exitCode := app.Run([]string{"recall", "handoff", "timeline", "hf-a1b2c3d"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	want := `hf-a1b2c3d: Build parser
+  2026-01-10  created   Build parser
+  2026-01-12  tried     [fail] Regex approach
+  2026-01-15  tried     [success] Hand-written lexer
+  2026-01-20  tried     [partial] Undated step
+  2026-01-20  phase     review (status: completed)
+  2026-01-20  completed
+`
+	if stdout.String() != want {
+		t.Errorf("unexpected timeline:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+
+	if exitCode := app.Run([]string{"recall", "handoff", "timeline", "hf-0000000"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for unknown handoff, got %d", exitCode)
+	}
+}
+
 func Test_HandoffAddCommand_CreatesHandoff(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -521,6 +710,68 @@ func Test_HandoffAddCommand_Stealth(t *testing.T) {
 	}
 }
 
+func Test_ListCommand_JSON(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "pattern", "Project lesson", "Project content")
+	store.Add("system", "gotcha", "System lesson", "System content")
+	store.Cite("L001")
+
+	if exitCode := app.Run([]string{"recall", "list", "--json"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	var out []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected 2 lessons, got %d", len(out))
+	}
+	for _, key := range []string{"id", "title", "category", "level", "uses", "velocity", "rating", "learned", "last_used", "content"} {
+		if _, ok := out[0][key]; !ok {
+			t.Errorf("expected key %q in lesson JSON, got %v", key, out[0])
+		}
+	}
+	if out[0]["id"] != "L001" || out[0]["uses"].(float64) != 1 || out[0]["rating"] != "[*----|***--]" {
+		t.Errorf("unexpected first lesson: %v", out[0])
+	}
+}
+
+func Test_HandoffListCommand_JSON(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	// Empty store still produces a valid array
+	if exitCode := app.Run([]string{"recall", "handoff", "list", "--json"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if strings.TrimSpace(stdout.String()) != "[]" {
+		t.Errorf("expected empty array, got: %s", stdout.String())
+	}
+
+	hstore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	hstore.Add("First", "First desc", false)
+	hstore.Add("Second", "", true)
+	done, _ := hstore.Add("Done", "", false)
+	hstore.Complete(done.ID)
+
+	stdout.Reset()
+	app.Run([]string{"recall", "handoff", "list", "--json"})
+
+	var out []models.Handoff
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected 2 active handoffs, got %d", len(out))
+	}
+	for _, h := range out {
+		if h.ID == "" || h.Status == "" || h.Created.IsZero() {
+			t.Errorf("expected full handoff model, got %+v", h)
+		}
+	}
+}
+
 func Test_HandoffListCommand_ShowsActive(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -602,6 +853,264 @@ func Test_HandoffUpdateCommand_ModifiesFields(t *testing.T) {
 	}
 }
 
+func Test_HandoffUpdateCommand_ForcePhase(t *testing.T) {
+	tmpDir := t.TempDir()
+	handoffsPath := filepath.Join(tmpDir, "HANDOFFS.md")
+	stealthPath := filepath.Join(tmpDir, "HANDOFFS_LOCAL.md")
+
+	store := handoffs.NewStore(handoffsPath, stealthPath)
+	handoff, _ := store.Add("Skip ahead", "", false)
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.handoffsPath = handoffsPath
+	app.stealthPath = stealthPath
+
+	if code := app.Run([]string{"recall", "handoff", "update", handoff.ID, "--phase", "review"}); code != 1 {
+		t.Fatalf("expected exit code 1 for research -> review, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "invalid phase transition research -> review") {
+		t.Errorf("expected transition error, got %q", stderr.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "update", handoff.ID, "--phase", "review", "--force-phase"}); code != 0 {
+		t.Fatalf("expected exit code 0 with --force-phase, got %d: %s", code, stderr.String())
+	}
+	updated, _ := store.Get(handoff.ID)
+	if updated.Phase != "review" {
+		t.Errorf("expected phase 'review', got %q", updated.Phase)
+	}
+}
+
+func Test_HandoffDependencyCommands(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	first, _ := hStore.Add("First", "", false)
+	second, _ := hStore.Add("Second", "", false)
+
+	if code := app.Run([]string{"recall", "handoff", "dependency", "add", first.ID, second.ID}); code != 0 {
+		t.Fatalf("dependency add failed: %s", stderr.String())
+	}
+	if h, _ := hStore.Get(first.ID); len(h.BlockedBy) != 1 || h.BlockedBy[0] != second.ID {
+		t.Errorf("expected %s blocked by %s, got %v", first.ID, second.ID, h.BlockedBy)
+	}
+	if !strings.Contains(stdout.String(), first.ID+" is now blocked by "+second.ID) {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "dependency", "add", second.ID, first.ID}); code != 1 {
+		t.Errorf("expected exit code 1 for a cycle, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "circular dependency") {
+		t.Errorf("expected circular dependency error, got: %s", stderr.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "dependency", "remove", first.ID, second.ID}); code != 0 {
+		t.Fatalf("dependency remove failed: %s", stderr.String())
+	}
+	if h, _ := hStore.Get(first.ID); len(h.BlockedBy) != 0 {
+		t.Errorf("expected no blockers after remove, got %v", h.BlockedBy)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "dependency", "add", first.ID}); code != 1 {
+		t.Errorf("expected usage error, got %d", code)
+	}
+}
+
+func Test_HandoffCheckDepsCommand_ReportsCycles(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
+	os.MkdirAll(projectDir, 0755)
+
+	handoffsPath := filepath.Join(projectDir, "HANDOFFS.md")
+	stealthPath := filepath.Join(projectDir, "HANDOFFS_LOCAL.md")
+
+	store := handoffs.NewStore(handoffsPath, stealthPath)
+	first, _ := store.Add("First", "", false)
+	second, _ := store.Add("Second", "", false)
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.handoffsPath = handoffsPath
+	app.stealthPath = stealthPath
+
+	if exitCode := app.Run([]string{"recall", "handoff", "update", first.ID, "--blocked-by", second.ID}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	// Closing the loop is rejected by Update
+	exitCode := app.Run([]string{"recall", "handoff", "update", second.ID, "--blocked-by", first.ID})
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "circular dependency: "+second.ID+" -> "+first.ID+" -> "+second.ID) {
+		t.Errorf("expected circular dependency error, got: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "handoff", "check-deps"}); exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "No circular dependencies found.") {
+		t.Errorf("expected clean report, got: %s", stdout.String())
+	}
+}
+
+func Test_HandoffListCommand_SortByPriority(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	for _, p := range [][]string{{"Low task", "low"}, {"Critical task", "critical"}, {"Medium task", "medium"}, {"High task", "high"}} {
+		if exitCode := app.Run([]string{"recall", "handoff", "add", p[0], "--priority", p[1]}); exitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "handoff", "list", "--sort-by", "priority"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	output := stdout.String()
+	order := []string{"Critical task", "High task", "Medium task", "Low task"}
+	last := -1
+	for _, title := range order {
+		idx := strings.Index(output, title)
+		if idx <= last {
+			t.Fatalf("expected order %v, got:\n%s", order, output)
+		}
+		last = idx
+	}
+
+	if exitCode := app.Run([]string{"recall", "handoff", "add", "Bad", "--priority", "urgent"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for invalid priority, got %d", exitCode)
+	}
+}
+
+func Test_HandoffListCommand_Filters(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+
+	fixtures := []struct {
+		title, status, phase, agent string
+	}{
+		{"Fresh idea", "not_started", "research", ""},
+		{"Active build", "in_progress", "implementing", "general-purpose"},
+		{"Active spike", "in_progress", "research", "explore"},
+		{"Stuck task", "blocked", "implementing", ""},
+		{"Needs eyes", "ready_for_review", "review", ""},
+	}
+	for _, f := range fixtures {
+		h, _ := hStore.Add(f.title, "", false)
+		updates := map[string]interface{}{"status": f.status, "phase": f.phase, "force_phase": true}
+		if f.agent != "" {
+			updates["agent"] = f.agent
+		}
+		if err := hStore.Update(h.ID, updates); err != nil {
+			t.Fatalf("update %s failed: %v", f.title, err)
+		}
+	}
+
+	list := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "handoff", "list"}, args...)); code != 0 {
+			t.Fatalf("list %v failed: %s", args, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	for _, f := range fixtures {
+		out := list("--status", f.status)
+		for _, other := range fixtures {
+			if want := other.status == f.status; strings.Contains(out, other.title) != want {
+				t.Errorf("--status %s: expected %q listed=%v, got:\n%s", f.status, other.title, want, out)
+			}
+		}
+	}
+
+	out := list("--status", "in_progress", "--phase", "research")
+	if !strings.Contains(out, "Active spike") || strings.Contains(out, "Active build") {
+		t.Errorf("expected status and phase filters combined, got:\n%s", out)
+	}
+	out = list("--agent", "general-purpose")
+	if !strings.Contains(out, "Active build") || strings.Contains(out, "Active spike") {
+		t.Errorf("expected agent filter, got:\n%s", out)
+	}
+
+	if got := strings.TrimSpace(list("--status", "in_progress", "--count")); got != "2" {
+		t.Errorf("expected count 2, got %q", got)
+	}
+	if got := strings.TrimSpace(list("--count")); got != "5" {
+		t.Errorf("expected count 5, got %q", got)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "list", "--status", "sleeping"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid status, got %d", code)
+	}
+}
+
+func Test_HandoffBulkUpdate(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+
+	statuses := map[string]string{
+		"Queued A": "not_started",
+		"Queued B": "not_started",
+		"Queued C": "not_started",
+		"Running":  "in_progress",
+		"Stuck":    "blocked",
+	}
+	ids := make(map[string]string)
+	for title, status := range statuses {
+		h, _ := hStore.Add(title, "", false)
+		hStore.Update(h.ID, map[string]interface{}{"status": status})
+		ids[title] = h.ID
+	}
+
+	args := []string{"recall", "handoff", "bulk-update", "--filter-status", "not_started",
+		"--set-status", "in_progress", "--set-next", "Pick up where we left off"}
+
+	if code := app.Run(append(args, "--dry-run")); code != 0 {
+		t.Fatalf("dry run failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Would update 3 handoffs") {
+		t.Errorf("unexpected dry run output: %s", stdout.String())
+	}
+	if h, _ := hStore.Get(ids["Queued A"]); h.Status != "not_started" {
+		t.Errorf("expected dry run to leave status unchanged, got %s", h.Status)
+	}
+
+	stdout.Reset()
+	if code := app.Run(args); code != 0 {
+		t.Fatalf("bulk update failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Updated 3 handoffs") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	for title, before := range statuses {
+		h, _ := hStore.Get(ids[title])
+		if before == "not_started" {
+			if h.Status != "in_progress" || h.NextSteps != "Pick up where we left off" {
+				t.Errorf("%s: expected update applied, got status=%s next=%q", title, h.Status, h.NextSteps)
+			}
+		} else if h.Status != before || h.NextSteps != "" {
+			t.Errorf("%s: expected no change, got status=%s next=%q", title, h.Status, h.NextSteps)
+		}
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "bulk-update", "--filter-status", "blocked"}); code != 1 {
+		t.Errorf("expected exit code 1 without any --set flag, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "handoff", "bulk-update", "--set-status", "done"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid status, got %d", code)
+	}
+}
+
 func Test_HandoffTriedCommand_AddsTried(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -735,3 +1244,817 @@ func Test_HandoffArchiveCommand_ArchivesOld(t *testing.T) {
 		t.Errorf("expected exit code 0, got %d", exitCode)
 	}
 }
+
+func Test_HandoffArchiveCommand_Flags(t *testing.T) {
+	tmpDir := t.TempDir()
+	handoffsPath := filepath.Join(tmpDir, "HANDOFFS.md")
+	stealthPath := filepath.Join(tmpDir, "HANDOFFS_LOCAL.md")
+
+	// Five completed handoffs, none older than the default 7-day cutoff
+	store := handoffs.NewStore(handoffsPath, stealthPath)
+	for i := 0; i < 5; i++ {
+		h, _ := store.Add(fmt.Sprintf("Done %d", i), "", false)
+		store.Complete(h.ID)
+	}
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.handoffsPath = handoffsPath
+	app.stealthPath = stealthPath
+
+	// Stealth-only leaves the project file alone
+	if code := app.Run([]string{"recall", "handoff", "archive", "--older-than", "0d", "--keep-min", "0", "--stealth"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if all, _ := store.ListAll(); len(all) != 5 {
+		t.Errorf("expected --stealth to keep all 5 project handoffs, got %d", len(all))
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "archive", "--older-than", "0d", "--keep-min", "2"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if all, _ := store.ListAll(); len(all) != 2 {
+		t.Errorf("expected --keep-min 2 to keep 2 handoffs, got %d", len(all))
+	}
+	if !strings.Contains(stdout.String(), "Archived 3 handoffs") {
+		t.Errorf("expected 3 archived, got: %s", stdout.String())
+	}
+
+	for _, bad := range [][]string{{"--older-than", "soon"}, {"--keep-min", "-1"}} {
+		if code := app.Run(append([]string{"recall", "handoff", "archive"}, bad...)); code != 1 {
+			t.Errorf("expected exit code 1 for %v, got %d", bad, code)
+		}
+	}
+}
+
+func Test_AddCommand_DuplicateWarnsAndForceOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "project", ".claude-recall", "LESSONS.md")
+	systemPath := filepath.Join(tmpDir, "system", "LESSONS.md")
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.projectPath = projectPath
+	app.systemPath = systemPath
+
+	app.Run([]string{"recall", "add", "pattern", "Use venv", "Isolate dependencies"})
+
+	exitCode := app.Run([]string{"recall", "add", "pattern", "Use venv", "Isolate dependencies"})
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1 for duplicate, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "duplicate of L001") || !strings.Contains(stderr.String(), "--force") {
+		t.Errorf("expected duplicate warning, got: %s", stderr.String())
+	}
+
+	exitCode = app.Run([]string{"recall", "add", "pattern", "Use venv", "Isolate dependencies", "--force"})
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0 with --force, got %d", exitCode)
+	}
+
+	store := lessons.NewStore(projectPath, systemPath)
+	lessonList, _ := store.List()
+	if len(lessonList) != 2 {
+		t.Errorf("expected 2 lessons after --force, got %d", len(lessonList))
+	}
+}
+
+func Test_SplitCommand(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	l, _ := store.Add("project", "pattern", "Shell habits", "Quote variables. Prefer set -euo pipefail.")
+
+	code := app.Run([]string{"recall", "split", l.ID, "--title2", "Strict mode", "--content2", "Prefer set -euo pipefail."})
+	if code != 0 {
+		t.Fatalf("split failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Split L001 -> L002: Strict mode") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+	if orig, _ := store.Get(l.ID); orig.Content != "Quote variables." {
+		t.Errorf("expected original content trimmed, got %q", orig.Content)
+	}
+
+	if code := app.Run([]string{"recall", "split", l.ID, "--title2", "Missing content"}); code != 1 {
+		t.Errorf("expected exit code 1 without --content2, got %d", code)
+	}
+}
+
+func Test_PromoteCommand_PrintsMapping(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "project", ".claude-recall", "LESSONS.md")
+	systemPath := filepath.Join(tmpDir, "system", "LESSONS.md")
+
+	store := lessons.NewStore(projectPath, systemPath)
+	lesson, _ := store.Add("project", "pattern", "Shareable", "Useful everywhere")
+
+	var stdout bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.projectPath = projectPath
+	app.systemPath = systemPath
+
+	exitCode := app.Run([]string{"recall", "promote", lesson.ID})
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+
+	if !strings.Contains(stdout.String(), "Promoted L001 -> S001") {
+		t.Errorf("expected promotion mapping, got: %s", stdout.String())
+	}
+}
+
+func Test_CacheStatsAndClear(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+
+	cache := fmt.Sprintf(`{"entries": {"abc": {"normalized_query": "q", "scores": {"L001": 5}, "timestamp": %d}}, "hits": 3, "misses": 2}`,
+		time.Now().Add(-90*time.Second).Unix())
+	os.MkdirAll(app.stateDir, 0755)
+	os.WriteFile(filepath.Join(app.stateDir, "relevance-cache.json"), []byte(cache), 0644)
+
+	if code := app.Run([]string{"recall", "cache", "stats"}); code != 0 {
+		t.Fatalf("cache stats exit code %d", code)
+	}
+	output := stdout.String()
+	for _, want := range []string{"Entries: 1", "Hits:    3", "Misses:  2", "Oldest:  1m3"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "cache", "clear"}); code != 0 {
+		t.Fatalf("cache clear exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "Cleared 1 cached relevance entries") {
+		t.Errorf("unexpected clear output: %s", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(app.stateDir, "relevance-cache.json")); !os.IsNotExist(err) {
+		t.Errorf("expected cache file removed, stat err = %v", err)
+	}
+}
+
+func Test_ScoreRelevance_CacheOnly(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	l1, _ := store.Add("project", "pattern", "Retry flaky tests", "Rerun once before debugging")
+	l2, _ := store.Add("project", "pattern", "Pin tool versions", "Avoid surprise upgrades")
+
+	if code := app.Run([]string{"recall", "score-relevance", "flaky tests", "--cache-only"}); code != 2 {
+		t.Fatalf("expected exit code 2 on cache miss, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "no cached results for query") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+
+	cache := fmt.Sprintf(`{"entries": {"abc": {"normalized_query": "flaky tests", "scores": {%q: 9, %q: 2}, "timestamp": %d}}}`,
+		l1.ID, l2.ID, time.Now().Unix())
+	os.MkdirAll(app.stateDir, 0755)
+	os.WriteFile(filepath.Join(app.stateDir, "relevance-cache.json"), []byte(cache), 0644)
+
+	stderr.Reset()
+	if code := app.Run([]string{"recall", "score-relevance", "flaky tests", "--cache-only", "--json"}); code != 0 {
+		t.Fatalf("cache-only exit code %d: %s", code, stderr.String())
+	}
+	var out struct {
+		Results []struct {
+			ID    string `json:"id"`
+			Score int    `json:"score"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(out.Results) != 2 || out.Results[0].ID != l1.ID || out.Results[0].Score != 9 {
+		t.Errorf("unexpected cached results: %+v", out.Results)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "cache", "list"}); code != 0 {
+		t.Fatalf("cache list exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "2 scores  flaky tests") {
+		t.Errorf("unexpected cache list output: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "cache", "delete", "Tests", "flaky"}); code != 0 {
+		t.Fatalf("cache delete exit code %d: %s", code, stderr.String())
+	}
+	if code := app.Run([]string{"recall", "cache", "delete", "flaky tests"}); code != 1 {
+		t.Errorf("expected exit code 1 deleting a missing entry, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "score-relevance", "flaky tests", "--cache-only"}); code != 2 {
+		t.Errorf("expected exit code 2 after delete, got %d", code)
+	}
+}
+
+func Test_HandoffCheckpointHistory(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	h, _ := hStore.Add("Phased work", "", false)
+
+	if code := app.Run([]string{"recall", "handoff", "checkpoint-history", h.ID}); code != 0 {
+		t.Fatalf("checkpoint-history failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No checkpoint history for "+h.ID) {
+		t.Errorf("unexpected empty output: %s", stdout.String())
+	}
+
+	hStore.Update(h.ID, map[string]interface{}{"next_steps": "Sketch the API"})
+	hStore.Update(h.ID, map[string]interface{}{"phase": "planning"})
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "checkpoint-history", h.ID}); code != 0 {
+		t.Fatalf("checkpoint-history failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "-> planning     Sketch the API") {
+		t.Errorf("unexpected checkpoint history output: %s", stdout.String())
+	}
+}
+
+func Test_HandoffClone(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	orig, _ := hStore.Add("Rotate keys", "Quarterly key rotation", false)
+
+	if code := app.Run([]string{"recall", "handoff", "clone", orig.ID, "--title", "Rotate keys Q3"}); code != 0 {
+		t.Fatalf("clone failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Cloned "+orig.ID+" as hf-") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	all, _ := hStore.List()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 handoffs, got %d", len(all))
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "clone"}); code != 1 {
+		t.Errorf("expected exit code 1 without id, got %d", code)
+	}
+}
+
+func Test_HandoffInject_MaxTokens(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	for _, title := range []string{"Auth refactor", "Billing export", "Search index"} {
+		h, _ := hStore.Add(title, "", false)
+		hStore.Update(h.ID, map[string]interface{}{"description": strings.Repeat("context ", 15)})
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "inject", "--max-tokens", "100"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if got := strings.Count(stdout.String(), "### ["); got == 0 || got >= 3 {
+		t.Errorf("expected budget to keep some but not all of 3 handoffs, got %d:\n%s", got, stdout.String())
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "handoff", "inject"})
+	if got := strings.Count(stdout.String(), "### ["); got != 3 {
+		t.Errorf("expected all 3 handoffs without a budget, got %d", got)
+	}
+}
+
+func Test_HandoffInject_StatusFiltersAndCompact(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	ids := make(map[string]string)
+	for _, hf := range []struct{ title, status string }{
+		{"Auth refactor", "in_progress"},
+		{"Billing export", "blocked"},
+		{"Search index", "not_started"},
+		{"Cache warmup", "in_progress"},
+	} {
+		h, _ := hStore.Add(hf.title, "", false)
+		hStore.Update(h.ID, map[string]interface{}{"status": hf.status, "next_steps": "Continue " + hf.title})
+		ids[hf.title] = h.ID
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "handoff", "inject"}, args...)); code != 0 {
+			t.Fatalf("inject %v failed: %s", args, stderr.String())
+		}
+		return stdout.String()
+	}
+	assertTitles := func(out string, want ...string) {
+		t.Helper()
+		wanted := make(map[string]bool)
+		for _, title := range want {
+			wanted[title] = true
+		}
+		for _, title := range []string{"Auth refactor", "Billing export", "Search index", "Cache warmup"} {
+			if strings.Contains(out, title) != wanted[title] {
+				t.Errorf("expected %q present=%v in:\n%s", title, wanted[title], out)
+			}
+		}
+	}
+
+	assertTitles(run("--only-in-progress"), "Auth refactor", "Cache warmup")
+	assertTitles(run("--only-blocked"), "Billing export")
+	assertTitles(run("--only-in-progress", "--only-blocked"), "Auth refactor", "Billing export", "Cache warmup")
+	if got := strings.Count(run("--max-handoffs", "2"), "### ["); got != 2 {
+		t.Errorf("expected 2 handoffs with --max-handoffs 2, got %d", got)
+	}
+
+	out := run("--only-blocked", "--compact")
+	want := "[" + ids["Billing export"] + "] Billing export (blocked/research): Continue Billing export\n"
+	if !strings.Contains(out, want) || strings.Contains(out, "### [") {
+		t.Errorf("expected compact line %q, got:\n%s", want, out)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "inject", "--max-handoffs", "x"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid --max-handoffs, got %d", code)
+	}
+}
+
+func Test_HandoffInject_StealthAndPublicOnly(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	for _, hf := range []struct {
+		title   string
+		stealth bool
+	}{{"Public refactor", false}, {"Secret spike", true}} {
+		h, _ := hStore.Add(hf.title, "", hf.stealth)
+		hStore.Update(h.ID, map[string]interface{}{"status": "in_progress", "next_steps": "Continue " + hf.title})
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "handoff"}, args...)); code != 0 {
+			t.Fatalf("%v failed: %s", args, stderr.String())
+		}
+		return stdout.String()
+	}
+	for _, tt := range []struct {
+		args        []string
+		want, avoid string
+	}{
+		{[]string{"inject", "--stealth-only"}, "Secret spike", "Public refactor"},
+		{[]string{"inject", "--public-only"}, "Public refactor", "Secret spike"},
+		{[]string{"inject-todos", "--stealth-only"}, "Secret spike", "Public refactor"},
+		{[]string{"inject-todos", "--public-only"}, "Public refactor", "Secret spike"},
+	} {
+		out := run(tt.args...)
+		if !strings.Contains(out, tt.want) || strings.Contains(out, tt.avoid) {
+			t.Errorf("%v: expected only %q, got:\n%s", tt.args, tt.want, out)
+		}
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "inject", "--stealth-only", "--public-only"}); code != 1 {
+		t.Errorf("expected exit code 1 for conflicting flags, got %d", code)
+	}
+}
+
+func Test_HandoffTemplates(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	app.baseDir = t.TempDir()
+	tmplDir := handoffs.TemplateDir(app.baseDir)
+	os.MkdirAll(tmplDir, 0755)
+	os.WriteFile(filepath.Join(tmplDir, "bug-fix.json"), []byte(`{
+  "phase": "implementing",
+  "description": "Reproduce, fix, and add a regression test",
+  "next_steps": "Write a failing test",
+  "refs": ["internal/"]
+}`), 0644)
+
+	if code := app.Run([]string{"recall", "handoff", "add", "Login loop", "--template", "bug-fix"}); code != 0 {
+		t.Fatalf("add from template failed: %s", stderr.String())
+	}
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	list, _ := hStore.List()
+	if len(list) != 1 {
+		t.Fatalf("expected 1 handoff, got %d", len(list))
+	}
+	h := list[0]
+	if h.Title != "Login loop" || h.Phase != "implementing" || h.NextSteps != "Write a failing test" ||
+		h.Description != "Reproduce, fix, and add a regression test" || len(h.Refs) != 1 || h.Refs[0] != "internal/" {
+		t.Errorf("handoff missing template defaults: %+v", h)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "template", "save", h.ID, "copy"}); code != 0 {
+		t.Fatalf("template save failed: %s", stderr.String())
+	}
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "template", "list"}); code != 0 {
+		t.Fatalf("template list failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "bug-fix - Reproduce") || !strings.Contains(stdout.String(), "copy - Reproduce") {
+		t.Errorf("unexpected template list: %s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "add", "X", "--template", "missing"}); code != 1 {
+		t.Errorf("expected exit code 1 for a missing template, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "handoff", "add", "X", "--template", "bug-fix", "--stealth"}); code != 1 {
+		t.Errorf("expected exit code 1 combining --template and --stealth, got %d", code)
+	}
+}
+
+func Test_HandoffMilestones(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	h, _ := hStore.Add("Payments rewrite", "", false)
+
+	for _, name := range []string{"Ledger model", "Refund flow", "Cutover"} {
+		if code := app.Run([]string{"recall", "handoff", "milestone", h.ID, "add", name}); code != 0 {
+			t.Fatalf("milestone add failed: %s", stderr.String())
+		}
+	}
+	for _, name := range []string{"Ledger model", "Refund flow"} {
+		if code := app.Run([]string{"recall", "handoff", "milestone", h.ID, "complete", name}); code != 0 {
+			t.Fatalf("milestone complete failed: %s", stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "list"}); code != 0 {
+		t.Fatalf("list failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Payments rewrite [2/3 milestones]") {
+		t.Errorf("expected milestone ratio in list, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "inject"}); code != 0 {
+		t.Fatalf("inject failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "- **Milestones**: [2/3 milestones]") {
+		t.Errorf("expected milestone ratio in inject output, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "milestone", h.ID, "list"}); code != 0 {
+		t.Fatalf("milestone list failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[ ] Cutover") || !strings.Contains(stdout.String(), "[x] Ledger model") {
+		t.Errorf("unexpected milestone list: %s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "milestone", h.ID, "complete", "Nope"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown milestone, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "handoff", "milestone", h.ID, "rename", "x"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown action, got %d", code)
+	}
+}
+
+func Test_HandoffNoteAndShow(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	h, _ := hStore.Add("Upgrade Go", "Bump toolchain", false)
+
+	for _, text := range []string{"CI image needs rebuild", "Linter flags new vet checks"} {
+		if code := app.Run([]string{"recall", "handoff", "note", h.ID, text}); code != 0 {
+			t.Fatalf("note failed: %s", stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "show", h.ID}); code != 0 {
+		t.Fatalf("show failed: %s", stderr.String())
+	}
+	output := stdout.String()
+	first := strings.Index(output, "CI image needs rebuild")
+	second := strings.Index(output, "Linter flags new vet checks")
+	if !strings.Contains(output, "Title: Upgrade Go") || !strings.Contains(output, "Notes:") || first < 0 || second < first {
+		t.Errorf("expected notes in order, got:\n%s", output)
+	}
+}
+
+func Test_Lint(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Healthy", "All good")
+
+	if code := app.Run([]string{"recall", "lint"}); code != 0 {
+		t.Fatalf("expected clean lint, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No issues found.") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	corrupt := `### [L001] [*----|-----] Broken
+- **Uses**: -1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> negative uses
+`
+	os.WriteFile(app.projectPath, []byte(corrupt), 0644)
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "lint"}); code != 1 {
+		t.Errorf("expected exit code 1 for corrupt file, got %d", code)
+	}
+	if want := app.projectPath + ":2: negative Uses value -1\n"; stdout.String() != want {
+		t.Errorf("lint output = %q, want %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	app.Run([]string{"recall", "lint", "--json"})
+	var issues []models.LintIssue
+	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) != 1 || issues[0].Line != 2 {
+		t.Errorf("unexpected JSON output (%v): %s", err, stdout.String())
+	}
+}
+
+func Test_HandoffProcessTranscript_MultiLineBlocks(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	transcript := map[string][]string{"assistant_texts": {
+		"HANDOFF: Single line handoff",
+		"Plan:\nHANDOFF: Split billing service\n  desc: Extract invoicing into its own module\n  phase: planning\n  refs: billing/invoice.go:88\nLet's begin.",
+	}}
+	data, _ := json.Marshal(transcript)
+	app.stdin = bytes.NewReader(data)
+
+	if code := app.Run([]string{"recall", "handoff", "process-transcript"}); code != 0 {
+		t.Fatalf("process-transcript failed: %s", stderr.String())
+	}
+	if strings.Count(stdout.String(), "added hf-") != 2 {
+		t.Errorf("expected two added handoffs, got: %s", stdout.String())
+	}
+
+	all, _ := handoffs.NewStore(app.handoffsPath, app.stealthPath).List()
+	byTitle := map[string]*models.Handoff{}
+	for _, h := range all {
+		byTitle[h.Title] = h
+	}
+	single, multi := byTitle["Single line handoff"], byTitle["Split billing service"]
+	if single == nil || multi == nil {
+		t.Fatalf("missing handoffs: %+v", byTitle)
+	}
+	if single.Description != "" || single.Phase != "research" || len(single.Refs) != 0 {
+		t.Errorf("single-line handoff should keep defaults: %+v", single)
+	}
+	if multi.Description != "Extract invoicing into its own module" || multi.Phase != "planning" ||
+		len(multi.Refs) != 1 || multi.Refs[0] != "billing/invoice.go:88" {
+		t.Errorf("multi-line fields not applied: %+v", multi)
+	}
+}
+
+func Test_HandoffGraph(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	first, _ := hStore.Add("First", "", false)
+	second, _ := hStore.Add("Second", "", false)
+	third, _ := hStore.Add("Third", "", false)
+	hStore.Update(second.ID, map[string]interface{}{"blocked_by": []string{first.ID}, "status": "in_progress"})
+	hStore.Update(third.ID, map[string]interface{}{"blocked_by": []string{second.ID}, "status": "blocked"})
+
+	if code := app.Run([]string{"recall", "handoff", "graph", "--dot"}); code != 0 {
+		t.Fatalf("graph failed: %s", stderr.String())
+	}
+	dot := stdout.String()
+	for _, want := range []string{
+		"digraph handoffs {",
+		fmt.Sprintf("%q -> %q;", first.ID, second.ID),
+		fmt.Sprintf("%q -> %q;", second.ID, third.ID),
+		fmt.Sprintf(`%q [label="%s\nSecond", shape=box];`, second.ID, second.ID),
+		fmt.Sprintf(`%q [label="%s\nThird", shape=diamond];`, third.ID, third.ID),
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("missing %s in:\n%s", want, dot)
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "graph", "--format", "json"}); code != 0 {
+		t.Fatalf("graph json failed: %s", stderr.String())
+	}
+	var g handoffs.Graph
+	if err := json.Unmarshal(stdout.Bytes(), &g); err != nil || len(g.Nodes) != 3 || len(g.Edges) != 2 {
+		t.Errorf("unexpected JSON graph (%v): %s", err, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "graph", "--format", "ascii"}); code != 0 {
+		t.Fatalf("graph ascii failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "\n    "+third.ID+" [blocked] Third") {
+		t.Errorf("unexpected ascii tree:\n%s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "graph", "--format", "svg"}); code != 1 {
+		t.Errorf("expected exit code 1 for unknown format, got %d", code)
+	}
+}
+
+func Test_HandoffList_Blocked(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	blocked, _ := hStore.Add("Ship release", "", false)
+	blocker, _ := hStore.Add("Fix flaky CI", "", false)
+	hStore.Add("Unrelated cleanup", "", false)
+	if err := hStore.AddDependency(blocked.ID, blocker.ID); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "list", "--blocked"}); code != 0 {
+		t.Fatalf("list --blocked failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "[blocked by: "+blocker.ID+" (Fix flaky CI)]") {
+		t.Errorf("expected blocker title inline, got:\n%s", out)
+	}
+	if strings.Contains(out, "Unrelated cleanup") {
+		t.Errorf("expected unblocked handoffs filtered out, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "list", "--blocked", "--resolve-ids=false"}); code != 0 {
+		t.Fatalf("list --blocked --resolve-ids=false failed: %s", stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "[blocked by: "+blocker.ID+"]") {
+		t.Errorf("expected bare blocker ID, got:\n%s", out)
+	}
+}
+
+func Test_HandoffComplete_SendsWebhook(t *testing.T) {
+	var got []handoffs.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p handoffs.WebhookPayload
+		json.NewDecoder(r.Body).Decode(&p)
+		got = append(got, p)
+	}))
+	defer server.Close()
+
+	app, _, _, stderr := newTestApp(t)
+	app.webhookURL = server.URL
+	app.webhookEvents = []string{"complete"}
+	h, _ := handoffs.NewStore(app.handoffsPath, app.stealthPath).Add("Ship release", "", false)
+
+	if code := app.Run([]string{"recall", "handoff", "complete", h.ID}); code != 0 {
+		t.Fatalf("handoff complete failed: %s", stderr.String())
+	}
+	if len(got) != 1 || got[0].Event != "complete" || got[0].Handoff.ID != h.ID ||
+		got[0].Handoff.Title != "Ship release" || got[0].Handoff.Status != "completed" {
+		t.Errorf("unexpected webhook payloads: %+v", got)
+	}
+}
+
+func Test_HandoffInjectStructured(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	first, _ := hStore.Add("Fix auth", "Tokens expire early", false)
+	hStore.Update(first.ID, map[string]interface{}{"status": "in_progress", "phase": "implementing", "priority": "high", "next_steps": "Add refresh"})
+	hStore.AddTriedStep(first.ID, "fail", "Longer TTL")
+	hStore.AddTriedStep(first.ID, "fail", "Clock skew leeway")
+	hStore.AddTriedStep(first.ID, "success", "Refresh on 401")
+	second, _ := hStore.Add("Write docs", "", false)
+
+	if code := app.Run([]string{"recall", "handoff", "inject-structured"}); code != 0 {
+		t.Fatalf("inject-structured failed: %s", stderr.String())
+	}
+	var got []structuredHandoff
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	byID := make(map[string]structuredHandoff)
+	for _, h := range got {
+		byID[h.ID] = h
+	}
+	if len(byID) != 2 {
+		t.Fatalf("expected 2 handoffs, got %+v", got)
+	}
+	want := structuredHandoff{
+		ID: first.ID, Title: "Fix auth", Status: "in_progress", Phase: "implementing",
+		Description: "Tokens expire early", NextSteps: "Add refresh", TriedCount: 3, LastOutcome: "success", Priority: "high",
+	}
+	if byID[first.ID] != want {
+		t.Errorf("got %+v, want %+v", byID[first.ID], want)
+	}
+	if h := byID[second.ID]; h.Title != "Write docs" || h.TriedCount != 0 || h.LastOutcome != "" || h.Status != "not_started" {
+		t.Errorf("unexpected second handoff: %+v", h)
+	}
+	// tried_count is a JSON number
+	if !strings.Contains(stdout.String(), `"tried_count":3`) {
+		t.Errorf("expected numeric tried_count, got %s", stdout.String())
+	}
+}
+
+func Test_Lint_VerifyChecksums(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Healthy", "All good")
+	handoffs.NewStore(app.handoffsPath, app.stealthPath).Add("Tracked work", "", false)
+
+	if code := app.Run([]string{"recall", "lint", "--verify-checksums"}); code != 0 {
+		t.Fatalf("expected clean lint, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	data, _ := os.ReadFile(app.projectPath)
+	os.WriteFile(app.projectPath, []byte(strings.Replace(string(data), "All good", "All bad!", 1)), 0644)
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "lint"}); code != 0 {
+		t.Errorf("expected plain lint to ignore checksums, got %d: %s", code, stdout.String())
+	}
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "lint", "--verify-checksums"}); code != 1 {
+		t.Errorf("expected exit code 1 for checksum mismatch, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), app.projectPath) || !strings.Contains(stdout.String(), "checksum mismatch") ||
+		strings.Contains(stdout.String(), app.handoffsPath) {
+		t.Errorf("expected only the lessons file to be flagged, got:\n%s", stdout.String())
+	}
+}
+
+func Test_HandoffInject_RecencyBias(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	ago := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+	fixture := `# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Recent tweak
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Priority**: low
+- **Created**: ` + ago(1) + ` | **Updated**: ` + ago(0) + `
+
+---
+
+### [hf-0000002] Old critical fix
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Priority**: critical
+- **Created**: ` + ago(40) + ` | **Updated**: ` + ago(30) + `
+
+---
+`
+	os.WriteFile(app.handoffsPath, []byte(fixture), 0644)
+
+	first := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if code := app.Run(append([]string{"recall", "handoff", "inject", "--compact"}, args...)); code != 0 {
+			t.Fatalf("inject %v failed: %s", args, stderr.String())
+		}
+		return strings.SplitN(strings.TrimPrefix(stdout.String(), injectHandoffsHeader), "\n", 2)[0]
+	}
+	if got := first("--recency-bias", "0.1"); !strings.Contains(got, "Old critical fix") {
+		t.Errorf("low recency bias: expected critical handoff first, got %q", got)
+	}
+	if got := first("--recency-bias", "0.9"); !strings.Contains(got, "Recent tweak") {
+		t.Errorf("high recency bias: expected recent handoff first, got %q", got)
+	}
+
+	app.handoffsRecencyBias = 0.1
+	if got := first(); !strings.Contains(got, "Old critical fix") {
+		t.Errorf("configured bias: expected critical handoff first, got %q", got)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "inject", "--recency-bias", "2"}); code != 1 {
+		t.Errorf("expected exit code 1 for out-of-range bias, got %d", code)
+	}
+}
+
+func Test_HandoffTried_FromTranscript(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	h, _ := hStore.Add("Flaky test", "", false)
+
+	line := func(typ, text string) string {
+		data, _ := json.Marshal(map[string]interface{}{
+			"type":    typ,
+			"message": map[string]interface{}{"content": []map[string]string{{"type": "text", "text": text}}},
+		})
+		return string(data) + "\n"
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	os.WriteFile(path, []byte(
+		line("user", "HANDOFF UPDATE "+h.ID+": tried fail - from the user, ignored")+
+			line("assistant", "HANDOFF UPDATE "+h.ID+": tried fail - Retry the test\nHANDOFF UPDATE "+h.ID+": tried partial - Add a sleep")+
+			line("assistant", "Found it.\nHANDOFF UPDATE "+h.ID+": tried success - Fix the race")), 0644)
+
+	if code := app.Run([]string{"recall", "handoff", "tried", "--from-transcript", path, "--dry-run"}); code != 0 {
+		t.Fatalf("dry run failed: %s", stderr.String())
+	}
+	if got, _ := hStore.Get(h.ID); len(got.Tried) != 0 {
+		t.Errorf("dry run wrote tried steps: %+v", got.Tried)
+	}
+	if strings.Count(stdout.String(), "Would add tried step") != 3 {
+		t.Errorf("expected 3 previewed steps, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "tried", "--from-transcript", path}); code != 0 {
+		t.Fatalf("tried --from-transcript failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Added 3 tried steps to 1 handoffs") {
+		t.Errorf("unexpected summary: %s", stdout.String())
+	}
+	got, _ := hStore.Get(h.ID)
+	want := []struct{ outcome, desc string }{{"fail", "Retry the test"}, {"partial", "Add a sleep"}, {"success", "Fix the race"}}
+	if len(got.Tried) != len(want) {
+		t.Fatalf("expected %d tried steps, got %+v", len(want), got.Tried)
+	}
+	for i, w := range want {
+		if got.Tried[i].Outcome != w.outcome || got.Tried[i].Description != w.desc {
+			t.Errorf("tried[%d] = %+v, want %s - %s", i, got.Tried[i], w.outcome, w.desc)
+		}
+	}
+}
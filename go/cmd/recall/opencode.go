@@ -7,7 +7,10 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/pbrown/claude-recall/internal/anthropic"
 	"github.com/pbrown/claude-recall/internal/handoffs"
 	"github.com/pbrown/claude-recall/internal/lessons"
 	"github.com/pbrown/claude-recall/internal/models"
@@ -33,10 +36,10 @@ HANDOFF DUTY: For MAJOR work (3+ files, multi-step, integration), you MUST:
 
 // Regex patterns for session-idle processing
 var (
-	// Citation pattern: [L001] or [S001]
-	citationPattern = regexp.MustCompile(`\[([LS]\d{3})\]`)
+	// Citation pattern: [L001], [S001], or [W001]
+	citationPattern = regexp.MustCompile(`\[([LSW]\d{3})\]`)
 	// Listing pattern: [L001] [*** - lesson listing format to skip
-	listingPattern = regexp.MustCompile(`\[([LS]\d{3})\]\s+\[\*`)
+	listingPattern = regexp.MustCompile(`\[([LSW]\d{3})\]\s+\[\*`)
 	// LESSON: pattern - optional category, title - content
 	lessonPattern = regexp.MustCompile(`(?:AI )?LESSON:\s*(?:([^:]+):\s*)?(.+?)\s*-\s*(.+)`)
 	// HANDOFF: pattern - start a new handoff
@@ -56,6 +59,8 @@ func (a *App) runOpencode(args []string) int {
 		fmt.Fprintln(a.stderr, "  pre-compact    - Prepare context for compaction")
 		fmt.Fprintln(a.stderr, "  post-compact   - Process after compaction")
 		fmt.Fprintln(a.stderr, "  session-end    - Cleanup at session end")
+		fmt.Fprintln(a.stderr, "  transcript-analyze - Report metrics for a JSONL transcript")
+		fmt.Fprintln(a.stderr, "  batch          - Run session-idle over a directory of session JSONs")
 		return 1
 	}
 
@@ -71,6 +76,10 @@ func (a *App) runOpencode(args []string) int {
 		return a.runOpencodePostCompact(a.stdin)
 	case "session-end":
 		return a.runOpencodeSessionEnd(a.stdin)
+	case "transcript-analyze":
+		return a.runOpencodeTranscriptAnalyze(args[1:])
+	case "batch":
+		return a.runOpencodeBatch(args[1:])
 	default:
 		fmt.Fprintf(a.stderr, "unknown opencode subcommand: %s\n", subcmd)
 		return 1
@@ -107,21 +116,25 @@ func (a *App) runOpencodeSessionStart(stdin io.Reader) int {
 	}
 
 	// Create stores
-	lessonStore := lessons.NewStore(a.projectPath, a.systemPath)
-	handoffStore := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	lessonStore := a.lessonStore()
+	handoffStore := a.handoffStore()
 
 	// Get lessons context
 	lessonsContext := ""
 	allLessons, err := lessonStore.List()
 	if err == nil && len(allLessons) > 0 {
-		lessonsContext = formatLessonsContext(allLessons, input.TopN)
+		tmpl, err := a.lessonsTemplate("")
+		if err != nil {
+			fmt.Fprintf(a.stderr, "warning: %v (using the built-in format)\n", err)
+		}
+		lessonsContext = formatLessonsContext(allLessons, input.TopN, tmpl, a.stderr)
 	}
 
 	// Get handoffs context
 	handoffsContext := ""
 	activeHandoffs, err := handoffStore.List()
 	if err == nil && len(activeHandoffs) > 0 {
-		handoffsContext = formatHandoffsContext(activeHandoffs)
+		handoffsContext = formatHandoffsContext(activeHandoffs, a.handoffsRecencyBias, a.clock())
 	}
 
 	// Get todos prompt
@@ -172,15 +185,44 @@ type SessionIdleInput struct {
 	SessionID        string                   `json:"session_id"`
 	Messages         []map[string]interface{} `json:"messages"`
 	CheckpointOffset int                      `json:"checkpoint_offset"`
+	DryRun           bool                     `json:"dry_run"` // Report operations without modifying files
+	Verbose          bool                     `json:"verbose"` // Log the patterns matched in each message
+
+	// Todos is the latest todo list ({"subject" or "content", "status"}).
+	// When AutoHandoffThreshold (default 4) or more are pending and the
+	// session has no active handoff, a handoff is suggested, or created
+	// if AutoCreateHandoff is set.
+	Todos                []map[string]interface{} `json:"todos"`
+	AutoHandoffThreshold int                      `json:"auto_handoff_threshold"`
+	AutoCreateHandoff    bool                     `json:"auto_create_handoff"`
 }
 
+// defaultAutoHandoffThreshold is how many pending todos suggest a handoff
+const defaultAutoHandoffThreshold = 4
+
 // SessionIdleOutput is the JSON output for session-idle
 type SessionIdleOutput struct {
-	Citations           []string `json:"citations"`
-	LessonsAdded        []string `json:"lessons_added"`
-	HandoffOps          []string `json:"handoff_ops"`
-	NewCheckpointOffset int      `json:"new_checkpoint_offset"`
-	Error               string   `json:"error,omitempty"`
+	Citations           []string   `json:"citations"`
+	LessonsAdded        []string   `json:"lessons_added"`
+	HandoffOps          []string   `json:"handoff_ops"`
+	NewCheckpointOffset int        `json:"new_checkpoint_offset"`
+	DryRun              bool       `json:"dry_run,omitempty"`
+	DryRunOps           []DryRunOp `json:"dry_run_ops,omitempty"`
+	VerboseLog          []string   `json:"verbose_log,omitempty"` // One entry per message, in verbose mode
+	Error               string     `json:"error,omitempty"`
+
+	SuggestHandoff       bool   `json:"suggest_handoff,omitempty"`         // Enough pending todos for a handoff
+	AutoCreatedHandoffID string `json:"auto_created_handoff_id,omitempty"` // Handoff created from the todos
+}
+
+// DryRunOp is a mutation session-idle would have made in dry-run mode
+type DryRunOp struct {
+	Type        string `json:"type"`         // cite|add_lesson|start_handoff|tried|complete_handoff
+	ID          string `json:"id,omitempty"` // Lesson or handoff ID (empty for new items)
+	Category    string `json:"category,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Outcome     string `json:"outcome,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 // runOpencodeSessionIdle handles the session-idle subcommand
@@ -191,39 +233,51 @@ func (a *App) runOpencodeSessionIdle(stdin io.Reader) int {
 		return 1
 	}
 
+	output := a.processSessionIdle(input, a.stderr)
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error encoding output JSON: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(a.stdout, string(data))
+
+	return 0
+}
+
+// processSessionIdle applies (or, in dry-run mode, records) the citations,
+// LESSON: commands, and handoff patterns in a session's new messages.
+// Non-fatal problems are reported to warn.
+func (a *App) processSessionIdle(input SessionIdleInput, warn io.Writer) SessionIdleOutput {
 	// Create stores
-	lessonStore := lessons.NewStore(a.projectPath, a.systemPath)
-	handoffStore := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	lessonStore := a.lessonStore()
+	handoffStore := a.handoffStore()
 
 	output := SessionIdleOutput{
 		Citations:           []string{},
 		LessonsAdded:        []string{},
 		HandoffOps:          []string{},
 		NewCheckpointOffset: len(input.Messages),
+		DryRun:              input.DryRun,
+	}
+
+	// In dry-run mode mutations are recorded in ops instead of applied
+	var ops *[]DryRunOp
+	if input.DryRun {
+		output.DryRunOps = []DryRunOp{}
+		ops = &output.DryRunOps
 	}
+	var cited []string
 
 	// Process messages starting from checkpoint_offset
 	for i := input.CheckpointOffset; i < len(input.Messages); i++ {
 		msg := input.Messages[i]
 
-		// Handle both string and array content types
-		var content string
-		if str, ok := msg["content"].(string); ok {
-			content = str
-		} else if arr, ok := msg["content"].([]interface{}); ok {
-			// Extract text from content blocks
-			var texts []string
-			for _, block := range arr {
-				if b, ok := block.(map[string]interface{}); ok {
-					if t, ok := b["type"].(string); ok && t == "text" {
-						if text, ok := b["text"].(string); ok {
-							texts = append(texts, text)
-						}
-					}
-				}
-			}
-			content = strings.Join(texts, " ")
-		} else {
+		content, ok := messageText(msg)
+		if input.Verbose {
+			output.VerboseLog = append(output.VerboseLog, verboseMessageLog(i, content, ok))
+		}
+		if !ok {
 			continue
 		}
 
@@ -231,30 +285,141 @@ func (a *App) runOpencodeSessionIdle(stdin io.Reader) int {
 		citations := extractCitations(content)
 		for _, cid := range citations {
 			output.Citations = append(output.Citations, cid)
+			if ops != nil {
+				*ops = append(*ops, DryRunOp{Type: "cite", ID: cid})
+				continue
+			}
 			// Cite the lesson (errors logged but don't fail the operation)
-			if err := lessonStore.Cite(cid); err != nil {
+			if err := lessonStore.CiteInSession(cid, input.SessionID); err != nil {
 				// Log but continue - non-existent lesson citations are not fatal
-				fmt.Fprintf(a.stderr, "warning: failed to cite %s: %v\n", cid, err)
+				fmt.Fprintf(warn, "warning: failed to cite %s: %v\n", cid, err)
+				continue
 			}
+			cited = append(cited, cid)
 		}
 
 		// Parse LESSON: commands
-		lessonsAdded := parseLessonCommands(content, lessonStore)
+		lessonsAdded := parseLessonCommands(content, lessonStore, ops)
 		output.LessonsAdded = append(output.LessonsAdded, lessonsAdded...)
 
 		// Parse handoff patterns
-		handoffOps := parseHandoffPatterns(content, handoffStore)
+		handoffOps := parseHandoffPatterns(content, handoffStore, ops)
 		output.HandoffOps = append(output.HandoffOps, handoffOps...)
 	}
 
-	data, err := json.Marshal(output)
+	// Track which lessons were cited together in this batch
+	if err := lessons.RecordCoCitations(a.stateDir, cited); err != nil {
+		fmt.Fprintf(warn, "warning: failed to record co-citations: %v\n", err)
+	}
+
+	a.autoHandoffFromTodos(input, handoffStore, &output, ops, warn)
+
+	return output
+}
+
+// autoHandoffFromTodos suggests (or, with AutoCreateHandoff, creates) a
+// handoff when a session without an active handoff has a long todo list.
+// The handoff is titled after the first pending todo, with all pending
+// todos as its next steps; an active handoff with that title already
+// covers the list.
+func (a *App) autoHandoffFromTodos(input SessionIdleInput, store *handoffs.Store, output *SessionIdleOutput, ops *[]DryRunOp, warn io.Writer) {
+	threshold := input.AutoHandoffThreshold
+	if threshold <= 0 {
+		threshold = defaultAutoHandoffThreshold
+	}
+
+	var pending []string
+	for _, todo := range input.Todos {
+		subject, _ := todo["subject"].(string)
+		if subject == "" {
+			subject, _ = todo["content"].(string) // TodoWrite's field name
+		}
+		status, _ := todo["status"].(string)
+		if subject != "" && status != "completed" {
+			pending = append(pending, subject)
+		}
+	}
+	if len(pending) < threshold {
+		return
+	}
+
+	if input.SessionID != "" {
+		if id, err := a.getSessionHandoff(input.SessionID); err == nil && id != "" {
+			if h, err := store.Get(id); err == nil && h.Status != "completed" {
+				return
+			}
+		}
+	}
+	// Without a session link (or from an earlier session), an active handoff
+	// already titled after the first todo is the one this list belongs to
+	active, err := store.List()
 	if err != nil {
-		fmt.Fprintf(a.stderr, "error encoding output JSON: %v\n", err)
-		return 1
+		fmt.Fprintf(warn, "warning: failed to list handoffs: %v\n", err)
+		return
+	}
+	for _, h := range active {
+		if h.Title == pending[0] {
+			return
+		}
 	}
-	fmt.Fprintln(a.stdout, string(data))
 
-	return 0
+	output.SuggestHandoff = true
+	if !input.AutoCreateHandoff {
+		return
+	}
+	if ops != nil {
+		*ops = append(*ops, DryRunOp{Type: "start_handoff", Title: pending[0]})
+		return
+	}
+
+	h, err := store.Add(pending[0], "", false)
+	if err != nil {
+		fmt.Fprintf(warn, "warning: failed to create handoff from todos: %v\n", err)
+		return
+	}
+	if err := store.Update(h.ID, map[string]interface{}{"next_steps": strings.Join(pending, "; ")}); err != nil {
+		fmt.Fprintf(warn, "warning: failed to set next steps on %s: %v\n", h.ID, err)
+	}
+	if input.SessionID != "" {
+		a.setSessionHandoff(input.SessionID, h.ID, "")
+	}
+	output.AutoCreatedHandoffID = h.ID
+	output.HandoffOps = append(output.HandoffOps, fmt.Sprintf("started %s", h.ID))
+}
+
+// verboseMessageLog describes the patterns session-idle matched in message
+// i, e.g. "message[2]: found citation L001, found LESSON: pattern"
+func verboseMessageLog(i int, content string, hasText bool) string {
+	prefix := fmt.Sprintf("message[%d]: ", i)
+	if !hasText {
+		return prefix + "no text content"
+	}
+
+	var found []string
+	for _, cid := range extractCitations(content) {
+		found = append(found, "found citation "+cid)
+	}
+	for _, m := range lessonPattern.FindAllStringSubmatch(content, -1) {
+		category := strings.TrimSpace(m[1])
+		if category == "" {
+			category = "pattern"
+		}
+		found = append(found, "found LESSON: "+category)
+	}
+	for _, m := range opencodeHandoffStartPattern.FindAllStringSubmatch(content, -1) {
+		found = append(found, "found HANDOFF: "+strings.TrimSpace(m[1]))
+	}
+	for _, m := range opencodeHandoffUpdatePattern.FindAllStringSubmatch(content, -1) {
+		found = append(found, fmt.Sprintf("found HANDOFF UPDATE %s: tried %s", m[1], m[2]))
+	}
+	for _, m := range opencodeHandoffCompletePattern.FindAllStringSubmatch(content, -1) {
+		found = append(found, "found HANDOFF COMPLETE "+m[1])
+	}
+
+	if len(found) == 0 {
+		return prefix + "no matches"
+	}
+	return prefix + strings.Join(found, ", ")
 }
 
 // PreCompactInput is the JSON input for pre-compact
@@ -280,7 +445,7 @@ func (a *App) runOpencodePreCompact(stdin io.Reader) int {
 		return 1
 	}
 
-	handoffStore := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	handoffStore := a.handoffStore()
 
 	output := PreCompactOutput{
 		ContextToInject:     "",
@@ -337,23 +502,25 @@ func (a *App) runOpencodePostCompact(stdin io.Reader) int {
 		return 1
 	}
 
-	handoffStore := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	handoffStore := a.handoffStore()
 
 	output := PostCompactOutput{
 		SuggestComplete: false,
 	}
 
-	// Update handoff if exists
+	// Update handoff if exists. The checkpoint is saved on its own so a
+	// rejected phase can't lose it; the phase is the agent's report of where
+	// it is, so it skips the transition rules.
 	if input.HandoffID != "" {
-		updates := make(map[string]interface{})
-		if input.Phase != "" {
-			updates["phase"] = input.Phase
-		}
 		if input.Summary != "" {
-			updates["checkpoint"] = input.Summary
+			if err := handoffStore.Update(input.HandoffID, map[string]interface{}{"checkpoint": input.Summary}); err != nil {
+				fmt.Fprintf(a.stderr, "warning: failed to save checkpoint on %s: %v\n", input.HandoffID, err)
+			}
 		}
-		if len(updates) > 0 {
-			handoffStore.Update(input.HandoffID, updates)
+		if input.Phase != "" {
+			if err := handoffStore.Update(input.HandoffID, map[string]interface{}{"phase": input.Phase, "force_phase": true}); err != nil {
+				fmt.Fprintf(a.stderr, "warning: failed to set phase on %s: %v\n", input.HandoffID, err)
+			}
 		}
 
 		// Check for completion indicators
@@ -381,11 +548,16 @@ type SessionEndInput struct {
 	Summary   string                   `json:"summary"`
 	NextSteps string                   `json:"next_steps"`
 	Messages  []map[string]interface{} `json:"messages"`
+
+	// ExtractLessons asks the API for lesson candidates from the session
+	ExtractLessons bool `json:"extract_lessons"`
 }
 
 // SessionEndOutput is the JSON output for session-end
 type SessionEndOutput struct {
-	Processed bool `json:"processed"`
+	Processed        bool                        `json:"processed"`
+	Metrics          models.SessionMetrics       `json:"metrics"`
+	SuggestedLessons []anthropic.SuggestedLesson `json:"suggested_lessons,omitempty"`
 }
 
 // runOpencodeSessionEnd handles the session-end subcommand
@@ -396,7 +568,7 @@ func (a *App) runOpencodeSessionEnd(stdin io.Reader) int {
 		return 1
 	}
 
-	handoffStore := handoffs.NewStore(a.handoffsPath, a.stealthPath)
+	handoffStore := a.handoffStore()
 
 	// Update handoff if exists and clean exit
 	if input.HandoffID != "" && input.ExitType == "clean" {
@@ -414,6 +586,11 @@ func (a *App) runOpencodeSessionEnd(stdin io.Reader) int {
 
 	output := SessionEndOutput{
 		Processed: true,
+		Metrics:   a.sessionMetrics(input.Messages),
+	}
+
+	if input.ExtractLessons {
+		output.SuggestedLessons = a.suggestSessionLessons(input.Messages)
 	}
 
 	data, err := json.Marshal(output)
@@ -426,10 +603,115 @@ func (a *App) runOpencodeSessionEnd(stdin io.Reader) int {
 	return 0
 }
 
+// sessionMetrics counts the citations, LESSON: commands, and handoff
+// patterns across the whole session. session-idle has already applied
+// them, so they are gathered with a dry run.
+func (a *App) sessionMetrics(messages []map[string]interface{}) models.SessionMetrics {
+	idle := a.processSessionIdle(SessionIdleInput{Messages: messages, DryRun: true}, a.stderr)
+
+	timestamps := make([]time.Time, len(messages))
+	for i, msg := range messages {
+		timestamps[i] = messageTime(msg)
+	}
+
+	metrics := models.SessionMetrics{
+		CitationCount:           len(idle.Citations),
+		MessagesProcessed:       len(messages),
+		SessionDurationEstimate: models.EstimateSessionDuration(timestamps),
+	}
+	for _, op := range idle.DryRunOps {
+		switch op.Type {
+		case "cite":
+		case "add_lesson":
+			metrics.LessonsAdded++
+		default:
+			metrics.HandoffOpsCount++
+		}
+	}
+	return metrics
+}
+
+// suggestSessionLessons asks the API for lessons in the session's assistant
+// messages. Failures are reported on stderr and yield no suggestions, so a
+// flaky API never fails session-end.
+func (a *App) suggestSessionLessons(messages []map[string]interface{}) []anthropic.SuggestedLesson {
+	var texts []string
+	for _, msg := range messages {
+		if role, _ := msg["role"].(string); role != "assistant" {
+			continue
+		}
+		if text, ok := messageText(msg); ok && strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	extract := a.extractLessons
+	if extract == nil {
+		extract = func(texts []string) ([]anthropic.SuggestedLesson, error) {
+			return anthropic.ExtractLessons(texts, 30*time.Second)
+		}
+	}
+	suggested, err := extract(texts)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "warning: lesson extraction failed: %v\n", err)
+		return nil
+	}
+	return suggested
+}
+
 // Helper functions
 
-// formatLessonsContext formats lessons for context injection
-func formatLessonsContext(allLessons []*models.Lesson, topN int) string {
+// messageTime returns when a message was sent: an RFC 3339 or Unix
+// millisecond "timestamp", or opencode's "time": {"created": ms}. Zero if
+// the message has neither.
+func messageTime(msg map[string]interface{}) time.Time {
+	switch ts := msg["timestamp"].(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t
+		}
+	case float64:
+		return time.UnixMilli(int64(ts))
+	}
+	if tm, ok := msg["time"].(map[string]interface{}); ok {
+		if created, ok := tm["created"].(float64); ok {
+			return time.UnixMilli(int64(created))
+		}
+	}
+	return time.Time{}
+}
+
+// messageText returns a message's text, handling both string content and
+// arrays of content blocks. ok is false when content has neither form.
+func messageText(msg map[string]interface{}) (string, bool) {
+	if str, ok := msg["content"].(string); ok {
+		return str, true
+	}
+	arr, ok := msg["content"].([]interface{})
+	if !ok {
+		return "", false
+	}
+	// Extract text from content blocks
+	var texts []string
+	for _, block := range arr {
+		if b, ok := block.(map[string]interface{}); ok {
+			if t, ok := b["type"].(string); ok && t == "text" {
+				if text, ok := b["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+	}
+	return strings.Join(texts, " "), true
+}
+
+// formatLessonsContext formats lessons for context injection, with tmpl
+// when non-nil. A failing template falls back to the built-in format with
+// a warning.
+func formatLessonsContext(allLessons []*models.Lesson, topN int, tmpl *template.Template, warn io.Writer) string {
 	if len(allLessons) == 0 {
 		return ""
 	}
@@ -456,6 +738,18 @@ func formatLessonsContext(allLessons []*models.Lesson, topN int) string {
 		topN = len(scored)
 	}
 
+	if tmpl != nil {
+		top := make([]*models.Lesson, topN)
+		for i := range top {
+			top[i] = scored[i].lesson
+		}
+		out, err := renderLessonsTemplate(tmpl, top, topN)
+		if err == nil {
+			return out
+		}
+		fmt.Fprintf(warn, "warning: %v (using the built-in format)\n", err)
+	}
+
 	var sb strings.Builder
 	sb.WriteString("## Recent Lessons\n\n")
 	for i := 0; i < topN; i++ {
@@ -467,11 +761,13 @@ func formatLessonsContext(allLessons []*models.Lesson, topN int) string {
 	return sb.String()
 }
 
-// formatHandoffsContext formats handoffs for context injection
-func formatHandoffsContext(handoffList []*models.Handoff) string {
+// formatHandoffsContext formats handoffs for context injection, ordered by
+// recencyBias (see orderHandoffsByRecency)
+func formatHandoffsContext(handoffList []*models.Handoff, recencyBias float64, now time.Time) string {
 	if len(handoffList) == 0 {
 		return ""
 	}
+	handoffList = orderHandoffsByRecency(handoffList, recencyBias, now)
 
 	var sb strings.Builder
 	sb.WriteString("## Active Handoffs\n\n")
@@ -606,8 +902,9 @@ func extractCitations(text string) []string {
 	return citations
 }
 
-// parseLessonCommands parses LESSON: commands and adds lessons
-func parseLessonCommands(text string, store *lessons.Store) []string {
+// parseLessonCommands parses LESSON: commands and adds lessons. With a
+// non-nil dryRun, the adds are recorded there instead.
+func parseLessonCommands(text string, store *lessons.Store, dryRun *[]DryRunOp) []string {
 	var added []string
 
 	matches := lessonPattern.FindAllStringSubmatch(text, -1)
@@ -620,6 +917,10 @@ func parseLessonCommands(text string, store *lessons.Store) []string {
 			title := strings.TrimSpace(match[2])
 			content := strings.TrimSpace(match[3])
 
+			if dryRun != nil {
+				*dryRun = append(*dryRun, DryRunOp{Type: "add_lesson", Category: category, Title: title})
+				continue
+			}
 			lesson, err := store.Add("project", category, title, content)
 			if err == nil {
 				added = append(added, lesson.ID)
@@ -630,8 +931,9 @@ func parseLessonCommands(text string, store *lessons.Store) []string {
 	return added
 }
 
-// parseHandoffPatterns parses handoff patterns and performs operations
-func parseHandoffPatterns(text string, store *handoffs.Store) []string {
+// parseHandoffPatterns parses handoff patterns and performs operations. With
+// a non-nil dryRun, the operations are recorded there instead.
+func parseHandoffPatterns(text string, store *handoffs.Store, dryRun *[]DryRunOp) []string {
 	var ops []string
 
 	// HANDOFF: start
@@ -639,6 +941,10 @@ func parseHandoffPatterns(text string, store *handoffs.Store) []string {
 	for _, match := range startMatches {
 		if len(match) > 1 {
 			title := strings.TrimSpace(match[1])
+			if dryRun != nil {
+				*dryRun = append(*dryRun, DryRunOp{Type: "start_handoff", Title: title})
+				continue
+			}
 			h, err := store.Add(title, "", false)
 			if err == nil {
 				ops = append(ops, fmt.Sprintf("started %s", h.ID))
@@ -653,6 +959,10 @@ func parseHandoffPatterns(text string, store *handoffs.Store) []string {
 			id := match[1]
 			outcome := match[2]
 			desc := strings.TrimSpace(match[3])
+			if dryRun != nil {
+				*dryRun = append(*dryRun, DryRunOp{Type: "tried", ID: id, Outcome: outcome, Description: desc})
+				continue
+			}
 			err := store.AddTriedStep(id, outcome, desc)
 			if err == nil {
 				ops = append(ops, fmt.Sprintf("updated %s (tried %s)", id, outcome))
@@ -665,6 +975,10 @@ func parseHandoffPatterns(text string, store *handoffs.Store) []string {
 	for _, match := range completeMatches {
 		if len(match) > 1 {
 			id := match[1]
+			if dryRun != nil {
+				*dryRun = append(*dryRun, DryRunOp{Type: "complete_handoff", ID: id})
+				continue
+			}
 			err := store.Complete(id)
 			if err == nil {
 				ops = append(ops, fmt.Sprintf("completed %s", id))
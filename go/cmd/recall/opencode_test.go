@@ -3,13 +3,16 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pbrown/claude-recall/internal/anthropic"
 	"github.com/pbrown/claude-recall/internal/handoffs"
 	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
 )
 
 // ============================================================================
@@ -345,6 +348,11 @@ func TestOpencodeSessionIdle_ExtractsLessonCitations(t *testing.T) {
 	if !found {
 		t.Errorf("expected citations to contain '%s', got: %v", lesson.ID, citations)
 	}
+
+	events, _ := lessons.NewAuditLog(stateDir).Read()
+	if len(events) == 0 || events[len(events)-1].Event != lessons.AuditCite || events[len(events)-1].SessionID != "test-session-123" {
+		t.Errorf("expected the cite audited with the session ID, got %+v", events)
+	}
 }
 
 func TestOpencodeSessionIdle_ExtractsSystemLessonCitations(t *testing.T) {
@@ -519,6 +527,84 @@ func TestOpencodeSessionIdle_ParsesLessonCommand(t *testing.T) {
 	}
 }
 
+func TestOpencodeSessionIdle_DryRunLeavesFilesUntouched(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
+	systemDir := filepath.Join(tmpDir, "system")
+	stateDir := filepath.Join(tmpDir, "state")
+	os.MkdirAll(projectDir, 0755)
+	os.MkdirAll(systemDir, 0755)
+	os.MkdirAll(stateDir, 0755)
+
+	projectPath := filepath.Join(projectDir, "LESSONS.md")
+	systemPath := filepath.Join(systemDir, "LESSONS.md")
+	handoffsPath := filepath.Join(projectDir, "HANDOFFS.md")
+	stealthPath := filepath.Join(projectDir, "HANDOFFS_LOCAL.md")
+
+	lStore := lessons.NewStore(projectPath, systemPath)
+	lesson, _ := lStore.Add("project", "pattern", "Existing Lesson", "Content")
+	hStore := handoffs.NewStore(handoffsPath, stealthPath)
+	h, _ := hStore.Add("Existing handoff", "", false)
+
+	lessonsBefore, _ := os.ReadFile(projectPath)
+	handoffsBefore, _ := os.ReadFile(handoffsPath)
+
+	input := map[string]interface{}{
+		"cwd":        filepath.Dir(projectDir),
+		"session_id": "test-session-123",
+		"messages": []map[string]interface{}{
+			{
+				"role":    "assistant",
+				"content": "Applying [" + lesson.ID + "] here.\nLESSON: gotcha: Check the cache - stale entries bite\nHANDOFF COMPLETE " + h.ID,
+			},
+		},
+		"checkpoint_offset": 0,
+		"dry_run":           true,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	var stdout bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.projectPath = projectPath
+	app.systemPath = systemPath
+	app.handoffsPath = handoffsPath
+	app.stealthPath = stealthPath
+	app.stateDir = stateDir
+
+	if exitCode := app.runOpencodeSessionIdle(strings.NewReader(string(inputJSON))); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", exitCode)
+	}
+
+	var result SessionIdleOutput
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if !result.DryRun {
+		t.Error("expected dry_run in output")
+	}
+	want := []DryRunOp{
+		{Type: "cite", ID: lesson.ID},
+		{Type: "add_lesson", Category: "gotcha", Title: "Check the cache"},
+		{Type: "complete_handoff", ID: h.ID},
+	}
+	if len(result.DryRunOps) != len(want) {
+		t.Fatalf("expected %d dry-run ops, got %+v", len(want), result.DryRunOps)
+	}
+	for i, op := range want {
+		if result.DryRunOps[i] != op {
+			t.Errorf("op %d = %+v, want %+v", i, result.DryRunOps[i], op)
+		}
+	}
+
+	if lessonsAfter, _ := os.ReadFile(projectPath); !bytes.Equal(lessonsBefore, lessonsAfter) {
+		t.Errorf("expected LESSONS.md unchanged, got:\n%s", lessonsAfter)
+	}
+	if handoffsAfter, _ := os.ReadFile(handoffsPath); !bytes.Equal(handoffsBefore, handoffsAfter) {
+		t.Errorf("expected HANDOFFS.md unchanged, got:\n%s", handoffsAfter)
+	}
+}
+
 func TestOpencodeSessionIdle_ParsesHandoffStartPattern(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -1151,6 +1237,39 @@ func TestOpencodePostCompact_UpdatesHandoffPhase(t *testing.T) {
 	}
 }
 
+func TestOpencodePostCompact_InvalidPhaseKeepsCheckpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	handoffsPath := filepath.Join(tmpDir, "HANDOFFS.md")
+	stealthPath := filepath.Join(tmpDir, "HANDOFFS_LOCAL.md")
+	hStore := handoffs.NewStore(handoffsPath, stealthPath)
+	h, _ := hStore.Add("Work Item", "Description", false)
+
+	inputJSON, _ := json.Marshal(map[string]interface{}{
+		"handoff_id": h.ID,
+		"phase":      "bogus",
+		"summary":    "Wired up the parser",
+	})
+
+	var stdout, stderr bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &stderr
+	app.handoffsPath = handoffsPath
+	app.stealthPath = stealthPath
+	app.stateDir = tmpDir
+
+	if exitCode := app.runOpencodePostCompact(strings.NewReader(string(inputJSON))); exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+	updated, _ := hStore.Get(h.ID)
+	if updated.Checkpoint != "Wired up the parser" {
+		t.Errorf("expected checkpoint to be saved, got %q", updated.Checkpoint)
+	}
+	if !strings.Contains(stderr.String(), "failed to set phase") {
+		t.Errorf("expected a phase warning, got: %s", stderr.String())
+	}
+}
+
 func TestOpencodePostCompact_DetectsCompletionIndicators(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
@@ -1302,6 +1421,65 @@ func TestOpencodeSessionEnd_UpdatesHandoffWithSummary(t *testing.T) {
 	}
 }
 
+func TestOpencodeSessionEnd_ExtractLessons(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	var gotTexts []string
+	app.extractLessons = func(texts []string) ([]anthropic.SuggestedLesson, error) {
+		gotTexts = texts
+		return []anthropic.SuggestedLesson{
+			{Category: "gotcha", Title: "Close response bodies", Content: "Always defer resp.Body.Close()."},
+		}, nil
+	}
+
+	run := func(extract bool) SessionEndOutput {
+		t.Helper()
+		input, _ := json.Marshal(map[string]interface{}{
+			"session_id":      "test-session-123",
+			"exit_type":       "clean",
+			"extract_lessons": extract,
+			"messages": []map[string]interface{}{
+				{"role": "user", "content": "Why is the server leaking connections?"},
+				{"role": "assistant", "content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "I learned that response bodies must be closed."},
+				}},
+			},
+		})
+		stdout.Reset()
+		if code := app.runOpencodeSessionEnd(bytes.NewReader(input)); code != 0 {
+			t.Fatalf("session-end failed: %s", stderr.String())
+		}
+		var out SessionEndOutput
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("failed to parse output JSON: %v", err)
+		}
+		return out
+	}
+
+	out := run(true)
+	if len(out.SuggestedLessons) != 1 {
+		t.Fatalf("expected 1 suggested lesson, got %+v", out.SuggestedLessons)
+	}
+	if l := out.SuggestedLessons[0]; l.Category != "gotcha" || l.Title != "Close response bodies" || l.Content == "" {
+		t.Errorf("unexpected suggestion: %+v", l)
+	}
+	if len(gotTexts) != 1 || gotTexts[0] != "I learned that response bodies must be closed." {
+		t.Errorf("expected only assistant text passed to extraction, got %q", gotTexts)
+	}
+
+	gotTexts = nil
+	if out := run(false); len(out.SuggestedLessons) != 0 || gotTexts != nil {
+		t.Errorf("expected no extraction without extract_lessons, got %+v", out.SuggestedLessons)
+	}
+
+	// API failures are warnings, not errors
+	app.extractLessons = func([]string) ([]anthropic.SuggestedLesson, error) {
+		return nil, errors.New("rate limited")
+	}
+	if out := run(true); len(out.SuggestedLessons) != 0 || !strings.Contains(stderr.String(), "lesson extraction failed") {
+		t.Errorf("expected warning and no suggestions, got %+v (%s)", out.SuggestedLessons, stderr.String())
+	}
+}
+
 // ============================================================================
 // Integration Tests
 // ============================================================================
@@ -1365,3 +1543,226 @@ func TestOpencodeCommand_RequiresSubcommand(t *testing.T) {
 		t.Error("expected non-zero exit code for missing subcommand")
 	}
 }
+
+func TestOpencodeSessionEnd_Metrics(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Wrap errors", "Use %w when wrapping")
+
+	input, _ := json.Marshal(map[string]interface{}{
+		"session_id": "test-session-123",
+		"exit_type":  "clean",
+		"messages": []map[string]interface{}{
+			{"role": "user", "content": "Fix the flaky test", "timestamp": "2025-03-01T09:00:00Z"},
+			{"role": "assistant", "content": "Applying [L001]: wrap the error. HANDOFF: Fix flaky test",
+				"time": map[string]interface{}{"created": 1740820500000}}, // 09:15:00Z
+			{"role": "assistant", "content": "LESSON: gotcha: Flaky timers - Use a fake clock in tests\nAlso [L001] again",
+				"timestamp": "2025-03-01T09:45:30Z"},
+		},
+	})
+	if code := app.runOpencodeSessionEnd(bytes.NewReader(input)); code != 0 {
+		t.Fatalf("session-end failed: %s", stderr.String())
+	}
+
+	var out SessionEndOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse output JSON: %v", err)
+	}
+	want := models.SessionMetrics{
+		CitationCount:           2,
+		LessonsAdded:            1,
+		HandoffOpsCount:         1,
+		MessagesProcessed:       3,
+		SessionDurationEstimate: "45m30s",
+	}
+	if out.Metrics != want {
+		t.Errorf("metrics = %+v, want %+v", out.Metrics, want)
+	}
+
+	// Metrics are gathered without re-applying the session's commands
+	if all, _ := store.List(); len(all) != 1 {
+		t.Errorf("expected no lessons added at session end, got %d", len(all))
+	}
+	if l, _ := store.Get("L001"); l.Uses != 0 {
+		t.Errorf("expected L001 uses unchanged, got %d", l.Uses)
+	}
+}
+
+func TestOpencodeSessionIdle_VerboseLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
+	systemDir := filepath.Join(tmpDir, "system")
+	stateDir := filepath.Join(tmpDir, "state")
+	os.MkdirAll(projectDir, 0755)
+	os.MkdirAll(systemDir, 0755)
+	os.MkdirAll(stateDir, 0755)
+
+	projectPath := filepath.Join(projectDir, "LESSONS.md")
+	systemPath := filepath.Join(systemDir, "LESSONS.md")
+	lStore := lessons.NewStore(projectPath, systemPath)
+	lesson, _ := lStore.Add("project", "pattern", "Existing Lesson", "Content")
+
+	input := map[string]interface{}{
+		"messages": []map[string]interface{}{
+			{"role": "user", "content": "Please fix the build"},
+			{"role": "assistant", "content": "Applying [" + lesson.ID + "] here.\nLESSON: gotcha: Check the cache - stale entries bite"},
+			{"role": "assistant", "content": "HANDOFF: Split billing service"},
+		},
+		"verbose": true,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	var stdout bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.projectPath = projectPath
+	app.systemPath = systemPath
+	app.handoffsPath = filepath.Join(projectDir, "HANDOFFS.md")
+	app.stealthPath = filepath.Join(projectDir, "HANDOFFS_LOCAL.md")
+	app.stateDir = stateDir
+
+	if exitCode := app.runOpencodeSessionIdle(strings.NewReader(string(inputJSON))); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", exitCode)
+	}
+
+	var result SessionIdleOutput
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	want := []string{
+		"message[0]: no matches",
+		"message[1]: found citation " + lesson.ID + ", found LESSON: gotcha",
+		"message[2]: found HANDOFF: Split billing service",
+	}
+	if len(result.VerboseLog) != len(want) {
+		t.Fatalf("expected %d verbose log entries, got %q", len(want), result.VerboseLog)
+	}
+	for i, entry := range want {
+		if result.VerboseLog[i] != entry {
+			t.Errorf("verbose_log[%d] = %q, want %q", i, result.VerboseLog[i], entry)
+		}
+	}
+
+	// Verbose mode still performs the operations
+	if len(result.Citations) != 1 || len(result.LessonsAdded) != 1 || len(result.HandoffOps) != 1 {
+		t.Errorf("expected operations to run, got %+v", result)
+	}
+}
+
+func TestOpencodeSessionIdle_AutoCreatesHandoffFromTodos(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project", ".claude-recall")
+	stateDir := filepath.Join(tmpDir, "state")
+	os.MkdirAll(projectDir, 0755)
+	os.MkdirAll(stateDir, 0755)
+
+	var stdout bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &bytes.Buffer{}
+	app.projectPath = filepath.Join(projectDir, "LESSONS.md")
+	app.systemPath = filepath.Join(tmpDir, "system", "LESSONS.md")
+	app.handoffsPath = filepath.Join(projectDir, "HANDOFFS.md")
+	app.stealthPath = filepath.Join(projectDir, "HANDOFFS_LOCAL.md")
+	app.stateDir = stateDir
+
+	var todos []map[string]interface{}
+	for _, subject := range []string{"Design schema", "Write migration", "Update API", "Add tests", "Update docs"} {
+		todos = append(todos, map[string]interface{}{"subject": subject, "status": "pending"})
+	}
+	todos = append(todos, map[string]interface{}{"subject": "Read the ticket", "status": "completed"})
+
+	run := func(autoCreate bool) SessionIdleOutput {
+		t.Helper()
+		stdout.Reset()
+		inputJSON, _ := json.Marshal(map[string]interface{}{
+			"session_id":          "session-1",
+			"messages":            []map[string]interface{}{},
+			"todos":               todos,
+			"auto_create_handoff": autoCreate,
+		})
+		if exitCode := app.runOpencodeSessionIdle(bytes.NewReader(inputJSON)); exitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", exitCode)
+		}
+		var result SessionIdleOutput
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("failed to parse output: %v", err)
+		}
+		return result
+	}
+
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+
+	// Without auto_create_handoff, a handoff is only suggested
+	if result := run(false); !result.SuggestHandoff || result.AutoCreatedHandoffID != "" {
+		t.Errorf("expected only a suggestion, got %+v", result)
+	}
+	if list, _ := hStore.List(); len(list) != 0 {
+		t.Fatalf("expected no handoffs, got %d", len(list))
+	}
+
+	result := run(true)
+	if result.AutoCreatedHandoffID == "" {
+		t.Fatalf("expected a handoff to be created, got %+v", result)
+	}
+	h, err := hStore.Get(result.AutoCreatedHandoffID)
+	if err != nil {
+		t.Fatalf("created handoff not found: %v", err)
+	}
+	if h.Title != "Design schema" || h.NextSteps != "Design schema; Write migration; Update API; Add tests; Update docs" {
+		t.Errorf("unexpected handoff: title %q, next steps %q", h.Title, h.NextSteps)
+	}
+
+	// The session now has an active handoff, so no second one is created
+	if again := run(true); again.AutoCreatedHandoffID != "" || again.SuggestHandoff {
+		t.Errorf("expected no new handoff for a session with one, got %+v", again)
+	}
+	if list, _ := hStore.List(); len(list) != 1 {
+		t.Errorf("expected 1 handoff, got %d", len(list))
+	}
+}
+
+func TestOpencodeSessionIdle_AutoCreateDedupesByTitleWithoutSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	var stdout bytes.Buffer
+	app := NewApp()
+	app.stdout = &stdout
+	app.stderr = &bytes.Buffer{}
+	app.projectPath = filepath.Join(tmpDir, "LESSONS.md")
+	app.systemPath = filepath.Join(tmpDir, "system", "LESSONS.md")
+	app.handoffsPath = filepath.Join(tmpDir, "HANDOFFS.md")
+	app.stealthPath = filepath.Join(tmpDir, "HANDOFFS_LOCAL.md")
+	app.stateDir = filepath.Join(tmpDir, "state")
+
+	var todos []map[string]interface{}
+	for _, subject := range []string{"Design schema", "Write migration", "Update API", "Add tests", "Update docs"} {
+		todos = append(todos, map[string]interface{}{"subject": subject, "status": "pending"})
+	}
+	inputJSON, _ := json.Marshal(map[string]interface{}{
+		"messages":            []map[string]interface{}{},
+		"todos":               todos,
+		"auto_create_handoff": true,
+	})
+
+	var created []string
+	for i := 0; i < 2; i++ {
+		stdout.Reset()
+		if exitCode := app.runOpencodeSessionIdle(bytes.NewReader(inputJSON)); exitCode != 0 {
+			t.Fatalf("run %d: expected exit code 0, got %d", i, exitCode)
+		}
+		var result SessionIdleOutput
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("run %d: failed to parse output: %v", i, err)
+		}
+		if result.AutoCreatedHandoffID != "" {
+			created = append(created, result.AutoCreatedHandoffID)
+		}
+	}
+
+	if len(created) != 1 {
+		t.Errorf("expected one handoff across two runs, created %v", created)
+	}
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	if list, _ := hStore.List(); len(list) != 1 {
+		t.Errorf("expected 1 handoff, got %d", len(list))
+	}
+}
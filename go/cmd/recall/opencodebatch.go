@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// batchSessionResult is one session's entry in opencode batch output
+type batchSessionResult struct {
+	File      string `json:"file"`
+	SessionID string `json:"session_id,omitempty"`
+	SessionIdleOutput
+}
+
+// runOpencodeBatch runs session-idle over every *.json session file in a
+// directory and prints a JSON array of per-session results, in file name
+// order. Sessions are processed by up to --parallel workers.
+func (a *App) runOpencodeBatch(args []string) int {
+	var dir string
+	parallel := 1
+	dryRun := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--sessions" && i+1 < len(args):
+			dir = args[i+1]
+			i++
+		case args[i] == "--parallel" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(a.stderr, "error: invalid --parallel '%s'\n", args[i+1])
+				return 1
+			}
+			parallel = n
+			i++
+		case args[i] == "--dry-run":
+			dryRun = true
+		}
+	}
+	if dir == "" {
+		fmt.Fprintln(a.stderr, "usage: recall opencode batch --sessions <dir> [--parallel N] [--dry-run]")
+		return 1
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing sessions: %v\n", err)
+		return 1
+	}
+	sort.Strings(files)
+
+	results := make([]batchSessionResult, len(files))
+	warnings := make([]bytes.Buffer, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = a.processSessionFile(files[i], dryRun, &warnings[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Warnings are buffered per session so parallel workers don't interleave
+	for i := range warnings {
+		a.stderr.Write(warnings[i].Bytes())
+	}
+	return a.writeJSON(results)
+}
+
+// processSessionFile runs session-idle processing on one session JSON file.
+// A file that cannot be read or parsed yields a result with Error set.
+func (a *App) processSessionFile(path string, dryRun bool, warn *bytes.Buffer) batchSessionResult {
+	result := batchSessionResult{File: filepath.Base(path)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	var input SessionIdleInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		result.Error = fmt.Sprintf("parsing session JSON: %v", err)
+		return result
+	}
+	if dryRun {
+		input.DryRun = true
+	}
+
+	result.SessionID = input.SessionID
+	result.SessionIdleOutput = a.processSessionIdle(input, warn)
+	return result
+}
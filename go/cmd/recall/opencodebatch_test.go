@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpencodeBatch_ProcessesEverySession(t *testing.T) {
+	for _, parallel := range []string{"1", "3"} {
+		t.Run("parallel="+parallel, func(t *testing.T) {
+			app, store, stdout, stderr := newTestApp(t)
+			store.Add("project", "pattern", "First lesson", "Content one")
+			store.Add("project", "gotcha", "Second lesson", "Content two")
+
+			sessionsDir := t.TempDir()
+			sessions := map[string]string{
+				"a.json": "Applying [L001] and [L002] here.",
+				"b.json": "Only [L002] applies.",
+				"c.json": "No citations in this one.",
+			}
+			for name, text := range sessions {
+				data, _ := json.Marshal(map[string]interface{}{
+					"session_id": strings.TrimSuffix(name, ".json"),
+					"messages":   []map[string]interface{}{{"role": "assistant", "content": text}},
+				})
+				os.WriteFile(filepath.Join(sessionsDir, name), data, 0644)
+			}
+			os.WriteFile(filepath.Join(sessionsDir, "notes.txt"), []byte("ignored"), 0644)
+
+			code := app.Run([]string{"recall", "opencode", "batch", "--sessions", sessionsDir, "--parallel", parallel})
+			if code != 0 {
+				t.Fatalf("batch failed: %s", stderr.String())
+			}
+
+			var results []batchSessionResult
+			if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+				t.Fatalf("invalid JSON %q: %v", stdout.String(), err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("expected 3 results, got %d", len(results))
+			}
+			for i, want := range []struct {
+				file      string
+				citations int
+			}{{"a.json", 2}, {"b.json", 1}, {"c.json", 0}} {
+				r := results[i]
+				if r.File != want.file || r.SessionID != strings.TrimSuffix(want.file, ".json") || len(r.Citations) != want.citations {
+					t.Errorf("result %d: expected %s with %d citations, got %+v", i, want.file, want.citations, r)
+				}
+			}
+
+			l2, _ := store.Get("L002")
+			if l2.Uses != 2 {
+				t.Errorf("expected L002 cited twice across sessions, got %d uses", l2.Uses)
+			}
+		})
+	}
+}
+
+func TestOpencodeBatch_DryRunAndErrors(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "First lesson", "Content one")
+
+	sessionsDir := t.TempDir()
+	os.WriteFile(filepath.Join(sessionsDir, "good.json"),
+		[]byte(`{"messages": [{"role": "assistant", "content": "Using [L001]. LESSON: pattern: New idea - Some content"}]}`), 0644)
+	os.WriteFile(filepath.Join(sessionsDir, "bad.json"), []byte(`{"messages": `), 0644)
+
+	if code := app.Run([]string{"recall", "opencode", "batch", "--sessions", sessionsDir, "--dry-run"}); code != 0 {
+		t.Fatalf("batch failed: %s", stderr.String())
+	}
+	var results []batchSessionResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout.String(), err)
+	}
+	if len(results) != 2 || results[0].File != "bad.json" || results[0].Error == "" {
+		t.Fatalf("expected bad.json to report a parse error first, got %+v", results)
+	}
+	if !results[1].DryRun || len(results[1].Citations) != 1 || len(results[1].DryRunOps) != 2 {
+		t.Errorf("expected dry-run result with 1 citation and 2 ops, got %+v", results[1])
+	}
+
+	list, _ := store.List()
+	if len(list) != 1 || list[0].Uses != 0 {
+		t.Errorf("expected dry run to leave lessons untouched, got %+v", list)
+	}
+
+	if code := app.Run([]string{"recall", "opencode", "batch"}); code != 1 {
+		t.Errorf("expected exit code 1 without --sessions, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "opencode", "batch", "--sessions", sessionsDir, "--parallel", "0"}); code != 1 {
+		t.Errorf("expected exit code 1 for --parallel 0, got %d", code)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// runHandoffReport writes a Markdown status report of active handoffs,
+// suitable for pasting into a standup channel or GitHub comment
+func (a *App) runHandoffReport(args []string) int {
+	format := "markdown"
+	staleDays := -1
+	includeMetrics := false
+	var outPath string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--include-metrics":
+			includeMetrics = true
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--filter-stale":
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 0 {
+					fmt.Fprintf(a.stderr, "error: invalid --filter-stale '%s'\n", args[i+1])
+					return 1
+				}
+				staleDays = n
+				i++
+			}
+		case "--out":
+			if i+1 < len(args) {
+				outPath = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if format != "markdown" {
+		fmt.Fprintf(a.stderr, "error: unknown format %q (use markdown)\n", format)
+		return 1
+	}
+
+	store := a.handoffStore()
+	active, err := store.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+
+	now := time.Now()
+	if staleDays >= 0 {
+		var stale []*models.Handoff
+		for _, h := range active {
+			if daysSince(h.Updated, now) >= staleDays {
+				stale = append(stale, h)
+			}
+		}
+		active = stale
+	}
+
+	report := formatHandoffReport(active, now, includeMetrics)
+	if outPath == "" {
+		fmt.Fprint(a.stdout, report)
+		return 0
+	}
+	if err := os.WriteFile(outPath, []byte(report), 0644); err != nil {
+		fmt.Fprintf(a.stderr, "error writing %s: %v\n", outPath, err)
+		return 1
+	}
+	fmt.Fprintf(a.stdout, "Wrote report for %d handoffs to %s\n", len(active), outPath)
+	return 0
+}
+
+// formatHandoffReport renders handoffs as a Markdown table, oldest update
+// first. Handoffs untouched for HandoffStaleDays or more are marked stale.
+func formatHandoffReport(handoffList []*models.Handoff, now time.Time, includeMetrics bool) string {
+	sorted := append([]*models.Handoff(nil), handoffList...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Updated.Before(sorted[j].Updated)
+	})
+
+	var sb strings.Builder
+	sb.WriteString("# Handoff Status Report\n\n")
+	fmt.Fprintf(&sb, "_Generated %s_\n\n", now.Format("2006-01-02"))
+	sb.WriteString("## Active Handoffs\n\n")
+
+	if len(sorted) == 0 {
+		sb.WriteString("_No active handoffs._\n")
+	} else {
+		sb.WriteString("| ID | Title | Status | Phase | Last Tried | Next Steps | Days Since Update |\n")
+		sb.WriteString("|----|-------|--------|-------|------------|------------|-------------------|\n")
+		for _, h := range sorted {
+			lastTried := "-"
+			if n := len(h.Tried); n > 0 {
+				lastTried = fmt.Sprintf("%s: %s", h.Tried[n-1].Outcome, h.Tried[n-1].Description)
+			}
+			nextSteps := h.NextSteps
+			if nextSteps == "" {
+				nextSteps = "-"
+			}
+			days := daysSince(h.Updated, now)
+			age := strconv.Itoa(days)
+			if days >= models.HandoffStaleDays {
+				age += " **(stale)**"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				h.ID, markdownCell(h.Title), h.Status, h.Phase,
+				markdownCell(lastTried), markdownCell(nextSteps), age)
+		}
+	}
+
+	if includeMetrics {
+		byStatus := make(map[string]int)
+		stale := 0
+		for _, h := range sorted {
+			byStatus[h.Status]++
+			if daysSince(h.Updated, now) >= models.HandoffStaleDays {
+				stale++
+			}
+		}
+		statuses := make([]string, 0, len(byStatus))
+		for status := range byStatus {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+
+		sb.WriteString("\n## Metrics\n\n")
+		fmt.Fprintf(&sb, "- **Active**: %d\n", len(sorted))
+		for _, status := range statuses {
+			fmt.Fprintf(&sb, "- **%s**: %d\n", status, byStatus[status])
+		}
+		fmt.Fprintf(&sb, "- **Stale (%d+ days)**: %d\n", models.HandoffStaleDays, stale)
+	}
+
+	return sb.String()
+}
+
+// daysSince returns whole days elapsed from t to now
+func daysSince(t, now time.Time) int {
+	if t.After(now) {
+		return 0
+	}
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// markdownCell makes text safe for a single Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeReportFixture writes one fresh and one stale active handoff plus a
+// completed one that reports must skip
+func writeReportFixture(t *testing.T, app *App) {
+	t.Helper()
+	ago := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+	fixture := `# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Fresh work
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: ` + ago(3) + ` | **Updated**: ` + ago(1) + `
+- **Description**: Moving along
+
+**Tried**:
+1. [fail] Cache everything - memory blew up
+2. [partial] Cache hot keys - mostly works
+
+**Next**: Tune eviction | measure
+
+---
+
+### [hf-0000002] Forgotten spike
+- **Status**: blocked | **Phase**: research | **Agent**: user
+- **Created**: ` + ago(30) + ` | **Updated**: ` + ago(20) + `
+- **Description**: Waiting on vendor
+
+---
+
+### [hf-0000003] Shipped feature
+- **Status**: completed | **Phase**: review | **Agent**: user
+- **Created**: ` + ago(30) + ` | **Updated**: ` + ago(2) + `
+- **Description**: Done
+
+---
+`
+	os.MkdirAll(filepath.Dir(app.handoffsPath), 0755)
+	if err := os.WriteFile(app.handoffsPath, []byte(fixture), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+}
+
+func Test_HandoffReport_Markdown(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	writeReportFixture(t, app)
+
+	if code := app.Run([]string{"recall", "handoff", "report", "--format", "markdown", "--include-metrics"}); code != 0 {
+		t.Fatalf("report failed: %s", stderr.String())
+	}
+	out := stdout.String()
+
+	for _, want := range []string{
+		"# Handoff Status Report",
+		"## Active Handoffs",
+		"| ID | Title | Status | Phase | Last Tried | Next Steps | Days Since Update |",
+		"| hf-0000001 | Fresh work | in_progress | implementing | partial: Cache hot keys - mostly works | Tune eviction \\| measure | ",
+		"| hf-0000002 | Forgotten spike | blocked | research | - | - | ",
+		"## Metrics",
+		"- **Active**: 2",
+		"- **blocked**: 1",
+		"- **Stale (7+ days)**: 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		stale := strings.HasSuffix(line, "**(stale)** |")
+		if strings.HasPrefix(line, "| hf-0000001") && stale {
+			t.Errorf("expected fresh handoff not to be stale: %s", line)
+		}
+		if strings.HasPrefix(line, "| hf-0000002") && !stale {
+			t.Errorf("expected forgotten handoff to be stale: %s", line)
+		}
+	}
+	if strings.Contains(out, "Shipped feature") {
+		t.Errorf("expected completed handoffs to be skipped:\n%s", out)
+	}
+	if strings.Index(out, "hf-0000002") > strings.Index(out, "hf-0000001") {
+		t.Errorf("expected oldest update first:\n%s", out)
+	}
+}
+
+func Test_HandoffReport_FilterStaleAndOut(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	writeReportFixture(t, app)
+	outPath := filepath.Join(t.TempDir(), "report.md")
+
+	if code := app.Run([]string{"recall", "handoff", "report", "--filter-stale", "7", "--out", outPath}); code != 0 {
+		t.Fatalf("report failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Wrote report for 1 handoffs") {
+		t.Errorf("unexpected stdout: %s", stdout.String())
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("expected report file: %v", err)
+	}
+	if !strings.Contains(string(data), "Forgotten spike") || strings.Contains(string(data), "Fresh work") {
+		t.Errorf("expected only the stale handoff, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "## Metrics") {
+		t.Errorf("expected no metrics without --include-metrics:\n%s", data)
+	}
+
+	for _, bad := range [][]string{{"--format", "html"}, {"--filter-stale", "soon"}} {
+		if code := app.Run(append([]string{"recall", "handoff", "report"}, bad...)); code != 1 {
+			t.Errorf("expected exit code 1 for %v, got %d", bad, code)
+		}
+	}
+}
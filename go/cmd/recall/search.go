@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/scoring"
+)
+
+// searchSnippetLen is the maximum snippet length shown per search result
+const searchSnippetLen = 100
+
+// searchResult is a single ranked lesson or handoff match
+type searchResult struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Score   int    `json:"score"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// runFind matches lessons whose title or category contains the query,
+// ignoring case. A single match is shown in full; several are listed.
+// Exits 1 when nothing matches.
+func (a *App) runFind(args []string) int {
+	var category string
+	var words []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--category" && i+1 < len(args) {
+			category = args[i+1]
+			i++
+		} else {
+			words = append(words, args[i])
+		}
+	}
+	query := strings.ToLower(strings.Join(words, " "))
+	if query == "" {
+		fmt.Fprintln(a.stderr, "usage: recall find <partial-title> [--category C]")
+		return 1
+	}
+
+	allLessons, err := a.lessonStore().List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	var matches []*models.Lesson
+	for _, l := range allLessons {
+		if category != "" && !strings.EqualFold(l.Category, category) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(l.Title), query) || strings.Contains(strings.ToLower(l.Category), query) {
+			matches = append(matches, l)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		fmt.Fprintf(a.stdout, "No lessons match %q.\n", query)
+		return 1
+	case 1:
+		a.printLessonDetail(matches[0])
+	default:
+		for _, l := range matches {
+			a.printLessonLine(l)
+		}
+	}
+	return 0
+}
+
+// runSearch performs full-text search across lessons and handoffs
+func (a *App) runSearch(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall search <query> [--type lessons|handoffs|all] [--top N] [--json]")
+		return 1
+	}
+
+	query := args[0]
+	searchType := "all"
+	topN := 10
+	jsonOutput := false
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--type":
+			if i+1 < len(args) {
+				searchType = args[i+1]
+				i++
+			}
+		case "--top":
+			if i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil {
+					topN = n
+				}
+				i++
+			}
+		case "--json":
+			jsonOutput = true
+		}
+	}
+
+	if searchType != "all" && searchType != "lessons" && searchType != "handoffs" {
+		fmt.Fprintf(a.stderr, "error: unknown type %q (use lessons, handoffs, or all)\n", searchType)
+		return 1
+	}
+
+	// Load both stores in parallel
+	var (
+		wg                    sync.WaitGroup
+		lessonList            []*models.Lesson
+		handoffList           []*models.Handoff
+		lessonErr, handoffErr error
+	)
+	if searchType != "handoffs" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lessonList, lessonErr = a.lessonStore().List()
+		}()
+	}
+	if searchType != "lessons" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handoffList, handoffErr = a.handoffStore().ListAll()
+		}()
+	}
+	wg.Wait()
+
+	if lessonErr != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", lessonErr)
+		return 1
+	}
+	if handoffErr != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", handoffErr)
+		return 1
+	}
+
+	results := rankSearchResults(query, lessonList, handoffList)
+	if len(results) > topN {
+		results = results[:topN]
+	}
+
+	if jsonOutput {
+		data, err := json.Marshal(map[string][]searchResult{"results": results})
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error encoding output JSON: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(a.stdout, string(data))
+		return 0
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(a.stdout, "No results found.")
+		return 0
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(a.stdout, "[%s] %s (score: %d/10) %s\n", r.Type, r.ID, r.Score, r.Title)
+		if r.Snippet != "" {
+			fmt.Fprintf(a.stdout, "    -> %s\n", r.Snippet)
+		}
+	}
+
+	return 0
+}
+
+// rankSearchResults scores lessons and handoffs as a single BM25 corpus so
+// their scores are directly comparable, dropping non-matching entries.
+// Handoffs are wrapped as lessons carrying their searchable text.
+func rankSearchResults(query string, lessonList []*models.Lesson, handoffList []*models.Handoff) []searchResult {
+	corpus := make([]*models.Lesson, 0, len(lessonList)+len(handoffList))
+	byDoc := make(map[*models.Lesson]searchResult, cap(corpus))
+
+	for _, l := range lessonList {
+		corpus = append(corpus, l)
+		byDoc[l] = searchResult{Type: "lesson", ID: l.ID, Title: l.Title, Snippet: l.Content}
+	}
+	for _, h := range handoffList {
+		doc := &models.Lesson{ID: h.ID, Title: h.Title, Content: scoring.HandoffText(h)}
+		corpus = append(corpus, doc)
+		byDoc[doc] = searchResult{Type: "handoff", ID: h.ID, Title: h.Title, Snippet: h.Description}
+	}
+
+	var results []searchResult
+	for _, sl := range scoring.NewBM25Scorer(corpus).Score(query) {
+		if sl.Score < 1 {
+			continue
+		}
+		r := byDoc[sl.Lesson]
+		r.Score = sl.Score
+		r.Snippet = truncateContent(r.Snippet, searchSnippetLen)
+		results = append(results, r)
+	}
+
+	if results == nil {
+		results = []searchResult{}
+	}
+	return results
+}
+
+// handoffSearchResult is a single ranked handoff match
+type handoffSearchResult struct {
+	ID      string `json:"id"`
+	Score   int    `json:"score"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// runHandoffSearch ranks handoffs by BM25 over their title, description,
+// next steps, and tried steps
+func (a *App) runHandoffSearch(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff search <query> [--status S] [--top N] [--json]")
+		return 1
+	}
+
+	query := args[0]
+	status := ""
+	topN := 10
+	jsonOutput := false
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--status":
+			if i+1 < len(args) {
+				status = args[i+1]
+				i++
+			}
+		case "--top":
+			if i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil {
+					topN = n
+				}
+				i++
+			}
+		case "--json":
+			jsonOutput = true
+		}
+	}
+
+	if status != "" && !models.IsValidHandoffStatus(status) {
+		fmt.Fprintf(a.stderr, "error: invalid status %q\n", status)
+		return 1
+	}
+
+	handoffList, err := a.handoffStore().ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	if status != "" {
+		var filtered []*models.Handoff
+		for _, h := range handoffList {
+			if h.Status == status {
+				filtered = append(filtered, h)
+			}
+		}
+		handoffList = filtered
+	}
+
+	results := []handoffSearchResult{}
+	for _, sh := range scoring.ScoreHandoffs(handoffList, query) {
+		if sh.Score < 1 || len(results) >= topN {
+			break
+		}
+		results = append(results, handoffSearchResult{
+			ID:      sh.Handoff.ID,
+			Score:   sh.Score,
+			Title:   sh.Handoff.Title,
+			Snippet: truncateContent(sh.Handoff.Description, searchSnippetLen),
+		})
+	}
+
+	if jsonOutput {
+		return a.writeJSON(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(a.stdout, "No results found.")
+		return 0
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(a.stdout, "%s (score: %d/10) %s\n", r.ID, r.Score, r.Title)
+		if r.Snippet != "" {
+			fmt.Fprintf(a.stdout, "    -> %s\n", r.Snippet)
+		}
+	}
+	return 0
+}
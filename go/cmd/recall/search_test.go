@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+func Test_SearchCommand_BothStores(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "gotcha", "Flaky migration tests", "Reset the schema before each migration test")
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+	hstore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	h, _ := hstore.Add("Schema migration rollout", "Roll the migration out behind a flag", false)
+
+	exitCode := app.Run([]string{"recall", "search", "migration"})
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "[lesson] L001") {
+		t.Errorf("expected lesson result, got: %s", output)
+	}
+	if !strings.Contains(output, "[handoff] "+h.ID) {
+		t.Errorf("expected handoff result, got: %s", output)
+	}
+	if strings.Contains(output, "L002") {
+		t.Errorf("expected non-matching lesson to be omitted, got: %s", output)
+	}
+}
+
+func Test_SearchCommand_TypeFilter(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "gotcha", "Flaky migration tests", "Reset the schema before each migration test")
+	hstore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	hstore.Add("Schema migration rollout", "Roll the migration out behind a flag", false)
+
+	if exitCode := app.Run([]string{"recall", "search", "migration", "--type", "handoffs"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "[lesson]") || !strings.Contains(stdout.String(), "[handoff]") {
+		t.Errorf("expected only handoff results, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "search", "migration", "--type", "lessons", "--json"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	var out struct {
+		Results []searchResult `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(out.Results) != 1 || out.Results[0].Type != "lesson" || out.Results[0].ID != "L001" {
+		t.Errorf("expected single lesson result, got %+v", out.Results)
+	}
+	if out.Results[0].Score < 1 || out.Results[0].Snippet == "" {
+		t.Errorf("expected score and snippet, got %+v", out.Results[0])
+	}
+}
+
+func Test_SearchCommand_InvalidType(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	if exitCode := app.Run([]string{"recall", "search", "x", "--type", "bogus"}); exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "unknown type") {
+		t.Errorf("expected unknown type error, got: %s", stderr.String())
+	}
+}
+
+func Test_HandoffSearch(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+
+	partial, _ := hStore.Add("Webhook retries", "Retry failed deliveries", false)
+	full, _ := hStore.Add("Webhook signature retries", "Verify webhook signature before retry", false)
+	hStore.Add("Dark mode", "Theme toggle", false)
+	done, _ := hStore.Add("Old webhook cleanup", "Remove legacy webhook retry queue", false)
+	hStore.Complete(done.ID)
+
+	if code := app.Run([]string{"recall", "handoff", "search", "webhook signature retry", "--json"}); code != 0 {
+		t.Fatalf("handoff search failed: %s", stderr.String())
+	}
+	var results []handoffSearchResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 matching handoffs, got %+v", results)
+	}
+	if results[0].ID != full.ID || results[0].Score != 10 {
+		t.Errorf("expected handoff matching every term first, got %+v", results[0])
+	}
+	rank := map[string]int{}
+	for i, r := range results {
+		rank[r.ID] = i
+	}
+	if rank[partial.ID] <= rank[full.ID] {
+		t.Errorf("expected fewer matches to rank lower: %+v", results)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "search", "webhook", "--status", "completed", "--json"}); code != 0 {
+		t.Fatalf("handoff search failed: %s", stderr.String())
+	}
+	results = nil
+	json.Unmarshal(stdout.Bytes(), &results)
+	if len(results) != 1 || results[0].ID != done.ID {
+		t.Errorf("expected only the completed handoff, got %+v", results)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "search", "webhook", "--status", "bogus"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid status, got %d", code)
+	}
+}
+
+func Test_FindCommand(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "gotcha", "Flaky migration tests", "Reset the schema before each migration test")
+	store.Add("project", "pattern", "Migration rollout order", "Deploy schema changes before code")
+	store.Add("project", "pattern", "Docker networking", "Containers use bridge networks")
+
+	find := func(args ...string) (int, string) {
+		stdout.Reset()
+		code := app.Run(append([]string{"recall", "find"}, args...))
+		return code, stdout.String()
+	}
+
+	// Exact title: single match shows full details
+	code, out := find("Docker networking")
+	if code != 0 || !strings.Contains(out, "ID: L003") || !strings.Contains(out, "Content:\nContainers use bridge networks") {
+		t.Errorf("expected details for exact match, got %d:\n%s", code, out)
+	}
+
+	// Partial, case-insensitive: multiple matches use the list view
+	code, out = find("MIGRATION")
+	if code != 0 || !strings.Contains(out, "L001 ") || !strings.Contains(out, "L002 ") || strings.Contains(out, "L003") {
+		t.Errorf("expected both migration lessons listed, got %d:\n%s", code, out)
+	}
+	if strings.Contains(out, "Content:") {
+		t.Errorf("expected list view for multiple matches, got:\n%s", out)
+	}
+
+	// Categories match too, and --category narrows the search
+	if code, out = find("gotch"); code != 0 || !strings.Contains(out, "ID: L001") {
+		t.Errorf("expected category match, got %d:\n%s", code, out)
+	}
+	if code, out = find("migration", "--category", "pattern"); code != 0 || !strings.Contains(out, "ID: L002") {
+		t.Errorf("expected --category to narrow to L002, got %d:\n%s", code, out)
+	}
+
+	if code, _ = find("kubernetes"); code != 1 {
+		t.Errorf("expected exit code 1 for no matches, got %d", code)
+	}
+	if code, _ = find(); code != 1 || !strings.Contains(stderr.String(), "usage: recall find") {
+		t.Errorf("expected usage error without a query, got %d", code)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// snapshotVersion is the current export format version
+const snapshotVersion = 1
+
+// snapshot is the portable export format for lessons and handoffs
+type snapshot struct {
+	Version  int               `json:"version"`
+	Lessons  []*models.Lesson  `json:"lessons"`
+	Handoffs []*models.Handoff `json:"handoffs"`
+}
+
+// runExport writes all lessons and handoffs as a JSON snapshot
+func (a *App) runExport(args []string) int {
+	var outputPath string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--csv":
+			return a.runExportCSV(args)
+		case "--json":
+			// JSON is the default format
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputPath = args[i+1]
+				i++
+			}
+		}
+	}
+
+	// Shared libraries belong to their own repos, so snapshots hold only local lessons
+	lessonStore := a.localLessonStore()
+	allLessons, err := lessonStore.List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	handoffStore := a.handoffStore()
+	allHandoffs, err := handoffStore.ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+
+	snap := snapshot{
+		Version:  snapshotVersion,
+		Lessons:  allLessons,
+		Handoffs: allHandoffs,
+	}
+	if snap.Lessons == nil {
+		snap.Lessons = []*models.Lesson{}
+	}
+	if snap.Handoffs == nil {
+		snap.Handoffs = []*models.Handoff{}
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error encoding snapshot: %v\n", err)
+		return 1
+	}
+
+	if outputPath == "" {
+		fmt.Fprintln(a.stdout, string(data))
+		return 0
+	}
+
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintf(a.stderr, "error writing snapshot: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Exported %d lessons and %d handoffs to %s\n", len(allLessons), len(allHandoffs), outputPath)
+	return 0
+}
+
+// runImport reads a JSON snapshot and merges it into the stores
+func (a *App) runImport(args []string) int {
+	for _, arg := range args {
+		if arg == "--from-markdown" {
+			return a.runImportMarkdown(args)
+		}
+	}
+
+	var inputPath string
+	policy := lessons.ConflictSkip
+	level := ""
+	categoryMap := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			// JSON is the default (and currently only) format
+		case strings.HasPrefix(arg, "--conflict="):
+			policy = strings.TrimPrefix(arg, "--conflict=")
+		case arg == "--conflict":
+			if i+1 < len(args) {
+				policy = args[i+1]
+				i++
+			}
+		case arg == "--level":
+			if i+1 < len(args) {
+				level = args[i+1]
+				i++
+			}
+		case arg == "--category" && i+1 < len(args):
+			categoryMap[lessons.CategoryWildcard] = args[i+1]
+			i++
+		case arg == "--category-map" && i+1 < len(args):
+			var mapping map[string]string
+			if err := json.Unmarshal([]byte(args[i+1]), &mapping); err != nil {
+				fmt.Fprintf(a.stderr, "error: invalid --category-map (want {\"old\":\"new\"}): %v\n", err)
+				return 1
+			}
+			for from, to := range mapping {
+				categoryMap[from] = to
+			}
+			i++
+		default:
+			inputPath = arg
+		}
+	}
+
+	if inputPath == "" {
+		fmt.Fprintln(a.stderr, "usage: recall import [--json] <path|-> [--conflict=skip|overwrite|renumber] [--level project|system] [--category C] [--category-map JSON]")
+		return 1
+	}
+	if err := lessons.ValidateCategoryMap(categoryMap); err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if level != "" && level != "project" && level != "system" {
+		fmt.Fprintf(a.stderr, "error: invalid level '%s': must be project or system\n", level)
+		return 1
+	}
+
+	var data []byte
+	var err error
+	if inputPath == "-" {
+		data, err = io.ReadAll(a.stdin)
+	} else {
+		data, err = os.ReadFile(inputPath)
+	}
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading snapshot: %v\n", err)
+		return 1
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		fmt.Fprintf(a.stderr, "error parsing snapshot JSON: %v\n", err)
+		return 1
+	}
+	if snap.Version != snapshotVersion {
+		fmt.Fprintf(a.stderr, "error: unsupported snapshot version %d\n", snap.Version)
+		return 1
+	}
+
+	// --level forces every lesson into one store; mismatched IDs get renumbered
+	if level != "" {
+		for _, l := range snap.Lessons {
+			l.Level = level
+		}
+	}
+
+	// --category-map renames listed categories; --category catches the rest
+	if len(categoryMap) > 0 {
+		lessons.RewriteCategories(snap.Lessons, categoryMap)
+	}
+
+	// Session IDs are machine-local: drop links to sessions we don't know about
+	if mappings, err := a.loadSessionHandoffs(); err == nil {
+		for _, h := range snap.Handoffs {
+			var known []string
+			for _, sid := range h.Sessions {
+				if _, ok := mappings[sid]; ok {
+					known = append(known, sid)
+				}
+			}
+			h.Sessions = known
+		}
+	}
+
+	lessonStore := a.lessonStore()
+	lessonResult, err := lessonStore.Import(snap.Lessons, policy)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error importing lessons: %v\n", err)
+		return 1
+	}
+
+	handoffStore := a.handoffStore()
+	handoffResult, err := handoffStore.Import(snap.Handoffs, policy)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error importing handoffs: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Lessons: %d added, %d overwritten, %d skipped\n",
+		lessonResult.Added, lessonResult.Overwritten, lessonResult.Skipped)
+	a.printRenumbered(lessonResult.Renumbered)
+	fmt.Fprintf(a.stdout, "Handoffs: %d added, %d overwritten, %d skipped\n",
+		handoffResult.Added, handoffResult.Overwritten, handoffResult.Skipped)
+	a.printRenumbered(handoffResult.Renumbered)
+
+	return 0
+}
+
+// runImportMarkdown adds lessons found in a freeform Markdown file
+func (a *App) runImportMarkdown(args []string) int {
+	var inputPath string
+	category := "pattern"
+	level := "project"
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--from-markdown":
+			if i+1 < len(args) {
+				inputPath = args[i+1]
+				i++
+			}
+		case "--category":
+			if i+1 < len(args) {
+				category = args[i+1]
+				i++
+			}
+		case "--level":
+			if i+1 < len(args) {
+				level = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if inputPath == "" {
+		fmt.Fprintln(a.stderr, "usage: recall import --from-markdown <path|-> [--category C] [--level project|system]")
+		return 1
+	}
+	if level != "project" && level != "system" {
+		fmt.Fprintf(a.stderr, "error: invalid level '%s': must be project or system\n", level)
+		return 1
+	}
+	if err := lessons.ValidateCategory(category); err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+
+	var r io.Reader = a.stdin
+	if inputPath != "-" {
+		f, err := os.Open(inputPath)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error reading markdown: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	found, err := lessons.ImportMarkdown(r, level, category, a.stderr)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error parsing markdown: %v\n", err)
+		return 1
+	}
+	if len(found) == 0 {
+		fmt.Fprintln(a.stdout, "No lessons found.")
+		return 0
+	}
+
+	store := a.lessonStore()
+	result, err := store.Import(found, lessons.ConflictRenumber)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error importing lessons: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Imported %d lessons from %s\n", result.Added, inputPath)
+	return 0
+}
+
+// printRenumbered prints old -> new ID mappings in a stable order
+func (a *App) printRenumbered(renumbered map[string]string) {
+	oldIDs := make([]string, 0, len(renumbered))
+	for id := range renumbered {
+		oldIDs = append(oldIDs, id)
+	}
+	sort.Strings(oldIDs)
+	for _, id := range oldIDs {
+		fmt.Fprintf(a.stdout, "  %s -> %s\n", id, renumbered[id])
+	}
+}
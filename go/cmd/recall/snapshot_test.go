@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+func Test_ExportImport_RoundTrip(t *testing.T) {
+	src, srcStore, srcOut, srcErr := newTestApp(t)
+	srcStore.Add("project", "pattern", "Project lesson", "Project content")
+	srcStore.Add("system", "gotcha", "System lesson", "System content")
+	hStore := handoffs.NewStore(src.handoffsPath, src.stealthPath)
+	h, _ := hStore.Add("Some work", "desc", false)
+	hStore.Update(h.ID, map[string]interface{}{"sessions": []string{"gone-session"}})
+
+	snapPath := filepath.Join(t.TempDir(), "snapshot.json")
+	if code := src.Run([]string{"recall", "export", "--json", "--output", snapPath}); code != 0 {
+		t.Fatalf("export failed: %s", srcErr.String())
+	}
+	if !strings.Contains(srcOut.String(), "Exported 2 lessons and 1 handoffs") {
+		t.Errorf("unexpected export output: %s", srcOut.String())
+	}
+
+	data, _ := os.ReadFile(snapPath)
+	var snap map[string]interface{}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatalf("snapshot is not valid JSON: %v", err)
+	}
+	if snap["version"].(float64) != 1 {
+		t.Errorf("expected version 1, got %v", snap["version"])
+	}
+
+	dst, dstStore, dstOut, dstErr := newTestApp(t)
+	if code := dst.Run([]string{"recall", "import", "--json", snapPath}); code != 0 {
+		t.Fatalf("import failed: %s", dstErr.String())
+	}
+
+	all, _ := dstStore.List()
+	if len(all) != 2 || all[0].ID != "L001" || all[1].ID != "S001" {
+		t.Errorf("expected L001 and S001 imported, got %v", all)
+	}
+
+	imported, err := handoffs.NewStore(dst.handoffsPath, dst.stealthPath).Get(h.ID)
+	if err != nil {
+		t.Fatalf("expected handoff %s imported: %v", h.ID, err)
+	}
+	if len(imported.Sessions) != 0 {
+		t.Errorf("expected unknown sessions dropped, got %v", imported.Sessions)
+	}
+	if !strings.Contains(dstOut.String(), "Lessons: 2 added") {
+		t.Errorf("unexpected import output: %s", dstOut.String())
+	}
+}
+
+func Test_Import_RenumberAndLevelOverride(t *testing.T) {
+	src, srcStore, _, _ := newTestApp(t)
+	srcStore.Add("system", "gotcha", "System lesson", "System content")
+	snapPath := filepath.Join(t.TempDir(), "snapshot.json")
+	src.Run([]string{"recall", "export", "-o", snapPath})
+
+	dst, dstStore, dstOut, dstErr := newTestApp(t)
+	dstStore.Add("project", "pattern", "Local lesson", "Local content")
+
+	code := dst.Run([]string{"recall", "import", snapPath, "--conflict=renumber", "--level", "project"})
+	if code != 0 {
+		t.Fatalf("import failed: %s", dstErr.String())
+	}
+
+	if !strings.Contains(dstOut.String(), "S001 -> L002") {
+		t.Errorf("expected S001 -> L002 mapping, got: %s", dstOut.String())
+	}
+	l, err := dstStore.Get("L002")
+	if err != nil || l.Title != "System lesson" {
+		t.Errorf("expected system lesson imported as L002, got %v (%v)", l, err)
+	}
+}
+
+func Test_Import_CategoryOverrides(t *testing.T) {
+	src, srcStore, _, srcErr := newTestApp(t)
+	srcStore.Add("project", "pattern", "Pattern lesson", "Pattern content")
+	srcStore.Add("project", "gotcha", "Gotcha lesson", "Gotcha content")
+	srcStore.Add("project", "decision", "Decision lesson", "Decision content")
+
+	snapPath := filepath.Join(t.TempDir(), "snapshot.json")
+	if code := src.Run([]string{"recall", "export", "--json", "--output", snapPath}); code != 0 {
+		t.Fatalf("export failed: %s", srcErr.String())
+	}
+
+	importCategories := func(args ...string) []string {
+		t.Helper()
+		dst, dstStore, _, dstErr := newTestApp(t)
+		if code := dst.Run(append([]string{"recall", "import", snapPath}, args...)); code != 0 {
+			t.Fatalf("import %v failed: %s", args, dstErr.String())
+		}
+		all, _ := dstStore.List()
+		var categories []string
+		for _, l := range all {
+			categories = append(categories, l.Category)
+		}
+		return categories
+	}
+
+	got := importCategories("--category-map", `{"pattern":"practice","gotcha":"pitfall"}`)
+	if strings.Join(got, ",") != "practice,pitfall,decision" {
+		t.Errorf("expected mapped categories, got %v", got)
+	}
+	got = importCategories("--category", "imported")
+	if strings.Join(got, ",") != "imported,imported,imported" {
+		t.Errorf("expected every category overridden, got %v", got)
+	}
+	got = importCategories("--category", "imported", "--category-map", `{"gotcha":"pitfall"}`)
+	if strings.Join(got, ",") != "imported,pitfall,imported" {
+		t.Errorf("expected map entries to beat --category, got %v", got)
+	}
+
+	dst, _, _, _ := newTestApp(t)
+	if code := dst.Run([]string{"recall", "import", snapPath, "--category-map", "pattern=practice"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid --category-map, got %d", code)
+	}
+	for _, args := range [][]string{
+		{"--category", "two words"},
+		{"--category-map", `{"gotcha":"pit|fall"}`},
+	} {
+		if code := dst.Run(append([]string{"recall", "import", snapPath}, args...)); code != 1 {
+			t.Errorf("expected exit code 1 for invalid category in %v, got %d", args, code)
+		}
+	}
+	if code := dst.Run([]string{"recall", "import", "--from-markdown", snapPath, "--category", "two words"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid --from-markdown category, got %d", code)
+	}
+}
+
+func Test_Import_InvalidConflictPolicy(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+	snapPath := filepath.Join(t.TempDir(), "snapshot.json")
+	os.WriteFile(snapPath, []byte(`{"version":1,"lessons":[],"handoffs":[]}`), 0644)
+
+	if code := app.Run([]string{"recall", "import", snapPath, "--conflict=merge"}); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "invalid conflict policy") {
+		t.Errorf("expected policy error, got: %s", stderr.String())
+	}
+}
+
+func Test_Import_FromMarkdown(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.Add("project", "pattern", "Existing lesson", "Existing content")
+
+	mdPath := filepath.Join(t.TempDir(), "notes.md")
+	os.WriteFile(mdPath, []byte(`## Wrap errors
+Use fmt.Errorf with %w.
+
+### [gotcha] Nothing here
+
+> tip: Keep functions short
+`), 0644)
+
+	code := app.Run([]string{"recall", "import", "--from-markdown", mdPath, "--category", "decision", "--level", "project"})
+	if code != 0 {
+		t.Fatalf("import failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Imported 2 lessons") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Nothing here") {
+		t.Errorf("expected warning for empty block, got: %s", stderr.String())
+	}
+
+	l, err := store.Get("L002")
+	if err != nil || l.Title != "Wrap errors" || l.Category != "decision" {
+		t.Errorf("expected L002 'Wrap errors' (decision), got %v (%v)", l, err)
+	}
+	if l, err := store.Get("L003"); err != nil || l.Content != "Keep functions short" {
+		t.Errorf("expected tip imported as L003, got %v (%v)", l, err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+func Test_HandoffCheckStale(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	writeReportFixture(t, app)
+
+	if code := app.Run([]string{"recall", "handoff", "check-stale"}); code != 0 {
+		t.Fatalf("check-stale failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "hf-0000002") || !strings.Contains(out, "Forgotten spike") || !strings.Contains(out, "blocked") {
+		t.Errorf("expected 20-day-old handoff reported, got:\n%s", out)
+	}
+	if strings.Contains(out, "hf-0000001") || strings.Contains(out, "hf-0000003") {
+		t.Errorf("expected fresh and completed handoffs skipped, got:\n%s", out)
+	}
+	if fields := strings.Fields(out[strings.Index(out, "hf-0000002"):]); fields[len(fields)-1] != "20" {
+		t.Errorf("expected 20 days since update, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "check-stale", "--days", "30"}); code != 0 {
+		t.Fatalf("check-stale --days 30 failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No stale handoffs") {
+		t.Errorf("expected nothing stale at 30 days, got:\n%s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "check-stale", "--days", "soon"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid --days, got %d", code)
+	}
+}
+
+func Test_HandoffCheckStale_AutoFlag(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	writeReportFixture(t, app)
+
+	if code := app.Run([]string{"recall", "handoff", "check-stale", "--days", "7", "--auto-flag"}); code != 0 {
+		t.Fatalf("check-stale --auto-flag failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Flagged 1 stale handoffs") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+	h, _ := handoffs.NewStore(app.handoffsPath, app.stealthPath).Get("hf-0000002")
+	if !h.Stale {
+		t.Error("expected hf-0000002 flagged stale")
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "list", "--stale"}); code != 0 {
+		t.Fatalf("list --stale failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "hf-0000002 [blocked] Forgotten spike [stale]") || strings.Contains(out, "hf-0000001") {
+		t.Errorf("expected only the flagged handoff, got:\n%s", out)
+	}
+}
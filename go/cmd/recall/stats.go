@@ -0,0 +1,406 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// statsSchemaVersion is bumped whenever the stats JSON shape changes
+const statsSchemaVersion = 1
+
+// statsTopCited is the number of most-cited lessons reported
+const statsTopCited = 5
+
+// statsCategoryTopVelocity is the number of fastest-moving lessons reported
+// for a single category
+const statsCategoryTopVelocity = 3
+
+// statsCitationWindow is how far back category citation counts look
+const statsCitationWindow = 30 * 24 * time.Hour
+
+// statsReport is the JSON output of recall stats
+type statsReport struct {
+	SchemaVersion int          `json:"schema_version"`
+	Since         string       `json:"since,omitempty"`
+	Lessons       lessonStats  `json:"lessons"`
+	Handoffs      handoffStats `json:"handoffs"`
+}
+
+// lessonStats summarizes lesson usage
+type lessonStats struct {
+	Total       int                `json:"total"`
+	ByLevel     map[string]int     `json:"by_level"`
+	TopCited    []citedLesson      `json:"top_cited"`
+	AvgVelocity float64            `json:"avg_velocity"`
+	CategoryPct map[string]float64 `json:"category_pct"`
+}
+
+// citedLesson is a lesson entry in the top-cited list
+type citedLesson struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Uses  int    `json:"uses"`
+}
+
+// categoryStats summarizes the lessons in one category
+type categoryStats struct {
+	Category       string          `json:"category"`
+	Lessons        int             `json:"lessons"`
+	AvgUses        float64         `json:"avg_uses"`
+	AvgVelocity    float64         `json:"avg_velocity"`
+	TotalCitations int             `json:"total_citations"`        // Sum of uses
+	RecentCites    int             `json:"citations_last_30d"`     // From the audit log
+	TopVelocity    []velocityEntry `json:"top_velocity,omitempty"` // Only for --category
+}
+
+// velocityEntry is a lesson entry in a category's top-velocity list
+type velocityEntry struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Velocity float64 `json:"velocity"`
+}
+
+// handoffStats summarizes handoffs by status
+type handoffStats struct {
+	Total      int                `json:"total"`
+	ByStatus   map[string]int     `json:"by_status"`
+	AvgAgeDays map[string]float64 `json:"avg_age_days"`
+}
+
+// runStats prints usage metrics across lessons and handoffs
+func (a *App) runStats(args []string) int {
+	if len(args) > 0 && args[0] == "categories" {
+		return a.runStatsCategories(args[1:])
+	}
+	for _, arg := range args {
+		if arg == "--category" {
+			return a.runStatsCategory(args)
+		}
+	}
+
+	jsonOutput := false
+	var since time.Time
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		case "--since":
+			if i+1 < len(args) {
+				t, err := time.Parse("2006-01-02", args[i+1])
+				if err != nil {
+					fmt.Fprintf(a.stderr, "error: invalid --since date %q (use YYYY-MM-DD)\n", args[i+1])
+					return 1
+				}
+				since = t
+				i++
+			}
+		}
+	}
+
+	lessonList, err := a.lessonStore().List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+	handoffList, err := a.handoffStore().ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+
+	report := computeStats(lessonList, handoffList, since, time.Now())
+
+	if jsonOutput {
+		return a.writeJSON(report)
+	}
+
+	a.printStats(report)
+	return 0
+}
+
+// computeStats builds a stats report. Lessons learned and handoffs created
+// before since are excluded (zero since includes everything).
+func computeStats(lessonList []*models.Lesson, handoffList []*models.Handoff, since, now time.Time) statsReport {
+	report := statsReport{
+		SchemaVersion: statsSchemaVersion,
+		Lessons: lessonStats{
+			ByLevel:     map[string]int{"project": 0, "system": 0},
+			TopCited:    []citedLesson{},
+			CategoryPct: map[string]float64{},
+		},
+		Handoffs: handoffStats{
+			ByStatus:   map[string]int{},
+			AvgAgeDays: map[string]float64{},
+		},
+	}
+	if !since.IsZero() {
+		report.Since = since.Format("2006-01-02")
+	}
+
+	// Lessons
+	var included []*models.Lesson
+	for _, l := range lessonList {
+		if since.IsZero() || !l.Learned.Before(since) {
+			included = append(included, l)
+		}
+	}
+
+	categoryCounts := make(map[string]int)
+	totalVelocity := 0.0
+	for _, l := range included {
+		report.Lessons.ByLevel[l.Level]++
+		categoryCounts[l.Category]++
+		totalVelocity += l.Velocity
+	}
+	report.Lessons.Total = len(included)
+
+	if len(included) > 0 {
+		report.Lessons.AvgVelocity = round2(totalVelocity / float64(len(included)))
+		for cat, n := range categoryCounts {
+			report.Lessons.CategoryPct[cat] = round2(100 * float64(n) / float64(len(included)))
+		}
+	}
+
+	cited := append([]*models.Lesson{}, included...)
+	sort.SliceStable(cited, func(i, j int) bool {
+		return cited[i].Uses > cited[j].Uses
+	})
+	for _, l := range cited {
+		if len(report.Lessons.TopCited) >= statsTopCited || l.Uses == 0 {
+			break
+		}
+		report.Lessons.TopCited = append(report.Lessons.TopCited, citedLesson{ID: l.ID, Title: l.Title, Uses: l.Uses})
+	}
+
+	// Handoffs
+	totalAge := make(map[string]float64)
+	for _, h := range handoffList {
+		if !since.IsZero() && h.Created.Before(since) {
+			continue
+		}
+		report.Handoffs.Total++
+		report.Handoffs.ByStatus[h.Status]++
+		totalAge[h.Status] += now.Sub(h.Created).Hours() / 24
+	}
+	for status, n := range report.Handoffs.ByStatus {
+		report.Handoffs.AvgAgeDays[status] = round2(totalAge[status] / float64(n))
+	}
+
+	return report
+}
+
+// printStats writes a human-readable stats report
+func (a *App) printStats(r statsReport) {
+	if r.Since != "" {
+		fmt.Fprintf(a.stdout, "Since %s\n\n", r.Since)
+	}
+
+	fmt.Fprintf(a.stdout, "Lessons: %d (project: %d, system: %d)\n",
+		r.Lessons.Total, r.Lessons.ByLevel["project"], r.Lessons.ByLevel["system"])
+	fmt.Fprintf(a.stdout, "Average velocity: %.2f\n", r.Lessons.AvgVelocity)
+
+	if len(r.Lessons.CategoryPct) > 0 {
+		fmt.Fprintln(a.stdout, "Categories:")
+		for _, cat := range sortedKeys(r.Lessons.CategoryPct) {
+			fmt.Fprintf(a.stdout, "  %-12s %5.1f%%\n", cat, r.Lessons.CategoryPct[cat])
+		}
+	}
+
+	if len(r.Lessons.TopCited) > 0 {
+		fmt.Fprintln(a.stdout, "Top cited:")
+		for _, l := range r.Lessons.TopCited {
+			fmt.Fprintf(a.stdout, "  [%s] %d uses - %s\n", l.ID, l.Uses, l.Title)
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "\nHandoffs: %d\n", r.Handoffs.Total)
+	for _, status := range sortedKeys(r.Handoffs.AvgAgeDays) {
+		fmt.Fprintf(a.stdout, "  %-12s %3d  (avg age %.1f days)\n",
+			status, r.Handoffs.ByStatus[status], r.Handoffs.AvgAgeDays[status])
+	}
+}
+
+// runStatsCategory prints metrics scoped to one category: lesson count,
+// average uses, fastest-moving lessons, and recent citations
+func (a *App) runStatsCategory(args []string) int {
+	jsonOutput := false
+	category := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		case "--category":
+			if i+1 < len(args) {
+				category = args[i+1]
+				i++
+			}
+		}
+	}
+	if category == "" {
+		fmt.Fprintln(a.stderr, "usage: recall stats --category <cat> [--json]")
+		return 1
+	}
+
+	lessonList, err := a.lessonStore().List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+	events, err := lessons.NewAuditLog(a.stateDir).Read()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading audit log: %v\n", err)
+		return 1
+	}
+
+	stats := computeCategoryStats(lessonList, events, category, time.Now())
+	if stats.Lessons == 0 {
+		fmt.Fprintf(a.stderr, "no lessons in category %q\n", category)
+		return 1
+	}
+
+	if jsonOutput {
+		return a.writeJSON(stats)
+	}
+
+	fmt.Fprintf(a.stdout, "Category: %s\n", stats.Category)
+	fmt.Fprintf(a.stdout, "Lessons: %d\n", stats.Lessons)
+	fmt.Fprintf(a.stdout, "Average uses: %.2f\n", stats.AvgUses)
+	fmt.Fprintf(a.stdout, "Citations (last 30 days): %d\n", stats.RecentCites)
+	if len(stats.TopVelocity) > 0 {
+		fmt.Fprintln(a.stdout, "Top by velocity:")
+		for _, l := range stats.TopVelocity {
+			fmt.Fprintf(a.stdout, "  [%s] %.2f - %s\n", l.ID, l.Velocity, l.Title)
+		}
+	}
+	return 0
+}
+
+// runStatsCategories lists every category with its lesson count and
+// average velocity, most-cited first
+func (a *App) runStatsCategories(args []string) int {
+	jsonOutput := false
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		}
+	}
+
+	lessonList, err := a.lessonStore().List()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	all := computeCategoryList(lessonList)
+	if jsonOutput {
+		return a.writeJSON(all)
+	}
+
+	if len(all) == 0 {
+		fmt.Fprintln(a.stdout, "No lessons found.")
+		return 0
+	}
+	for _, c := range all {
+		fmt.Fprintf(a.stdout, "  %-12s %3d lessons  avg velocity %.2f  %d citations\n",
+			c.Category, c.Lessons, c.AvgVelocity, c.TotalCitations)
+	}
+	return 0
+}
+
+// computeCategoryStats summarizes one category. Citations in the last 30
+// days are counted from audit log cite events for the category's lessons.
+func computeCategoryStats(lessonList []*models.Lesson, events []lessons.AuditEvent, category string, now time.Time) categoryStats {
+	stats := categoryStats{Category: category}
+
+	var matched []*models.Lesson
+	ids := make(map[string]bool)
+	for _, l := range lessonList {
+		if l.Category == category {
+			matched = append(matched, l)
+			ids[l.ID] = true
+		}
+	}
+	if len(matched) == 0 {
+		return stats
+	}
+
+	totalVelocity := 0.0
+	for _, l := range matched {
+		stats.TotalCitations += l.Uses
+		totalVelocity += l.Velocity
+	}
+	stats.Lessons = len(matched)
+	stats.AvgUses = round2(float64(stats.TotalCitations) / float64(len(matched)))
+	stats.AvgVelocity = round2(totalVelocity / float64(len(matched)))
+
+	byVelocity := append([]*models.Lesson{}, matched...)
+	sort.SliceStable(byVelocity, func(i, j int) bool {
+		return byVelocity[i].Velocity > byVelocity[j].Velocity
+	})
+	for _, l := range byVelocity {
+		if len(stats.TopVelocity) >= statsCategoryTopVelocity {
+			break
+		}
+		stats.TopVelocity = append(stats.TopVelocity, velocityEntry{ID: l.ID, Title: l.Title, Velocity: l.Velocity})
+	}
+
+	windowStart := now.Add(-statsCitationWindow)
+	for _, e := range events {
+		if e.Event == lessons.AuditCite && ids[e.LessonID] && !e.Timestamp.Before(windowStart) {
+			stats.RecentCites++
+		}
+	}
+	return stats
+}
+
+// computeCategoryList summarizes every category, sorted by total
+// citations descending (ties by name)
+func computeCategoryList(lessonList []*models.Lesson) []categoryStats {
+	byCategory := make(map[string]*categoryStats)
+	totalVelocity := make(map[string]float64)
+	for _, l := range lessonList {
+		c, ok := byCategory[l.Category]
+		if !ok {
+			c = &categoryStats{Category: l.Category}
+			byCategory[l.Category] = c
+		}
+		c.Lessons++
+		c.TotalCitations += l.Uses
+		totalVelocity[l.Category] += l.Velocity
+	}
+
+	all := make([]categoryStats, 0, len(byCategory))
+	for cat, c := range byCategory {
+		c.AvgUses = round2(float64(c.TotalCitations) / float64(c.Lessons))
+		c.AvgVelocity = round2(totalVelocity[cat] / float64(c.Lessons))
+		all = append(all, *c)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].TotalCitations != all[j].TotalCitations {
+			return all[i].TotalCitations > all[j].TotalCitations
+		}
+		return all[i].Category < all[j].Category
+	})
+	return all
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// round2 rounds to two decimal places for stable output
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
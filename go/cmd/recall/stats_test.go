@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func statsFixture(now time.Time) ([]*models.Lesson, []*models.Handoff) {
+	day := 24 * time.Hour
+	lessonList := []*models.Lesson{
+		{ID: "L001", Title: "One", Level: "project", Category: "pattern", Uses: 12, Velocity: 2.0, Learned: now.Add(-30 * day)},
+		{ID: "L002", Title: "Two", Level: "project", Category: "pattern", Uses: 3, Velocity: 1.0, Learned: now.Add(-20 * day)},
+		{ID: "L003", Title: "Three", Level: "project", Category: "gotcha", Uses: 0, Velocity: 0, Learned: now.Add(-2 * day)},
+		{ID: "S001", Title: "Four", Level: "system", Category: "decision", Uses: 50, Velocity: 0.5, Learned: now.Add(-90 * day)},
+	}
+	handoffList := []*models.Handoff{
+		{ID: "hf-0000001", Status: "in_progress", Created: now.Add(-2 * day)},
+		{ID: "hf-0000002", Status: "in_progress", Created: now.Add(-4 * day)},
+		{ID: "hf-0000003", Status: "completed", Created: now.Add(-10 * day)},
+	}
+	return lessonList, handoffList
+}
+
+func Test_ComputeStats_KnownFixture(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lessonList, handoffList := statsFixture(now)
+
+	r := computeStats(lessonList, handoffList, time.Time{}, now)
+
+	if r.SchemaVersion != statsSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", statsSchemaVersion, r.SchemaVersion)
+	}
+	if r.Lessons.Total != 4 || r.Lessons.ByLevel["project"] != 3 || r.Lessons.ByLevel["system"] != 1 {
+		t.Errorf("unexpected lesson counts: %+v", r.Lessons)
+	}
+	// (2.0 + 1.0 + 0 + 0.5) / 4 = 0.875
+	if r.Lessons.AvgVelocity != 0.88 {
+		t.Errorf("expected avg velocity 0.88, got %v", r.Lessons.AvgVelocity)
+	}
+	if r.Lessons.CategoryPct["pattern"] != 50 || r.Lessons.CategoryPct["gotcha"] != 25 || r.Lessons.CategoryPct["decision"] != 25 {
+		t.Errorf("unexpected category distribution: %v", r.Lessons.CategoryPct)
+	}
+
+	// Zero-use lessons never appear in the top-cited list
+	var top []string
+	for _, l := range r.Lessons.TopCited {
+		top = append(top, l.ID)
+	}
+	if strings.Join(top, ",") != "S001,L001,L002" {
+		t.Errorf("expected top cited S001,L001,L002, got %v", top)
+	}
+
+	if r.Handoffs.Total != 3 || r.Handoffs.ByStatus["in_progress"] != 2 || r.Handoffs.ByStatus["completed"] != 1 {
+		t.Errorf("unexpected handoff counts: %+v", r.Handoffs)
+	}
+	if r.Handoffs.AvgAgeDays["in_progress"] != 3 || r.Handoffs.AvgAgeDays["completed"] != 10 {
+		t.Errorf("unexpected handoff ages: %v", r.Handoffs.AvgAgeDays)
+	}
+}
+
+func Test_ComputeStats_Since(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lessonList, handoffList := statsFixture(now)
+
+	r := computeStats(lessonList, handoffList, now.Add(-25*24*time.Hour), now)
+
+	if r.Since != "2026-02-04" {
+		t.Errorf("expected since 2026-02-04, got %q", r.Since)
+	}
+	if r.Lessons.Total != 2 || r.Lessons.ByLevel["system"] != 0 {
+		t.Errorf("expected 2 project lessons after since, got %+v", r.Lessons)
+	}
+	if r.Lessons.CategoryPct["pattern"] != 50 || r.Lessons.CategoryPct["gotcha"] != 50 {
+		t.Errorf("unexpected category distribution: %v", r.Lessons.CategoryPct)
+	}
+	if r.Handoffs.Total != 3 {
+		t.Errorf("expected all 3 handoffs, got %d", r.Handoffs.Total)
+	}
+}
+
+func Test_StatsCommand_JSON(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+
+	store.Add("project", "pattern", "Project lesson", "Content")
+	store.Add("system", "gotcha", "System lesson", "Other content")
+	store.Cite("L001")
+
+	if exitCode := app.Run([]string{"recall", "stats", "--json"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d. stderr: %s", exitCode, stderr.String())
+	}
+
+	var r statsReport
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if r.SchemaVersion != 1 || r.Lessons.Total != 2 || len(r.Lessons.TopCited) != 1 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+
+	stdout.Reset()
+	if exitCode := app.Run([]string{"recall", "stats"}); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "Lessons: 2 (project: 1, system: 1)") {
+		t.Errorf("unexpected text output: %s", stdout.String())
+	}
+
+	if exitCode := app.Run([]string{"recall", "stats", "--since", "yesterday"}); exitCode != 1 {
+		t.Errorf("expected exit code 1 for invalid date, got %d", exitCode)
+	}
+}
+
+func Test_ComputeCategoryStats(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	lessonList := []*models.Lesson{
+		{ID: "L001", Title: "Slow", Category: "pattern", Uses: 10, Velocity: 0.5},
+		{ID: "L002", Title: "Fast", Category: "pattern", Uses: 2, Velocity: 3.0},
+		{ID: "L003", Title: "Medium", Category: "pattern", Uses: 6, Velocity: 1.0},
+		{ID: "L004", Title: "Stale", Category: "pattern", Uses: 0, Velocity: 0},
+		{ID: "L005", Title: "Other", Category: "gotcha", Uses: 40, Velocity: 5.0},
+	}
+	events := []lessons.AuditEvent{
+		{Timestamp: now.Add(-1 * day), Event: lessons.AuditCite, LessonID: "L001"},
+		{Timestamp: now.Add(-29 * day), Event: lessons.AuditCite, LessonID: "L002"},
+		{Timestamp: now.Add(-31 * day), Event: lessons.AuditCite, LessonID: "L002"}, // outside window
+		{Timestamp: now.Add(-1 * day), Event: lessons.AuditEdit, LessonID: "L003"},  // not a citation
+		{Timestamp: now.Add(-1 * day), Event: lessons.AuditCite, LessonID: "L005"},  // other category
+	}
+
+	stats := computeCategoryStats(lessonList, events, "pattern", now)
+	if stats.Lessons != 4 || stats.AvgUses != 4.5 || stats.TotalCitations != 18 || stats.RecentCites != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if len(stats.TopVelocity) != 3 || stats.TopVelocity[0].ID != "L002" || stats.TopVelocity[1].ID != "L003" || stats.TopVelocity[2].ID != "L001" {
+		t.Errorf("unexpected top velocity: %+v", stats.TopVelocity)
+	}
+
+	if missing := computeCategoryStats(lessonList, events, "decision", now); missing.Lessons != 0 {
+		t.Errorf("expected no lessons for unknown category, got %+v", missing)
+	}
+
+	all := computeCategoryList(lessonList)
+	if len(all) != 2 || all[0].Category != "gotcha" || all[1].Category != "pattern" {
+		t.Fatalf("expected categories sorted by citations, got %+v", all)
+	}
+	if all[1].Lessons != 4 || all[1].AvgVelocity != 1.13 {
+		t.Errorf("unexpected pattern summary: %+v", all[1])
+	}
+}
+
+func Test_StatsCategoryCommand(t *testing.T) {
+	app, store, stdout, stderr := newTestApp(t)
+	store.SetAuditLog(lessons.NewAuditLog(app.stateDir))
+
+	store.Add("project", "pattern", "Use table tests", "Go idiom")
+	store.Add("project", "gotcha", "Nil maps panic on write", "Initialize first")
+	store.Add("project", "pattern", "Wrap errors with context", "fmt.Errorf %w")
+	store.Cite("L001")
+	store.Cite("L001")
+	store.Cite("L002")
+
+	if code := app.Run([]string{"recall", "stats", "--category", "pattern", "--json"}); code != 0 {
+		t.Fatalf("stats --category failed: %s", stderr.String())
+	}
+	var stats categoryStats
+	if err := json.Unmarshal(stdout.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if stats.Lessons != 2 || stats.AvgUses != 1 || stats.RecentCites != 2 {
+		t.Errorf("unexpected category stats: %+v", stats)
+	}
+	for _, l := range stats.TopVelocity {
+		if l.ID == "L002" {
+			t.Errorf("gotcha lesson leaked into pattern stats: %+v", stats.TopVelocity)
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "stats", "categories"}); code != 0 {
+		t.Fatalf("stats categories failed: %s", stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "pattern") || !strings.Contains(lines[0], "2 citations") {
+		t.Errorf("unexpected categories output:\n%s", stdout.String())
+	}
+
+	if code := app.Run([]string{"recall", "stats", "--category", "decision"}); code != 1 {
+		t.Errorf("expected exit code 1 for empty category, got %d", code)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// syncFormatVersion is the current sync file format version
+const syncFormatVersion = 1
+
+// syncFile is the JSON document stored in the sync remote for one level
+type syncFile struct {
+	Version int              `json:"version"`
+	Level   string           `json:"level"`
+	Pushed  time.Time        `json:"pushed"`
+	Lessons []*models.Lesson `json:"lessons"`
+}
+
+// syncOptions holds the flags shared by sync push and pull
+type syncOptions struct {
+	level  string
+	remote string
+	dryRun bool
+	force  bool
+	policy string
+}
+
+// runSync dispatches to sync subcommands
+func (a *App) runSync(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall sync <push|pull> [--level project|system] [--remote DIR] [--dry-run]")
+		fmt.Fprintln(a.stderr, "  push            - Publish local lessons to the sync remote")
+		fmt.Fprintln(a.stderr, "  pull            - Merge lessons from the sync remote")
+		return 1
+	}
+
+	opts := syncOptions{level: "project", remote: a.syncRemote, policy: lessons.ConflictSkip}
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		arg := rest[i]
+		switch {
+		case arg == "--dry-run":
+			opts.dryRun = true
+		case arg == "--force":
+			opts.force = true
+		case strings.HasPrefix(arg, "--conflict="):
+			opts.policy = strings.TrimPrefix(arg, "--conflict=")
+		case arg == "--conflict" && i+1 < len(rest):
+			opts.policy = rest[i+1]
+			i++
+		case arg == "--level" && i+1 < len(rest):
+			opts.level = rest[i+1]
+			i++
+		case arg == "--remote" && i+1 < len(rest):
+			opts.remote = rest[i+1]
+			i++
+		}
+	}
+
+	if opts.level != "project" && opts.level != "system" {
+		fmt.Fprintf(a.stderr, "error: invalid level '%s': must be project or system\n", opts.level)
+		return 1
+	}
+	if opts.remote == "" {
+		fmt.Fprintln(a.stderr, "error: no sync remote configured (set sync_remote in config.json or pass --remote)")
+		return 1
+	}
+	if strings.Contains(opts.remote, "://") {
+		fmt.Fprintf(a.stderr, "error: sync remote must be a directory; mount %s locally (e.g. rclone mount, sshfs)\n", opts.remote)
+		return 1
+	}
+
+	switch args[0] {
+	case "push":
+		return a.runSyncPush(opts)
+	case "pull":
+		return a.runSyncPull(opts)
+	default:
+		fmt.Fprintf(a.stderr, "unknown sync subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// runSyncPush writes local lessons to the remote. It refuses to overwrite
+// remote lessons that differ from or are missing locally unless --force.
+func (a *App) runSyncPush(opts syncOptions) int {
+	local, err := a.levelLessons(opts.level)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	path := syncRemotePath(opts.remote, opts.level)
+	remote, err := readSyncFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(a.stderr, "error reading remote: %v\n", err)
+		return 1
+	}
+
+	if remote != nil {
+		conflicts, remoteOnly := compareSyncLessons(local, remote.Lessons)
+		if (len(conflicts) > 0 || len(remoteOnly) > 0) && !opts.force {
+			a.printSyncConflicts(conflicts)
+			if len(remoteOnly) > 0 {
+				fmt.Fprintf(a.stderr, "remote has lessons missing locally: %s\n", strings.Join(remoteOnly, ", "))
+			}
+			fmt.Fprintln(a.stderr, "error: remote has diverged; run 'recall sync pull' first or push with --force")
+			return 1
+		}
+	}
+
+	if opts.dryRun {
+		fmt.Fprintf(a.stdout, "Would push %d %s lessons to %s\n", len(local), opts.level, path)
+		return 0
+	}
+
+	if err := writeSyncFile(path, &syncFile{
+		Version: syncFormatVersion,
+		Level:   opts.level,
+		Pushed:  time.Now().UTC(),
+		Lessons: local,
+	}); err != nil {
+		fmt.Fprintf(a.stderr, "error writing remote: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Pushed %d %s lessons to %s\n", len(local), opts.level, path)
+	return 0
+}
+
+// runSyncPull merges remote lessons into the local store using the import
+// conflict policy. Lessons whose ID exists locally with different content
+// are reported as conflicts.
+func (a *App) runSyncPull(opts syncOptions) int {
+	path := syncRemotePath(opts.remote, opts.level)
+	remote, err := readSyncFile(path)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading remote: %v\n", err)
+		return 1
+	}
+
+	local, err := a.levelLessons(opts.level)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing lessons: %v\n", err)
+		return 1
+	}
+
+	for _, l := range remote.Lessons {
+		l.Level = opts.level
+	}
+	conflicts, newIDs := compareSyncLessons(local, remote.Lessons)
+	a.printSyncConflicts(conflicts)
+
+	if opts.dryRun {
+		fmt.Fprintf(a.stdout, "Would pull %d new lessons (%d conflicts) from %s\n", len(newIDs), len(conflicts), path)
+		return 0
+	}
+
+	// Lessons already identical locally are in sync; only new and conflicting
+	// ones go through the import policy (so renumber doesn't duplicate them)
+	changed := make(map[string]bool)
+	for _, id := range append(conflicts, newIDs...) {
+		changed[id] = true
+	}
+	var incoming []*models.Lesson
+	for _, l := range remote.Lessons {
+		if changed[l.ID] {
+			incoming = append(incoming, l)
+		}
+	}
+
+	store := a.localLessonStore()
+	result, err := store.Import(incoming, opts.policy)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error importing lessons: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Pulled from %s: %d added, %d overwritten, %d skipped, %d unchanged\n",
+		path, result.Added, result.Overwritten, result.Skipped, len(remote.Lessons)-len(incoming))
+	a.printRenumbered(result.Renumbered)
+	return 0
+}
+
+// levelLessons returns the local lessons stored at one level
+func (a *App) levelLessons(level string) ([]*models.Lesson, error) {
+	store := a.localLessonStore()
+	all, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	var result []*models.Lesson
+	for _, l := range all {
+		if l.Level == level {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+// printSyncConflicts reports IDs whose local and remote lessons differ
+func (a *App) printSyncConflicts(conflicts []string) {
+	if len(conflicts) > 0 {
+		fmt.Fprintf(a.stderr, "conflicts (same ID, different lesson): %s\n", strings.Join(conflicts, ", "))
+	}
+}
+
+// compareSyncLessons returns the IDs present on both sides with a different
+// title or content, and the IDs only present in remote
+func compareSyncLessons(local, remote []*models.Lesson) (conflicts, remoteOnly []string) {
+	byID := make(map[string]*models.Lesson, len(local))
+	for _, l := range local {
+		byID[l.ID] = l
+	}
+	for _, r := range remote {
+		l, ok := byID[r.ID]
+		switch {
+		case !ok:
+			remoteOnly = append(remoteOnly, r.ID)
+		case l.Title != r.Title || l.Content != r.Content:
+			conflicts = append(conflicts, r.ID)
+		}
+	}
+	sort.Strings(conflicts)
+	sort.Strings(remoteOnly)
+	return conflicts, remoteOnly
+}
+
+// syncRemotePath returns the sync file for a level within the remote directory
+func syncRemotePath(remote, level string) string {
+	return filepath.Join(remote, "lessons-"+level+".json")
+}
+
+// readSyncFile loads and validates a sync file
+func readSyncFile(path string) (*syncFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sf syncFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if sf.Version != syncFormatVersion {
+		return nil, fmt.Errorf("unsupported sync format version %d", sf.Version)
+	}
+	return &sf, nil
+}
+
+// writeSyncFile writes a sync file atomically (temp file + rename) so readers
+// on shared mounts never see a partial file
+func writeSyncFile(path string, sf *syncFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
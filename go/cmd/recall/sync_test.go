@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Sync_PushPullWithConflicts(t *testing.T) {
+	remote := t.TempDir()
+	appA, storeA, outA, errA := newTestApp(t)
+	appB, storeB, outB, errB := newTestApp(t)
+	appA.syncRemote = remote
+	appB.syncRemote = remote
+
+	storeA.Add("project", "pattern", "Alpha lesson", "Shared by A")
+	if code := appA.Run([]string{"recall", "sync", "push"}); code != 0 {
+		t.Fatalf("push failed: %s", errA.String())
+	}
+	if _, err := os.Stat(filepath.Join(remote, "lessons-project.json")); err != nil {
+		t.Fatalf("expected JSON sync file: %v", err)
+	}
+
+	// B pulls A's lesson, adds its own, and pushes back
+	if code := appB.Run([]string{"recall", "sync", "pull"}); code != 0 {
+		t.Fatalf("pull failed: %s", errB.String())
+	}
+	if l, err := storeB.Get("L001"); err != nil || l.Title != "Alpha lesson" {
+		t.Fatalf("expected L001 pulled into B, got %v (%v)", l, err)
+	}
+	storeB.Add("project", "gotcha", "Beta lesson", "Written by B")
+	if code := appB.Run([]string{"recall", "sync", "push"}); code != 0 {
+		t.Fatalf("B push failed: %s", errB.String())
+	}
+	if !strings.Contains(outB.String(), "Pushed 2 project lessons") {
+		t.Errorf("unexpected push output: %s", outB.String())
+	}
+
+	// A wrote a different L002 meanwhile: push must refuse
+	storeA.Add("project", "decision", "Gamma lesson", "Written by A offline")
+	if code := appA.Run([]string{"recall", "sync", "push"}); code != 1 {
+		t.Fatalf("expected push to fail on conflict, got %d", code)
+	}
+	if !strings.Contains(errA.String(), "conflicts (same ID, different lesson): L002") {
+		t.Errorf("expected L002 conflict, got: %s", errA.String())
+	}
+
+	// Dry-run pull reports without writing
+	outA.Reset()
+	if code := appA.Run([]string{"recall", "sync", "pull", "--dry-run"}); code != 0 {
+		t.Fatalf("dry-run pull failed: %s", errA.String())
+	}
+	if !strings.Contains(outA.String(), "Would pull 0 new lessons (1 conflicts)") {
+		t.Errorf("unexpected dry-run output: %s", outA.String())
+	}
+
+	// Renumbering keeps both sides' L002
+	if code := appA.Run([]string{"recall", "sync", "pull", "--conflict=renumber"}); code != 0 {
+		t.Fatalf("pull failed: %s", errA.String())
+	}
+	if l, err := storeA.Get("L002"); err != nil || l.Title != "Gamma lesson" {
+		t.Errorf("expected A's own L002 kept, got %v (%v)", l, err)
+	}
+	all, _ := storeA.List()
+	titles := map[string]int{}
+	for _, l := range all {
+		titles[l.Title]++
+	}
+	if titles["Beta lesson"] != 1 || titles["Alpha lesson"] != 1 || len(all) != 3 {
+		t.Errorf("expected B's lesson renumbered into A without duplicating L001, got %v", titles)
+	}
+
+	if code := appA.Run([]string{"recall", "sync", "push", "--force"}); code != 0 {
+		t.Errorf("forced push failed: %s", errA.String())
+	}
+}
+
+func Test_Sync_RequiresDirectoryRemote(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	if code := app.Run([]string{"recall", "sync", "push"}); code != 1 {
+		t.Errorf("expected exit code 1 without remote, got %d", code)
+	}
+	if code := app.Run([]string{"recall", "sync", "push", "--remote", "s3://bucket/lessons"}); code != 1 {
+		t.Errorf("expected exit code 1 for URL remote, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mount s3://bucket/lessons locally") {
+		t.Errorf("expected mount hint, got: %s", stderr.String())
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// runHandoffLogTime records minutes spent on a handoff
+func (a *App) runHandoffLogTime(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(a.stderr, "usage: recall handoff log-time <id> <minutes> [--session <session-id>]")
+		return 1
+	}
+
+	id := args[0]
+	minutes, err := strconv.Atoi(args[1])
+	if err != nil || minutes <= 0 {
+		fmt.Fprintf(a.stderr, "error: invalid minutes '%s' (must be a positive integer)\n", args[1])
+		return 1
+	}
+	var sessionID string
+	for i := 2; i < len(args); i++ {
+		if args[i] == "--session" && i+1 < len(args) {
+			sessionID = args[i+1]
+			i++
+		}
+	}
+
+	store := a.handoffStore()
+	if err := store.LogTime(id, sessionID, minutes); err != nil {
+		fmt.Fprintf(a.stderr, "error logging time: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Logged %s on handoff %s\n", formatMinutes(minutes), id)
+	return 0
+}
+
+// runHandoffTimeReport shows total logged time per handoff, or per session
+// for a single handoff with --id
+func (a *App) runHandoffTimeReport(args []string) int {
+	var id string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--id" && i+1 < len(args) {
+			id = args[i+1]
+			i++
+		}
+	}
+
+	store := a.handoffStore()
+	if id != "" {
+		h, err := store.Get(id)
+		if err != nil {
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			return 1
+		}
+		a.printSessionTimes(h)
+		return 0
+	}
+
+	all, err := store.ListAll()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error listing handoffs: %v\n", err)
+		return 1
+	}
+	var logged []*models.Handoff
+	for _, h := range all {
+		if h.TotalMinutes() > 0 {
+			logged = append(logged, h)
+		}
+	}
+	if len(logged) == 0 {
+		fmt.Fprintln(a.stdout, "No time logged.")
+		return 0
+	}
+	sort.SliceStable(logged, func(i, j int) bool {
+		return logged[i].TotalMinutes() > logged[j].TotalMinutes()
+	})
+
+	total := 0
+	for _, h := range logged {
+		fmt.Fprintf(a.stdout, "%-12s %8s  %s\n", h.ID, formatMinutes(h.TotalMinutes()), h.Title)
+		total += h.TotalMinutes()
+	}
+	fmt.Fprintf(a.stdout, "\nTotal: %s across %d handoffs\n", formatMinutes(total), len(logged))
+	return 0
+}
+
+// printSessionTimes prints one handoff's logged time grouped by session, in
+// the order sessions first logged time
+func (a *App) printSessionTimes(h *models.Handoff) {
+	fmt.Fprintf(a.stdout, "[%s] %s\n", h.ID, h.Title)
+	if len(h.TimeLog) == 0 {
+		fmt.Fprintln(a.stdout, "No time logged.")
+		return
+	}
+
+	var sessions []string
+	bySession := make(map[string]int)
+	for _, e := range h.TimeLog {
+		if _, seen := bySession[e.SessionID]; !seen {
+			sessions = append(sessions, e.SessionID)
+		}
+		bySession[e.SessionID] += e.Minutes
+	}
+	for _, s := range sessions {
+		label := s
+		if label == "" {
+			label = "(no session)"
+		}
+		fmt.Fprintf(a.stdout, "  %-24s %8s\n", label, formatMinutes(bySession[s]))
+	}
+	fmt.Fprintf(a.stdout, "Total: %s\n", formatMinutes(h.TotalMinutes()))
+}
+
+// formatMinutes renders minutes as "45m" or "2h05m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
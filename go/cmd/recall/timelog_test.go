@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/handoffs"
+)
+
+func Test_HandoffLogTime_AndTimeReport(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	hStore := handoffs.NewStore(app.handoffsPath, app.stealthPath)
+	auth, _ := hStore.Add("Auth refactor", "", false)
+	billing, _ := hStore.Add("Billing export", "", false)
+	hStore.Add("Untracked", "", false)
+
+	for _, args := range [][]string{
+		{auth.ID, "45", "--session", "sess-1"},
+		{auth.ID, "30", "--session", "sess-2"},
+		{auth.ID, "20", "--session", "sess-1"},
+		{billing.ID, "10"},
+	} {
+		if code := app.Run(append([]string{"recall", "handoff", "log-time"}, args...)); code != 0 {
+			t.Fatalf("log-time %v failed: %s", args, stderr.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "time-report"}); code != 0 {
+		t.Fatalf("time-report failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "1h35m  Auth refactor") || !strings.Contains(out, "10m  Billing export") {
+		t.Errorf("expected per-handoff totals, got:\n%s", out)
+	}
+	if strings.Index(out, "Auth refactor") > strings.Index(out, "Billing export") || strings.Contains(out, "Untracked") {
+		t.Errorf("expected handoffs with time, largest first:\n%s", out)
+	}
+	if !strings.Contains(out, "Total: 1h45m across 2 handoffs") {
+		t.Errorf("expected grand total, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "handoff", "time-report", "--id", auth.ID}); code != 0 {
+		t.Fatalf("time-report --id failed: %s", stderr.String())
+	}
+	out = stdout.String()
+	if !strings.Contains(out, "sess-1") || !strings.Contains(out, "1h05m") || !strings.Contains(out, "Total: 1h35m") {
+		t.Errorf("expected per-session breakdown, got:\n%s", out)
+	}
+
+	if code := app.Run([]string{"recall", "handoff", "log-time", auth.ID, "abc"}); code != 1 {
+		t.Errorf("expected exit code 1 for invalid minutes, got %d", code)
+	}
+}
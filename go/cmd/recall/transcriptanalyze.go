@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// healthyCitationRate is the citations-per-assistant-message rate that
+// scores 100: one lesson citation every four replies
+const healthyCitationRate = 0.25
+
+// fileModifyingTools are the tool names counted as file modifications
+var fileModifyingTools = map[string]bool{
+	"Edit":         true,
+	"MultiEdit":    true,
+	"Write":        true,
+	"NotebookEdit": true,
+}
+
+// handoffOpCounts tallies handoff commands found in a transcript
+type handoffOpCounts struct {
+	Started   int `json:"started"`
+	Updated   int `json:"updated"`
+	Completed int `json:"completed"`
+}
+
+// lessonCitationCount is a lesson ID and the number of messages citing it
+type lessonCitationCount struct {
+	ID        string `json:"id"`
+	Citations int    `json:"citations"`
+}
+
+// transcriptReport is the output of opencode transcript-analyze
+type transcriptReport struct {
+	Messages             int                   `json:"messages"`
+	UserMessages         int                   `json:"user_messages"`
+	AssistantMessages    int                   `json:"assistant_messages"`
+	Citations            int                   `json:"citations"`
+	UniqueCitations      int                   `json:"unique_citations"`
+	LessonsAdded         int                   `json:"lessons_added"`
+	HandoffOps           handoffOpCounts       `json:"handoff_operations"`
+	ToolUses             int                   `json:"tool_uses"`
+	FileModifications    int                   `json:"file_modifications"`
+	FilesModified        int                   `json:"files_modified"`
+	EstimatedTokens      int                   `json:"estimated_tokens"`
+	ReportedInputTokens  int                   `json:"reported_input_tokens"`
+	ReportedOutputTokens int                   `json:"reported_output_tokens"`
+	CitationsPerMessage  float64               `json:"citations_per_message"`
+	HealthScore          int                   `json:"health_score"` // 0-100
+	TopLessons           []lessonCitationCount `json:"top_lessons,omitempty"`
+}
+
+// runOpencodeTranscriptAnalyze reports session metrics for a JSONL transcript
+func (a *App) runOpencodeTranscriptAnalyze(args []string) int {
+	var path string
+	jsonOutput := false
+	topN := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		case "--top-lessons":
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 0 {
+					fmt.Fprintf(a.stderr, "error: invalid --top-lessons %q\n", args[i+1])
+					return 1
+				}
+				topN = n
+				i++
+			}
+		default:
+			path = args[i]
+		}
+	}
+
+	if path == "" {
+		fmt.Fprintln(a.stderr, "usage: recall opencode transcript-analyze <path> [--json] [--top-lessons N]")
+		return 1
+	}
+
+	data, err := a.readTranscript(path)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error reading transcript: %v\n", err)
+		return 1
+	}
+
+	report := analyzeTranscript(data, topN)
+	if jsonOutput {
+		return a.writeJSON(report)
+	}
+
+	fmt.Fprintf(a.stdout, "Transcript: %s\n", path)
+	fmt.Fprintf(a.stdout, "Messages: %d (%d user, %d assistant)\n", report.Messages, report.UserMessages, report.AssistantMessages)
+	fmt.Fprintf(a.stdout, "Lesson citations: %d (%d unique)\n", report.Citations, report.UniqueCitations)
+	fmt.Fprintf(a.stdout, "Lessons added: %d\n", report.LessonsAdded)
+	fmt.Fprintf(a.stdout, "Handoff operations: %d started, %d updated, %d completed\n",
+		report.HandoffOps.Started, report.HandoffOps.Updated, report.HandoffOps.Completed)
+	fmt.Fprintf(a.stdout, "File modifications: %d across %d files (%d tool uses)\n",
+		report.FileModifications, report.FilesModified, report.ToolUses)
+	if report.ReportedInputTokens > 0 || report.ReportedOutputTokens > 0 {
+		fmt.Fprintf(a.stdout, "Tokens: ~%d estimated (%d in / %d out reported)\n",
+			report.EstimatedTokens, report.ReportedInputTokens, report.ReportedOutputTokens)
+	} else {
+		fmt.Fprintf(a.stdout, "Tokens: ~%d estimated\n", report.EstimatedTokens)
+	}
+	fmt.Fprintf(a.stdout, "Session health: %d/100 (%.2f citations per message)\n", report.HealthScore, report.CitationsPerMessage)
+	if len(report.TopLessons) > 0 {
+		fmt.Fprintln(a.stdout, "Top lessons:")
+		for _, l := range report.TopLessons {
+			fmt.Fprintf(a.stdout, "  %-6s %d\n", l.ID, l.Citations)
+		}
+	}
+	return 0
+}
+
+// analyzeTranscript computes report metrics. A lesson counts once per
+// assistant message that cites it; tokens are estimated at 4 chars each.
+func analyzeTranscript(data *transcriptData, topN int) transcriptReport {
+	report := transcriptReport{
+		Messages:             data.UserMessages + data.AssistantMessages,
+		UserMessages:         data.UserMessages,
+		AssistantMessages:    data.AssistantMessages,
+		ToolUses:             len(data.ToolUses),
+		EstimatedTokens:      (data.TextChars + 3) / 4,
+		ReportedInputTokens:  data.InputTokens,
+		ReportedOutputTokens: data.OutputTokens,
+	}
+
+	counts := make(map[string]int)
+	for _, text := range data.Texts {
+		for _, id := range extractCitations(text) {
+			counts[id]++
+			report.Citations++
+		}
+		report.LessonsAdded += len(lessonPattern.FindAllStringIndex(text, -1))
+		report.HandoffOps.Started += len(opencodeHandoffStartPattern.FindAllStringIndex(text, -1))
+		report.HandoffOps.Updated += len(opencodeHandoffUpdatePattern.FindAllStringIndex(text, -1))
+		report.HandoffOps.Completed += len(opencodeHandoffCompletePattern.FindAllStringIndex(text, -1))
+	}
+	report.UniqueCitations = len(counts)
+
+	files := make(map[string]bool)
+	for _, ev := range data.ToolUses {
+		if fileModifyingTools[ev.Name] {
+			report.FileModifications++
+			if ev.FilePath != "" {
+				files[ev.FilePath] = true
+			}
+		}
+	}
+	report.FilesModified = len(files)
+
+	if data.AssistantMessages > 0 {
+		report.CitationsPerMessage = float64(report.Citations) / float64(data.AssistantMessages)
+		report.HealthScore = int(math.Round(math.Min(1, report.CitationsPerMessage/healthyCitationRate) * 100))
+	}
+
+	if topN > 0 {
+		for id, n := range counts {
+			report.TopLessons = append(report.TopLessons, lessonCitationCount{ID: id, Citations: n})
+		}
+		sort.Slice(report.TopLessons, func(i, j int) bool {
+			if report.TopLessons[i].Citations != report.TopLessons[j].Citations {
+				return report.TopLessons[i].Citations > report.TopLessons[j].Citations
+			}
+			return report.TopLessons[i].ID < report.TopLessons[j].ID
+		})
+		if len(report.TopLessons) > topN {
+			report.TopLessons = report.TopLessons[:topN]
+		}
+	}
+
+	return report
+}
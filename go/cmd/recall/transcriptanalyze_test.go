@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// analyzeFixture is a synthetic session: 2 user and 4 assistant messages,
+// 5 lesson citations (3 unique), one listing that must not count, one new
+// lesson, all three handoff operations, and three file edits to two files
+const analyzeFixture = `{"type":"user","message":{"role":"user","content":"Fix the flaky test"}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Per [L001] and [L002], retry with backoff.\nHANDOFF: Fix flaky test"}],"usage":{"input_tokens":100,"output_tokens":20}}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit","input":{"file_path":"a_test.go"}},{"type":"tool_use","name":"Read","input":{"file_path":"b.go"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Applying [L001] again. [S001] [***--] listed only.\nHANDOFF UPDATE hf-1234567: tried success - added retries"},{"type":"tool_use","name":"Write","input":{"file_path":"b.go"}},{"type":"tool_use","name":"Edit","input":{"file_path":"a_test.go"}}],"usage":{"input_tokens":50,"output_tokens":10}}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Done [L001] [S002].\nLESSON: gotcha: Flaky timers - use a fake clock\nHANDOFF COMPLETE hf-1234567"}]}}
+{"type":"summary","summary":"not a message"}
+`
+
+func writeAnalyzeFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(analyzeFixture), 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func Test_TranscriptAnalyze_JSONMetrics(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	path := writeAnalyzeFixture(t)
+
+	if code := app.Run([]string{"recall", "opencode", "transcript-analyze", path, "--json", "--top-lessons", "2"}); code != 0 {
+		t.Fatalf("transcript-analyze failed: %s", stderr.String())
+	}
+
+	var report transcriptReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+
+	checks := []struct {
+		name      string
+		got, want int
+	}{
+		{"messages", report.Messages, 6},
+		{"user_messages", report.UserMessages, 2},
+		{"assistant_messages", report.AssistantMessages, 4},
+		{"citations", report.Citations, 5},
+		{"unique_citations", report.UniqueCitations, 3},
+		{"lessons_added", report.LessonsAdded, 1},
+		{"handoffs started", report.HandoffOps.Started, 1},
+		{"handoffs updated", report.HandoffOps.Updated, 1},
+		{"handoffs completed", report.HandoffOps.Completed, 1},
+		{"tool_uses", report.ToolUses, 4},
+		{"file_modifications", report.FileModifications, 3},
+		{"files_modified", report.FilesModified, 2},
+		{"reported_input_tokens", report.ReportedInputTokens, 150},
+		{"reported_output_tokens", report.ReportedOutputTokens, 30},
+		{"health_score", report.HealthScore, 100},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if report.CitationsPerMessage != 1.25 {
+		t.Errorf("citations_per_message = %v, want 1.25", report.CitationsPerMessage)
+	}
+	if report.EstimatedTokens <= 0 {
+		t.Errorf("expected a positive token estimate, got %d", report.EstimatedTokens)
+	}
+
+	if len(report.TopLessons) != 2 || report.TopLessons[0] != (lessonCitationCount{"L001", 3}) ||
+		report.TopLessons[1] != (lessonCitationCount{"L002", 1}) {
+		t.Errorf("unexpected top lessons: %+v", report.TopLessons)
+	}
+}
+
+func Test_TranscriptAnalyze_HealthScaling(t *testing.T) {
+	data := &transcriptData{AssistantMessages: 8, Texts: []string{"see [L001]"}}
+	report := analyzeTranscript(data, 0)
+	// 1 citation / 8 messages = 0.125, half the healthy rate
+	if report.HealthScore != 50 {
+		t.Errorf("health_score = %d, want 50", report.HealthScore)
+	}
+	if report.TopLessons != nil {
+		t.Errorf("expected no top lessons without --top-lessons, got %+v", report.TopLessons)
+	}
+}
+
+func Test_TranscriptAnalyze_TextOutput(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	path := writeAnalyzeFixture(t)
+
+	if code := app.Run([]string{"recall", "opencode", "transcript-analyze", path}); code != 0 {
+		t.Fatalf("transcript-analyze failed: %s", stderr.String())
+	}
+	for _, want := range []string{
+		"Messages: 6 (2 user, 4 assistant)",
+		"Lesson citations: 5 (3 unique)",
+		"Handoff operations: 1 started, 1 updated, 1 completed",
+		"File modifications: 3 across 2 files (4 tool uses)",
+		"Session health: 100/100 (1.25 citations per message)",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout.String())
+		}
+	}
+
+	if code := app.Run([]string{"recall", "opencode", "transcript-analyze", filepath.Join(t.TempDir(), "missing.jsonl")}); code != 1 {
+		t.Errorf("expected exit code 1 for missing transcript, got %d", code)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/checkpoint"
+	"github.com/pbrown/claude-recall/internal/citations"
+	"github.com/pbrown/claude-recall/internal/debuglog"
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/transcript"
+)
+
+// defaultWatchInterval is how often the watcher polls the transcript
+const defaultWatchInterval = 500 * time.Millisecond
+
+// runWatch dispatches watch start|stop|status|run
+func (a *App) runWatch(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(a.stderr, "usage: recall watch <start|stop|status> [args...]")
+		fmt.Fprintln(a.stderr, "  start <transcript> [--session ID] [--interval D]  - Watch in the background")
+		fmt.Fprintln(a.stderr, "  stop                                              - Stop the background watcher")
+		fmt.Fprintln(a.stderr, "  status                                            - Show whether a watcher is running")
+		fmt.Fprintln(a.stderr, "  run <transcript> [--session ID] [--interval D]    - Watch in the foreground")
+		return 1
+	}
+
+	switch args[0] {
+	case "start":
+		return a.runWatchStart(args[1:])
+	case "stop":
+		return a.runWatchStop()
+	case "status":
+		return a.runWatchStatus()
+	case "run":
+		return a.runWatchRun(args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "unknown watch subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// watchOptions are the arguments shared by watch start and watch run
+type watchOptions struct {
+	transcriptPath string
+	sessionID      string
+	interval       time.Duration
+}
+
+// parseWatchOptions parses <transcript> [--session ID] [--interval D]. The
+// session ID defaults to the transcript file name, as Claude Code names
+// transcripts <session-id>.jsonl.
+func parseWatchOptions(args []string) (watchOptions, error) {
+	opts := watchOptions{interval: defaultWatchInterval}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--session" && i+1 < len(args):
+			opts.sessionID = args[i+1]
+			i++
+		case args[i] == "--interval" && i+1 < len(args):
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 {
+				return opts, fmt.Errorf("invalid --interval %q", args[i+1])
+			}
+			opts.interval = d
+			i++
+		case opts.transcriptPath == "":
+			opts.transcriptPath = args[i]
+		}
+	}
+	if opts.transcriptPath == "" {
+		return opts, fmt.Errorf("missing transcript path")
+	}
+	if opts.sessionID == "" {
+		opts.sessionID = strings.TrimSuffix(filepath.Base(opts.transcriptPath), ".jsonl")
+	}
+	return opts, nil
+}
+
+// watchPIDPath returns the watcher's PID file in the state directory
+func (a *App) watchPIDPath() string {
+	return filepath.Join(a.stateDir, "watch.pid")
+}
+
+// runningWatchPID returns the PID of a live watcher, or 0 if none is running
+func (a *App) runningWatchPID() int {
+	data, err := os.ReadFile(a.watchPIDPath())
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil || proc.Signal(syscall.Signal(0)) != nil {
+		return 0
+	}
+	return pid
+}
+
+// runWatchStart launches "recall watch run" as a background process
+func (a *App) runWatchStart(args []string) int {
+	opts, err := parseWatchOptions(args)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		fmt.Fprintln(a.stderr, "usage: recall watch start <transcript> [--session ID] [--interval D]")
+		return 1
+	}
+	if pid := a.runningWatchPID(); pid != 0 {
+		fmt.Fprintf(a.stderr, "error: watcher already running (pid %d)\n", pid)
+		return 1
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error locating recall binary: %v\n", err)
+		return 1
+	}
+	transcriptPath, _ := filepath.Abs(opts.transcriptPath)
+	cmd := exec.Command(self, "watch", "run", transcriptPath,
+		"--session", opts.sessionID, "--interval", opts.interval.String())
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(a.stderr, "error starting watcher: %v\n", err)
+		return 1
+	}
+	pid := cmd.Process.Pid
+	cmd.Process.Release()
+
+	// Written here as well as by the child so status is accurate immediately
+	if err := os.WriteFile(a.watchPIDPath(), []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		fmt.Fprintf(a.stderr, "error writing PID file: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.stdout, "Watching %s (pid %d)\n", transcriptPath, pid)
+	return 0
+}
+
+// runWatchStop signals the background watcher to exit
+func (a *App) runWatchStop() int {
+	pid := a.runningWatchPID()
+	if pid == 0 {
+		os.Remove(a.watchPIDPath())
+		fmt.Fprintln(a.stdout, "No watcher running")
+		return 0
+	}
+
+	proc, _ := os.FindProcess(pid)
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		fmt.Fprintf(a.stderr, "error stopping watcher (pid %d): %v\n", pid, err)
+		return 1
+	}
+	os.Remove(a.watchPIDPath())
+	fmt.Fprintf(a.stdout, "Stopped watcher (pid %d)\n", pid)
+	return 0
+}
+
+// runWatchStatus reports whether a watcher is running
+func (a *App) runWatchStatus() int {
+	if pid := a.runningWatchPID(); pid != 0 {
+		fmt.Fprintf(a.stdout, "Watcher running (pid %d)\n", pid)
+	} else {
+		fmt.Fprintln(a.stdout, "No watcher running")
+	}
+	return 0
+}
+
+// runWatchRun watches a transcript in the foreground until interrupted
+func (a *App) runWatchRun(args []string) int {
+	opts, err := parseWatchOptions(args)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		fmt.Fprintln(a.stderr, "usage: recall watch run <transcript> [--session ID] [--interval D]")
+		return 1
+	}
+
+	pidPath := a.watchPIDPath()
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(pidPath, []byte(pid+"\n"), 0644); err != nil {
+		fmt.Fprintf(a.stderr, "error writing PID file: %v\n", err)
+		return 1
+	}
+	defer func() {
+		// Leave the file alone if another watcher has since taken over
+		if data, err := os.ReadFile(pidPath); err == nil && strings.TrimSpace(string(data)) == pid {
+			os.Remove(pidPath)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	done := make(chan struct{})
+	go func() {
+		<-signals
+		close(done)
+	}()
+
+	a.newTranscriptWatcher(opts).run(done)
+	return 0
+}
+
+// transcriptWatcher cites lessons as they appear in a growing transcript
+// and adds any AI LESSON markers. It shares the stop hook's per-session
+// checkpoint offsets, so it does everything the stop hook would have done
+// with the lines it consumes: citations are never counted twice, and AI
+// lessons are never missed.
+type transcriptWatcher struct {
+	opts           watchOptions
+	checkpointPath string
+	projectDir     string
+	store          *lessons.Store
+	dlog           *debuglog.Logger
+}
+
+// newTranscriptWatcher creates a watcher using the App's stores and state
+func (a *App) newTranscriptWatcher(opts watchOptions) *transcriptWatcher {
+	return &transcriptWatcher{
+		opts:           opts,
+		checkpointPath: filepath.Join(a.stateDir, "checkpoints.txt"),
+		projectDir:     a.projectDir,
+		store:          a.lessonStore(),
+		dlog:           debuglog.New(a.stateDir, a.debugLevel),
+	}
+}
+
+// run polls the transcript until done is closed
+func (w *transcriptWatcher) run(done <-chan struct{}) {
+	w.dlog.LogWatch("started", w.opts.transcriptPath, "session "+w.opts.sessionID)
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.poll(); err != nil {
+			w.dlog.LogWatch("error", w.opts.transcriptPath, err.Error())
+		}
+		select {
+		case <-done:
+			w.dlog.LogWatch("stopped", w.opts.transcriptPath, "session "+w.opts.sessionID)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll processes complete lines appended since the last checkpoint, citing
+// any lessons found in new assistant messages and adding their AI lessons.
+// Returns the cited IDs.
+func (w *transcriptWatcher) poll() ([]string, error) {
+	offset, err := checkpoint.GetOffset(w.checkpointPath, w.opts.sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(w.opts.transcriptPath)
+	if os.IsNotExist(err) {
+		return nil, nil // not written yet
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// A trailing partial line is still being written; pick it up next time
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return nil, nil
+	}
+	data = data[:end+1]
+
+	messages, err := transcript.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var cited []string
+	for _, c := range citations.ExtractFromMessages(messages) {
+		if c.Type == "H" {
+			continue
+		}
+		if err := w.store.CiteInSession(c.ID, w.opts.sessionID); err != nil {
+			w.dlog.LogWatch("error", w.opts.transcriptPath, fmt.Sprintf("cite %s: %v", c.ID, err))
+			continue
+		}
+		cited = append(cited, c.ID)
+	}
+	w.dlog.LogCitationBatch(w.opts.sessionID, w.projectDir, cited)
+
+	for _, msg := range messages {
+		if msg.Type != "assistant" {
+			continue
+		}
+		for _, l := range lessons.ExtractAILessons(msg.Content) {
+			if _, err := w.store.Add("project", l.Category, l.Title, l.Content); err != nil {
+				w.dlog.LogWatch("error", w.opts.transcriptPath, fmt.Sprintf("add lesson: %v", err))
+			}
+		}
+	}
+
+	if err := checkpoint.SetOffset(w.checkpointPath, w.opts.sessionID, offset+int64(len(data))); err != nil {
+		return cited, err
+	}
+	return cited, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const watchAssistantLine = `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Following [L001] here."}]}}` + "\n"
+
+func Test_TranscriptWatcher_CitesAppendedLines(t *testing.T) {
+	app, store, _, _ := newTestApp(t)
+	lesson, _ := store.Add("project", "pattern", "Watched lesson", "Cited live")
+
+	transcriptPath := filepath.Join(t.TempDir(), "sess-1.jsonl")
+	os.WriteFile(transcriptPath, []byte(`{"type":"user","message":{"role":"user","content":[]}}`+"\n"), 0644)
+
+	opts, err := parseWatchOptions([]string{transcriptPath, "--interval", "20ms"})
+	if err != nil {
+		t.Fatalf("parseWatchOptions failed: %v", err)
+	}
+	if opts.sessionID != "sess-1" {
+		t.Errorf("expected session ID from file name, got %q", opts.sessionID)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		app.newTranscriptWatcher(opts).run(done)
+		close(stopped)
+	}()
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+
+	f, _ := os.OpenFile(transcriptPath, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(watchAssistantLine)
+	f.WriteString(`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"[L001`) // still being written
+	f.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if l, _ := store.Get(lesson.ID); l.Uses == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected citation to be processed within 2 seconds")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Later polls must not re-cite lines already processed or half-written ones
+	time.Sleep(100 * time.Millisecond)
+	if l, _ := store.Get(lesson.ID); l.Uses != 1 {
+		t.Errorf("expected uses to stay 1, got %d", l.Uses)
+	}
+}
+
+func Test_TranscriptWatcher_PollSkipsMissingTranscript(t *testing.T) {
+	app, _, _, _ := newTestApp(t)
+	w := app.newTranscriptWatcher(watchOptions{transcriptPath: filepath.Join(t.TempDir(), "none.jsonl"), sessionID: "x"})
+	if cited, err := w.poll(); err != nil || len(cited) != 0 {
+		t.Errorf("expected no citations and no error, got %v, %v", cited, err)
+	}
+}
+
+func Test_TranscriptWatcher_PollAddsAILessons(t *testing.T) {
+	app, store, _, _ := newTestApp(t)
+	transcriptPath := filepath.Join(t.TempDir(), "sess-2.jsonl")
+	os.WriteFile(transcriptPath, []byte(`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"AI LESSON: gotcha: Close response bodies - Leaks connections otherwise"}]}}`+"\n"), 0644)
+
+	w := app.newTranscriptWatcher(watchOptions{transcriptPath: transcriptPath, sessionID: "sess-2"})
+	if _, err := w.poll(); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	// The watcher consumed the stop hook's offset, so it must add the lesson
+	all, _ := store.List()
+	if len(all) != 1 || all[0].Category != "gotcha" || all[0].Title != "Close response bodies" || all[0].Content != "Leaks connections otherwise" {
+		t.Errorf("expected the AI lesson to be added, got %+v", all)
+	}
+}
+
+func Test_WatchStatusAndStop(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+
+	if code := app.Run([]string{"recall", "watch", "status"}); code != 0 || !strings.Contains(stdout.String(), "No watcher running") {
+		t.Errorf("expected no watcher, got %d: %s", code, stdout.String())
+	}
+
+	// A live PID counts as running
+	os.WriteFile(app.watchPIDPath(), []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+	stdout.Reset()
+	app.Run([]string{"recall", "watch", "status"})
+	if !strings.Contains(stdout.String(), "Watcher running (pid "+strconv.Itoa(os.Getpid())+")") {
+		t.Errorf("expected running watcher, got: %s", stdout.String())
+	}
+
+	// A stale PID file is cleaned up by stop
+	os.WriteFile(app.watchPIDPath(), []byte("not-a-pid\n"), 0644)
+	stdout.Reset()
+	if code := app.Run([]string{"recall", "watch", "stop"}); code != 0 || !strings.Contains(stdout.String(), "No watcher running") {
+		t.Errorf("expected stop to report no watcher, got %d: %s", code, stdout.String())
+	}
+	if _, err := os.Stat(app.watchPIDPath()); !os.IsNotExist(err) {
+		t.Error("expected stale PID file to be removed")
+	}
+
+	if code := app.Run([]string{"recall", "watch", "start"}); code != 1 {
+		t.Errorf("expected exit code 1 without a transcript, got %d", code)
+	}
+}
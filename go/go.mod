@@ -1,3 +1,10 @@
 module github.com/pbrown/claude-recall
 
 go 1.21
+
+require (
+	github.com/fsnotify/fsnotify v1.8.0
+	golang.org/x/time v0.8.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -21,6 +21,9 @@ const (
 	DefaultMaxTokens = 1024
 )
 
+// defaultBaseURL is the API endpoint used by NewClient (overridden in tests)
+var defaultBaseURL = "https://api.anthropic.com/v1"
+
 // Client wraps the Anthropic API
 type Client struct {
 	apiKey     string
@@ -40,7 +43,7 @@ func NewClient() (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		baseURL: "https://api.anthropic.com/v1",
+		baseURL: defaultBaseURL,
 	}, nil
 }
 
@@ -94,11 +97,19 @@ func (c *Client) Complete(prompt string) (string, error) {
 
 // CompleteWithTimeout sends a completion request with a custom timeout
 func (c *Client) CompleteWithTimeout(prompt string, timeout time.Duration) (string, error) {
+	return c.CompleteWithModel(prompt, HaikuModel, timeout)
+}
+
+// CompleteWithModel sends a completion request to a specific model
+func (c *Client) CompleteWithModel(prompt, model string, timeout time.Duration) (string, error) {
+	// Respect the shared request rate before touching the network
+	sharedRateLimiter().Wait()
+
 	// Create a client with the specified timeout
 	client := &http.Client{Timeout: timeout}
 
 	req := MessagesRequest{
-		Model:     HaikuModel,
+		Model:     model,
 		MaxTokens: DefaultMaxTokens,
 		Messages: []Message{
 			{Role: "user", Content: prompt},
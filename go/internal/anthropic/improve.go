@@ -0,0 +1,62 @@
+package anthropic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ImproveLesson asks the model for a clearer, more actionable rewrite of a
+// lesson's content. An empty model uses HaikuModel.
+func ImproveLesson(title, content, model string, timeout time.Duration) (string, error) {
+	if model == "" {
+		model = HaikuModel
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return "", err
+	}
+
+	response, err := client.CompleteWithModel(buildImprovePrompt(title, content), model, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	improved := stripCodeFence(response)
+	if improved == "" {
+		return "", fmt.Errorf("model returned an empty suggestion")
+	}
+	return improved, nil
+}
+
+// buildImprovePrompt creates the prompt for rewriting a lesson
+func buildImprovePrompt(title, content string) string {
+	return fmt.Sprintf(`Rewrite this coding lesson so it is clearer and more actionable.
+
+Lesson title: %s
+Lesson content: %s
+
+Guidelines:
+- Say concretely what to do (or avoid) and when it applies
+- Keep the original meaning; do not invent facts
+- One to three sentences, no headings or bullet points
+
+Output ONLY the rewritten lesson content, nothing else.`, title, content)
+}
+
+// stripCodeFence trims whitespace and any markdown code fence the model
+// wrapped around its answer
+func stripCodeFence(response string) string {
+	response = strings.TrimSpace(response)
+	if strings.HasPrefix(response, "```") {
+		response = response[3:]
+		if nl := strings.Index(response, "\n"); nl >= 0 {
+			response = response[nl+1:]
+		}
+		if idx := strings.LastIndex(response, "```"); idx >= 0 {
+			response = response[:idx]
+		}
+	}
+	return strings.TrimSpace(response)
+}
@@ -0,0 +1,49 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImproveLesson_UsesRequestedModel(t *testing.T) {
+	var got MessagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(MessagesResponse{
+			Content: []ContentBlock{{Type: "text", Text: "```\nRun go vet before committing.\n```"}},
+		})
+	}))
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+
+	improved, err := ImproveLesson("Vet", "check stuff", "claude-haiku-3", 5*time.Second)
+	if err != nil {
+		t.Fatalf("ImproveLesson failed: %v", err)
+	}
+	if improved != "Run go vet before committing." {
+		t.Errorf("expected code fence stripped, got %q", improved)
+	}
+	if got.Model != "claude-haiku-3" {
+		t.Errorf("expected requested model, got %q", got.Model)
+	}
+	if len(got.Messages) != 1 || !strings.Contains(got.Messages[0].Content, "Lesson content: check stuff") {
+		t.Errorf("unexpected prompt: %+v", got.Messages)
+	}
+
+	if _, err := ImproveLesson("Vet", "check stuff", "", 5*time.Second); err != nil {
+		t.Fatalf("ImproveLesson failed: %v", err)
+	}
+	if got.Model != HaikuModel {
+		t.Errorf("expected default model %s, got %q", HaikuModel, got.Model)
+	}
+}
@@ -0,0 +1,91 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSuggestedLessons caps how many lessons ExtractLessons returns
+const maxSuggestedLessons = 5
+
+// SuggestedLesson is a lesson candidate found in a session transcript
+type SuggestedLesson struct {
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
+
+// ExtractLessons asks the model to identify reusable lessons in a session's
+// assistant texts (e.g. "I learned that..." or "Note for next time:").
+// Suggestions missing a title or content are dropped; a missing category
+// defaults to pattern.
+func ExtractLessons(texts []string, timeout time.Duration) ([]SuggestedLesson, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no texts provided")
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.CompleteWithTimeout(buildExtractLessonsPrompt(texts), timeout)
+	if err != nil {
+		return nil, err
+	}
+	return parseSuggestedLessons(response)
+}
+
+// buildExtractLessonsPrompt creates the prompt for lesson extraction
+func buildExtractLessonsPrompt(texts []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("Review this coding session and identify lessons worth remembering for future sessions:\n")
+	sb.WriteString("gotchas hit, patterns that worked, corrections, and notes like \"next time...\".\n\n")
+	sb.WriteString("Return ONLY a JSON array (no markdown code blocks) of objects with these fields:\n")
+	sb.WriteString("[{\"category\": \"pattern|correction|decision|gotcha|preference\", \"title\": \"short title\", \"content\": \"one or two actionable sentences\"}]\n\n")
+	sb.WriteString(fmt.Sprintf("Return at most %d lessons, and [] if there is nothing reusable.\n\n", maxSuggestedLessons))
+	sb.WriteString("Session:\n")
+
+	// Include last 20 messages max
+	start := len(texts) - 20
+	if start < 0 {
+		start = 0
+	}
+	for _, text := range texts[start:] {
+		if len(text) > 2000 {
+			text = text[:2000] + "..."
+		}
+		sb.WriteString(fmt.Sprintf("---\n%s\n", text))
+	}
+
+	return sb.String()
+}
+
+// parseSuggestedLessons parses the model's JSON array of lessons
+func parseSuggestedLessons(response string) ([]SuggestedLesson, error) {
+	var raw []SuggestedLesson
+	if err := json.Unmarshal([]byte(stripCodeFence(response)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse lessons JSON: %w", err)
+	}
+
+	suggested := []SuggestedLesson{}
+	for _, l := range raw {
+		l.Category = strings.TrimSpace(l.Category)
+		l.Title = strings.TrimSpace(l.Title)
+		l.Content = strings.TrimSpace(l.Content)
+		if l.Title == "" || l.Content == "" {
+			continue
+		}
+		if l.Category == "" {
+			l.Category = "pattern"
+		}
+		suggested = append(suggested, l)
+		if len(suggested) == maxSuggestedLessons {
+			break
+		}
+	}
+	return suggested, nil
+}
@@ -0,0 +1,70 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractLessons_ParsesSuggestions(t *testing.T) {
+	var got MessagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(MessagesResponse{
+			Content: []ContentBlock{{Type: "text", Text: "```json\n" + `[
+  {"category": "gotcha", "title": "Close response bodies", "content": "Always defer resp.Body.Close() after a successful request."},
+  {"title": "Run vet", "content": "Run go vet before committing."},
+  {"category": "pattern", "title": "", "content": "No title, dropped"}
+]` + "\n```"}},
+		})
+	}))
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+
+	texts := []string{
+		"I learned that the HTTP client leaks connections unless the body is closed.",
+		"Note for next time: run go vet first.",
+	}
+	suggested, err := ExtractLessons(texts, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ExtractLessons failed: %v", err)
+	}
+
+	want := []SuggestedLesson{
+		{Category: "gotcha", Title: "Close response bodies", Content: "Always defer resp.Body.Close() after a successful request."},
+		{Category: "pattern", Title: "Run vet", Content: "Run go vet before committing."},
+	}
+	if len(suggested) != len(want) {
+		t.Fatalf("expected %d suggestions, got %+v", len(want), suggested)
+	}
+	for i := range want {
+		if suggested[i] != want[i] {
+			t.Errorf("suggestion %d = %+v, want %+v", i, suggested[i], want[i])
+		}
+	}
+	if len(got.Messages) != 1 || !strings.Contains(got.Messages[0].Content, "Note for next time: run go vet first.") {
+		t.Errorf("expected transcript in prompt, got %+v", got.Messages)
+	}
+}
+
+func TestExtractLessons_Errors(t *testing.T) {
+	if _, err := ExtractLessons(nil, time.Second); err == nil {
+		t.Error("expected error for empty texts")
+	}
+	if _, err := parseSuggestedLessons("not json"); err == nil {
+		t.Error("expected error for malformed response")
+	}
+	suggested, err := parseSuggestedLessons("[]")
+	if err != nil || len(suggested) != 0 {
+		t.Errorf("expected no suggestions, got %+v (%v)", suggested, err)
+	}
+}
@@ -0,0 +1,100 @@
+package anthropic
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/pbrown/claude-recall/internal/debuglog"
+)
+
+// DefaultRPS is the API request rate used when CLAUDE_RECALL_API_RPS is unset
+const DefaultRPS = 2.0
+
+// RateLimiter throttles API requests with a token bucket shared by every
+// caller in the process. The clock and sleep are injectable for tests.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+	now     func() time.Time
+	sleep   func(time.Duration)
+	logger  *debuglog.Logger
+}
+
+var (
+	sharedLimiter     *RateLimiter
+	sharedLimiterOnce sync.Once
+)
+
+// NewRateLimiter creates a limiter allowing rps requests per second with a
+// burst of one. rps <= 0 disables limiting.
+func NewRateLimiter(rps float64) *RateLimiter {
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rpsLimit(rps), 1),
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// sharedRateLimiter returns the package limiter, creating it on first use
+// from CLAUDE_RECALL_API_RPS
+func sharedRateLimiter() *RateLimiter {
+	sharedLimiterOnce.Do(func() {
+		sharedLimiter = NewRateLimiter(rpsFromEnv())
+	})
+	return sharedLimiter
+}
+
+// SetRateLimit replaces the shared limiter's rate (rps <= 0 disables limiting)
+func SetRateLimit(rps float64) {
+	rl := sharedRateLimiter()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limiter.SetLimitAt(rl.now(), rpsLimit(rps))
+}
+
+// SetLogger sets the debug logger used to report throttled requests
+func SetLogger(l *debuglog.Logger) {
+	rl := sharedRateLimiter()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.logger = l
+}
+
+// Wait blocks until the next request may proceed and returns how long it was delayed
+func (r *RateLimiter) Wait() time.Duration {
+	r.mu.Lock()
+	now := r.now()
+	delay := r.limiter.ReserveN(now, 1).DelayFrom(now)
+	logger := r.logger
+	r.mu.Unlock()
+
+	if delay > 0 {
+		if logger != nil {
+			logger.LogRateLimited(delay)
+		}
+		r.sleep(delay)
+	}
+	return delay
+}
+
+// rpsFromEnv reads CLAUDE_RECALL_API_RPS, falling back to DefaultRPS
+func rpsFromEnv() float64 {
+	if val := os.Getenv("CLAUDE_RECALL_API_RPS"); val != "" {
+		if rps, err := strconv.ParseFloat(val, 64); err == nil {
+			return rps
+		}
+	}
+	return DefaultRPS
+}
+
+// rpsLimit converts requests per second to a rate.Limit (<= 0 means unlimited)
+func rpsLimit(rps float64) rate.Limit {
+	if rps <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(rps)
+}
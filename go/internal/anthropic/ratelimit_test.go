@@ -0,0 +1,109 @@
+package anthropic
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/debuglog"
+)
+
+// fakeClock is a manually advanced clock for driving RateLimiter
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newFakeLimiter(rps float64, clock *fakeClock, advanceOnSleep bool) *RateLimiter {
+	rl := NewRateLimiter(rps)
+	rl.now = clock.now
+	rl.sleep = func(d time.Duration) {
+		if advanceOnSleep {
+			clock.t = clock.t.Add(d)
+		}
+	}
+	return rl
+}
+
+func TestRateLimiter_RespectsRate(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	start := clock.t
+	rl := newFakeLimiter(2, clock, true)
+
+	for i := 0; i < 10; i++ {
+		rl.Wait()
+	}
+
+	// First request is free, the remaining 9 are spaced 500ms apart
+	if elapsed := clock.t.Sub(start); elapsed != 4500*time.Millisecond {
+		t.Errorf("expected 10 requests at 2 rps to take 4.5s, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Backpressure(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl := newFakeLimiter(2, clock, false)
+
+	// Callers arriving at the same instant queue up behind each other
+	for i := 0; i < 10; i++ {
+		want := time.Duration(i) * 500 * time.Millisecond
+		if got := rl.Wait(); got != want {
+			t.Errorf("request %d: expected delay %v, got %v", i, want, got)
+		}
+	}
+
+	// Once time catches up, requests flow without delay again
+	clock.t = clock.t.Add(10 * time.Second)
+	if got := rl.Wait(); got != 0 {
+		t.Errorf("expected no delay after idle period, got %v", got)
+	}
+}
+
+func TestRateLimiter_DisabledWhenZero(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl := newFakeLimiter(0, clock, false)
+
+	for i := 0; i < 10; i++ {
+		if got := rl.Wait(); got != 0 {
+			t.Fatalf("expected no delay when disabled, got %v", got)
+		}
+	}
+}
+
+func TestSetLogger_SafeWithConcurrentWaits(t *testing.T) {
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+	defer SetLogger(nil)
+
+	// Run with -race: SetLogger must not race with the shared limiter's readers
+	logger := debuglog.New(t.TempDir(), 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); SetLogger(logger) }()
+		go func() { defer wg.Done(); sharedRateLimiter().Wait() }()
+	}
+	wg.Wait()
+
+	if rl := sharedRateLimiter(); rl.logger != logger {
+		t.Error("expected the shared limiter to use the new logger")
+	}
+}
+
+func TestRpsFromEnv(t *testing.T) {
+	t.Setenv("CLAUDE_RECALL_API_RPS", "")
+	if got := rpsFromEnv(); got != DefaultRPS {
+		t.Errorf("expected default %v, got %v", DefaultRPS, got)
+	}
+
+	t.Setenv("CLAUDE_RECALL_API_RPS", "5.5")
+	if got := rpsFromEnv(); got != 5.5 {
+		t.Errorf("expected 5.5, got %v", got)
+	}
+
+	t.Setenv("CLAUDE_RECALL_API_RPS", "fast")
+	if got := rpsFromEnv(); got != DefaultRPS {
+		t.Errorf("expected default for invalid value, got %v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,20 +12,27 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
 const (
-	// RelevanceCacheTTLDays is the cache expiration time
-	RelevanceCacheTTLDays = 7
+	// DefaultCacheTTL is how long cached scores stay valid when no TTL is configured
+	DefaultCacheTTL = time.Hour
 
 	// RelevanceCacheSimilarityThreshold for fuzzy matching
 	RelevanceCacheSimilarityThreshold = 0.7
 
 	// MaxQueryLength to prevent huge prompts
 	MaxQueryLength = 5000
+
+	// DefaultBatchSize is the number of lessons scored per API request
+	DefaultBatchSize = 50
+
+	// DefaultMaxParallel caps concurrent scoring requests
+	DefaultMaxParallel = 4
 )
 
 // ScoredLesson represents a lesson with a relevance score
@@ -48,13 +56,28 @@ type cacheEntry struct {
 	Timestamp       float64        `json:"timestamp"`
 }
 
-// relevanceCache stores all cache entries
+// relevanceCache stores all cache entries and lookup counters
 type relevanceCache struct {
 	Entries map[string]cacheEntry `json:"entries"`
+	Hits    int                   `json:"hits"`
+	Misses  int                   `json:"misses"`
 }
 
-// ScoreRelevance scores lessons by relevance to a query
-func ScoreRelevance(lessons []*models.Lesson, query string, stateDir string, timeout time.Duration) (*RelevanceResult, error) {
+// relevanceCacheFile is the cache file name within the state directory
+const relevanceCacheFile = "relevance-cache.json"
+
+// now is the clock used for cache expiry (replaced in tests)
+var now = time.Now
+
+// ErrNoCachedResults is returned by CachedRelevance when no valid cache
+// entry matches the query
+var ErrNoCachedResults = errors.New("no cached results for query")
+
+// ScoreRelevance scores lessons by relevance to a query. Lesson sets larger
+// than batchSize are split into batches scored by up to maxParallel
+// concurrent requests (batchSize <= 0 sends a single request). Cached scores
+// are reused for cacheTTL (<= 0 uses DefaultCacheTTL).
+func ScoreRelevance(lessons []*models.Lesson, query string, stateDir string, timeout time.Duration, batchSize, maxParallel int, cacheTTL time.Duration) (*RelevanceResult, error) {
 	if len(lessons) == 0 {
 		return &RelevanceResult{
 			ScoredLessons: []ScoredLesson{},
@@ -67,29 +90,20 @@ func ScoreRelevance(lessons []*models.Lesson, query string, stateDir string, tim
 		query = query[:MaxQueryLength]
 	}
 
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+
 	// Load cache
-	cachePath := filepath.Join(stateDir, "relevance-cache.json")
+	cachePath := filepath.Join(stateDir, relevanceCacheFile)
 	cache := loadCache(cachePath)
 
-	// Check cache
-	queryHash := hashQuery(query)
-	normalizedQuery := normalizeQuery(query)
-
-	// Check exact match
-	if entry, ok := cache.Entries[queryHash]; ok {
-		if isEntryValid(entry) {
-			return buildResultFromCache(lessons, entry.Scores, query, true), nil
-		}
-	}
-
-	// Check similarity match
-	for _, entry := range cache.Entries {
-		if isEntryValid(entry) {
-			if jaccardSimilarity(normalizedQuery, entry.NormalizedQuery) >= RelevanceCacheSimilarityThreshold {
-				return buildResultFromCache(lessons, entry.Scores, query, true), nil
-			}
-		}
+	if entry, ok := cache.lookup(query, cacheTTL); ok {
+		cache.Hits++
+		saveCache(cachePath, cache, cacheTTL)
+		return buildResultFromCache(lessons, entry.Scores, query, true), nil
 	}
+	cache.Misses++
 
 	// Cache miss - call API
 	client, err := NewClient()
@@ -101,8 +115,7 @@ func ScoreRelevance(lessons []*models.Lesson, query string, stateDir string, tim
 		}, nil
 	}
 
-	prompt := buildRelevancePrompt(lessons, query)
-	response, err := client.CompleteWithTimeout(prompt, timeout)
+	scores, err := scoreBatches(client, splitBatches(lessons, batchSize), query, timeout, maxParallel)
 	if err != nil {
 		return &RelevanceResult{
 			ScoredLessons: []ScoredLesson{},
@@ -111,20 +124,117 @@ func ScoreRelevance(lessons []*models.Lesson, query string, stateDir string, tim
 		}, nil
 	}
 
-	// Parse scores from response
-	scores := parseScores(response)
-
 	// Update cache
-	cache.Entries[queryHash] = cacheEntry{
-		NormalizedQuery: normalizedQuery,
+	cache.Entries[hashQuery(query)] = cacheEntry{
+		NormalizedQuery: normalizeQuery(query),
 		Scores:          scores,
-		Timestamp:       float64(time.Now().Unix()),
+		Timestamp:       unixSeconds(now()),
 	}
-	saveCache(cachePath, cache)
+	saveCache(cachePath, cache, cacheTTL)
 
 	return buildResultFromCache(lessons, scores, query, false), nil
 }
 
+// CachedRelevance is ScoreRelevance restricted to the cache: it never calls
+// the API, returning ErrNoCachedResults when no valid entry matches query
+func CachedRelevance(lessons []*models.Lesson, query string, stateDir string, cacheTTL time.Duration) (*RelevanceResult, error) {
+	if len(query) > MaxQueryLength {
+		query = query[:MaxQueryLength]
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+
+	cachePath := filepath.Join(stateDir, relevanceCacheFile)
+	cache := loadCache(cachePath)
+	entry, ok := cache.lookup(query, cacheTTL)
+	if !ok {
+		cache.Misses++
+		saveCache(cachePath, cache, cacheTTL)
+		return nil, ErrNoCachedResults
+	}
+	cache.Hits++
+	saveCache(cachePath, cache, cacheTTL)
+	return buildResultFromCache(lessons, entry.Scores, query, true), nil
+}
+
+// lookup finds a valid entry for query: an exact match, or else one whose
+// normalized query is similar enough
+func (c *relevanceCache) lookup(query string, ttl time.Duration) (cacheEntry, bool) {
+	if entry, ok := c.Entries[hashQuery(query)]; ok && isEntryValid(entry, ttl) {
+		return entry, true
+	}
+
+	normalizedQuery := normalizeQuery(query)
+	for _, entry := range c.Entries {
+		if isEntryValid(entry, ttl) && jaccardSimilarity(normalizedQuery, entry.NormalizedQuery) >= RelevanceCacheSimilarityThreshold {
+			return entry, true
+		}
+	}
+	return cacheEntry{}, false
+}
+
+// splitBatches splits lessons into consecutive batches of at most size
+func splitBatches(lessons []*models.Lesson, size int) [][]*models.Lesson {
+	if size <= 0 || len(lessons) <= size {
+		return [][]*models.Lesson{lessons}
+	}
+
+	var batches [][]*models.Lesson
+	for start := 0; start < len(lessons); start += size {
+		end := start + size
+		if end > len(lessons) {
+			end = len(lessons)
+		}
+		batches = append(batches, lessons[start:end])
+	}
+	return batches
+}
+
+// scoreBatches scores each batch with its own request, running at most
+// maxParallel at once, and merges the scores. Any failed batch fails the whole call.
+func scoreBatches(client *Client, batches [][]*models.Lesson, query string, timeout time.Duration, maxParallel int) (map[string]int, error) {
+	if maxParallel <= 0 {
+		maxParallel = 1
+	}
+
+	results := make([]map[string]int, len(batches))
+	errCh := make(chan error, len(batches))
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []*models.Lesson) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			response, err := client.CompleteWithTimeout(buildRelevancePrompt(batch, query), timeout)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			results[i] = parseScores(response)
+		}(i, batch)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int)
+	for _, batchScores := range results {
+		for id, score := range batchScores {
+			scores[id] = score
+		}
+	}
+	return scores, nil
+}
+
 // buildRelevancePrompt creates the prompt for Haiku
 func buildRelevancePrompt(lessons []*models.Lesson, query string) string {
 	var sb strings.Builder
@@ -149,7 +259,7 @@ func buildRelevancePrompt(lessons []*models.Lesson, query string) string {
 // parseScores extracts scores from the API response
 func parseScores(response string) map[string]int {
 	scores := make(map[string]int)
-	pattern := regexp.MustCompile(`^\[?([LS]\d{3})\]?:\s*(\d+)`)
+	pattern := regexp.MustCompile(`^\[?([LSW]\d{3})\]?:\s*(\d+)`)
 
 	for _, line := range strings.Split(response, "\n") {
 		match := pattern.FindStringSubmatch(strings.TrimSpace(line))
@@ -222,14 +332,13 @@ func loadCache(path string) *relevanceCache {
 	return cache
 }
 
-func saveCache(path string, cache *relevanceCache) {
+func saveCache(path string, cache *relevanceCache, ttl time.Duration) {
 	// Ensure directory exists
 	os.MkdirAll(filepath.Dir(path), 0755)
 
 	// Evict expired entries
-	cutoff := float64(time.Now().AddDate(0, 0, -RelevanceCacheTTLDays).Unix())
 	for k, v := range cache.Entries {
-		if v.Timestamp < cutoff {
+		if !isEntryValid(v, ttl) {
 			delete(cache.Entries, k)
 		}
 	}
@@ -242,9 +351,98 @@ func saveCache(path string, cache *relevanceCache) {
 	os.WriteFile(path, data, 0644)
 }
 
-func isEntryValid(entry cacheEntry) bool {
-	cutoff := float64(time.Now().AddDate(0, 0, -RelevanceCacheTTLDays).Unix())
-	return entry.Timestamp >= cutoff
+func isEntryValid(entry cacheEntry, ttl time.Duration) bool {
+	return entry.Timestamp >= unixSeconds(now().Add(-ttl))
+}
+
+// unixSeconds converts t to fractional Unix seconds, the cache timestamp format
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// CacheStats summarizes the relevance cache
+type CacheStats struct {
+	Entries   int
+	Hits      int
+	Misses    int
+	OldestAge time.Duration // Age of the oldest entry (0 if empty)
+}
+
+// GetCacheStats reads the relevance cache in stateDir
+func GetCacheStats(stateDir string) CacheStats {
+	cache := loadCache(filepath.Join(stateDir, relevanceCacheFile))
+	stats := CacheStats{Entries: len(cache.Entries), Hits: cache.Hits, Misses: cache.Misses}
+
+	current := unixSeconds(now())
+	for _, e := range cache.Entries {
+		age := time.Duration((current - e.Timestamp) * float64(time.Second))
+		if age > stats.OldestAge {
+			stats.OldestAge = age
+		}
+	}
+	return stats
+}
+
+// ClearCache deletes the relevance cache in stateDir, returning how many
+// entries it held
+func ClearCache(stateDir string) (int, error) {
+	path := filepath.Join(stateDir, relevanceCacheFile)
+	entries := len(loadCache(path).Entries)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return entries, nil
+}
+
+// CacheEntryInfo describes one cached query result
+type CacheEntryInfo struct {
+	Query     string    // Normalized query (lowercase words, sorted)
+	Timestamp time.Time // When the scores were cached
+	Scores    int       // Number of lesson scores cached
+}
+
+// ListCache returns the relevance cache entries in stateDir, newest first
+func ListCache(stateDir string) []CacheEntryInfo {
+	cache := loadCache(filepath.Join(stateDir, relevanceCacheFile))
+	infos := make([]CacheEntryInfo, 0, len(cache.Entries))
+	for _, e := range cache.Entries {
+		sec := int64(e.Timestamp)
+		nsec := int64((e.Timestamp - float64(sec)) * 1e9)
+		infos = append(infos, CacheEntryInfo{Query: e.NormalizedQuery, Timestamp: time.Unix(sec, nsec), Scores: len(e.Scores)})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Timestamp.After(infos[j].Timestamp)
+	})
+	return infos
+}
+
+// DeleteCacheEntry removes the cached result for query from the relevance
+// cache in stateDir, reporting whether there was one. Queries match after
+// normalization, so word order, case, and punctuation don't matter.
+func DeleteCacheEntry(stateDir, query string) (bool, error) {
+	path := filepath.Join(stateDir, relevanceCacheFile)
+	cache := loadCache(path)
+
+	normalized := normalizeQuery(query)
+	found := false
+	for k, e := range cache.Entries {
+		if k == hashQuery(query) || e.NormalizedQuery == normalized {
+			delete(cache.Entries, k)
+			found = true
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // Query normalization helpers
@@ -0,0 +1,235 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// concurrencyServer is a fake Messages API that scores every lesson ID in
+// the prompt and records how many requests were in flight at once
+type concurrencyServer struct {
+	mu       sync.Mutex
+	requests int
+	inFlight int
+	peak     int
+	release  chan struct{} // closed once `expect` requests have arrived
+	expect   int
+}
+
+func (cs *concurrencyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req MessagesRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	cs.mu.Lock()
+	cs.requests++
+	cs.inFlight++
+	if cs.inFlight > cs.peak {
+		cs.peak = cs.inFlight
+	}
+	if cs.requests == cs.expect {
+		close(cs.release)
+	}
+	cs.mu.Unlock()
+
+	// Hold each request until all expected requests are in flight (or give up)
+	select {
+	case <-cs.release:
+	case <-time.After(2 * time.Second):
+	}
+
+	var sb strings.Builder
+	for _, m := range regexp.MustCompile(`\[(L\d{3})\]`).FindAllStringSubmatch(req.Messages[0].Content, -1) {
+		sb.WriteString(fmt.Sprintf("%s: 7\n", m[1]))
+	}
+
+	cs.mu.Lock()
+	cs.inFlight--
+	cs.mu.Unlock()
+
+	json.NewEncoder(w).Encode(MessagesResponse{
+		Content: []ContentBlock{{Type: "text", Text: sb.String()}},
+	})
+}
+
+func TestScoreRelevance_ParallelBatches(t *testing.T) {
+	cs := &concurrencyServer{release: make(chan struct{}), expect: 3}
+	server := httptest.NewServer(cs)
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	// Disable throttling so all batches can be in flight together
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+
+	var lessons []*models.Lesson
+	for i := 1; i <= 60; i++ {
+		lessons = append(lessons, models.NewLesson(fmt.Sprintf("L%03d", i), "Title", "Content"))
+	}
+
+	result, err := ScoreRelevance(lessons, "parallel scoring query", t.TempDir(), 5*time.Second, 20, 4, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("unexpected result error: %s", result.Error)
+	}
+
+	if cs.requests != 3 {
+		t.Errorf("expected 3 requests, got %d", cs.requests)
+	}
+	if cs.peak != 3 {
+		t.Errorf("expected 3 concurrent requests, peak was %d", cs.peak)
+	}
+
+	// Scores from every batch are merged
+	if len(result.ScoredLessons) != 60 {
+		t.Fatalf("expected 60 scored lessons, got %d", len(result.ScoredLessons))
+	}
+	for _, sl := range result.ScoredLessons {
+		if sl.Score != 7 {
+			t.Errorf("expected %s to score 7, got %d", sl.Lesson.ID, sl.Score)
+		}
+	}
+}
+
+func TestScoreRelevance_CacheTTLExpiry(t *testing.T) {
+	cs := &concurrencyServer{release: make(chan struct{}), expect: 1}
+	server := httptest.NewServer(cs)
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	origNow := now
+	now = func() time.Time { return clock }
+	defer func() { now = origNow }()
+
+	lessons := []*models.Lesson{models.NewLesson("L001", "Title", "Content")}
+	stateDir := t.TempDir()
+	ttl := 100 * time.Millisecond
+
+	score := func() *RelevanceResult {
+		t.Helper()
+		result, err := ScoreRelevance(lessons, "cache ttl query", stateDir, 5*time.Second, 20, 1, ttl)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if score().CacheHit {
+		t.Error("first call should not come from cache")
+	}
+	if !score().CacheHit {
+		t.Error("second call within TTL should come from cache")
+	}
+	if cs.requests != 1 {
+		t.Fatalf("expected 1 request before expiry, got %d", cs.requests)
+	}
+
+	clock = clock.Add(150 * time.Millisecond)
+	if score().CacheHit {
+		t.Error("call after TTL should not come from cache")
+	}
+	if cs.requests != 2 {
+		t.Errorf("expected a fresh request after expiry, got %d requests", cs.requests)
+	}
+
+	stats := GetCacheStats(stateDir)
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	n, err := ClearCache(stateDir)
+	if err != nil || n != 1 {
+		t.Errorf("ClearCache = %d, %v; want 1, nil", n, err)
+	}
+	if stats := GetCacheStats(stateDir); stats.Entries != 0 || stats.Hits != 0 {
+		t.Errorf("expected empty cache after clear, got %+v", stats)
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	var lessons []*models.Lesson
+	for i := 0; i < 45; i++ {
+		lessons = append(lessons, &models.Lesson{})
+	}
+
+	batches := splitBatches(lessons, 20)
+	if len(batches) != 3 || len(batches[2]) != 5 {
+		t.Errorf("expected batches of 20/20/5, got %d batches", len(batches))
+	}
+	if got := splitBatches(lessons, 0); len(got) != 1 || len(got[0]) != 45 {
+		t.Errorf("expected single batch when batch size is 0")
+	}
+}
+
+func TestCachedRelevance_NeverCallsAPI(t *testing.T) {
+	cs := &concurrencyServer{release: make(chan struct{}), expect: 1}
+	server := httptest.NewServer(cs)
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	lessons := []*models.Lesson{
+		models.NewLesson("L001", "First", "Content"),
+		models.NewLesson("L002", "Second", "Content"),
+	}
+	stateDir := t.TempDir()
+
+	if _, err := CachedRelevance(lessons, "cached query", stateDir, 0); err != ErrNoCachedResults {
+		t.Fatalf("expected ErrNoCachedResults on empty cache, got %v", err)
+	}
+
+	cache := fmt.Sprintf(`{"entries": {"x": {"normalized_query": "cached query", "scores": {"L001": 3, "L002": 9}, "timestamp": %d}}}`,
+		time.Now().Unix())
+	os.WriteFile(filepath.Join(stateDir, relevanceCacheFile), []byte(cache), 0644)
+
+	result, err := CachedRelevance(lessons, "Query, cached", stateDir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.CacheHit || len(result.ScoredLessons) != 2 || result.ScoredLessons[0].Lesson.ID != "L002" {
+		t.Errorf("unexpected cached result: %+v", result)
+	}
+	if cs.requests != 0 {
+		t.Errorf("expected no API requests, got %d", cs.requests)
+	}
+
+	if entries := ListCache(stateDir); len(entries) != 1 || entries[0].Query != "cached query" || entries[0].Scores != 2 {
+		t.Errorf("unexpected ListCache: %+v", entries)
+	}
+	if found, err := DeleteCacheEntry(stateDir, "query cached"); !found || err != nil {
+		t.Fatalf("DeleteCacheEntry = %v, %v; want true, nil", found, err)
+	}
+	if found, _ := DeleteCacheEntry(stateDir, "query cached"); found {
+		t.Error("expected second delete to find nothing")
+	}
+	if _, err := CachedRelevance(lessons, "cached query", stateDir, 0); err != ErrNoCachedResults {
+		t.Errorf("expected ErrNoCachedResults after delete, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package anthropic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// ScoreUsefulness asks the model how likely each lesson is to still be
+// useful (0-10), independent of any query. Used to audit stale lessons.
+// Lessons the model does not score are absent from the result.
+func ScoreUsefulness(lessons []*models.Lesson, timeout time.Duration) (map[string]int, error) {
+	if len(lessons) == 0 {
+		return map[string]int{}, nil
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int)
+	for _, batch := range splitBatches(lessons, DefaultBatchSize) {
+		response, err := client.CompleteWithTimeout(buildUsefulnessPrompt(batch), timeout)
+		if err != nil {
+			return nil, err
+		}
+		for id, score := range parseScores(response) {
+			scores[id] = score
+		}
+	}
+	return scores, nil
+}
+
+// buildUsefulnessPrompt creates the prompt for usefulness scoring
+func buildUsefulnessPrompt(lessons []*models.Lesson) string {
+	var sb strings.Builder
+
+	sb.WriteString("These are lessons a coding assistant recorded from past sessions. Score how likely each is\n")
+	sb.WriteString("to still be useful (0-10). 10 = broadly applicable and current, 0 = obsolete, trivial, or too vague to act on.\n\n")
+	sb.WriteString("Lessons:\n")
+
+	for _, l := range lessons {
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s (learned %s, last used %s)\n", l.ID, l.Title, l.Content,
+			l.Learned.Format("2006-01-02"), l.LastUsed.Format("2006-01-02")))
+	}
+
+	sb.WriteString("\nOutput ONLY lines in format: ID: SCORE\n")
+	sb.WriteString("Example:\n")
+	sb.WriteString("L001: 8\n")
+	sb.WriteString("S002: 3\n\n")
+	sb.WriteString("No explanations, just ID: SCORE lines.")
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func TestScoreUsefulness_ParsesScores(t *testing.T) {
+	var got MessagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(MessagesResponse{
+			Content: []ContentBlock{{Type: "text", Text: "L001: 9\nL002: 2"}},
+		})
+	}))
+	defer server.Close()
+
+	origURL := defaultBaseURL
+	defaultBaseURL = server.URL
+	defer func() { defaultBaseURL = origURL }()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	SetRateLimit(0)
+	defer SetRateLimit(DefaultRPS)
+
+	lessons := []*models.Lesson{
+		models.NewLesson("L001", "Wrap errors", "Use %w when wrapping errors"),
+		models.NewLesson("L002", "Old flag", "Pass --legacy to the v1 tool"),
+	}
+	scores, err := ScoreUsefulness(lessons, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ScoreUsefulness failed: %v", err)
+	}
+	if scores["L001"] != 9 || scores["L002"] != 2 {
+		t.Errorf("unexpected scores: %v", scores)
+	}
+	if len(got.Messages) != 1 || !strings.Contains(got.Messages[0].Content, "[L002] Old flag: Pass --legacy to the v1 tool") {
+		t.Errorf("unexpected prompt: %+v", got.Messages)
+	}
+}
@@ -0,0 +1,99 @@
+// Package checksum maintains the "**Checksum**:" footer that LESSONS.md and
+// HANDOFFS.md files end with, so accidental corruption can be detected.
+package checksum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// footerPrefix starts the checksum footer line
+const footerPrefix = "**Checksum**: "
+
+// ErrChecksumMismatch is returned when a file's checksum footer doesn't
+// match the content around it
+type ErrChecksumMismatch struct {
+	Expected string // From the footer
+	Actual   string // Computed from the content
+}
+
+func (e *ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// Sum returns the hex SHA-256 of content
+func Sum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Append returns content followed by a footer line holding its checksum
+func Append(content string) string {
+	return content + footerPrefix + Sum([]byte(content)) + "\n"
+}
+
+// Split separates data into the content the checksum covers (everything
+// but the footer line) and the checksum recorded in the footer. The last
+// footer line wins. ok is false when data has no footer.
+func Split(data []byte) (content []byte, sum string, line int, ok bool) {
+	start := -1
+	if bytes.HasPrefix(data, []byte(footerPrefix)) {
+		start = 0
+	}
+	if i := bytes.LastIndex(data, []byte("\n"+footerPrefix)); i >= 0 {
+		start = i + 1
+	}
+	if start < 0 {
+		return data, "", 0, false
+	}
+
+	end := len(data)
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		end = start + i + 1
+	}
+	sum = string(bytes.TrimSpace(data[start+len(footerPrefix) : end]))
+	content = append(append([]byte{}, data[:start]...), data[end:]...)
+	line = bytes.Count(data[:start], []byte("\n")) + 1
+	return content, sum, line, true
+}
+
+// Verify checks data's footer against its content. Data without a footer
+// (written before checksums were added) passes.
+func Verify(data []byte) error {
+	content, expected, _, ok := Split(data)
+	if !ok {
+		return nil
+	}
+	if actual := Sum(content); actual != expected {
+		return &ErrChecksumMismatch{Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// LintFile reports a missing or mismatched checksum footer in the file at
+// path. A missing file has no issues.
+func LintFile(path string) ([]models.LintIssue, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, line, ok := Split(data)
+	if !ok {
+		return []models.LintIssue{{File: path, Line: bytes.Count(data, []byte("\n")) + 1, Message: "no checksum footer"}}, nil
+	}
+	var mismatch *ErrChecksumMismatch
+	if err := Verify(data); errors.As(err, &mismatch) {
+		return []models.LintIssue{{File: path, Line: line, Message: mismatch.Error()}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,72 @@
+package checksum
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppend_Verify(t *testing.T) {
+	data := []byte(Append("# LESSONS.md\n\n### [L001] Title\n"))
+	if err := Verify(data); err != nil {
+		t.Fatalf("expected intact data to verify, got %v", err)
+	}
+
+	corrupted := []byte(strings.Replace(string(data), "Title", "Titlf", 1))
+	var mismatch *ErrChecksumMismatch
+	if err := Verify(corrupted); !errors.As(err, &mismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if mismatch.Expected == mismatch.Actual || mismatch.Actual != Sum([]byte("# LESSONS.md\n\n### [L001] Titlf\n")) {
+		t.Errorf("unexpected mismatch: %+v", mismatch)
+	}
+}
+
+func TestVerify_NoFooter(t *testing.T) {
+	if err := Verify([]byte("# LESSONS.md\n")); err != nil {
+		t.Errorf("expected data without a footer to pass, got %v", err)
+	}
+}
+
+func TestVerify_ContentAfterFooter(t *testing.T) {
+	data := Append("# HANDOFFS.md\n") + "### [hf-0000001] Appended by hand\n"
+	if err := Verify([]byte(data)); err == nil {
+		t.Error("expected content after the footer to be covered by the checksum")
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.md")
+	bad := filepath.Join(dir, "bad.md")
+	bare := filepath.Join(dir, "bare.md")
+	os.WriteFile(good, []byte(Append("a\nb\n")), 0644)
+	os.WriteFile(bad, []byte(strings.Replace(Append("a\nb\n"), "b", "c", 1)), 0644)
+	os.WriteFile(bare, []byte("a\nb\n"), 0644)
+
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{good, ""},
+		{filepath.Join(dir, "missing.md"), ""},
+		{bad, bad + ":3: checksum mismatch"},
+		{bare, bare + ":3: no checksum footer"},
+	} {
+		issues, err := LintFile(tt.path)
+		if err != nil {
+			t.Fatalf("LintFile(%s): %v", tt.path, err)
+		}
+		if tt.want == "" {
+			if len(issues) != 0 {
+				t.Errorf("%s: expected no issues, got %v", tt.path, issues)
+			}
+			continue
+		}
+		if len(issues) != 1 || !strings.HasPrefix(issues[0].String(), tt.want) {
+			t.Errorf("%s: expected issue %q, got %v", tt.path, tt.want, issues)
+		}
+	}
+}
@@ -8,13 +8,13 @@ import (
 
 // Citation represents a lesson or handoff citation.
 type Citation struct {
-	Type string // "L", "S", or "H"
+	Type string // "L", "S", "W", or "H"
 	ID   string // Full ID like "L001", "S002", "H001"
 }
 
-// citationPattern matches valid citations: [L001], [S002], [H001]
+// citationPattern matches valid citations: [L001], [S002], [W003], [H001]
 // Uses word boundary to ensure we match the full pattern
-var citationPattern = regexp.MustCompile(`\[([LSH])(\d{3})\]`)
+var citationPattern = regexp.MustCompile(`\[([LSWH])(\d{3})\]`)
 
 // Extract extracts all valid citations from text.
 // It filters out star ratings, numeric patterns, and template text.
@@ -4,11 +4,15 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/pbrown/claude-recall/internal/lessons"
+	"github.com/pbrown/claude-recall/internal/similarity"
 )
 
 // Config holds the configuration for claude-recall.
@@ -17,15 +21,52 @@ type Config struct {
 	StateDir   string `json:"state_dir"`   // State directory, default: ~/.local/state/claude-recall
 	ProjectDir string `json:"project_dir"` // Project root, default: git root or cwd
 	DebugLevel int    `json:"debug_level"` // Debug level 0-3, from CLAUDE_RECALL_DEBUG
+
+	ScoreCacheTTL int      `json:"score_cache_ttl"` // Relevance score cache TTL in seconds, default: 3600
+	SharedPaths   []string `json:"shared_paths"`    // Extra read-only LESSONS.md files merged into lists
+	SyncRemote    string   `json:"sync_remote"`     // Directory lessons are pushed to / pulled from
+	MaxTokens     int      `json:"max_tokens"`      // Token budget for injected context, default: 0 (unlimited)
+	RecencyWeight float64  `json:"recency_weight"`  // Share of inject ranking given to recency (0-1), default: 0
+	WorkspacePath string   `json:"workspace_path"`  // Team workspace LESSONS.md above the system level (W### IDs)
+
+	HandoffsRecencyBias float64 `json:"handoffs_recency_bias"` // Share of handoff inject ordering given to recency (0-1), default: 0
+
+	PreventionWeight *float64 `json:"prevention_weight"` // Uses each recorded prevention is worth in inject ranking, default: 2 (0 ignores preventions)
+
+	DedupThreshold float64 `json:"dedup_threshold"` // Similarity (0-1) at which add rejects a duplicate, default: 0.85
+	DedupAlgo      string  `json:"dedup_algo"`      // Dedup text similarity: jaccard or cosine, default: jaccard
+
+	WebhookURL    string   `json:"webhook_url"`    // POSTed handoff status events; empty disables
+	WebhookEvents []string `json:"webhook_events"` // Events to send (complete, status_change), default: all
 }
 
+// DefaultScoreCacheTTL is the default relevance score cache TTL in seconds.
+const DefaultScoreCacheTTL = 3600
+
+// DefaultPreventionWeight is how many uses one recorded prevention is worth.
+const DefaultPreventionWeight = 2.0
+
+// DefaultDedupThreshold is the default duplicate-lesson similarity threshold.
+const DefaultDedupThreshold = lessons.DefaultDedupThreshold
+
+// DefaultDedupAlgo is the default duplicate-lesson similarity algorithm.
+const DefaultDedupAlgo = similarity.AlgoJaccard
+
 // Load reads configuration from the given JSON file path,
 // applies defaults for missing values, and overrides with environment variables.
+// A file with mistyped values is rejected (see ValidateSchema); unknown keys
+// are allowed, since the shell hooks and plugins share the file.
 func Load(configPath string) (*Config, error) {
 	cfg := &Config{}
 
 	// Try to read config file
 	if data, err := os.ReadFile(configPath); err == nil {
+		if err := ValidateSchema(data); err != nil {
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || len(schemaErr.Problems) > 0 {
+				return nil, err
+			}
+		}
 		if err := json.Unmarshal(data, cfg); err != nil {
 			return nil, err
 		}
@@ -46,6 +87,20 @@ func Load(configPath string) (*Config, error) {
 		cfg.DebugLevel = 3
 	}
 
+	// Clamp recency weight to a valid blend factor
+	if cfg.RecencyWeight < 0 {
+		cfg.RecencyWeight = 0
+	}
+	if cfg.RecencyWeight > 1 {
+		cfg.RecencyWeight = 1
+	}
+	if cfg.HandoffsRecencyBias < 0 {
+		cfg.HandoffsRecencyBias = 0
+	}
+	if cfg.HandoffsRecencyBias > 1 {
+		cfg.HandoffsRecencyBias = 1
+	}
+
 	return cfg, nil
 }
 
@@ -65,6 +120,19 @@ func applyDefaults(cfg *Config) {
 	if cfg.ProjectDir == "" {
 		cfg.ProjectDir = findProjectDir()
 	}
+	if cfg.ScoreCacheTTL <= 0 {
+		cfg.ScoreCacheTTL = DefaultScoreCacheTTL
+	}
+	if cfg.PreventionWeight == nil {
+		weight := DefaultPreventionWeight
+		cfg.PreventionWeight = &weight
+	}
+	if cfg.DedupThreshold <= 0 {
+		cfg.DedupThreshold = DefaultDedupThreshold
+	}
+	if !similarity.IsValidAlgo(cfg.DedupAlgo) {
+		cfg.DedupAlgo = DefaultDedupAlgo
+	}
 }
 
 // applyEnvOverrides overrides config values with environment variables.
@@ -89,6 +157,13 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.ProjectDir = val
 	}
 
+	// Dedup threshold: CLAUDE_RECALL_DEDUP_THRESHOLD
+	if val := os.Getenv("CLAUDE_RECALL_DEDUP_THRESHOLD"); val != "" {
+		if threshold, err := strconv.ParseFloat(val, 64); err == nil && threshold > 0 {
+			cfg.DedupThreshold = threshold
+		}
+	}
+
 	// Debug level: CLAUDE_RECALL_DEBUG > RECALL_DEBUG > LESSONS_DEBUG
 	if val := os.Getenv("CLAUDE_RECALL_DEBUG"); val != "" {
 		if level, err := strconv.Atoi(val); err == nil {
@@ -14,7 +14,7 @@ func Test_LoadConfig_MissingFile_ReturnsDefaults(t *testing.T) {
 	// Clear any env vars that might interfere
 	envVars := []string{
 		"CLAUDE_RECALL_BASE", "CLAUDE_RECALL_STATE", "PROJECT_DIR", "CLAUDE_RECALL_DEBUG",
-		"RECALL_BASE", "LESSONS_BASE", "RECALL_DEBUG", "LESSONS_DEBUG",
+		"RECALL_BASE", "LESSONS_BASE", "RECALL_DEBUG", "LESSONS_DEBUG", "CLAUDE_RECALL_DEDUP_THRESHOLD",
 	}
 	for _, v := range envVars {
 		t.Setenv(v, "")
@@ -44,6 +44,21 @@ func Test_LoadConfig_MissingFile_ReturnsDefaults(t *testing.T) {
 	if cfg.DebugLevel != 0 {
 		t.Errorf("expected DebugLevel=0, got %d", cfg.DebugLevel)
 	}
+	if cfg.ScoreCacheTTL != DefaultScoreCacheTTL {
+		t.Errorf("expected ScoreCacheTTL=%d, got %d", DefaultScoreCacheTTL, cfg.ScoreCacheTTL)
+	}
+	if cfg.MaxTokens != 0 {
+		t.Errorf("expected MaxTokens=0 (unlimited), got %d", cfg.MaxTokens)
+	}
+	if cfg.RecencyWeight != 0 {
+		t.Errorf("expected RecencyWeight=0, got %g", cfg.RecencyWeight)
+	}
+	if *cfg.PreventionWeight != DefaultPreventionWeight {
+		t.Errorf("expected PreventionWeight=%g, got %g", DefaultPreventionWeight, *cfg.PreventionWeight)
+	}
+	if cfg.DedupThreshold != DefaultDedupThreshold || cfg.DedupAlgo != "jaccard" {
+		t.Errorf("expected dedup defaults 0.85/jaccard, got %g/%q", cfg.DedupThreshold, cfg.DedupAlgo)
+	}
 }
 
 func Test_LoadConfig_ValidFile_ReturnsValues(t *testing.T) {
@@ -56,6 +71,12 @@ func Test_LoadConfig_ValidFile_ReturnsValues(t *testing.T) {
 		"state_dir":  "/custom/state",
 		"project_dir": "/custom/project",
 		"debug_level": 2,
+		"score_cache_ttl": 7200,
+		"max_tokens": 1500,
+		"recency_weight": 0.25,
+		"prevention_weight": 3.5,
+		"dedup_threshold": 0.7,
+		"dedup_algo": "cosine",
 	}
 	data, _ := json.Marshal(configData)
 	if err := os.WriteFile(configPath, data, 0644); err != nil {
@@ -65,7 +86,7 @@ func Test_LoadConfig_ValidFile_ReturnsValues(t *testing.T) {
 	// Clear env vars
 	envVars := []string{
 		"CLAUDE_RECALL_BASE", "CLAUDE_RECALL_STATE", "PROJECT_DIR", "CLAUDE_RECALL_DEBUG",
-		"RECALL_BASE", "LESSONS_BASE", "RECALL_DEBUG", "LESSONS_DEBUG",
+		"RECALL_BASE", "LESSONS_BASE", "RECALL_DEBUG", "LESSONS_DEBUG", "CLAUDE_RECALL_DEDUP_THRESHOLD",
 	}
 	for _, v := range envVars {
 		t.Setenv(v, "")
@@ -88,6 +109,36 @@ func Test_LoadConfig_ValidFile_ReturnsValues(t *testing.T) {
 	if cfg.DebugLevel != 2 {
 		t.Errorf("expected DebugLevel=2, got %d", cfg.DebugLevel)
 	}
+	if cfg.ScoreCacheTTL != 7200 {
+		t.Errorf("expected ScoreCacheTTL=7200, got %d", cfg.ScoreCacheTTL)
+	}
+	if cfg.MaxTokens != 1500 {
+		t.Errorf("expected MaxTokens=1500, got %d", cfg.MaxTokens)
+	}
+	if cfg.RecencyWeight != 0.25 {
+		t.Errorf("expected RecencyWeight=0.25, got %g", cfg.RecencyWeight)
+	}
+	if *cfg.PreventionWeight != 3.5 {
+		t.Errorf("expected PreventionWeight=3.5, got %g", *cfg.PreventionWeight)
+	}
+	if cfg.DedupThreshold != 0.7 || cfg.DedupAlgo != "cosine" {
+		t.Errorf("expected dedup 0.7/cosine, got %g/%q", cfg.DedupThreshold, cfg.DedupAlgo)
+	}
+}
+
+func Test_LoadConfig_ZeroPreventionWeight(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"prevention_weight": 0}`), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if *cfg.PreventionWeight != 0 {
+		t.Errorf("expected an explicit PreventionWeight=0 to be kept, got %g", *cfg.PreventionWeight)
+	}
 }
 
 func Test_LoadConfig_EnvOverrides(t *testing.T) {
@@ -117,6 +168,7 @@ func Test_LoadConfig_EnvOverrides(t *testing.T) {
 	t.Setenv("CLAUDE_RECALL_STATE", "/env/state")
 	t.Setenv("PROJECT_DIR", "/env/project")
 	t.Setenv("CLAUDE_RECALL_DEBUG", "3")
+	t.Setenv("CLAUDE_RECALL_DEDUP_THRESHOLD", "0.6")
 
 	cfg, err := Load(configPath)
 	if err != nil {
@@ -136,6 +188,9 @@ func Test_LoadConfig_EnvOverrides(t *testing.T) {
 	if cfg.DebugLevel != 3 {
 		t.Errorf("expected DebugLevel=3 (from env), got %d", cfg.DebugLevel)
 	}
+	if cfg.DedupThreshold != 0.6 {
+		t.Errorf("expected DedupThreshold=0.6 (from env), got %g", cfg.DedupThreshold)
+	}
 }
 
 func Test_LoadConfig_LegacyEnvVars(t *testing.T) {
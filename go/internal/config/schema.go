@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// schemaJSON is the JSON Schema for config.json. It also lists the keys the
+// shell hooks and OpenCode plugin read (enabled, remindEvery, ...), since
+// they share the file.
+//
+//go:embed schema.json
+var schemaJSON []byte
+
+// schemaNode is the subset of JSON Schema the config schema uses
+type schemaNode struct {
+	Type                 string                 `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *schemaNode            `json:"items"`
+	Enum                 []string               `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+}
+
+// configSchema is schemaJSON parsed once at startup
+var configSchema = mustParseSchema(schemaJSON)
+
+func mustParseSchema(data []byte) *schemaNode {
+	var node schemaNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		panic(fmt.Sprintf("config: invalid embedded schema: %v", err))
+	}
+	return &node
+}
+
+// SchemaError lists every way a config file breaks the schema. Unknown keys
+// are kept apart from bad values: other tools share config.json, so Load
+// tolerates them and only config validate warns.
+type SchemaError struct {
+	Problems    []string // Bad values, e.g. `max_tokens: expected integer, got string`
+	UnknownKeys []string // e.g. `unknown key "debug_levl"`
+}
+
+func (e *SchemaError) Error() string {
+	return "invalid config: " + strings.Join(append(append([]string{}, e.Problems...), e.UnknownKeys...), "; ")
+}
+
+// ValidateSchema checks config.json contents against the embedded schema:
+// no unknown keys, and every value of the right type and within bounds.
+// Returns a *SchemaError listing all problems and unknown keys, or a JSON
+// syntax error.
+func ValidateSchema(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+
+	schemaErr := &SchemaError{}
+	validateNode(configSchema, "", value, schemaErr)
+	if len(schemaErr.Problems) > 0 || len(schemaErr.UnknownKeys) > 0 {
+		return schemaErr
+	}
+	return nil
+}
+
+// validateNode records each way value breaks node in schemaErr
+func validateNode(node *schemaNode, path string, value interface{}, schemaErr *SchemaError) {
+	label := path
+	if label == "" {
+		label = "config"
+	}
+	fail := func(format string, args ...interface{}) {
+		schemaErr.Problems = append(schemaErr.Problems, label+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch node.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", jsonType(value))
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child, known := node.Properties[k]
+			if !known {
+				if node.AdditionalProperties == nil || *node.AdditionalProperties {
+					continue
+				}
+				schemaErr.UnknownKeys = append(schemaErr.UnknownKeys, fmt.Sprintf("unknown key %q", joinPath(path, k)))
+				continue
+			}
+			validateNode(child, joinPath(path, k), obj[k], schemaErr)
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %s", jsonType(value))
+			return
+		}
+		if node.Items != nil {
+			for i, item := range arr {
+				validateNode(node.Items, fmt.Sprintf("%s[%d]", label, i), item, schemaErr)
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("expected string, got %s", jsonType(value))
+			return
+		}
+		if len(node.Enum) > 0 && !containsString(node.Enum, s) {
+			fail("%q is not one of %s", s, strings.Join(node.Enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %s", jsonType(value))
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("expected %s, got %s", node.Type, jsonType(value))
+			return
+		}
+		if node.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				fail("expected integer, got %s", n)
+				return
+			}
+		}
+		f, _ := n.Float64()
+		if node.Minimum != nil && f < *node.Minimum {
+			fail("%s is below the minimum %g", n, *node.Minimum)
+		}
+		if node.Maximum != nil && f > *node.Maximum {
+			fail("%s is above the maximum %g", n, *node.Maximum)
+		}
+	}
+}
+
+// jsonType names the JSON type of a decoded value for error messages
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_ValidateSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string // Expected problems; none means valid
+	}{
+		{"empty object", `{}`, nil},
+		{"all recall keys", `{"base": "/b", "state_dir": "/s", "project_dir": "/p", "debug_level": 2,
+			"score_cache_ttl": 60, "shared_paths": ["/x/LESSONS.md"], "sync_remote": "/r", "max_tokens": 1500,
+			"recency_weight": 0.5, "workspace_path": "/w", "handoffs_recency_bias": 0.5, "prevention_weight": 2, "dedup_threshold": 0.9,
+			"dedup_algo": "cosine", "webhook_url": "https://example.com/hook", "webhook_events": ["complete"]}`, nil},
+		{"shared adapter keys", `{"enabled": true, "handoffsEnabled": false, "debugLevel": 1, "topLessonsToShow": 5, "remindEvery": 12,
+			"small_model": "claude-3-5-haiku-latest", "claudeRecall": {"alerts": {"enabled": false}}}`, nil},
+		{"typo'd key", `{"debug_levl": 1}`, []string{`unknown key "debug_levl"`}},
+		{"string for int", `{"max_tokens": "1500"}`, []string{"max_tokens: expected integer, got string"}},
+		{"float for int", `{"score_cache_ttl": 1.5}`, []string{"score_cache_ttl: expected integer, got 1.5"}},
+		{"out of range", `{"recency_weight": 2}`, []string{"recency_weight: 2 is above the maximum 1"}},
+		{"bad enum", `{"dedup_algo": "levenshtein"}`, []string{`dedup_algo: "levenshtein" is not one of jaccard, cosine`}},
+		{"bad webhook event", `{"webhook_events": ["deleted"]}`,
+			[]string{`webhook_events[0]: "deleted" is not one of complete, status_change`}},
+		{"bad array item", `{"shared_paths": ["/ok", 3]}`, []string{"shared_paths[1]: expected string, got integer"}},
+		{"not an object", `[]`, []string{"config: expected object, got array"}},
+		{"reports every problem", `{"enabled": "yes", "zzz": 1}`,
+			[]string{"enabled: expected boolean, got string", `unknown key "zzz"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchema([]byte(tt.data))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("expected valid config, got %v", err)
+				}
+				return
+			}
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected *SchemaError, got %v", err)
+			}
+			got := append(append([]string{}, schemaErr.Problems...), schemaErr.UnknownKeys...)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("problems = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateSchema([]byte(`{"base": `)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func Test_LoadConfig_RejectsSchemaViolations(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(configPath, []byte(`{"max_tokens": "lots"}`), 0644)
+
+	_, err := Load(configPath)
+	if err == nil || !strings.Contains(err.Error(), "max_tokens: expected integer, got string") {
+		t.Errorf("expected type error, got %v", err)
+	}
+
+	// Unknown keys are left to config validate
+	os.WriteFile(configPath, []byte(`{"max_tokenz": 100, "max_tokens": 50}`), 0644)
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected unknown keys to load, got %v", err)
+	}
+	if cfg.MaxTokens != 50 {
+		t.Errorf("MaxTokens = %d, want 50", cfg.MaxTokens)
+	}
+}
+
+func Test_LoadConfig_ShippedPluginConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "plugins", "claude-recall", "config.json"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	if err := ValidateSchema(data); err != nil {
+		t.Errorf("shipped config breaks the schema: %v", err)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(configPath, data, 0644)
+	if _, err := Load(configPath); err != nil {
+		t.Errorf("loading shipped config failed: %v", err)
+	}
+}
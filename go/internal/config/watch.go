@@ -0,0 +1,136 @@
+package config
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchedConfig holds the most recently loaded configuration for a config
+// file and can reload it when the file changes. It is safe for concurrent use.
+type WatchedConfig struct {
+	path string
+
+	mu       sync.RWMutex
+	cfg      *Config
+	loadedAt time.Time
+}
+
+// NewWatchedConfig creates a WatchedConfig for the config file at path. The
+// file is not read until Load is called.
+func NewWatchedConfig(path string) *WatchedConfig {
+	return &WatchedConfig{path: path}
+}
+
+// Path returns the watched config file path.
+func (w *WatchedConfig) Path() string {
+	return w.path
+}
+
+// Load reads the config file (see Load) and makes it current.
+func (w *WatchedConfig) Load() (*Config, error) {
+	cfg, err := Load(w.path)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cfg = cfg
+	w.loadedAt = time.Now()
+	return cfg, nil
+}
+
+// Current returns the last successfully loaded config (nil before Load).
+func (w *WatchedConfig) Current() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.cfg
+}
+
+// LoadedAt returns when the config was last loaded (zero before Load).
+func (w *WatchedConfig) LoadedAt() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.loadedAt
+}
+
+// fileWatcher is the subset of *fsnotify.Watcher that Watch uses.
+type fileWatcher interface {
+	Add(name string) error
+	Close() error
+	events() <-chan fsnotify.Event
+	errors() <-chan error
+}
+
+// fsnotifyWatcher adapts *fsnotify.Watcher to fileWatcher.
+type fsnotifyWatcher struct {
+	*fsnotify.Watcher
+}
+
+func (w fsnotifyWatcher) events() <-chan fsnotify.Event { return w.Events }
+func (w fsnotifyWatcher) errors() <-chan error          { return w.Errors }
+
+// newFileWatcher creates the watcher used by Watch (replaced in tests).
+var newFileWatcher = func() (fileWatcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return fsnotifyWatcher{w}, nil
+}
+
+// Watch reloads the config whenever the file is written or created (as when
+// an editor renames a new copy into place), calling onChange with each
+// successfully loaded config. Reloads that fail (e.g. a half-written file)
+// are skipped. The returned stop function ends the watch.
+func (w *WatchedConfig) Watch(onChange func(*Config)) (stop func(), err error) {
+	watcher, err := newFileWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// Watch the directory so editors that replace the file are still seen
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-watcher.events():
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(w.path) ||
+					event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if cfg, err := w.Load(); err == nil {
+					onChange(cfg)
+				}
+			case _, ok := <-watcher.errors():
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+			wg.Wait()
+		})
+	}, nil
+}
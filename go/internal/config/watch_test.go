@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fakeWatcher is a fileWatcher driven by the test
+type fakeWatcher struct {
+	added  []string
+	evCh   chan fsnotify.Event
+	errCh  chan error
+	closed bool
+}
+
+func (f *fakeWatcher) Add(name string) error         { f.added = append(f.added, name); return nil }
+func (f *fakeWatcher) Close() error                  { f.closed = true; return nil }
+func (f *fakeWatcher) events() <-chan fsnotify.Event { return f.evCh }
+func (f *fakeWatcher) errors() <-chan error          { return f.errCh }
+
+func TestWatchedConfig_WatchReloadsOnChange(t *testing.T) {
+	for _, v := range []string{"CLAUDE_RECALL_STATE", "PROJECT_DIR"} {
+		t.Setenv(v, "")
+	}
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	os.WriteFile(configPath, []byte(`{"state_dir": "/old/state"}`), 0644)
+
+	fake := &fakeWatcher{evCh: make(chan fsnotify.Event), errCh: make(chan error)}
+	orig := newFileWatcher
+	newFileWatcher = func() (fileWatcher, error) { return fake, nil }
+	defer func() { newFileWatcher = orig }()
+
+	wc := NewWatchedConfig(configPath)
+	if cfg, err := wc.Load(); err != nil || cfg.StateDir != "/old/state" {
+		t.Fatalf("Load() = %+v, %v", cfg, err)
+	}
+
+	changes := make(chan *Config, 1)
+	stop, err := wc.Watch(func(cfg *Config) { changes <- cfg })
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	if len(fake.added) != 1 || fake.added[0] != dir {
+		t.Errorf("expected watch on %s, got %v", dir, fake.added)
+	}
+
+	// Events for other files and non-write ops are ignored
+	fake.evCh <- fsnotify.Event{Name: filepath.Join(dir, "other.json"), Op: fsnotify.Write}
+	fake.evCh <- fsnotify.Event{Name: configPath, Op: fsnotify.Chmod}
+
+	os.WriteFile(configPath, []byte(`{"state_dir": "/new/state"}`), 0644)
+	fake.evCh <- fsnotify.Event{Name: configPath, Op: fsnotify.Write}
+
+	select {
+	case cfg := <-changes:
+		if cfg.StateDir != "/new/state" {
+			t.Errorf("expected reloaded StateDir=/new/state, got %q", cfg.StateDir)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config change")
+	}
+	if wc.Current().StateDir != "/new/state" {
+		t.Errorf("expected Current() to return the reloaded config, got %q", wc.Current().StateDir)
+	}
+
+	stop()
+	if !fake.closed {
+		t.Error("expected stop to close the watcher")
+	}
+	select {
+	case cfg := <-changes:
+		t.Errorf("unexpected extra change: %+v", cfg)
+	default:
+	}
+}
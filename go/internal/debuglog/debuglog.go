@@ -3,9 +3,11 @@
 package debuglog
 
 import (
+	"bufio"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
@@ -65,6 +67,39 @@ func (l *Logger) LogInjectionSkip(hook string, projectDir string, reason string,
 	})
 }
 
+// LogBudgetDrop logs items left out of an injection to fit the token budget.
+// kind: "lessons" or "handoffs"
+func (l *Logger) LogBudgetDrop(hook string, projectDir string, kind string, dropped int, maxTokens int) {
+	if l.debugLevel < 1 || dropped == 0 {
+		return
+	}
+
+	l.write(map[string]interface{}{
+		"event":       "injection_budget_dropped",
+		"level":       "info",
+		"hook":        hook,
+		"project_dir": projectDir,
+		"kind":        kind,
+		"dropped":     dropped,
+		"max_tokens":  maxTokens,
+	})
+}
+
+// LogWatch logs transcript watcher activity.
+// action: "started", "stopped", or "error"
+func (l *Logger) LogWatch(action string, transcriptPath string, detail string) {
+	if l.debugLevel < 1 {
+		return
+	}
+
+	l.write(map[string]interface{}{
+		"event":           "watch_" + action,
+		"level":           "info",
+		"transcript_path": transcriptPath,
+		"detail":          detail,
+	})
+}
+
 // LogScoreRelevanceError logs errors from the score-relevance command.
 func (l *Logger) LogScoreRelevanceError(query string, errMsg string) {
 	if l.debugLevel < 1 {
@@ -96,6 +131,120 @@ func (l *Logger) LogStopHook(sessionID string, citationsProcessed int, citationI
 	})
 }
 
+// LogCitationBatch logs the set of lessons cited while processing a session's
+// transcript, for per-lesson citation analytics.
+func (l *Logger) LogCitationBatch(sessionID, projectDir string, ids []string) {
+	if l.debugLevel < 1 || len(ids) == 0 {
+		return
+	}
+
+	l.write(map[string]interface{}{
+		"event":       citationBatchEvent,
+		"level":       "info",
+		"session_id":  sessionID,
+		"project_dir": projectDir,
+		"count":       len(ids),
+		"lesson_ids":  ids,
+	})
+}
+
+const citationBatchEvent = "citation_batch"
+
+// CitationBatch is a citation_batch event read back from recall.log.
+type CitationBatch struct {
+	SessionID  string   `json:"session_id"`
+	ProjectDir string   `json:"project_dir"`
+	LessonIDs  []string `json:"lesson_ids"`
+}
+
+// CitationCount is the aggregated citation total for one lesson.
+type CitationCount struct {
+	ID        string `json:"id"`
+	Citations int    `json:"citations"`
+	Sessions  int    `json:"sessions"`
+}
+
+// ReadCitationBatches returns all citation_batch events in {stateDir}/recall.log.
+// A missing log yields no batches; malformed lines are skipped.
+func ReadCitationBatches(stateDir string) ([]CitationBatch, error) {
+	f, err := os.Open(filepath.Join(stateDir, "recall.log"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var batches []CitationBatch
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Event string `json:"event"`
+			CitationBatch
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Event != citationBatchEvent {
+			continue
+		}
+		batches = append(batches, entry.CitationBatch)
+	}
+	return batches, scanner.Err()
+}
+
+// AggregateCitations totals citations per lesson ID, optionally restricted to
+// one session (empty sessionID means all sessions). Results are ranked by
+// citations, then sessions, then ID.
+func AggregateCitations(batches []CitationBatch, sessionID string) []CitationCount {
+	totals := make(map[string]*CitationCount)
+	sessions := make(map[string]map[string]bool)
+
+	for _, b := range batches {
+		if sessionID != "" && b.SessionID != sessionID {
+			continue
+		}
+		for _, id := range b.LessonIDs {
+			c, ok := totals[id]
+			if !ok {
+				c = &CitationCount{ID: id}
+				totals[id] = c
+				sessions[id] = make(map[string]bool)
+			}
+			c.Citations++
+			sessions[id][b.SessionID] = true
+		}
+	}
+
+	counts := make([]CitationCount, 0, len(totals))
+	for id, c := range totals {
+		c.Sessions = len(sessions[id])
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Citations != counts[j].Citations {
+			return counts[i].Citations > counts[j].Citations
+		}
+		if counts[i].Sessions != counts[j].Sessions {
+			return counts[i].Sessions > counts[j].Sessions
+		}
+		return counts[i].ID < counts[j].ID
+	})
+	return counts
+}
+
+// LogRateLimited logs an API request delayed by the rate limiter (debug level).
+func (l *Logger) LogRateLimited(delay time.Duration) {
+	if l.debugLevel < 2 {
+		return
+	}
+
+	l.write(map[string]interface{}{
+		"event":    "api_rate_limited",
+		"level":    "debug",
+		"delay_ms": delay.Milliseconds(),
+	})
+}
+
 func (l *Logger) write(entry map[string]interface{}) {
 	entry["timestamp"] = time.Now().Format(time.RFC3339)
 
@@ -0,0 +1,76 @@
+package debuglog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogCitationBatch_ReadBack(t *testing.T) {
+	stateDir := t.TempDir()
+
+	New(stateDir, 0).LogCitationBatch("s0", "/proj", []string{"L001"})
+	logger := New(stateDir, 1)
+	logger.LogCitationBatch("s1", "/proj", []string{"L001", "L002"})
+	logger.LogCitationBatch("s1", "/proj", nil)
+	logger.LogStopHook("s1", 2, []string{"L001", "L002"}, 0, nil)
+
+	batches, err := ReadCitationBatches(stateDir)
+	if err != nil {
+		t.Fatalf("ReadCitationBatches failed: %v", err)
+	}
+	// Level 0 and empty batches are not logged; other events are ignored
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d: %+v", len(batches), batches)
+	}
+	b := batches[0]
+	if b.SessionID != "s1" || b.ProjectDir != "/proj" || len(b.LessonIDs) != 2 {
+		t.Errorf("unexpected batch: %+v", b)
+	}
+}
+
+func TestReadCitationBatches_MissingLog(t *testing.T) {
+	batches, err := ReadCitationBatches(t.TempDir())
+	if err != nil || len(batches) != 0 {
+		t.Errorf("expected no batches and no error, got %v, %v", batches, err)
+	}
+}
+
+func TestAggregateCitations(t *testing.T) {
+	stateDir := t.TempDir()
+	log := `{"event":"citation_batch","session_id":"a","lesson_ids":["L001","L002"]}
+{"event":"lessons_injected","lesson_ids":["L009","L009","L009"]}
+not json
+{"event":"citation_batch","session_id":"a","lesson_ids":["L002"]}
+{"event":"citation_batch","session_id":"b","lesson_ids":["L002","S001"]}
+{"event":"citation_batch","session_id":"b","lesson_ids":["L001"]}
+`
+	if err := os.WriteFile(filepath.Join(stateDir, "recall.log"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	batches, err := ReadCitationBatches(stateDir)
+	if err != nil {
+		t.Fatalf("ReadCitationBatches failed: %v", err)
+	}
+
+	all := AggregateCitations(batches, "")
+	want := []CitationCount{
+		{ID: "L002", Citations: 3, Sessions: 2},
+		{ID: "L001", Citations: 2, Sessions: 2},
+		{ID: "S001", Citations: 1, Sessions: 1},
+	}
+	if len(all) != len(want) {
+		t.Fatalf("expected %d counts, got %+v", len(want), all)
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("rank %d = %+v, want %+v", i+1, all[i], want[i])
+		}
+	}
+
+	onlyB := AggregateCitations(batches, "b")
+	if len(onlyB) != 3 || onlyB[0].Citations != 1 || onlyB[0].ID != "L001" {
+		t.Errorf("unexpected session-filtered counts: %+v", onlyB)
+	}
+}
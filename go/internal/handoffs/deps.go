@@ -0,0 +1,251 @@
+package handoffs
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// DetectCycles returns every blocked-by cycle among the store's handoffs.
+// Each cycle starts and ends with the same ID (hf-aaa -> hf-bbb -> hf-aaa).
+func DetectCycles(store *Store) ([][]string, error) {
+	all, err := store.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return findCycles(dependencyGraph(all)), nil
+}
+
+// FormatCycle renders a cycle as "hf-aaa -> hf-bbb -> hf-aaa"
+func FormatCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
+
+// dependencyGraph maps each handoff ID to the IDs it is blocked by
+func dependencyGraph(handoffs []*models.Handoff) map[string][]string {
+	graph := make(map[string][]string, len(handoffs))
+	for _, h := range handoffs {
+		graph[h.ID] = h.BlockedBy
+	}
+	return graph
+}
+
+// findCycles walks the graph depth-first and records each back edge as a
+// cycle. IDs are visited in sorted order so results are deterministic.
+func findCycles(graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	ids := make([]string, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	state := make(map[string]int, len(graph))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		for _, dep := range graph[id] {
+			switch state[dep] {
+			case visiting:
+				// Back edge: the cycle is the stack from dep to here, closed by dep
+				start := len(stack) - 1
+				for stack[start] != dep {
+					start--
+				}
+				cycle := append([]string{}, stack[start:]...)
+				cycles = append(cycles, append(cycle, dep))
+			case unvisited:
+				if _, ok := graph[dep]; ok {
+					visit(dep)
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	return cycles
+}
+
+// AddDependency marks id as blocked by depID. Both handoffs must exist and
+// the new edge must not create a cycle. Adding an existing dependency is a
+// no-op.
+func (s *Store) AddDependency(id, depID string) error {
+	if id == depID {
+		return fmt.Errorf("handoff %s cannot depend on itself", id)
+	}
+	h, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Get(depID); err != nil {
+		return err
+	}
+	for _, dep := range h.BlockedBy {
+		if dep == depID {
+			return nil
+		}
+	}
+	blockedBy := append(append([]string{}, h.BlockedBy...), depID)
+	return s.Update(id, map[string]interface{}{"blocked_by": blockedBy})
+}
+
+// RemoveDependency drops depID from id's blockers. depID need not still
+// exist, so references to deleted handoffs can be cleaned up. As when a
+// blocker completes, a blocked handoff left with none moves to not_started.
+func (s *Store) RemoveDependency(id, depID string) error {
+	h, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	remaining := make([]string, 0, len(h.BlockedBy))
+	for _, dep := range h.BlockedBy {
+		if dep != depID {
+			remaining = append(remaining, dep)
+		}
+	}
+	if len(remaining) == len(h.BlockedBy) {
+		return fmt.Errorf("handoff %s is not blocked by %s", id, depID)
+	}
+
+	updates := map[string]interface{}{"blocked_by": remaining}
+	if len(remaining) == 0 && h.Status == "blocked" {
+		updates["status"] = "not_started"
+	}
+	return s.Update(id, updates)
+}
+
+// validateBlockedBy checks that every blocker of id exists and that setting
+// them would not introduce a cycle through id
+func (s *Store) validateBlockedBy(id string, blockedBy []string) error {
+	all, err := s.ListAll()
+	if err != nil {
+		return err
+	}
+
+	graph := dependencyGraph(all)
+	for _, dep := range blockedBy {
+		if _, ok := graph[dep]; !ok {
+			return fmt.Errorf("blocked_by references unknown handoff %s", dep)
+		}
+	}
+	graph[id] = blockedBy
+
+	for _, cycle := range findCycles(graph) {
+		for _, member := range cycle {
+			if member == id {
+				return fmt.Errorf("circular dependency: %s", FormatCycle(rotateCycle(cycle, id)))
+			}
+		}
+	}
+
+	return nil
+}
+
+// rotateCycle reorders a closed cycle so it starts and ends at id
+func rotateCycle(cycle []string, id string) []string {
+	open := cycle[:len(cycle)-1]
+	for i, member := range open {
+		if member == id {
+			rotated := append(append([]string{}, open[i:]...), open[:i]...)
+			return append(rotated, id)
+		}
+	}
+	return cycle
+}
+
+// releaseBlockers removes completedID from the BlockedBy of every handoff
+// in list. A blocked handoff left with no blockers moves back to
+// not_started; those are returned so the caller can notify after writing.
+func releaseBlockers(list []*models.Handoff, completedID string) (changed bool, unblocked []models.Handoff) {
+	for _, h := range list {
+		remaining := make([]string, 0, len(h.BlockedBy))
+		for _, dep := range h.BlockedBy {
+			if dep != completedID {
+				remaining = append(remaining, dep)
+			}
+		}
+		if len(remaining) == len(h.BlockedBy) {
+			continue
+		}
+		h.BlockedBy = remaining
+		if len(remaining) == 0 && h.Status == "blocked" {
+			h.Status = "not_started"
+			unblocked = append(unblocked, *h)
+		}
+		h.Updated = time.Now()
+		h.Stale = false
+		changed = true
+	}
+	return changed, unblocked
+}
+
+// resolveBlockers releases completedID's dependents in every handoffs file
+// except donePath, which the caller already resolved in the same write that
+// completed the handoff
+func (s *Store) resolveBlockers(completedID, donePath string) error {
+	for _, file := range []struct {
+		path    string
+		stealth bool
+	}{{s.projectPath, false}, {s.stealthPath, true}} {
+		if file.path == donePath {
+			continue
+		}
+		if err := s.resolveBlockersIn(file.path, file.stealth, completedID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveBlockersIn applies releaseBlockers to one handoffs file under its lock
+func (s *Store) resolveBlockersIn(path string, stealth bool, completedID string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return err
+	}
+
+	changed, unblocked := releaseBlockers(handoffs, completedID)
+	if !changed {
+		return nil
+	}
+	if err := s.writeHandoffs(path, handoffs); err != nil {
+		return err
+	}
+	fl.Release()
+	for _, h := range unblocked {
+		s.notifyStatus(h, "blocked")
+	}
+	return nil
+}
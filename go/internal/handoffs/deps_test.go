@@ -0,0 +1,266 @@
+package handoffs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func newDepsTestStore(t *testing.T, n int) (*Store, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	var ids []string
+	for i := 0; i < n; i++ {
+		h, err := store.Add("Work item", "", false)
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		ids = append(ids, h.ID)
+	}
+	return store, ids
+}
+
+func Test_Store_Update_BlockedBySelfReference(t *testing.T) {
+	store, ids := newDepsTestStore(t, 1)
+	a := ids[0]
+
+	err := store.Update(a, map[string]interface{}{"blocked_by": []string{a}})
+	if err == nil {
+		t.Fatal("Expected error for self-reference")
+	}
+	want := "circular dependency: " + a + " -> " + a
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func Test_Store_Update_BlockedByTwoNodeCycle(t *testing.T) {
+	store, ids := newDepsTestStore(t, 2)
+	a, b := ids[0], ids[1]
+
+	if err := store.Update(a, map[string]interface{}{"blocked_by": []string{b}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	err := store.Update(b, map[string]interface{}{"blocked_by": []string{a}})
+	if err == nil {
+		t.Fatal("Expected error for two-node cycle")
+	}
+	want := "circular dependency: " + b + " -> " + a + " -> " + b
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	// Rejected update must not be persisted
+	h, _ := store.Get(b)
+	if len(h.BlockedBy) != 0 {
+		t.Errorf("Expected BlockedBy unchanged, got %v", h.BlockedBy)
+	}
+}
+
+func Test_Store_Update_BlockedByThreeNodeCycle(t *testing.T) {
+	store, ids := newDepsTestStore(t, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+
+	store.Update(a, map[string]interface{}{"blocked_by": []string{b}})
+	store.Update(b, map[string]interface{}{"blocked_by": []string{c}})
+
+	err := store.Update(c, map[string]interface{}{"blocked_by": []string{a}})
+	if err == nil {
+		t.Fatal("Expected error for three-node cycle")
+	}
+	want := "circular dependency: " + c + " -> " + a + " -> " + b + " -> " + c
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func Test_Store_Update_BlockedByUnknownID(t *testing.T) {
+	store, ids := newDepsTestStore(t, 1)
+
+	err := store.Update(ids[0], map[string]interface{}{"blocked_by": []string{"hf-0000000"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown handoff hf-0000000") {
+		t.Errorf("Expected unknown handoff error, got %v", err)
+	}
+}
+
+func Test_Store_Update_BlockedByValidChain(t *testing.T) {
+	store, ids := newDepsTestStore(t, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+
+	if err := store.Update(a, map[string]interface{}{"blocked_by": []string{b, c}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Update(b, map[string]interface{}{"blocked_by": []string{c}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	cycles, err := DetectCycles(store)
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %v", cycles)
+	}
+}
+
+func Test_DetectCycles_FindsExistingCycles(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	// Import bypasses Update validation, so use it to seed a broken graph
+	self := models.NewHandoff("hf-0000001", "Self")
+	self.BlockedBy = []string{"hf-0000001"}
+	x := models.NewHandoff("hf-000000a", "X")
+	x.BlockedBy = []string{"hf-000000b"}
+	y := models.NewHandoff("hf-000000b", "Y")
+	y.BlockedBy = []string{"hf-000000c"}
+	z := models.NewHandoff("hf-000000c", "Z")
+	z.BlockedBy = []string{"hf-000000a"}
+
+	if _, err := store.Import([]*models.Handoff{self, x, y, z}, ConflictSkip); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	cycles, err := DetectCycles(store)
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 cycles, got %v", cycles)
+	}
+	if got := FormatCycle(cycles[0]); got != "hf-0000001 -> hf-0000001" {
+		t.Errorf("Unexpected first cycle: %s", got)
+	}
+	if got := FormatCycle(cycles[1]); got != "hf-000000a -> hf-000000b -> hf-000000c -> hf-000000a" {
+		t.Errorf("Unexpected second cycle: %s", got)
+	}
+}
+
+func Test_Store_Update_CompletingResolvesBlockers(t *testing.T) {
+	store, ids := newDepsTestStore(t, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+
+	if err := store.Update(b, map[string]interface{}{"blocked_by": []string{a}, "status": "blocked"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Update(c, map[string]interface{}{"blocked_by": []string{b}, "status": "blocked"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	// A stealth handoff blocked by A and C keeps C as its blocker
+	d, err := store.Add("Stealth item", "", true)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Update(d.ID, map[string]interface{}{"blocked_by": []string{a, c}, "status": "blocked"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if err := store.Update(a, map[string]interface{}{"status": "completed"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	hb, _ := store.Get(b)
+	if len(hb.BlockedBy) != 0 || hb.Status != "not_started" {
+		t.Errorf("Expected B unblocked and not_started, got blocked_by=%v status=%s", hb.BlockedBy, hb.Status)
+	}
+	hc, _ := store.Get(c)
+	if len(hc.BlockedBy) != 1 || hc.BlockedBy[0] != b || hc.Status != "blocked" {
+		t.Errorf("Expected C still blocked by B, got blocked_by=%v status=%s", hc.BlockedBy, hc.Status)
+	}
+	hd, _ := store.Get(d.ID)
+	if len(hd.BlockedBy) != 1 || hd.BlockedBy[0] != c || hd.Status != "blocked" {
+		t.Errorf("Expected stealth handoff blocked by C only, got blocked_by=%v status=%s", hd.BlockedBy, hd.Status)
+	}
+}
+
+func Test_Store_Complete_ResolvesBlockers(t *testing.T) {
+	store, ids := newDepsTestStore(t, 2)
+	a, b := ids[0], ids[1]
+
+	if err := store.Update(b, map[string]interface{}{"blocked_by": []string{a}, "status": "blocked"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	d, _ := store.Add("Stealth item", "", true)
+	if err := store.Update(d.ID, map[string]interface{}{"blocked_by": []string{a}, "status": "blocked"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// Same-file dependents are released in the write that completes A
+	writes := map[string]int{}
+	origWriteFile := writeFile
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		writes[filepath.Base(name)]++
+		return origWriteFile(name, data, perm)
+	}
+	defer func() { writeFile = origWriteFile }()
+
+	if err := store.Complete(a); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	for _, id := range []string{b, d.ID} {
+		h, _ := store.Get(id)
+		if len(h.BlockedBy) != 0 || h.Status != "not_started" {
+			t.Errorf("Expected %s unblocked and not_started, got blocked_by=%v status=%s", id, h.BlockedBy, h.Status)
+		}
+	}
+	if writes["HANDOFFS.md.tmp"] != 1 || writes["HANDOFFS_LOCAL.md.tmp"] != 1 {
+		t.Errorf("Expected one write per file, got %v", writes)
+	}
+}
+
+func Test_Store_AddRemoveDependency(t *testing.T) {
+	store, ids := newDepsTestStore(t, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+
+	if err := store.AddDependency(a, b); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.AddDependency(a, c); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.AddDependency(a, b); err != nil {
+		t.Fatalf("re-adding a dependency should be a no-op, got %v", err)
+	}
+	h, _ := store.Get(a)
+	if strings.Join(h.BlockedBy, ",") != b+","+c {
+		t.Errorf("expected BlockedBy [%s %s], got %v", b, c, h.BlockedBy)
+	}
+
+	// b -> c -> ... -> a -> b would close a loop
+	if err := store.AddDependency(b, a); err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Errorf("expected circular dependency error, got %v", err)
+	}
+	if err := store.AddDependency(a, "hf-missing"); err == nil {
+		t.Error("expected error for unknown dependency")
+	}
+	if err := store.AddDependency(a, a); err == nil {
+		t.Error("expected error for self dependency")
+	}
+
+	if err := store.RemoveDependency(a, b); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	h, _ = store.Get(a)
+	if len(h.BlockedBy) != 1 || h.BlockedBy[0] != c {
+		t.Errorf("expected BlockedBy [%s], got %v", c, h.BlockedBy)
+	}
+	if err := store.RemoveDependency(a, b); err == nil {
+		t.Error("expected error removing a dependency that is not set")
+	}
+
+	// Removing the last blocker of a blocked handoff unblocks it
+	store.Update(a, map[string]interface{}{"status": "blocked"})
+	if err := store.RemoveDependency(a, c); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	if h, _ = store.Get(a); len(h.BlockedBy) != 0 || h.Status != "not_started" {
+		t.Errorf("expected unblocked not_started handoff, got %v %s", h.BlockedBy, h.Status)
+	}
+}
@@ -0,0 +1,29 @@
+package handoffs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// durationUnits maps the calendar suffixes ParseDuration accepts to their
+// length. A month is approximated as 30 days.
+var durationUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'm': 30 * 24 * time.Hour,
+}
+
+// ParseDuration parses an age such as "30d", "2w", or "1m" (days, weeks,
+// or 30-day months)
+func ParseDuration(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w, 1m)", s)
+	}
+	unit, ok := durationUnits[s[len(s)-1]]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w, 1m)", s)
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,32 @@
+package handoffs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	day := 24 * time.Hour
+	tests := map[string]time.Duration{
+		"30d": 30 * day,
+		"2w":  14 * day,
+		"1m":  30 * day,
+		"0d":  0,
+	}
+	for in, want := range tests {
+		got, err := ParseDuration(in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "d", "30", "30h", "-1d", "abcw", "1.5w"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package handoffs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// GraphNode is a handoff in the dependency graph
+type GraphNode struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// GraphEdge points from a blocking handoff to the handoff it blocks
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the blocked-by dependency graph of a set of handoffs
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// dotShapes maps handoff statuses to Graphviz node shapes
+var dotShapes = map[string]string{
+	"in_progress": "box",
+	"blocked":     "diamond",
+	"completed":   "circle",
+}
+
+// BuildGraph builds the dependency graph of handoffs, sorted by ID.
+// Blockers that are not among the handoffs are left out.
+func BuildGraph(handoffList []*models.Handoff) Graph {
+	g := Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	known := make(map[string]bool, len(handoffList))
+	for _, h := range handoffList {
+		known[h.ID] = true
+		g.Nodes = append(g.Nodes, GraphNode{ID: h.ID, Title: h.Title, Status: h.Status})
+	}
+	for _, h := range handoffList {
+		for _, dep := range h.BlockedBy {
+			if known[dep] {
+				g.Edges = append(g.Edges, GraphEdge{From: dep, To: h.ID})
+			}
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// FormatDOT renders the graph in Graphviz DOT format. Node shapes show
+// status: box = in_progress, diamond = blocked, circle = completed.
+func (g Graph) FormatDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph handoffs {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		shape := dotShapes[n.Status]
+		if shape == "" {
+			shape = "ellipse"
+		}
+		label := fmt.Sprintf("%s\\n%s", n.ID, dotEscape(n.Title))
+		sb.WriteString(fmt.Sprintf("  %q [label=\"%s\", shape=%s];\n", n.ID, label, shape))
+	}
+	for _, e := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q;\n", e.From, e.To))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// FormatASCII renders the graph as an indented tree with each handoff
+// listed under the handoffs blocking it. Handoffs blocked by several others
+// appear under each; cycles are marked instead of followed.
+func (g Graph) FormatASCII() string {
+	byID := make(map[string]GraphNode, len(g.Nodes))
+	for _, n := range g.Nodes {
+		byID[n.ID] = n
+	}
+	children := make(map[string][]string)
+	blocked := make(map[string]bool)
+	for _, e := range g.Edges {
+		children[e.From] = append(children[e.From], e.To)
+		blocked[e.To] = true
+	}
+
+	var sb strings.Builder
+	printed := make(map[string]bool)
+	var walk func(id string, depth int, path map[string]bool)
+	walk = func(id string, depth int, path map[string]bool) {
+		n := byID[id]
+		indent := strings.Repeat("  ", depth)
+		if path[id] {
+			sb.WriteString(fmt.Sprintf("%s%s (cycle)\n", indent, id))
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s%s [%s] %s\n", indent, n.ID, n.Status, n.Title))
+		printed[id] = true
+		path[id] = true
+		for _, child := range children[id] {
+			walk(child, depth+1, path)
+		}
+		delete(path, id)
+	}
+
+	// Roots are handoffs nothing blocks; anything left over sits on a cycle
+	for _, n := range g.Nodes {
+		if !blocked[n.ID] {
+			walk(n.ID, 0, map[string]bool{})
+		}
+	}
+	for _, n := range g.Nodes {
+		if !printed[n.ID] {
+			walk(n.ID, 0, map[string]bool{})
+		}
+	}
+	return sb.String()
+}
+
+// dotEscape escapes a string for use inside a double-quoted DOT attribute
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
@@ -0,0 +1,74 @@
+package handoffs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// graphChain returns a -> b -> c, where each handoff blocks the next
+func graphChain() []*models.Handoff {
+	a := models.NewHandoff("hf-000000a", "Design schema")
+	a.Status = "completed"
+	b := models.NewHandoff("hf-000000b", `Write "migrations"`)
+	b.Status = "in_progress"
+	b.BlockedBy = []string{"hf-000000a"}
+	c := models.NewHandoff("hf-000000c", "Backfill data")
+	c.Status = "blocked"
+	c.BlockedBy = []string{"hf-000000b", "hf-missing"}
+	return []*models.Handoff{c, a, b}
+}
+
+func TestBuildGraph_Chain(t *testing.T) {
+	g := BuildGraph(graphChain())
+	if len(g.Nodes) != 3 || g.Nodes[0].ID != "hf-000000a" {
+		t.Errorf("expected 3 nodes sorted by ID, got %+v", g.Nodes)
+	}
+	want := []GraphEdge{{From: "hf-000000a", To: "hf-000000b"}, {From: "hf-000000b", To: "hf-000000c"}}
+	if len(g.Edges) != 2 || g.Edges[0] != want[0] || g.Edges[1] != want[1] {
+		t.Errorf("edges = %+v, want %+v (unknown blockers dropped)", g.Edges, want)
+	}
+}
+
+func TestGraph_FormatDOT(t *testing.T) {
+	dot := BuildGraph(graphChain()).FormatDOT()
+
+	if !strings.HasPrefix(dot, "digraph handoffs {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("expected a digraph block, got:\n%s", dot)
+	}
+	for _, want := range []string{
+		`"hf-000000a" [label="hf-000000a\nDesign schema", shape=circle];`,
+		`"hf-000000b" [label="hf-000000b\nWrite \"migrations\"", shape=box];`,
+		`"hf-000000c" [label="hf-000000c\nBackfill data", shape=diamond];`,
+		`"hf-000000a" -> "hf-000000b";`,
+		`"hf-000000b" -> "hf-000000c";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("missing %s in:\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, "{") != strings.Count(dot, "}") || strings.Contains(dot, "hf-missing") {
+		t.Errorf("malformed DOT output:\n%s", dot)
+	}
+}
+
+func TestGraph_FormatASCII(t *testing.T) {
+	got := BuildGraph(graphChain()).FormatASCII()
+	want := "hf-000000a [completed] Design schema\n" +
+		"  hf-000000b [in_progress] Write \"migrations\"\n" +
+		"    hf-000000c [blocked] Backfill data\n"
+	if got != want {
+		t.Errorf("ascii tree =\n%s\nwant\n%s", got, want)
+	}
+
+	// A cycle has no root but is still printed, with the loop marked
+	x := models.NewHandoff("hf-000000x", "X")
+	x.BlockedBy = []string{"hf-000000y"}
+	y := models.NewHandoff("hf-000000y", "Y")
+	y.BlockedBy = []string{"hf-000000x"}
+	cyclic := BuildGraph([]*models.Handoff{x, y}).FormatASCII()
+	if !strings.Contains(cyclic, "hf-000000x [not_started] X") || !strings.Contains(cyclic, "hf-000000x (cycle)") {
+		t.Errorf("unexpected cyclic tree:\n%s", cyclic)
+	}
+}
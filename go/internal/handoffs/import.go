@@ -0,0 +1,144 @@
+package handoffs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// Conflict policies for Import when an incoming ID already exists
+const (
+	ConflictSkip      = "skip"      // Keep the existing handoff
+	ConflictOverwrite = "overwrite" // Replace the existing handoff
+	ConflictRenumber  = "renumber"  // Import under a freshly generated ID
+)
+
+// ImportResult summarizes the outcome of an Import
+type ImportResult struct {
+	Added       int
+	Overwritten int
+	Skipped     int
+	Renumbered  map[string]string // Original ID -> newly assigned ID
+}
+
+// Import merges handoffs into the store, routing each to the project or
+// stealth file by its Stealth flag. When handoffs are renumbered, BlockedBy
+// references between imported handoffs are rewritten to the new IDs. Both
+// files are locked for the duration and rewritten atomically.
+func (s *Store) Import(incoming []*models.Handoff, policy string) (*ImportResult, error) {
+	switch policy {
+	case ConflictSkip, ConflictOverwrite, ConflictRenumber:
+	default:
+		return nil, fmt.Errorf("invalid conflict policy '%s': must be skip, overwrite, or renumber", policy)
+	}
+
+	paths := map[bool]string{false: s.projectPath, true: s.stealthPath}
+
+	// Lock both files in a fixed order
+	for _, stealth := range []bool{false, true} {
+		if err := os.MkdirAll(filepath.Dir(paths[stealth]), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		fl, err := lock.Acquire(paths[stealth] + ".lock")
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		defer fl.Release()
+	}
+
+	existing := make(map[bool][]*models.Handoff)
+	taken := make(map[string]bool)
+	for _, stealth := range []bool{false, true} {
+		loaded, err := s.loadHandoffs(paths[stealth], stealth)
+		if err != nil {
+			return nil, err
+		}
+		existing[stealth] = loaded
+		for _, h := range loaded {
+			taken[h.ID] = true
+		}
+	}
+
+	result := &ImportResult{Renumbered: make(map[string]string)}
+	dirty := make(map[bool]bool)
+	var added []*models.Handoff
+
+	for _, in := range incoming {
+		h := *in
+		h.BlockedBy = append([]string{}, in.BlockedBy...)
+		if h.Priority == "" {
+			h.Priority = models.DefaultHandoffPriority
+		}
+		stealth := h.Stealth
+
+		if taken[h.ID] {
+			switch policy {
+			case ConflictSkip:
+				result.Skipped++
+				continue
+			case ConflictOverwrite:
+				if replaceHandoff(existing, &h) {
+					dirty[false], dirty[true] = true, true
+					result.Overwritten++
+					continue
+				}
+			case ConflictRenumber:
+				newID := GenerateID()
+				for taken[newID] {
+					newID = GenerateID()
+				}
+				result.Renumbered[h.ID] = newID
+				h.ID = newID
+			}
+		}
+
+		taken[h.ID] = true
+		existing[stealth] = append(existing[stealth], &h)
+		added = append(added, &h)
+		dirty[stealth] = true
+		result.Added++
+	}
+
+	// Point blockers at renumbered IDs
+	for _, h := range added {
+		for i, dep := range h.BlockedBy {
+			if newID, ok := result.Renumbered[dep]; ok {
+				h.BlockedBy[i] = newID
+			}
+		}
+	}
+
+	for _, stealth := range []bool{false, true} {
+		if !dirty[stealth] {
+			continue
+		}
+		if err := s.writeHandoffs(paths[stealth], existing[stealth]); err != nil {
+			return nil, fmt.Errorf("failed to write handoffs: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// replaceHandoff swaps in h for the existing handoff with the same ID,
+// moving it between project and stealth files if its Stealth flag changed
+func replaceHandoff(existing map[bool][]*models.Handoff, h *models.Handoff) bool {
+	for _, stealth := range []bool{false, true} {
+		for i, e := range existing[stealth] {
+			if e.ID != h.ID {
+				continue
+			}
+			if stealth == h.Stealth {
+				existing[stealth][i] = h
+			} else {
+				existing[stealth] = append(existing[stealth][:i], existing[stealth][i+1:]...)
+				existing[h.Stealth] = append(existing[h.Stealth], h)
+			}
+			return true
+		}
+	}
+	return false
+}
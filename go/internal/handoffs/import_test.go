@@ -0,0 +1,71 @@
+package handoffs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func Test_Store_Import_RenumberRewritesBlockers(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	existing, _ := store.Add("Existing work", "", false)
+
+	// Incoming handoff collides with the existing ID; its dependent must follow
+	collide := models.NewHandoff(existing.ID, "Imported work")
+	dependent := models.NewHandoff("hf-1234567", "Depends on imported")
+	dependent.BlockedBy = []string{existing.ID}
+
+	result, err := store.Import([]*models.Handoff{collide, dependent}, ConflictRenumber)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	newID, ok := result.Renumbered[existing.ID]
+	if !ok || newID == existing.ID {
+		t.Fatalf("Expected %s to be renumbered, got %v", existing.ID, result.Renumbered)
+	}
+
+	h, err := store.Get("hf-1234567")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(h.BlockedBy) != 1 || h.BlockedBy[0] != newID {
+		t.Errorf("Expected BlockedBy [%s], got %v", newID, h.BlockedBy)
+	}
+
+	orig, _ := store.Get(existing.ID)
+	if orig.Title != "Existing work" {
+		t.Errorf("Expected original handoff untouched, got %q", orig.Title)
+	}
+}
+
+func Test_Store_Import_OverwriteAndStealth(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	existing, _ := store.Add("Existing work", "", false)
+
+	replacement := models.NewHandoff(existing.ID, "Replaced")
+	secret := models.NewHandoff("hf-abcdef0", "Secret")
+	secret.Stealth = true
+
+	result, err := store.Import([]*models.Handoff{replacement, secret}, ConflictOverwrite)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Overwritten != 1 || result.Added != 1 {
+		t.Errorf("Expected 1 overwritten and 1 added, got %+v", result)
+	}
+
+	h, _ := store.Get(existing.ID)
+	if h.Title != "Replaced" {
+		t.Errorf("Expected title 'Replaced', got %q", h.Title)
+	}
+	s, _ := store.Get("hf-abcdef0")
+	if s == nil || !s.Stealth {
+		t.Errorf("Expected stealth handoff in stealth file, got %+v", s)
+	}
+}
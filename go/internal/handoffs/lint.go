@@ -0,0 +1,178 @@
+package handoffs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/checksum"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+var (
+	// lintHeaderRegex matches any handoff header, including ones with IDs
+	// the parser would silently skip
+	lintHeaderRegex = regexp.MustCompile(`^### \[([^\]]*)\]`)
+	// lintIDRegex is the hf-XXXXXXX format (or the legacy A001 format)
+	lintIDRegex = regexp.MustCompile(`^(hf-[0-9a-f]{7}|[A-Z]\d{3})$`)
+
+	// Date fields, matched loosely so bad values can be reported
+	lintCreatedRegex     = regexp.MustCompile(`\*\*Created\*\*: ([^|\s]+)`)
+	lintUpdatedRegex     = regexp.MustCompile(`\*\*Updated\*\*: ([^|\s]+)`)
+	lintLastSessionRegex = regexp.MustCompile(`^- \*\*Last Session\*\*: (\S+)`)
+)
+
+// LintChecksums reports handoff files whose checksum footer is missing or
+// doesn't match their content. Missing files are skipped.
+func LintChecksums(store *Store) ([]models.LintIssue, error) {
+	var issues []models.LintIssue
+	for _, path := range []string{store.projectPath, store.stealthPath} {
+		fileIssues, err := checksum.LintFile(path)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
+
+// blockedByRef is a blocked-by entry awaiting resolution against all IDs
+type blockedByRef struct {
+	line      int
+	handoffID string
+	target    string
+}
+
+// Lint validates the project and stealth handoff files line by line,
+// reporting malformed IDs, duplicate IDs, bad dates, and blocked-by
+// references to handoffs that do not exist. Missing files are skipped.
+func Lint(store *Store) ([]models.LintIssue, error) {
+	paths := []string{store.projectPath, store.stealthPath}
+
+	seen := make(map[string]string) // ID -> "file:line" of its first definition
+	now := time.Now()
+	fileIssues := make([][]models.LintIssue, len(paths))
+	fileRefs := make([][]blockedByRef, len(paths))
+	for i, path := range paths {
+		var err error
+		fileIssues[i], fileRefs[i], err = lintHandoffFile(path, seen, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Blocked-by targets may live in either file, so resolve them last
+	var issues []models.LintIssue
+	for i, path := range paths {
+		for _, ref := range fileRefs[i] {
+			msg := ""
+			switch {
+			case ref.target == ref.handoffID:
+				msg = fmt.Sprintf("handoff %s is blocked by itself", ref.handoffID)
+			case seen[ref.target] == "":
+				msg = fmt.Sprintf("handoff %s is blocked by unknown handoff %s", ref.handoffID, ref.target)
+			default:
+				continue
+			}
+			fileIssues[i] = append(fileIssues[i], models.LintIssue{File: path, Line: ref.line, Message: msg})
+		}
+		sort.SliceStable(fileIssues[i], func(a, b int) bool {
+			return fileIssues[i][a].Line < fileIssues[i][b].Line
+		})
+		issues = append(issues, fileIssues[i]...)
+	}
+	return issues, nil
+}
+
+// lintHandoffFile checks one HANDOFFS.md, returning its issues and the
+// blocked-by references still to be resolved
+func lintHandoffFile(path string, seen map[string]string, now time.Time) ([]models.LintIssue, []blockedByRef, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var issues []models.LintIssue
+	var refs []blockedByRef
+	report := func(line int, format string, args ...interface{}) {
+		issues = append(issues, models.LintIssue{File: path, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	checkDate := func(line int, name, value string) (time.Time, bool) {
+		t, err := time.Parse(dateFormat, value)
+		if err != nil {
+			report(line, "invalid %s date %q", name, value)
+			return time.Time{}, false
+		}
+		if !models.IsReasonableDate(t, now) {
+			report(line, "%s date %s is out of range", name, value)
+			return t, false
+		}
+		return t, true
+	}
+
+	currentID := ""
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		if matches := lintHeaderRegex.FindStringSubmatch(line); matches != nil {
+			id := matches[1]
+			if !lintIDRegex.MatchString(id) {
+				report(lineNo, "invalid handoff ID %q (expected hf-XXXXXXX)", id)
+			}
+			if first, ok := seen[id]; ok {
+				report(lineNo, "duplicate handoff ID %s (first defined at %s)", id, first)
+			} else {
+				seen[id] = fmt.Sprintf("%s:%d", path, lineNo)
+			}
+			currentID = id
+			continue
+		}
+
+		if currentID == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "- **Created**:"):
+			var created, updated time.Time
+			createdOK, updatedOK := false, false
+			if m := lintCreatedRegex.FindStringSubmatch(line); m != nil {
+				created, createdOK = checkDate(lineNo, "Created", m[1])
+			}
+			if m := lintUpdatedRegex.FindStringSubmatch(line); m != nil {
+				updated, updatedOK = checkDate(lineNo, "Updated", m[1])
+			} else {
+				report(lineNo, "dates line has no Updated date")
+			}
+			if createdOK && updatedOK && updated.Before(created) {
+				report(lineNo, "Updated date %s is before Created date %s",
+					updated.Format(dateFormat), created.Format(dateFormat))
+			}
+		case strings.HasPrefix(line, "- **Last Session**:"):
+			if m := lintLastSessionRegex.FindStringSubmatch(line); m != nil {
+				checkDate(lineNo, "Last Session", m[1])
+			}
+		default:
+			if matches := blockedByRegex.FindStringSubmatch(line); matches != nil {
+				for _, target := range splitComma(matches[1]) {
+					refs = append(refs, blockedByRef{line: lineNo, handoffID: currentID, target: target})
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return issues, refs, nil
+}
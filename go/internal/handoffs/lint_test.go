@@ -0,0 +1,95 @@
+package handoffs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Lint_CleanHandoffs(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	first, _ := store.Add("First", "", false)
+	second, _ := store.Add("Second", "", true)
+	if err := store.Update(second.ID, map[string]interface{}{"blocked_by": []string{first.ID}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	issues, err := Lint(store)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got: %v", issues)
+	}
+}
+
+func Test_Lint_ReportsHandoffProblems(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "HANDOFFS.md")
+	stealthPath := filepath.Join(dir, "HANDOFFS_LOCAL.md")
+
+	project := `# HANDOFFS.md
+
+## Active Handoffs
+
+### [hf-1111111] Good
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-01 | **Updated**: 2026-01-02
+- **Blocked By**: hf-2222222, hf-9999999
+
+---
+
+### [hf-1111111] Duplicate
+- **Status**: not_started | **Phase**: research | **Agent**: user
+- **Created**: 2026-02-30 | **Updated**: 1990-01-01
+
+---
+
+### [handoff-x] Bad ID
+- **Status**: not_started | **Phase**: research | **Agent**: user
+- **Created**: 2026-01-05 | **Updated**: 2026-01-01
+- **Blocked By**: handoff-x
+
+---
+`
+	stealth := `### [hf-2222222] Stealth target
+- **Status**: not_started | **Phase**: research | **Agent**: user
+- **Created**: 2026-01-01 | **Updated**: 2026-01-01
+- **Last Session**: 2026-99-01
+
+---
+`
+	os.WriteFile(projectPath, []byte(project), 0644)
+	os.WriteFile(stealthPath, []byte(stealth), 0644)
+
+	issues, err := Lint(NewStore(projectPath, stealthPath))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	var lines []string
+	for _, i := range issues {
+		lines = append(lines, i.String())
+	}
+	output := strings.Join(lines, "\n")
+
+	want := []string{
+		projectPath + ":8: handoff hf-1111111 is blocked by unknown handoff hf-9999999",
+		projectPath + ":12: duplicate handoff ID hf-1111111 (first defined at " + projectPath + ":5)",
+		projectPath + `:14: invalid Created date "2026-02-30"`,
+		projectPath + ":14: Updated date 1990-01-01 is out of range",
+		projectPath + `:18: invalid handoff ID "handoff-x" (expected hf-XXXXXXX)`,
+		projectPath + ":20: Updated date 2026-01-01 is before Created date 2026-01-05",
+		projectPath + ":21: handoff handoff-x is blocked by itself",
+		stealthPath + `:4: invalid Last Session date "2026-99-01"`,
+	}
+	if len(issues) != len(want) {
+		t.Errorf("expected %d issues, got %d:\n%s", len(want), len(issues), output)
+	}
+	for i, w := range want {
+		if i < len(lines) && lines[i] != w {
+			t.Errorf("issue %d = %q, want %q", i, lines[i], w)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package handoffs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+var (
+	// startMarkerRegex: HANDOFF: title
+	startMarkerRegex = regexp.MustCompile(`^HANDOFF:\s*(.+)$`)
+	// startFieldRegex: an indented field line following a start marker
+	startFieldRegex = regexp.MustCompile(`^\s+(desc|description|phase|refs):\s*(.*)$`)
+)
+
+// StartBlock is a "HANDOFF: title" marker from a transcript, with any
+// indented fields that follow it:
+//
+//	HANDOFF: Title here
+//	  desc: Full description text
+//	  phase: implementing
+//	  refs: file.go:10, other.go:20
+type StartBlock struct {
+	Title       string
+	Description string
+	Phase       string   // Empty unless a valid phase was given
+	Refs        []string // File references
+}
+
+// ParseStartBlocks finds every handoff start marker in text. A block's
+// fields run until the first line that is not an indented field; unknown
+// phases are ignored.
+func ParseStartBlocks(text string) []StartBlock {
+	var blocks []StartBlock
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		matches := startMarkerRegex.FindStringSubmatch(strings.TrimRight(lines[i], "\r"))
+		if matches == nil {
+			continue
+		}
+
+		block := StartBlock{Title: strings.TrimSpace(matches[1])}
+		for i+1 < len(lines) {
+			field := startFieldRegex.FindStringSubmatch(strings.TrimRight(lines[i+1], "\r"))
+			if field == nil {
+				break
+			}
+			value := strings.TrimSpace(field[2])
+			switch field[1] {
+			case "desc", "description":
+				block.Description = value
+			case "phase":
+				if models.IsValidHandoffPhase(value) {
+					block.Phase = value
+				}
+			case "refs":
+				for _, ref := range strings.Split(value, ",") {
+					if ref = strings.TrimSpace(ref); ref != "" {
+						block.Refs = append(block.Refs, ref)
+					}
+				}
+			}
+			i++
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// Updates returns the store updates that apply the block's optional fields
+// to a newly added handoff (nil if there are none)
+func (b StartBlock) Updates() map[string]interface{} {
+	if b.Phase == "" && len(b.Refs) == 0 {
+		return nil
+	}
+	updates := make(map[string]interface{})
+	if b.Phase != "" {
+		updates["phase"] = b.Phase
+		updates["force_phase"] = true
+	}
+	if len(b.Refs) > 0 {
+		updates["refs"] = b.Refs
+	}
+	return updates
+}
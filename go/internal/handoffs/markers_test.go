@@ -0,0 +1,43 @@
+package handoffs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStartBlocks(t *testing.T) {
+	text := `Starting work now.
+HANDOFF: Single line title
+Some prose in between.
+HANDOFF: Multi-line block
+  desc: Full description text
+  phase: implementing
+  refs: file.go:10, other.go:20
+Back to prose.
+HANDOFF: Bad phase
+  phase: daydreaming
+  description: Long form key`
+
+	blocks := ParseStartBlocks(text)
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d: %+v", len(blocks), blocks)
+	}
+
+	if blocks[0].Title != "Single line title" || blocks[0].Description != "" || blocks[0].Updates() != nil {
+		t.Errorf("unexpected single-line block: %+v", blocks[0])
+	}
+
+	want := StartBlock{
+		Title:       "Multi-line block",
+		Description: "Full description text",
+		Phase:       "implementing",
+		Refs:        []string{"file.go:10", "other.go:20"},
+	}
+	if !reflect.DeepEqual(blocks[1], want) {
+		t.Errorf("multi-line block = %+v, want %+v", blocks[1], want)
+	}
+
+	if blocks[2].Phase != "" || blocks[2].Description != "Long form key" {
+		t.Errorf("expected invalid phase dropped, got %+v", blocks[2])
+	}
+}
@@ -2,13 +2,16 @@ package handoffs
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/pbrown/claude-recall/internal/checksum"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
@@ -19,6 +22,10 @@ var (
 	statusRegex = regexp.MustCompile(`^- \*\*Status\*\*: (\w+) \| \*\*Phase\*\*: ([\w-]+) \| \*\*Agent\*\*: ([\w-]+)`)
 	// Dates: - **Created**: 2026-01-15 | **Updated**: 2026-01-20
 	datesRegex = regexp.MustCompile(`^- \*\*Created\*\*: (\d{4}-\d{2}-\d{2}) \| \*\*Updated\*\*: (\d{4}-\d{2}-\d{2})`)
+	// Priority: - **Priority**: high
+	priorityRegex = regexp.MustCompile(`^- \*\*Priority\*\*: (\w+)`)
+	// Stale flag: - **Stale**: true
+	staleRegex = regexp.MustCompile(`^- \*\*Stale\*\*: true`)
 	// Refs: - **Refs**: path:line | path:line
 	refsRegex = regexp.MustCompile(`^- \*\*Refs\*\*: (.+)$`)
 	// Description: - **Description**: text
@@ -41,8 +48,24 @@ var (
 	sessionsRegex = regexp.MustCompile(`^- \*\*Sessions\*\*: (.+)$`)
 	// Tried header
 	triedHeaderRegex = regexp.MustCompile(`^\*\*Tried\*\*:$`)
-	// Tried item: 1. [success] Description
-	triedItemRegex = regexp.MustCompile(`^\d+\. \[(\w+)\] (.+)$`)
+	// Tried item: 1. [success 2026-01-20] Description (date optional)
+	triedItemRegex = regexp.MustCompile(`^\d+\. \[(\w+)(?: (\d{4}-\d{2}-\d{2}))?\] (.+)$`)
+	// Milestones header
+	milestonesHeaderRegex = regexp.MustCompile(`^\*\*Milestones\*\*:$`)
+	// Milestone item: - [x] Name (2026-01-20) or - [ ] Name
+	milestoneItemRegex = regexp.MustCompile(`^- \[( |x)\] (.+?)(?: \((\d{4}-\d{2}-\d{2})\))?$`)
+	// Time log header
+	timeLogHeaderRegex = regexp.MustCompile(`^\*\*Time Log\*\*:$`)
+	// Time log item: - [2026-01-20 14:05] 45m session-abc (session optional)
+	timeLogItemRegex = regexp.MustCompile(`^- \[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\] (\d+)m(?: (\S+))?$`)
+	// Checkpoint history header
+	checkpointHistoryHeaderRegex = regexp.MustCompile(`^\*\*Checkpoint History\*\*:$`)
+	// Checkpoint history item: - [2026-01-20 14:05] implementing: next steps (notes optional)
+	checkpointHistoryItemRegex = regexp.MustCompile(`^- \[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\] (\w+)(?:: (.+))?$`)
+	// Notes header
+	notesHeaderRegex = regexp.MustCompile(`^\*\*Notes\*\*:$`)
+	// Note item: - [2026-01-20 14:05] text (continuation lines indented by two spaces)
+	noteItemRegex = regexp.MustCompile(`^- \[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\] (.*)$`)
 	// Next: **Next**: text
 	nextRegex = regexp.MustCompile(`^\*\*Next\*\*: (.+)$`)
 	// Separator
@@ -51,14 +74,44 @@ var (
 
 const dateFormat = "2006-01-02"
 
+// noteTimeFormat keeps minutes so notes added the same day stay ordered
+const noteTimeFormat = "2006-01-02 15:04"
+
+// ParseOptions controls optional checks made while parsing
+type ParseOptions struct {
+	// VerifyChecksum fails parsing with *checksum.ErrChecksumMismatch when
+	// the file's checksum footer doesn't match its content
+	VerifyChecksum bool
+}
+
 // ParseFile reads and parses a HANDOFFS.md file
 func ParseFile(path string) ([]*models.Handoff, error) {
+	return ParseFileWithOptions(path, ParseOptions{})
+}
+
+// ParseFileWithOptions reads and parses a HANDOFFS.md file with opts
+func ParseFileWithOptions(path string, opts ParseOptions) ([]*models.Handoff, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	return Parse(f)
+	return ParseWithOptions(f, opts)
+}
+
+// ParseWithOptions parses HANDOFFS.md content from a reader with opts
+func ParseWithOptions(r io.Reader, opts ParseOptions) ([]*models.Handoff, error) {
+	if opts.VerifyChecksum {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := checksum.Verify(data); err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	return Parse(r)
 }
 
 // Parse parses HANDOFFS.md content from a reader
@@ -67,6 +120,11 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 	var current *models.Handoff
 	var inTried bool
 	var inHandoffCtx bool
+	var inNotes bool
+	var inMilestones bool
+	var inTimeLog bool
+	var inCheckpoints bool
+	var note *models.HandoffNote // Note being read (ended by a blank line)
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -80,6 +138,11 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 			current = models.NewHandoff(matches[1], matches[2])
 			inTried = false
 			inHandoffCtx = false
+			inNotes = false
+			inMilestones = false
+			inTimeLog = false
+			inCheckpoints = false
+			note = nil
 			continue
 		}
 
@@ -95,9 +158,34 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 			}
 			inTried = false
 			inHandoffCtx = false
+			inNotes = false
+			inMilestones = false
+			inTimeLog = false
+			inCheckpoints = false
+			note = nil
 			continue
 		}
 
+		// Notes: each starts with a timestamped bullet and runs to a blank line
+		if inNotes {
+			if line == "" {
+				note = nil
+				continue
+			}
+			if matches := noteItemRegex.FindStringSubmatch(line); matches != nil {
+				t, _ := time.ParseInLocation(noteTimeFormat, matches[1], time.Local)
+				current.Notes = append(current.Notes, models.HandoffNote{Timestamp: t, Text: matches[2]})
+				note = &current.Notes[len(current.Notes)-1]
+				continue
+			}
+			if note != nil && strings.HasPrefix(line, "  ") {
+				note.Text += "\n" + strings.TrimPrefix(line, "  ")
+				continue
+			}
+			inNotes = false
+			note = nil
+		}
+
 		// Status line
 		if matches := statusRegex.FindStringSubmatch(line); matches != nil {
 			current.Status = matches[1]
@@ -117,6 +205,18 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 			continue
 		}
 
+		// Priority line
+		if matches := priorityRegex.FindStringSubmatch(line); matches != nil {
+			current.Priority = matches[1]
+			continue
+		}
+
+		// Stale flag
+		if staleRegex.MatchString(line) {
+			current.Stale = true
+			continue
+		}
+
 		// Refs line
 		if matches := refsRegex.FindStringSubmatch(line); matches != nil {
 			refs := strings.Split(matches[1], " | ")
@@ -208,13 +308,83 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 			continue
 		}
 
+		// Milestones header
+		if milestonesHeaderRegex.MatchString(line) {
+			inMilestones = true
+			inTried = false
+			continue
+		}
+
+		// Time log header
+		if timeLogHeaderRegex.MatchString(line) {
+			inTimeLog = true
+			inTried = false
+			inMilestones = false
+			inCheckpoints = false
+			continue
+		}
+
+		// Checkpoint history header
+		if checkpointHistoryHeaderRegex.MatchString(line) {
+			inCheckpoints = true
+			inTried = false
+			inMilestones = false
+			inTimeLog = false
+			continue
+		}
+
+		// Notes header
+		if notesHeaderRegex.MatchString(line) {
+			inNotes = true
+			inTried = false
+			inMilestones = false
+			inTimeLog = false
+			inCheckpoints = false
+			continue
+		}
+
+		// Checkpoint history items
+		if inCheckpoints {
+			if matches := checkpointHistoryItemRegex.FindStringSubmatch(line); matches != nil {
+				t, _ := time.ParseInLocation(noteTimeFormat, matches[1], time.Local)
+				current.CheckpointHistory = append(current.CheckpointHistory, models.CheckpointEntry{Timestamp: t, Phase: matches[2], Notes: matches[3]})
+				continue
+			}
+		}
+
+		// Time log items
+		if inTimeLog {
+			if matches := timeLogItemRegex.FindStringSubmatch(line); matches != nil {
+				t, _ := time.ParseInLocation(noteTimeFormat, matches[1], time.Local)
+				minutes, _ := strconv.Atoi(matches[2])
+				current.TimeLog = append(current.TimeLog, models.TimeEntry{Timestamp: t, Minutes: minutes, SessionID: matches[3]})
+				continue
+			}
+		}
+
+		// Milestone items
+		if inMilestones {
+			if matches := milestoneItemRegex.FindStringSubmatch(line); matches != nil {
+				m := models.Milestone{Name: matches[2], Completed: matches[1] == "x"}
+				if t, err := time.Parse(dateFormat, matches[3]); err == nil && m.Completed {
+					m.CompletedAt = &t
+				}
+				current.Milestones = append(current.Milestones, m)
+				continue
+			}
+		}
+
 		// Tried items
 		if inTried {
 			if matches := triedItemRegex.FindStringSubmatch(line); matches != nil {
-				current.Tried = append(current.Tried, models.TriedStep{
+				step := models.TriedStep{
 					Outcome:     matches[1],
-					Description: matches[2],
-				})
+					Description: matches[3],
+				}
+				if t, err := time.Parse(dateFormat, matches[2]); err == nil {
+					step.Timestamp = t
+				}
+				current.Tried = append(current.Tried, step)
 				continue
 			}
 			// Empty line or non-matching line ends tried section
@@ -227,6 +397,9 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 		if matches := nextRegex.FindStringSubmatch(line); matches != nil {
 			current.NextSteps = matches[1]
 			inTried = false
+			inMilestones = false
+			inTimeLog = false
+			inCheckpoints = false
 			continue
 		}
 	}
@@ -240,10 +413,20 @@ func Parse(r io.Reader) ([]*models.Handoff, error) {
 		handoffs = append(handoffs, current)
 	}
 
+	// Tried steps written before timestamps existed date from the last update
+	for _, h := range handoffs {
+		for i := range h.Tried {
+			if h.Tried[i].Timestamp.IsZero() {
+				h.Tried[i].Timestamp = h.Updated
+			}
+		}
+	}
+
 	return handoffs, nil
 }
 
-// Serialize writes handoffs back to HANDOFFS.md format
+// Serialize writes handoffs back to HANDOFFS.md format, ending with a
+// checksum footer over everything above it
 func Serialize(handoffs []*models.Handoff) string {
 	var sb strings.Builder
 
@@ -256,7 +439,7 @@ func Serialize(handoffs []*models.Handoff) string {
 		sb.WriteString(SerializeHandoff(h))
 	}
 
-	return sb.String()
+	return checksum.Append(sb.String())
 }
 
 // SerializeHandoff formats a single handoff entry
@@ -274,6 +457,16 @@ func SerializeHandoff(h *models.Handoff) string {
 	sb.WriteString(fmt.Sprintf("- **Created**: %s | **Updated**: %s\n",
 		h.Created.Format(dateFormat), h.Updated.Format(dateFormat)))
 
+	// Priority
+	if h.Priority != "" {
+		sb.WriteString(fmt.Sprintf("- **Priority**: %s\n", h.Priority))
+	}
+
+	// Stale flag (optional)
+	if h.Stale {
+		sb.WriteString("- **Stale**: true\n")
+	}
+
 	// Refs (optional)
 	if len(h.Refs) > 0 {
 		sb.WriteString(fmt.Sprintf("- **Refs**: %s\n", strings.Join(h.Refs, " | ")))
@@ -326,7 +519,68 @@ func SerializeHandoff(h *models.Handoff) string {
 	if len(h.Tried) > 0 {
 		sb.WriteString("\n**Tried**:\n")
 		for i, step := range h.Tried {
-			sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, step.Outcome, step.Description))
+			outcome := step.Outcome
+			if !step.Timestamp.IsZero() {
+				outcome += " " + step.Timestamp.Format(dateFormat)
+			}
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, outcome, step.Description))
+		}
+	}
+
+	// Milestones section
+	if len(h.Milestones) > 0 {
+		sb.WriteString("\n**Milestones**:\n")
+		for _, m := range h.Milestones {
+			if !m.Completed {
+				sb.WriteString(fmt.Sprintf("- [ ] %s\n", m.Name))
+				continue
+			}
+			if m.CompletedAt != nil {
+				sb.WriteString(fmt.Sprintf("- [x] %s (%s)\n", m.Name, m.CompletedAt.Format(dateFormat)))
+			} else {
+				sb.WriteString(fmt.Sprintf("- [x] %s\n", m.Name))
+			}
+		}
+	}
+
+	// Time log section
+	if len(h.TimeLog) > 0 {
+		sb.WriteString("\n**Time Log**:\n")
+		for _, e := range h.TimeLog {
+			entry := fmt.Sprintf("- [%s] %dm", e.Timestamp.Format(noteTimeFormat), e.Minutes)
+			if e.SessionID != "" {
+				entry += " " + e.SessionID
+			}
+			sb.WriteString(entry + "\n")
+		}
+	}
+
+	// Checkpoint history section
+	if len(h.CheckpointHistory) > 0 {
+		sb.WriteString("\n**Checkpoint History**:\n")
+		for _, e := range h.CheckpointHistory {
+			entry := fmt.Sprintf("- [%s] %s", e.Timestamp.Format(noteTimeFormat), e.Phase)
+			if notes := strings.Join(strings.Fields(e.Notes), " "); notes != "" {
+				entry += ": " + notes
+			}
+			sb.WriteString(entry + "\n")
+		}
+	}
+
+	// Notes section (a blank line ends each note)
+	if len(h.Notes) > 0 {
+		sb.WriteString("\n**Notes**:\n")
+		for i, n := range h.Notes {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			var lines []string
+			for _, l := range strings.Split(n.Text, "\n") {
+				if strings.TrimSpace(l) != "" {
+					lines = append(lines, l)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", n.Timestamp.Format(noteTimeFormat), strings.Join(lines, "\n  ")))
 		}
 	}
 
@@ -1,10 +1,14 @@
 package handoffs
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pbrown/claude-recall/internal/checksum"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
@@ -445,3 +449,231 @@ func TestParse_HeaderOnly(t *testing.T) {
 		t.Errorf("Expected 0 handoffs, got %d", len(handoffs))
 	}
 }
+
+func TestSerialize_PriorityRoundTrip(t *testing.T) {
+	h := models.NewHandoff("hf-1234567", "Urgent fix")
+	h.Priority = "critical"
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "- **Priority**: critical\n") {
+		t.Errorf("Expected Priority line, got:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed[0].Priority != "critical" {
+		t.Errorf("Expected priority 'critical', got %q", parsed[0].Priority)
+	}
+}
+
+func TestParse_MissingPriorityDefaultsToMedium(t *testing.T) {
+	input := `### [hf-1234567] Legacy handoff
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-15 | **Updated**: 2026-01-20
+- **Description**: Written before priorities existed
+`
+
+	parsed, err := Parse(strings.NewReader(input))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed[0].Priority != "medium" {
+		t.Errorf("Expected default priority 'medium', got %q", parsed[0].Priority)
+	}
+}
+
+func TestParse_TriedStepTimestamps(t *testing.T) {
+	input := `### [hf-a1b2c3d] Dated Steps
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-15 | **Updated**: 2026-01-20
+
+**Tried**:
+1. [fail 2026-01-16] Regex approach
+2. [success] Undated legacy step
+
+**Next**: Ship it.
+
+---
+`
+
+	handoffs, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	h := handoffs[0]
+
+	if got := h.Tried[0].Timestamp.Format(dateFormat); got != "2026-01-16" {
+		t.Errorf("Expected dated step at 2026-01-16, got %s", got)
+	}
+	if h.Tried[0].Outcome != "fail" || h.Tried[0].Description != "Regex approach" {
+		t.Errorf("Unexpected dated step: %+v", h.Tried[0])
+	}
+	// Missing timestamp falls back to the handoff's Updated date
+	if got := h.Tried[1].Timestamp.Format(dateFormat); got != "2026-01-20" {
+		t.Errorf("Expected undated step to fall back to 2026-01-20, got %s", got)
+	}
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "1. [fail 2026-01-16] Regex approach\n2. [success 2026-01-20] Undated legacy step\n") {
+		t.Errorf("Expected dated tried steps in output, got:\n%s", output)
+	}
+}
+
+func TestSerialize_NotesRoundTrip(t *testing.T) {
+	h := models.NewHandoff("hf-1234567", "Noted work")
+	h.Tried = []models.TriedStep{{Outcome: "fail", Description: "First try", Timestamp: time.Date(2026, 1, 16, 0, 0, 0, 0, time.Local)}}
+	h.NextSteps = "Keep going"
+	h.Notes = []models.HandoffNote{
+		{Timestamp: time.Date(2026, 1, 16, 9, 30, 0, 0, time.Local), Text: "Vendor API is rate limited\nAsk for a higher quota"},
+		{Timestamp: time.Date(2026, 1, 17, 14, 5, 0, 0, time.Local), Text: "Quota approved"},
+	}
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "**Notes**:\n- [2026-01-16 09:30] Vendor API is rate limited\n  Ask for a higher quota\n\n- [2026-01-17 14:05] Quota approved\n") {
+		t.Errorf("Unexpected notes section:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := parsed[0]
+	if len(got.Notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(got.Notes))
+	}
+	for i, want := range h.Notes {
+		if got.Notes[i].Text != want.Text || !got.Notes[i].Timestamp.Equal(want.Timestamp) {
+			t.Errorf("Note %d = %+v, want %+v", i, got.Notes[i], want)
+		}
+	}
+	if len(got.Tried) != 1 || got.NextSteps != "Keep going" {
+		t.Errorf("Notes should not disturb tried/next: %+v", got)
+	}
+}
+
+func TestSerialize_MilestonesRoundTrip(t *testing.T) {
+	done := time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)
+	h := models.NewHandoff("hf-7654321", "Staged rollout")
+	h.NextSteps = "Ship it"
+	h.Milestones = []models.Milestone{
+		{Name: "Schema migrated", Completed: true, CompletedAt: &done},
+		{Name: "Canary at 5% (EU)"},
+	}
+	h.Notes = []models.HandoffNote{{Timestamp: time.Date(2026, 1, 17, 9, 0, 0, 0, time.Local), Text: "Watch error budget"}}
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "**Milestones**:\n- [x] Schema migrated (2026-01-18)\n- [ ] Canary at 5% (EU)\n") {
+		t.Errorf("Unexpected milestones section:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := parsed[0]
+	if len(got.Milestones) != 2 {
+		t.Fatalf("Expected 2 milestones, got %d", len(got.Milestones))
+	}
+	if m := got.Milestones[0]; m.Name != "Schema migrated" || !m.Completed || m.CompletedAt == nil || m.CompletedAt.Format(dateFormat) != "2026-01-18" {
+		t.Errorf("Unexpected completed milestone: %+v", m)
+	}
+	if m := got.Milestones[1]; m.Name != "Canary at 5% (EU)" || m.Completed || m.CompletedAt != nil {
+		t.Errorf("Unexpected pending milestone: %+v", m)
+	}
+	if len(got.Notes) != 1 || got.NextSteps != "Ship it" {
+		t.Errorf("Milestones should not disturb notes/next: %+v", got)
+	}
+}
+
+func TestSerialize_TimeLogRoundTrip(t *testing.T) {
+	h := models.NewHandoff("hf-2468ace", "Tracked work")
+	h.NextSteps = "Keep going"
+	h.TimeLog = []models.TimeEntry{
+		{Timestamp: time.Date(2026, 1, 16, 9, 30, 0, 0, time.Local), Minutes: 45, SessionID: "sess-a"},
+		{Timestamp: time.Date(2026, 1, 17, 14, 5, 0, 0, time.Local), Minutes: 90},
+	}
+	h.Milestones = []models.Milestone{{Name: "Spike done"}}
+	h.Notes = []models.HandoffNote{{Timestamp: time.Date(2026, 1, 17, 15, 0, 0, 0, time.Local), Text: "Looks good"}}
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "**Time Log**:\n- [2026-01-16 09:30] 45m sess-a\n- [2026-01-17 14:05] 90m\n") {
+		t.Errorf("Unexpected time log section:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := parsed[0]
+	if len(got.TimeLog) != 2 {
+		t.Fatalf("Expected 2 time entries, got %d", len(got.TimeLog))
+	}
+	for i, want := range h.TimeLog {
+		if e := got.TimeLog[i]; e.Minutes != want.Minutes || e.SessionID != want.SessionID || !e.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("Time entry %d = %+v, want %+v", i, e, want)
+		}
+	}
+	if got.TotalMinutes() != 135 {
+		t.Errorf("Expected 135 total minutes, got %d", got.TotalMinutes())
+	}
+	if len(got.Milestones) != 1 || len(got.Notes) != 1 || got.NextSteps != "Keep going" {
+		t.Errorf("Time log should not disturb milestones/notes/next: %+v", got)
+	}
+}
+
+func TestParseFile_VerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HANDOFFS.md")
+	h := models.NewHandoff("hf-0000001", "Checksummed")
+	h.Description = "Intact description"
+	data := []byte(Serialize([]*models.Handoff{h}))
+	os.WriteFile(path, data, 0644)
+
+	opts := ParseOptions{VerifyChecksum: true}
+	if parsed, err := ParseFileWithOptions(path, opts); err != nil || len(parsed) != 1 {
+		t.Fatalf("expected intact file to parse, got %v (%d handoffs)", err, len(parsed))
+	}
+
+	// Corrupt one byte of the content
+	i := strings.Index(string(data), "Intact")
+	data[i] = 'X'
+	os.WriteFile(path, data, 0644)
+
+	var mismatch *checksum.ErrChecksumMismatch
+	if _, err := ParseFileWithOptions(path, opts); !errors.As(err, &mismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
+
+func TestSerialize_CheckpointHistoryRoundTrip(t *testing.T) {
+	h := models.NewHandoff("hf-1357bdf", "Phased work")
+	h.NextSteps = "Ship it"
+	h.CheckpointHistory = []models.CheckpointEntry{
+		{Timestamp: time.Date(2026, 1, 16, 9, 30, 0, 0, time.Local), Phase: "planning", Notes: "Read the RFC"},
+		{Timestamp: time.Date(2026, 1, 17, 14, 5, 0, 0, time.Local), Phase: "implementing"},
+	}
+	h.Notes = []models.HandoffNote{{Timestamp: time.Date(2026, 1, 17, 15, 0, 0, 0, time.Local), Text: "Looks good"}}
+
+	output := SerializeHandoff(h)
+	if !strings.Contains(output, "**Checkpoint History**:\n- [2026-01-16 09:30] planning: Read the RFC\n- [2026-01-17 14:05] implementing\n") {
+		t.Errorf("Unexpected checkpoint history section:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := parsed[0]
+	if len(got.CheckpointHistory) != 2 {
+		t.Fatalf("Expected 2 checkpoint entries, got %d", len(got.CheckpointHistory))
+	}
+	for i, want := range h.CheckpointHistory {
+		if e := got.CheckpointHistory[i]; e.Phase != want.Phase || e.Notes != want.Notes || !e.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("Checkpoint entry %d = %+v, want %+v", i, e, want)
+		}
+	}
+	if len(got.Notes) != 1 || got.NextSteps != "Ship it" {
+		t.Errorf("Checkpoint history should not disturb notes/next: %+v", got)
+	}
+}
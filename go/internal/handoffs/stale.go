@@ -0,0 +1,82 @@
+package handoffs
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// ListStale returns active handoffs not updated since cutoff, least
+// recently updated first
+func (s *Store) ListStale(cutoff time.Time) ([]*models.Handoff, error) {
+	active, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []*models.Handoff
+	for _, h := range active {
+		if h.Updated.Before(cutoff) {
+			stale = append(stale, h)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].Updated.Before(stale[j].Updated)
+	})
+	return stale, nil
+}
+
+// FlagStale sets the Stale flag on the given handoffs without touching
+// their Updated date. Returns how many were newly flagged.
+func (s *Store) FlagStale(ids []string) (int, error) {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	flagged := 0
+	for _, file := range []struct {
+		path    string
+		stealth bool
+	}{{s.projectPath, false}, {s.stealthPath, true}} {
+		n, err := s.flagStaleIn(file.path, file.stealth, want)
+		if err != nil {
+			return flagged, err
+		}
+		flagged += n
+	}
+	return flagged, nil
+}
+
+// flagStaleIn applies FlagStale to one handoffs file under its lock
+func (s *Store) flagStaleIn(path string, stealth bool, want map[string]bool) (int, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for _, h := range handoffs {
+		if want[h.ID] && !h.Stale {
+			h.Stale = true
+			flagged++
+		}
+	}
+	if flagged == 0 {
+		return 0, nil
+	}
+	return flagged, s.writeHandoffs(path, handoffs)
+}
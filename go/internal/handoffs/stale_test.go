@@ -0,0 +1,64 @@
+package handoffs
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Store_ListAndFlagStale(t *testing.T) {
+	store, ids := newDepsTestStore(t, 3)
+	fresh, old, done := ids[0], ids[1], ids[2]
+
+	// Backdate two handoffs; the completed one must never count as stale
+	y, m, d := time.Now().AddDate(0, 0, -30).Date()
+	monthAgo := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	handoffs, _ := store.loadHandoffs(store.projectPath, false)
+	for _, h := range handoffs {
+		switch h.ID {
+		case old:
+			h.Updated = monthAgo
+		case done:
+			h.Updated = monthAgo
+			h.Status = "completed"
+		}
+	}
+	if err := store.writeHandoffs(store.projectPath, handoffs); err != nil {
+		t.Fatalf("writeHandoffs failed: %v", err)
+	}
+
+	stale, err := store.ListStale(time.Now().AddDate(0, 0, -14))
+	if err != nil {
+		t.Fatalf("ListStale failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != old {
+		t.Fatalf("expected only %s stale, got %v", old, stale)
+	}
+
+	if n, err := store.FlagStale([]string{old}); err != nil || n != 1 {
+		t.Fatalf("FlagStale = %d, %v; want 1, nil", n, err)
+	}
+	if n, _ := store.FlagStale([]string{old}); n != 0 {
+		t.Errorf("expected re-flagging to be a no-op, got %d", n)
+	}
+	h, _ := store.Get(old)
+	if !h.Stale || !h.Updated.Equal(monthAgo) {
+		t.Errorf("expected flagged with Updated kept at %s, got stale=%v updated=%s", monthAgo, h.Stale, h.Updated)
+	}
+	data, _ := os.ReadFile(store.projectPath)
+	if !strings.Contains(string(data), "- **Stale**: true\n") {
+		t.Errorf("expected stale flag in file:\n%s", data)
+	}
+
+	// Any update clears the flag
+	if err := store.Update(old, map[string]interface{}{"status": "in_progress"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if h, _ := store.Get(old); h.Stale {
+		t.Error("expected update to clear the stale flag")
+	}
+	if h, _ := store.Get(fresh); h.Stale {
+		t.Error("expected fresh handoff never flagged")
+	}
+}
@@ -7,16 +7,32 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pbrown/claude-recall/internal/lock"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
+// Status events passed to a Notifier
+const (
+	EventStatusChange = "status_change" // Any status change
+	EventComplete     = "complete"      // Status changed to completed (follows status_change)
+)
+
+// writeFile writes handoff files (replaced in tests)
+var writeFile = os.WriteFile
+
+// Notifier is called after a handoff's status change has been written, once
+// per event, with a snapshot of the handoff as written
+type Notifier func(event string, h models.Handoff)
+
 // Store manages handoffs in project and stealth HANDOFFS.md files
 type Store struct {
-	projectPath string // Path to HANDOFFS.md
-	stealthPath string // Path to HANDOFFS_LOCAL.md (stealth handoffs)
+	projectPath string   // Path to HANDOFFS.md
+	stealthPath string   // Path to HANDOFFS_LOCAL.md (stealth handoffs)
+	notify      Notifier // Optional status change callback
 }
 
 // NewStore creates a store with paths to handoff files
@@ -27,6 +43,24 @@ func NewStore(projectPath, stealthPath string) *Store {
 	}
 }
 
+// SetNotifier registers a callback for status changes made through this
+// store (nil disables)
+func (s *Store) SetNotifier(n Notifier) {
+	s.notify = n
+}
+
+// notifyStatus reports h's status change from oldStatus, if any. Callers
+// release the file lock first so a slow notifier never blocks other writers.
+func (s *Store) notifyStatus(h models.Handoff, oldStatus string) {
+	if s.notify == nil || h.Status == oldStatus {
+		return
+	}
+	s.notify(EventStatusChange, h)
+	if h.Status == "completed" {
+		s.notify(EventComplete, h)
+	}
+}
+
 // List returns all active (non-completed) handoffs
 func (s *Store) List() ([]*models.Handoff, error) {
 	all, err := s.ListAll()
@@ -128,7 +162,10 @@ func (s *Store) Add(title, description string, stealth bool) (*models.Handoff, e
 	return handoff, nil
 }
 
-// Update modifies an existing handoff
+// Update modifies an existing handoff. A phase change must be allowed by
+// models.HandoffPhaseTransitions unless updates["force_phase"] is true.
+// Completing a handoff releases the handoffs it was blocking (see
+// releaseBlockers): those in the same file in the same locked write.
 func (s *Store) Update(id string, updates map[string]interface{}) error {
 	// Find the handoff and its file
 	path, stealth, err := s.findHandoffFile(id)
@@ -144,6 +181,17 @@ func (s *Store) Update(id string, updates map[string]interface{}) error {
 	}
 	defer fl.Release()
 
+	if priority, ok := updates["priority"].(string); ok && !models.IsValidPriority(priority) {
+		return fmt.Errorf("invalid priority %q (use critical, high, medium, or low)", priority)
+	}
+
+	// Reject unknown blockers and dependency cycles before touching the file
+	if blockedBy, ok := updates["blocked_by"].([]string); ok {
+		if err := s.validateBlockedBy(id, blockedBy); err != nil {
+			return err
+		}
+	}
+
 	// Load handoffs
 	handoffs, err := s.loadHandoffs(path, stealth)
 	if err != nil {
@@ -152,10 +200,34 @@ func (s *Store) Update(id string, updates map[string]interface{}) error {
 
 	// Find and update the handoff
 	found := false
+	completed := false
+	var updated models.Handoff
+	var oldStatus string
 	for _, h := range handoffs {
 		if h.ID == id {
+			if phase, ok := updates["phase"].(string); ok {
+				if force, _ := updates["force_phase"].(bool); force {
+					if !models.IsValidHandoffPhase(phase) {
+						return fmt.Errorf("invalid phase %q", phase)
+					}
+				} else if err := models.ValidatePhaseTransition(h.Phase, phase); err != nil {
+					return err
+				}
+			}
+			oldStatus = h.Status
+			oldPhase, oldNextSteps := h.Phase, h.NextSteps
 			applyHandoffUpdates(h, updates)
 			h.Updated = time.Now()
+			if h.Phase != oldPhase {
+				h.CheckpointHistory = append(h.CheckpointHistory, models.CheckpointEntry{
+					Timestamp: h.Updated,
+					Phase:     h.Phase,
+					Notes:     oldNextSteps,
+				})
+			}
+			h.Stale = false
+			completed = oldStatus != "completed" && h.Status == "completed"
+			updated = *h
 			found = true
 			break
 		}
@@ -165,8 +237,26 @@ func (s *Store) Update(id string, updates map[string]interface{}) error {
 		return fmt.Errorf("handoff %s not found", id)
 	}
 
+	var unblocked []models.Handoff
+	if completed {
+		_, unblocked = releaseBlockers(handoffs, id)
+	}
+
 	// Write back
-	return s.writeHandoffs(path, handoffs)
+	if err := s.writeHandoffs(path, handoffs); err != nil {
+		return err
+	}
+	fl.Release()
+	s.notifyStatus(updated, oldStatus)
+	if !completed {
+		return nil
+	}
+	for _, h := range unblocked {
+		s.notifyStatus(h, "blocked")
+	}
+
+	// Dependents in the other file are released under that file's lock
+	return s.resolveBlockers(id, path)
 }
 
 // AddTriedStep adds a tried step to a handoff
@@ -200,11 +290,14 @@ func (s *Store) AddTriedStep(id, outcome, description string) error {
 	found := false
 	for _, h := range handoffs {
 		if h.ID == id {
+			now := time.Now()
 			h.Tried = append(h.Tried, models.TriedStep{
 				Outcome:     outcome,
 				Description: description,
+				Timestamp:   now,
 			})
-			h.Updated = time.Now()
+			h.Updated = now
+			h.Stale = false
 			found = true
 			break
 		}
@@ -218,7 +311,186 @@ func (s *Store) AddTriedStep(id, outcome, description string) error {
 	return s.writeHandoffs(path, handoffs)
 }
 
-// Complete marks a handoff as completed
+// AddNote appends a timestamped free-form note to a handoff
+func (s *Store) AddNote(id, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+
+	// Find the handoff and its file
+	path, stealth, err := s.findHandoffFile(id)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	// Load handoffs
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return err
+	}
+
+	// Find and update the handoff
+	for _, h := range handoffs {
+		if h.ID == id {
+			now := time.Now()
+			h.Notes = append(h.Notes, models.HandoffNote{Timestamp: now, Text: strings.TrimSpace(text)})
+			h.Updated = now
+			h.Stale = false
+			return s.writeHandoffs(path, handoffs)
+		}
+	}
+
+	return fmt.Errorf("handoff %s not found", id)
+}
+
+// LogTime records minutes spent on a handoff, optionally tied to a session
+func (s *Store) LogTime(id, sessionID string, minutes int) error {
+	if minutes <= 0 {
+		return fmt.Errorf("minutes must be positive, got %d", minutes)
+	}
+	if strings.ContainsAny(sessionID, " \t\n") {
+		return fmt.Errorf("session ID cannot contain whitespace: %q", sessionID)
+	}
+
+	// Find the handoff and its file
+	path, stealth, err := s.findHandoffFile(id)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	// Load handoffs
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range handoffs {
+		if h.ID == id {
+			now := time.Now()
+			h.TimeLog = append(h.TimeLog, models.TimeEntry{Timestamp: now, Minutes: minutes, SessionID: sessionID})
+			h.Updated = now
+			h.Stale = false
+			return s.writeHandoffs(path, handoffs)
+		}
+	}
+
+	return fmt.Errorf("handoff %s not found", id)
+}
+
+// AddMilestone appends a pending milestone to a handoff. Milestone names
+// must be unique within the handoff.
+func (s *Store) AddMilestone(id, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("milestone name cannot be empty")
+	}
+
+	// Find the handoff and its file
+	path, stealth, err := s.findHandoffFile(id)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	// Load handoffs
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return err
+	}
+
+	// Find and update the handoff
+	for _, h := range handoffs {
+		if h.ID == id {
+			for _, m := range h.Milestones {
+				if m.Name == name {
+					return fmt.Errorf("milestone %q already exists on %s", name, id)
+				}
+			}
+			h.Milestones = append(h.Milestones, models.Milestone{Name: name})
+			h.Updated = time.Now()
+			h.Stale = false
+			return s.writeHandoffs(path, handoffs)
+		}
+	}
+
+	return fmt.Errorf("handoff %s not found", id)
+}
+
+// CompleteMilestone marks a handoff's milestone as completed
+func (s *Store) CompleteMilestone(id, name string) error {
+	name = strings.TrimSpace(name)
+
+	// Find the handoff and its file
+	path, stealth, err := s.findHandoffFile(id)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	// Load handoffs
+	handoffs, err := s.loadHandoffs(path, stealth)
+	if err != nil {
+		return err
+	}
+
+	// Find and update the milestone
+	for _, h := range handoffs {
+		if h.ID != id {
+			continue
+		}
+		for i := range h.Milestones {
+			m := &h.Milestones[i]
+			if m.Name != name {
+				continue
+			}
+			if m.Completed {
+				return fmt.Errorf("milestone %q is already completed", name)
+			}
+			now := time.Now()
+			m.Completed = true
+			m.CompletedAt = &now
+			h.Updated = now
+			h.Stale = false
+			return s.writeHandoffs(path, handoffs)
+		}
+		return fmt.Errorf("milestone %q not found on %s", name, id)
+	}
+
+	return fmt.Errorf("handoff %s not found", id)
+}
+
+// Complete marks a handoff as completed and releases the handoffs it was
+// blocking, like Update with status completed
 func (s *Store) Complete(id string) error {
 	// Find the handoff and its file
 	path, stealth, err := s.findHandoffFile(id)
@@ -242,10 +514,15 @@ func (s *Store) Complete(id string) error {
 
 	// Find and update the handoff
 	found := false
+	var completed models.Handoff
+	var oldStatus string
 	for _, h := range handoffs {
 		if h.ID == id {
+			oldStatus = h.Status
 			h.Status = "completed"
 			h.Updated = time.Now()
+			h.Stale = false
+			completed = *h
 			found = true
 			break
 		}
@@ -255,33 +532,150 @@ func (s *Store) Complete(id string) error {
 		return fmt.Errorf("handoff %s not found", id)
 	}
 
+	var unblocked []models.Handoff
+	if oldStatus != "completed" {
+		_, unblocked = releaseBlockers(handoffs, id)
+	}
+
 	// Write back
-	return s.writeHandoffs(path, handoffs)
+	if err := s.writeHandoffs(path, handoffs); err != nil {
+		return err
+	}
+	fl.Release()
+	s.notifyStatus(completed, oldStatus)
+	if oldStatus == "completed" {
+		return nil
+	}
+	for _, h := range unblocked {
+		s.notifyStatus(h, "blocked")
+	}
+
+	// Dependents in the other file are released under that file's lock
+	return s.resolveBlockers(id, path)
 }
 
-// Archive removes old completed handoffs (keep last N or within N days)
-func (s *Store) Archive() (int, error) {
-	archived := 0
+// Clone copies a handoff under a new ID into the same file. Planning fields
+// (description, phase, agent, priority, refs, next steps, blockers) are kept;
+// status resets to not_started and tried steps, sessions, and session context
+// are cleared. An empty newTitle keeps the original title.
+func (s *Store) Clone(id string, newTitle string) (*models.Handoff, error) {
+	path, stealth, err := s.findHandoffFile(id)
+	if err != nil {
+		return nil, err
+	}
 
-	// Archive from project file
-	n, err := s.archiveFile(s.projectPath, false)
+	// Acquire lock
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
 	if err != nil {
-		return archived, err
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
 	}
-	archived += n
+	defer fl.Release()
 
-	// Archive from stealth file
-	n, err = s.archiveFile(s.stealthPath, true)
+	// Load handoffs
+	handoffs, err := s.loadHandoffs(path, stealth)
 	if err != nil {
-		return archived, err
+		return nil, err
 	}
-	archived += n
 
+	var orig *models.Handoff
+	for _, h := range handoffs {
+		if h.ID == id {
+			orig = h
+			break
+		}
+	}
+	if orig == nil {
+		return nil, fmt.Errorf("handoff %s not found", id)
+	}
+
+	title := orig.Title
+	if newTitle != "" {
+		title = newTitle
+	}
+
+	clone := models.NewHandoff(GenerateID(), title)
+	clone.Description = orig.Description
+	clone.NextSteps = orig.NextSteps
+	clone.Phase = orig.Phase
+	clone.Agent = orig.Agent
+	clone.Priority = orig.Priority
+	clone.Refs = append(clone.Refs, orig.Refs...)
+	clone.BlockedBy = append(clone.BlockedBy, orig.BlockedBy...)
+	clone.Stealth = orig.Stealth
+
+	handoffs = append(handoffs, clone)
+	if err := s.writeHandoffs(path, handoffs); err != nil {
+		return nil, fmt.Errorf("failed to write handoffs: %w", err)
+	}
+
+	return clone, nil
+}
+
+// ArchiveOptions controls which completed handoffs Archive removes
+type ArchiveOptions struct {
+	MaxAge      time.Duration // Archive completed handoffs not updated within this long
+	KeepMin     int           // Always keep this many most recent completed handoffs
+	StealthOnly bool          // Only archive from the stealth file
+}
+
+// DefaultArchiveOptions returns the HandoffMaxAgeDays / HandoffMaxCompleted policy
+func DefaultArchiveOptions() ArchiveOptions {
+	return ArchiveOptions{
+		MaxAge:  time.Duration(models.HandoffMaxAgeDays) * 24 * time.Hour,
+		KeepMin: models.HandoffMaxCompleted,
+	}
+}
+
+// Archive removes old completed handoffs (keep last N or within N days)
+func (s *Store) Archive() (int, error) {
+	return s.ArchiveWith(DefaultArchiveOptions())
+}
+
+// ArchiveWith removes completed handoffs older than opts.MaxAge, keeping at
+// least the opts.KeepMin most recent ones
+func (s *Store) ArchiveWith(opts ArchiveOptions) (int, error) {
+	type handoffFile struct {
+		path    string
+		stealth bool
+	}
+	files := []handoffFile{{s.stealthPath, true}}
+	if !opts.StealthOnly {
+		files = append(files, handoffFile{s.projectPath, false})
+	}
+
+	// The files are independent (each has its own lock), so archive them
+	// in parallel
+	counts := make([]int, len(files))
+	errs := make(chan error, len(files))
+	var wg sync.WaitGroup
+	for i, f := range files {
+		wg.Add(1)
+		go func(i int, path string, stealth bool) {
+			defer wg.Done()
+			n, err := s.archiveFile(path, stealth, opts)
+			if err != nil {
+				errs <- err
+				return
+			}
+			counts[i] = n
+		}(i, f.path, f.stealth)
+	}
+	wg.Wait()
+	close(errs)
+
+	archived := 0
+	for _, n := range counts {
+		archived += n
+	}
+	if err := <-errs; err != nil {
+		return archived, err
+	}
 	return archived, nil
 }
 
 // archiveFile archives completed handoffs from a single file
-func (s *Store) archiveFile(path string, stealth bool) (int, error) {
+func (s *Store) archiveFile(path string, stealth bool, opts ArchiveOptions) (int, error) {
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return 0, nil
@@ -318,9 +712,9 @@ func (s *Store) archiveFile(path string, stealth bool) (int, error) {
 	})
 
 	// Keep completed that are:
-	// 1. Within HandoffMaxAgeDays, OR
-	// 2. Among the most recent HandoffMaxCompleted
-	cutoffDate := time.Now().AddDate(0, 0, -models.HandoffMaxAgeDays)
+	// 1. Within opts.MaxAge, OR
+	// 2. Among the most recent opts.KeepMin
+	cutoffDate := time.Now().Add(-opts.MaxAge)
 	var keep []*models.Handoff
 	for i, h := range completed {
 		// Keep if within age limit
@@ -328,8 +722,8 @@ func (s *Store) archiveFile(path string, stealth bool) (int, error) {
 			keep = append(keep, h)
 			continue
 		}
-		// Keep if among the most recent HandoffMaxCompleted
-		if i < models.HandoffMaxCompleted {
+		// Keep if among the most recent opts.KeepMin
+		if i < opts.KeepMin {
 			keep = append(keep, h)
 		}
 	}
@@ -376,10 +770,15 @@ func (s *Store) loadHandoffs(path string, stealth bool) ([]*models.Handoff, erro
 	return handoffs, nil
 }
 
-// writeHandoffs writes handoffs to a file
+// writeHandoffs writes handoffs to a file atomically (temp file + rename)
 func (s *Store) writeHandoffs(path string, handoffs []*models.Handoff) error {
 	content := Serialize(handoffs)
-	return os.WriteFile(path, []byte(content), 0644)
+	tmpPath := path + ".tmp"
+	if err := writeFile(tmpPath, []byte(content), 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
 
 // findHandoffFile returns the path and stealth flag for a handoff ID
@@ -414,6 +813,9 @@ func applyHandoffUpdates(h *models.Handoff, updates map[string]interface{}) {
 	if agent, ok := updates["agent"].(string); ok {
 		h.Agent = agent
 	}
+	if priority, ok := updates["priority"].(string); ok {
+		h.Priority = priority
+	}
 	if description, ok := updates["description"].(string); ok {
 		h.Description = description
 	}
@@ -1,6 +1,8 @@
 package handoffs
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -706,6 +708,230 @@ func Test_Store_List_IncludesStealth(t *testing.T) {
 	}
 }
 
+func Test_Store_ArchiveWith_AgeAndKeepMin(t *testing.T) {
+	ago := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+	completedFile := func(prefix string, ages ...int) string {
+		content := "# HANDOFFS.md - Active Work Tracking\n\n## Active Handoffs\n\n"
+		for i, age := range ages {
+			content += fmt.Sprintf("### [%s%d] Done %d days ago\n- **Status**: completed | **Phase**: review | **Agent**: user\n- **Created**: %s | **Updated**: %s\n\n**Next**: Done\n\n---\n\n",
+				prefix, i+1, age, ago(age), ago(age))
+		}
+		return content
+	}
+
+	for _, tc := range []struct {
+		name         string
+		opts         ArchiveOptions
+		wantArchived int
+	}{
+		{"age only", ArchiveOptions{MaxAge: 30 * 24 * time.Hour}, 3},
+		// KeepMin applies per file, so it also keeps the lone stealth handoff
+		{"keep-min covers recent", ArchiveOptions{MaxAge: 30 * 24 * time.Hour, KeepMin: 1}, 2},
+		{"keep-min retains old", ArchiveOptions{MaxAge: 30 * 24 * time.Hour, KeepMin: 3}, 1},
+		{"stealth only", ArchiveOptions{MaxAge: 30 * 24 * time.Hour, StealthOnly: true}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			projectPath := createTestHandoffsFile(t, dir, "HANDOFFS.md", completedFile("hf-000000", 5, 20, 40, 60))
+			stealthPath := createTestHandoffsFile(t, dir, "HANDOFFS_LOCAL.md", completedFile("hf-100000", 90))
+			store := NewStore(projectPath, stealthPath)
+
+			archived, err := store.ArchiveWith(tc.opts)
+			if err != nil {
+				t.Fatalf("ArchiveWith failed: %v", err)
+			}
+			if archived != tc.wantArchived {
+				t.Errorf("Expected %d archived, got %d", tc.wantArchived, archived)
+			}
+
+			remaining, _ := store.ListAll()
+			ids := map[string]bool{}
+			for _, h := range remaining {
+				ids[h.ID] = true
+			}
+			if !ids["hf-0000001"] || !ids["hf-0000002"] {
+				t.Errorf("Expected handoffs within 30 days to be kept, got %v", ids)
+			}
+			if tc.opts.StealthOnly && len(ids) != 4 {
+				t.Errorf("Expected all project handoffs kept with StealthOnly, got %v", ids)
+			}
+			if tc.opts.KeepMin == 3 && !ids["hf-0000003"] {
+				t.Errorf("Expected keep-min 3 to retain hf-0000003, got %v", ids)
+			}
+		})
+	}
+}
+
+func Test_Store_ArchiveWith_ArchivesFilesConcurrently(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -60).Format("2006-01-02")
+	largeFile := func(prefix string) string {
+		var sb strings.Builder
+		sb.WriteString("# HANDOFFS.md - Active Work Tracking\n\n## Active Handoffs\n\n")
+		for i := 0; i < 200; i++ {
+			fmt.Fprintf(&sb, "### [%s%03d] Old work %d\n- **Status**: completed | **Phase**: review | **Agent**: user\n- **Created**: %s | **Updated**: %s\n\n**Next**: Done\n\n---\n\n",
+				prefix, i, i, old, old)
+		}
+		return sb.String()
+	}
+
+	dir := t.TempDir()
+	projectPath := createTestHandoffsFile(t, dir, "HANDOFFS.md", largeFile("hf-0000"))
+	stealthPath := createTestHandoffsFile(t, dir, "HANDOFFS_LOCAL.md", largeFile("hf-1000"))
+	store := NewStore(projectPath, stealthPath)
+
+	// Each file write takes a fixed delay, so sequential archiving would
+	// take at least twice as long as one write
+	const delay = 200 * time.Millisecond
+	origWriteFile := writeFile
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		time.Sleep(delay)
+		return origWriteFile(name, data, perm)
+	}
+	defer func() { writeFile = origWriteFile }()
+
+	start := time.Now()
+	archived, err := store.ArchiveWith(ArchiveOptions{MaxAge: 30 * 24 * time.Hour})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("ArchiveWith failed: %v", err)
+	}
+	if archived != 400 {
+		t.Errorf("Expected 400 archived across both files, got %d", archived)
+	}
+	if elapsed >= 2*delay {
+		t.Errorf("Expected concurrent archiving under %v (sequential), took %v", 2*delay, elapsed)
+	}
+
+	remaining, _ := store.ListAll()
+	if len(remaining) != 0 {
+		t.Errorf("Expected both files emptied, %d handoffs remain", len(remaining))
+	}
+}
+
+func Test_Store_ArchiveWith_ReportsFileError(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	store.Add("Work", "", false)
+	store.Add("Secret work", "", true)
+
+	origWriteFile := writeFile
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		if strings.Contains(name, "LOCAL") {
+			return errors.New("disk full")
+		}
+		return origWriteFile(name, data, perm)
+	}
+	defer func() { writeFile = origWriteFile }()
+
+	if _, err := store.ArchiveWith(DefaultArchiveOptions()); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected stealth write error, got %v", err)
+	}
+}
+
+func Test_Store_Update_InvalidPriority(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	h, _ := store.Add("Work", "", false)
+
+	err := store.Update(h.ID, map[string]interface{}{"priority": "urgent"})
+	if err == nil || !strings.Contains(err.Error(), "invalid priority") {
+		t.Errorf("Expected invalid priority error, got %v", err)
+	}
+
+	if err := store.Update(h.ID, map[string]interface{}{"priority": "high"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	updated, _ := store.Get(h.ID)
+	if updated.Priority != "high" {
+		t.Errorf("Expected priority 'high', got %q", updated.Priority)
+	}
+}
+
+func Test_Store_Update_PhaseTransitions(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	h, _ := store.Add("Work", "", false)
+
+	for _, phase := range []string{"planning", "implementing", "review"} {
+		if err := store.Update(h.ID, map[string]interface{}{"phase": phase}); err != nil {
+			t.Fatalf("Update to %s failed: %v", phase, err)
+		}
+	}
+
+	err := store.Update(h.ID, map[string]interface{}{"phase": "research"})
+	var transitionErr *models.ErrInvalidPhaseTransition
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Expected ErrInvalidPhaseTransition, got %v", err)
+	}
+	if transitionErr.From != "review" || transitionErr.To != "research" {
+		t.Errorf("Expected review -> research, got %s -> %s", transitionErr.From, transitionErr.To)
+	}
+	updated, _ := store.Get(h.ID)
+	if updated.Phase != "review" {
+		t.Errorf("Expected phase to stay 'review', got %q", updated.Phase)
+	}
+
+	if err := store.Update(h.ID, map[string]interface{}{"phase": "research", "force_phase": true}); err != nil {
+		t.Fatalf("Forced update failed: %v", err)
+	}
+	updated, _ = store.Get(h.ID)
+	if updated.Phase != "research" {
+		t.Errorf("Expected forced phase 'research', got %q", updated.Phase)
+	}
+
+	err = store.Update(h.ID, map[string]interface{}{"phase": "done", "force_phase": true})
+	if err == nil || !strings.Contains(err.Error(), "invalid phase") {
+		t.Errorf("Expected invalid phase error even when forced, got %v", err)
+	}
+}
+
+func Test_Store_Update_PhaseChangeRecordsCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+	h, _ := store.Add("Work", "", false)
+
+	steps := map[string]string{
+		"planning":     "Read the RFC",
+		"implementing": "Draft the design doc",
+		"review":       "Write the migration",
+	}
+	start := time.Now().Truncate(time.Minute)
+	for _, phase := range []string{"planning", "implementing", "review"} {
+		if err := store.Update(h.ID, map[string]interface{}{"next_steps": steps[phase]}); err != nil {
+			t.Fatalf("Update next_steps failed: %v", err)
+		}
+		if err := store.Update(h.ID, map[string]interface{}{"phase": phase}); err != nil {
+			t.Fatalf("Update to %s failed: %v", phase, err)
+		}
+	}
+	end := time.Now()
+
+	updated, _ := store.Get(h.ID)
+	if len(updated.CheckpointHistory) != 3 {
+		t.Fatalf("Expected 3 checkpoint entries, got %+v", updated.CheckpointHistory)
+	}
+	for i, phase := range []string{"planning", "implementing", "review"} {
+		e := updated.CheckpointHistory[i]
+		if e.Phase != phase || e.Notes != steps[phase] {
+			t.Errorf("Entry %d = %+v, want phase %s with notes %q", i, e, phase, steps[phase])
+		}
+		if e.Timestamp.Before(start) || e.Timestamp.After(end) {
+			t.Errorf("Entry %d timestamp %v outside [%v, %v]", i, e.Timestamp, start, end)
+		}
+	}
+
+	// Updates that keep the phase don't add entries
+	if err := store.Update(h.ID, map[string]interface{}{"status": "in_progress"}); err != nil {
+		t.Fatalf("Update status failed: %v", err)
+	}
+	updated, _ = store.Get(h.ID)
+	if len(updated.CheckpointHistory) != 3 {
+		t.Errorf("Expected no new entry without a phase change, got %d", len(updated.CheckpointHistory))
+	}
+}
+
 func Test_Store_Update_NotFound(t *testing.T) {
 	dir := t.TempDir()
 	projectPath := filepath.Join(dir, "project", "HANDOFFS.md")
@@ -767,3 +993,173 @@ func Test_GenerateID(t *testing.T) {
 		}
 	}
 }
+
+func Test_Store_Clone(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	orig, err := store.Add("Weekly release", "Cut and publish the release", false)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	err = store.Update(orig.ID, map[string]interface{}{
+		"status":     "in_progress",
+		"phase":      "implementing",
+		"priority":   "high",
+		"refs":       []string{"scripts/release.sh:10"},
+		"next_steps": "Tag the build",
+		"sessions":   []string{"session-1"},
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.AddTriedStep(orig.ID, "success", "Bumped version"); err != nil {
+		t.Fatalf("AddTriedStep failed: %v", err)
+	}
+	before, _ := store.Get(orig.ID)
+
+	clone, err := store.Clone(orig.ID, "Weekly release (next)")
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	got, err := store.Get(clone.ID)
+	if err != nil {
+		t.Fatalf("Get clone failed: %v", err)
+	}
+	if got.ID == orig.ID || !strings.HasPrefix(got.ID, "hf-") {
+		t.Errorf("Expected a new hf- ID, got %q", got.ID)
+	}
+	if got.Title != "Weekly release (next)" || got.Status != "not_started" {
+		t.Errorf("Unexpected title/status: %q / %q", got.Title, got.Status)
+	}
+	if len(got.Tried) != 0 || len(got.Sessions) != 0 {
+		t.Errorf("Expected no tried steps or sessions, got %d / %d", len(got.Tried), len(got.Sessions))
+	}
+	if got.Description != "Cut and publish the release" || got.Phase != "implementing" || got.Priority != "high" ||
+		got.NextSteps != "Tag the build" || len(got.Refs) != 1 {
+		t.Errorf("Expected planning fields copied, got %+v", got)
+	}
+
+	after, _ := store.Get(orig.ID)
+	if after.Status != before.Status || len(after.Tried) != len(before.Tried) ||
+		len(after.Sessions) != len(before.Sessions) || after.Title != before.Title {
+		t.Errorf("Original handoff changed: before %+v, after %+v", before, after)
+	}
+
+	// Empty title keeps the original
+	if c, err := store.Clone(orig.ID, ""); err != nil || c.Title != "Weekly release" {
+		t.Errorf("Expected original title, got %v (%v)", c, err)
+	}
+	if _, err := store.Clone("hf-9999999", ""); err == nil {
+		t.Error("Expected error cloning a missing handoff")
+	}
+}
+
+func Test_Store_AddNote(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	h, err := store.Add("Migrate database", "Move to Postgres 16", false)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.AddNote(h.ID, "Staging snapshot taken"); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if err := store.AddNote(h.ID, "Replica lag spikes during copy\nThrottle to 50MB/s"); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+
+	got, err := store.Get(h.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(got.Notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(got.Notes))
+	}
+	if got.Notes[0].Text != "Staging snapshot taken" || got.Notes[1].Text != "Replica lag spikes during copy\nThrottle to 50MB/s" {
+		t.Errorf("Unexpected notes: %+v", got.Notes)
+	}
+	if got.Notes[1].Timestamp.Before(got.Notes[0].Timestamp) {
+		t.Error("Expected notes in chronological order")
+	}
+
+	if err := store.AddNote(h.ID, "   "); err == nil {
+		t.Error("Expected error for empty note")
+	}
+	if err := store.AddNote("hf-9999999", "text"); err == nil {
+		t.Error("Expected error for missing handoff")
+	}
+}
+
+func Test_Store_LogTime(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	h, _ := store.Add("Migrate database", "", false)
+	if err := store.LogTime(h.ID, "sess-1", 30); err != nil {
+		t.Fatalf("LogTime failed: %v", err)
+	}
+	if err := store.LogTime(h.ID, "", 15); err != nil {
+		t.Fatalf("LogTime failed: %v", err)
+	}
+
+	got, _ := store.Get(h.ID)
+	if len(got.TimeLog) != 2 || got.TimeLog[0].SessionID != "sess-1" || got.TotalMinutes() != 45 {
+		t.Errorf("Unexpected time log: %+v", got.TimeLog)
+	}
+
+	for _, tc := range []struct {
+		session string
+		minutes int
+	}{{"sess-1", 0}, {"sess-1", -5}, {"two words", 10}} {
+		if err := store.LogTime(h.ID, tc.session, tc.minutes); err == nil {
+			t.Errorf("Expected error for session %q minutes %d", tc.session, tc.minutes)
+		}
+	}
+	if err := store.LogTime("hf-9999999", "", 10); err == nil {
+		t.Error("Expected error for missing handoff")
+	}
+}
+
+func Test_Store_Milestones(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "HANDOFFS.md"), filepath.Join(dir, "HANDOFFS_LOCAL.md"))
+
+	h, err := store.Add("Launch v2", "", false)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	for _, name := range []string{"API frozen", "Docs written", "Beta feedback"} {
+		if err := store.AddMilestone(h.ID, name); err != nil {
+			t.Fatalf("AddMilestone failed: %v", err)
+		}
+	}
+	if err := store.AddMilestone(h.ID, "Docs written"); err == nil {
+		t.Error("Expected error for duplicate milestone")
+	}
+	if err := store.CompleteMilestone(h.ID, "API frozen"); err != nil {
+		t.Fatalf("CompleteMilestone failed: %v", err)
+	}
+	if err := store.CompleteMilestone(h.ID, "Beta feedback"); err != nil {
+		t.Fatalf("CompleteMilestone failed: %v", err)
+	}
+	if err := store.CompleteMilestone(h.ID, "API frozen"); err == nil {
+		t.Error("Expected error completing a milestone twice")
+	}
+	if err := store.CompleteMilestone(h.ID, "Unknown"); err == nil {
+		t.Error("Expected error for unknown milestone")
+	}
+
+	got, err := store.Get(h.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if done, total := got.MilestoneProgress(); done != 2 || total != 3 {
+		t.Errorf("Expected 2/3 milestones, got %d/%d", done, total)
+	}
+	if got.Milestones[1].Completed || got.Milestones[0].CompletedAt == nil {
+		t.Errorf("Unexpected milestones: %+v", got.Milestones)
+	}
+}
@@ -0,0 +1,121 @@
+package handoffs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// templateNameRegex restricts template names to safe file names
+var templateNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Template holds the defaults a new handoff is created with
+type Template struct {
+	Phase       string   `json:"phase,omitempty"`
+	Description string   `json:"description,omitempty"`
+	NextSteps   string   `json:"next_steps,omitempty"`
+	Refs        []string `json:"refs,omitempty"`
+}
+
+// TemplateDir returns the handoff template directory under the base directory
+func TemplateDir(base string) string {
+	return filepath.Join(base, "handoff-templates")
+}
+
+// TemplateFromHandoff captures a handoff's reusable fields as a template
+func TemplateFromHandoff(h *models.Handoff) Template {
+	return Template{
+		Phase:       h.Phase,
+		Description: h.Description,
+		NextSteps:   h.NextSteps,
+		Refs:        append([]string(nil), h.Refs...),
+	}
+}
+
+// Updates returns the store updates that apply the template's defaults
+// to a newly added handoff (nil if there are none)
+func (t Template) Updates() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if t.Phase != "" {
+		updates["phase"] = t.Phase
+		updates["force_phase"] = true
+	}
+	if t.NextSteps != "" {
+		updates["next_steps"] = t.NextSteps
+	}
+	if len(t.Refs) > 0 {
+		updates["refs"] = t.Refs
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return updates
+}
+
+// LoadTemplate reads <dir>/<name>.json
+func LoadTemplate(dir, name string) (*Template, error) {
+	if !templateNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	if t.Phase != "" && !models.IsValidHandoffPhase(t.Phase) {
+		return nil, fmt.Errorf("template %s has invalid phase %q", name, t.Phase)
+	}
+	return &t, nil
+}
+
+// SaveTemplate writes t to <dir>/<name>.json, replacing any existing template
+func SaveTemplate(dir, name string, t Template) error {
+	if !templateNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid template name %q (use letters, digits, - and _)", name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".json"), append(data, '\n'), 0644)
+}
+
+// ListTemplates returns the sorted names of the templates in dir.
+// A missing directory has no templates.
+func ListTemplates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
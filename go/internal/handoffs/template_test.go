@@ -0,0 +1,78 @@
+package handoffs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func TestTemplate_SaveLoadList(t *testing.T) {
+	dir := TemplateDir(t.TempDir())
+
+	h := models.NewHandoff("hf-000000a", "Fix flaky login")
+	h.Phase = "implementing"
+	h.Description = "Reproduce, bisect, fix, add regression test"
+	h.NextSteps = "Write a failing test"
+	h.Refs = []string{"internal/auth/"}
+
+	if err := SaveTemplate(dir, "bug-fix", TemplateFromHandoff(h)); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	if err := SaveTemplate(dir, "feature", Template{Phase: "research"}); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a template"), 0644)
+
+	names, err := ListTemplates(dir)
+	if err != nil {
+		t.Fatalf("ListTemplates failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"bug-fix", "feature"}) {
+		t.Errorf("names = %v, want [bug-fix feature]", names)
+	}
+
+	tmpl, err := LoadTemplate(dir, "bug-fix")
+	if err != nil {
+		t.Fatalf("LoadTemplate failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"phase":       "implementing",
+		"force_phase": true,
+		"next_steps":  "Write a failing test",
+		"refs":        []string{"internal/auth/"},
+	}
+	if !reflect.DeepEqual(tmpl.Updates(), want) {
+		t.Errorf("Updates() = %v, want %v", tmpl.Updates(), want)
+	}
+	if tmpl.Description != h.Description {
+		t.Errorf("Description = %q, want %q", tmpl.Description, h.Description)
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if names, err := ListTemplates(filepath.Join(dir, "missing")); err != nil || len(names) != 0 {
+		t.Errorf("expected no templates in a missing dir, got %v, %v", names, err)
+	}
+	if _, err := LoadTemplate(dir, "nope"); err == nil {
+		t.Error("expected error loading a missing template")
+	}
+	if _, err := LoadTemplate(dir, "../escape"); err == nil {
+		t.Error("expected error for a path-like template name")
+	}
+	if err := SaveTemplate(dir, "a/b", Template{}); err == nil {
+		t.Error("expected error saving a path-like template name")
+	}
+
+	os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"phase": "daydreaming"}`), 0644)
+	if _, err := LoadTemplate(dir, "bad"); err == nil {
+		t.Error("expected error for an invalid phase")
+	}
+	if (Template{Description: "only a description"}).Updates() != nil {
+		t.Error("expected nil updates when only the description is set")
+	}
+}
@@ -0,0 +1,56 @@
+package handoffs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// TimelineEvent is one dated entry in a handoff's history
+type TimelineEvent struct {
+	Time   time.Time
+	Kind   string // created|tried|session|phase|completed
+	Detail string
+}
+
+// Timeline reconstructs a handoff's history in chronological order. Only
+// creation and tried steps carry their own dates; session links are dated
+// by LastSession, and the current phase and completion by Updated.
+func Timeline(h *models.Handoff) []TimelineEvent {
+	events := []TimelineEvent{{Time: h.Created, Kind: "created", Detail: h.Title}}
+
+	for _, step := range h.Tried {
+		events = append(events, TimelineEvent{
+			Time:   step.Timestamp,
+			Kind:   "tried",
+			Detail: fmt.Sprintf("[%s] %s", step.Outcome, step.Description),
+		})
+	}
+
+	if len(h.Sessions) > 0 {
+		at := h.Updated
+		if h.LastSession != nil {
+			at = *h.LastSession
+		}
+		events = append(events, TimelineEvent{Time: at, Kind: "session", Detail: strings.Join(h.Sessions, ", ")})
+	}
+
+	events = append(events, TimelineEvent{
+		Time:   h.Updated,
+		Kind:   "phase",
+		Detail: fmt.Sprintf("%s (status: %s)", h.Phase, h.Status),
+	})
+
+	if h.Status == "completed" {
+		events = append(events, TimelineEvent{Time: h.Updated, Kind: "completed"})
+	}
+
+	// Stable so same-day events keep their logical order
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
@@ -0,0 +1,59 @@
+package handoffs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse(dateFormat, s)
+	return t
+}
+
+func TestTimeline_Chronological(t *testing.T) {
+	lastSession := day("2026-01-18")
+	h := models.NewHandoff("hf-a1b2c3d", "Build parser")
+	h.Created = day("2026-01-10")
+	h.Updated = day("2026-01-20")
+	h.Status = "completed"
+	h.Phase = "review"
+	h.Sessions = []string{"sess-1"}
+	h.LastSession = &lastSession
+	h.Tried = []models.TriedStep{
+		{Outcome: "success", Description: "Second try", Timestamp: day("2026-01-15")},
+		{Outcome: "fail", Description: "First try", Timestamp: day("2026-01-12")},
+	}
+
+	events := Timeline(h)
+
+	want := []struct{ date, kind string }{
+		{"2026-01-10", "created"},
+		{"2026-01-12", "tried"},
+		{"2026-01-15", "tried"},
+		{"2026-01-18", "session"},
+		{"2026-01-20", "phase"},
+		{"2026-01-20", "completed"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Time.Format(dateFormat) != w.date || events[i].Kind != w.kind {
+			t.Errorf("event %d = %s %s, want %s %s", i, events[i].Time.Format(dateFormat), events[i].Kind, w.date, w.kind)
+		}
+	}
+	if events[1].Detail != "[fail] First try" {
+		t.Errorf("Unexpected tried detail: %q", events[1].Detail)
+	}
+}
+
+func TestTimeline_OpenHandoffHasNoCompletion(t *testing.T) {
+	h := models.NewHandoff("hf-a1b2c3d", "Still going")
+	for _, e := range Timeline(h) {
+		if e.Kind == "completed" || e.Kind == "session" {
+			t.Errorf("Unexpected %s event for open handoff without sessions", e.Kind)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package handoffs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/webhook"
+)
+
+// WebhookPayload is the JSON body POSTed for a handoff event
+type WebhookPayload struct {
+	Event   string         `json:"event"`
+	Handoff WebhookHandoff `json:"handoff"`
+}
+
+// WebhookHandoff identifies the handoff in a WebhookPayload
+type WebhookHandoff struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// WebhookNotifier returns a Notifier that POSTs the given events (all if
+// events is empty) to url. Failures are written to warn and never fail
+// the store operation.
+func WebhookNotifier(url string, events []string, warn io.Writer) Notifier {
+	return func(event string, h models.Handoff) {
+		wanted := len(events) == 0
+		for _, e := range events {
+			wanted = wanted || e == event
+		}
+		if !wanted {
+			return
+		}
+		payload := WebhookPayload{
+			Event:   event,
+			Handoff: WebhookHandoff{ID: h.ID, Title: h.Title, Status: h.Status},
+		}
+		if err := webhook.Send(url, payload); err != nil {
+			fmt.Fprintf(warn, "warning: %s webhook for %s failed: %v\n", event, h.ID, err)
+		}
+	}
+}
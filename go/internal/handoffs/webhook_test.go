@@ -0,0 +1,102 @@
+package handoffs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/webhook"
+)
+
+// webhookRecorder collects the payloads and signatures POSTed to it
+type webhookRecorder struct {
+	payloads   []WebhookPayload
+	signatures []string
+	bodies     [][]byte
+}
+
+func newWebhookServer(t *testing.T) (*httptest.Server, *webhookRecorder) {
+	t.Helper()
+	rec := &webhookRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p WebhookPayload
+		json.Unmarshal(body, &p)
+		rec.payloads = append(rec.payloads, p)
+		rec.signatures = append(rec.signatures, r.Header.Get(webhook.SignatureHeader))
+		rec.bodies = append(rec.bodies, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, rec
+}
+
+func Test_Store_WebhookOnStatusChange(t *testing.T) {
+	server, rec := newWebhookServer(t)
+	t.Setenv(webhook.SecretEnv, "s3cret")
+
+	store, ids := newDepsTestStore(t, 1)
+	id := ids[0]
+	var warn strings.Builder
+	store.SetNotifier(WebhookNotifier(server.URL, nil, &warn))
+
+	// Non-status updates send nothing
+	if err := store.Update(id, map[string]interface{}{"description": "More detail"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if len(rec.payloads) != 0 {
+		t.Fatalf("expected no webhook for a description change, got %+v", rec.payloads)
+	}
+
+	if err := store.Update(id, map[string]interface{}{"status": "in_progress"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Complete(id); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	want := []WebhookPayload{
+		{Event: EventStatusChange, Handoff: WebhookHandoff{ID: id, Title: "Work item", Status: "in_progress"}},
+		{Event: EventStatusChange, Handoff: WebhookHandoff{ID: id, Title: "Work item", Status: "completed"}},
+		{Event: EventComplete, Handoff: WebhookHandoff{ID: id, Title: "Work item", Status: "completed"}},
+	}
+	if len(rec.payloads) != len(want) {
+		t.Fatalf("expected %d webhooks, got %+v", len(want), rec.payloads)
+	}
+	for i := range want {
+		if rec.payloads[i] != want[i] {
+			t.Errorf("webhook %d = %+v, want %+v", i, rec.payloads[i], want[i])
+		}
+		if sig := "sha256=" + webhook.Sign(rec.bodies[i], "s3cret"); rec.signatures[i] != sig {
+			t.Errorf("webhook %d signature = %q, want %q", i, rec.signatures[i], sig)
+		}
+	}
+	if warn.Len() != 0 {
+		t.Errorf("unexpected warnings: %s", warn.String())
+	}
+}
+
+func Test_Store_WebhookEventFilterAndFailure(t *testing.T) {
+	server, rec := newWebhookServer(t)
+	store, ids := newDepsTestStore(t, 1)
+	var warn strings.Builder
+	store.SetNotifier(WebhookNotifier(server.URL, []string{EventComplete}, &warn))
+
+	store.Update(ids[0], map[string]interface{}{"status": "in_progress"})
+	store.Complete(ids[0])
+	if len(rec.payloads) != 1 || rec.payloads[0].Event != EventComplete {
+		t.Errorf("expected only the complete event, got %+v", rec.payloads)
+	}
+
+	// An unreachable webhook warns but the update still succeeds
+	store, ids = newDepsTestStore(t, 1)
+	store.SetNotifier(WebhookNotifier("http://127.0.0.1:1", nil, &warn))
+	if err := store.Complete(ids[0]); err != nil {
+		t.Fatalf("expected Complete to succeed despite webhook failure: %v", err)
+	}
+	if !strings.Contains(warn.String(), "complete webhook for "+ids[0]+" failed") {
+		t.Errorf("expected webhook warning, got %q", warn.String())
+	}
+}
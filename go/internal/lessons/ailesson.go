@@ -0,0 +1,58 @@
+package lessons
+
+import (
+	"regexp"
+	"strings"
+)
+
+// aiLessonPattern matches "AI LESSON: category: title - content" or
+// "AI LESSON [type]: category: title - content"
+var aiLessonPattern = regexp.MustCompile(`(?m)AI LESSON(?:\s+\[[a-z]+\])?:\s*(.+)`)
+
+// AILesson is a lesson an assistant recorded inline with an AI LESSON marker
+type AILesson struct {
+	Category string
+	Title    string
+	Content  string
+}
+
+// ExtractAILessons returns the AI LESSON markers in text, in order. Markers
+// without a title are skipped.
+func ExtractAILessons(text string) []AILesson {
+	var found []AILesson
+	for _, match := range aiLessonPattern.FindAllStringSubmatch(text, -1) {
+		l := parseAILesson(strings.TrimSpace(match[1]))
+		if l.Title != "" {
+			found = append(found, l)
+		}
+	}
+	return found
+}
+
+// parseAILesson parses "category: title - content" from an AI LESSON match
+func parseAILesson(remainder string) AILesson {
+	// Split on first colon for category
+	parts := strings.SplitN(remainder, ":", 2)
+	if len(parts) < 2 {
+		// No category, treat whole thing as title
+		return AILesson{Category: "pattern", Title: strings.TrimSpace(remainder)}
+	}
+
+	l := AILesson{Category: strings.ToLower(strings.TrimSpace(parts[0]))}
+
+	// Validate category
+	switch l.Category {
+	case "pattern", "correction", "decision", "gotcha", "preference":
+		// valid
+	default:
+		l.Category = "pattern"
+	}
+
+	// Split on " - " for title vs content
+	titleParts := strings.SplitN(strings.TrimSpace(parts[1]), " - ", 2)
+	l.Title = strings.TrimSpace(titleParts[0])
+	if len(titleParts) > 1 {
+		l.Content = strings.TrimSpace(titleParts[1])
+	}
+	return l
+}
@@ -0,0 +1,22 @@
+package lessons
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ExtractAILessons(t *testing.T) {
+	text := "Done.\nAI LESSON: gotcha: Close bodies - Leaks otherwise\n" +
+		"AI LESSON [tip]: Bogus: Unknown category\n" +
+		"AI LESSON: Just a title\n" +
+		"AI LESSON: \n"
+
+	want := []AILesson{
+		{Category: "gotcha", Title: "Close bodies", Content: "Leaks otherwise"},
+		{Category: "pattern", Title: "Unknown category"},
+		{Category: "pattern", Title: "Just a title"},
+	}
+	if got := ExtractAILessons(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractAILessons() = %+v, want %+v", got, want)
+	}
+}
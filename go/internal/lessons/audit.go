@@ -0,0 +1,130 @@
+package lessons
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// AuditLogFile is the audit log file name within the state directory
+const AuditLogFile = "lessons-audit.log"
+
+// Audit event types
+const (
+	AuditAdd     = "add"
+	AuditEdit    = "edit"
+	AuditDelete  = "delete"
+	AuditCite    = "cite"
+	AuditPrevent = "prevent"
+	AuditDecay   = "decay"
+)
+
+// AuditEvent is one JSON-newline record in the audit log
+type AuditEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Event     string    `json:"event"`
+	LessonID  string    `json:"lesson_id"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Note      string    `json:"note,omitempty"`       // Free-text context (e.g. what a prevention caught)
+	SessionID string    `json:"session_id,omitempty"` // Session that triggered the event, if known
+}
+
+// AuditLog is an append-only record of lesson changes
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLog creates an audit log at <stateDir>/lessons-audit.log
+func NewAuditLog(stateDir string) *AuditLog {
+	return &AuditLog{path: filepath.Join(stateDir, AuditLogFile)}
+}
+
+// Log appends an event, stamping it with the current time if unset
+func (a *AuditLog) Log(event AuditEvent) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Read returns all events in the order they were logged. A missing log is
+// empty; malformed lines are skipped.
+func (a *AuditLog) Read() ([]AuditEvent, error) {
+	f, err := os.Open(a.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return events, nil
+}
+
+// auditFields returns the editable fields of a lesson as strings, in a
+// stable order, for diffing before and after an edit
+func auditFields(l *models.Lesson) [][2]string {
+	return [][2]string{
+		{"title", l.Title},
+		{"content", l.Content},
+		{"category", l.Category},
+		{"source", l.Source},
+		{"type", l.LessonType},
+		{"promotable", strconv.FormatBool(l.Promotable)},
+		{"confidence", strconv.Itoa(l.Confidence)},
+		{"weight", strconv.FormatFloat(l.Weight, 'g', -1, 64)},
+		{"triggers", strings.Join(l.Triggers, ",")},
+		{"tags", strings.Join(l.Tags, ",")},
+	}
+}
+
+// logAudit records an event if the store has an audit log. Auditing is
+// best-effort: a failed write never fails the lesson operation.
+func (s *Store) logAudit(event AuditEvent) {
+	if s.audit != nil {
+		s.audit.Log(event)
+	}
+}
@@ -0,0 +1,99 @@
+package lessons
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_AuditLog_RecordsStoreOperations(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	audit := NewAuditLog(filepath.Join(dir, "state"))
+	store.SetAuditLog(audit)
+
+	l, err := store.Add("project", "pattern", "Original title", "Some content")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Cite(l.ID); err != nil {
+		t.Fatalf("Cite failed: %v", err)
+	}
+	if err := store.Edit(l.ID, map[string]interface{}{"title": "New title", "confidence": 80}); err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if err := store.Delete(l.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	events, err := audit.Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	want := []AuditEvent{
+		{Event: AuditAdd, LessonID: "L001", Field: "title", NewValue: "Original title"},
+		{Event: AuditCite, LessonID: "L001", Field: "uses", OldValue: "0", NewValue: "1"},
+		{Event: AuditEdit, LessonID: "L001", Field: "title", OldValue: "Original title", NewValue: "New title"},
+		{Event: AuditEdit, LessonID: "L001", Field: "confidence", OldValue: "50", NewValue: "80"},
+		{Event: AuditDelete, LessonID: "L001", Field: "title", OldValue: "New title"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Timestamp.IsZero() {
+			t.Errorf("event %d: missing timestamp", i)
+		}
+		got.Timestamp = w.Timestamp
+		if got != w {
+			t.Errorf("event %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func Test_AuditLog_FailedOperationNotLogged(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "LESSONS.md"), filepath.Join(dir, "system.md"))
+	audit := NewAuditLog(dir)
+	store.SetAuditLog(audit)
+
+	if err := store.Cite("L404"); err == nil {
+		t.Fatal("expected error citing missing lesson")
+	}
+	if events, _ := audit.Read(); len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+}
+
+func Test_AuditLog_RecordsDecay(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	audit := NewAuditLog(filepath.Join(dir, "state"))
+	store.SetAuditLog(audit)
+
+	l, _ := store.Add("project", "pattern", "Decaying", "Some content")
+	store.Cite(l.ID)
+
+	// Dry runs change nothing, so they aren't logged
+	if _, _, err := ForceDecay(store, DecayConfig{DryRun: true}); err != nil {
+		t.Fatalf("ForceDecay dry run failed: %v", err)
+	}
+	if _, _, err := ForceDecay(store, DecayConfig{}); err != nil {
+		t.Fatalf("ForceDecay failed: %v", err)
+	}
+
+	events, _ := audit.Read()
+	var decays []AuditEvent
+	for _, e := range events {
+		if e.Event == AuditDecay {
+			decays = append(decays, e)
+		}
+	}
+	if len(decays) != 1 {
+		t.Fatalf("Expected 1 decay event, got %+v", decays)
+	}
+	if d := decays[0]; d.LessonID != l.ID || d.Field != "velocity" || d.OldValue != "1" || d.NewValue != "0.5" {
+		t.Errorf("Unexpected decay event: %+v", d)
+	}
+}
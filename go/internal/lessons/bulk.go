@@ -0,0 +1,106 @@
+package lessons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// BulkEdit is one lesson's updates within EditBulk (same keys as Edit)
+type BulkEdit struct {
+	ID      string
+	Updates map[string]interface{}
+}
+
+// NotFoundError lists lesson IDs that a bulk operation could not find
+type NotFoundError struct {
+	IDs []string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("lessons not found: %s", strings.Join(e.IDs, ", "))
+}
+
+// EditBulk applies every edit or none. All IDs and values are validated
+// before anything is written; then each affected lessons file is locked,
+// updated, and written once. Returns a *NotFoundError if any ID is missing.
+func (s *Store) EditBulk(edits []BulkEdit) error {
+	var missing []string
+	byPath := make(map[string][]BulkEdit)
+	for _, e := range edits {
+		if confidence, ok := e.Updates["confidence"].(int); ok && !models.IsValidConfidence(confidence) {
+			return fmt.Errorf("%s: invalid confidence %d (use 0-%d)", e.ID, confidence, models.MaxConfidence)
+		}
+		if weight, ok := e.Updates["weight"].(float64); ok && !models.IsValidWeight(weight) {
+			return fmt.Errorf("%s: invalid weight %g (must be positive)", e.ID, weight)
+		}
+		path, _, err := s.findLessonFile(e.ID)
+		if err != nil {
+			missing = append(missing, e.ID)
+			continue
+		}
+		byPath[path] = append(byPath[path], e)
+	}
+	if len(missing) > 0 {
+		return &NotFoundError{IDs: missing}
+	}
+
+	// Lock every affected file, in level order, before writing any of them
+	type fileEdit struct {
+		path, level string
+		lessons     []*models.Lesson
+	}
+	var files []fileEdit
+	for _, f := range s.levelFiles() {
+		if _, ok := byPath[f.path]; !ok {
+			continue
+		}
+		fl, err := lock.Acquire(f.path + ".lock")
+		if err != nil {
+			return fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		defer fl.Release()
+
+		lessons, err := s.loadLessons(f.path, f.level)
+		if err != nil {
+			return err
+		}
+		files = append(files, fileEdit{path: f.path, level: f.level, lessons: lessons})
+	}
+
+	// Apply in memory; a lesson removed since validation fails the whole bulk
+	var events []AuditEvent
+	for _, f := range files {
+		byID := make(map[string]*models.Lesson, len(f.lessons))
+		for _, l := range f.lessons {
+			byID[l.ID] = l
+		}
+		for _, e := range byPath[f.path] {
+			l, ok := byID[e.ID]
+			if !ok {
+				return &NotFoundError{IDs: []string{e.ID}}
+			}
+			before := auditFields(l)
+			applyUpdates(l, e.Updates)
+			after := auditFields(l)
+			for i := range before {
+				if before[i][1] != after[i][1] {
+					events = append(events, AuditEvent{Event: AuditEdit, LessonID: e.ID, Field: before[i][0],
+						OldValue: before[i][1], NewValue: after[i][1]})
+				}
+			}
+		}
+	}
+
+	for _, f := range files {
+		if err := s.writeLessons(f.path, f.lessons, f.level); err != nil {
+			return fmt.Errorf("failed to write lessons: %w", err)
+		}
+	}
+	for _, event := range events {
+		s.logAudit(event)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package lessons
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+)
+
+// CoGraphFile is the co-citation matrix file name within the state directory
+const CoGraphFile = "lesson-cograph.json"
+
+// lessonIDPattern matches project, system, and workspace lesson IDs
+var lessonIDPattern = regexp.MustCompile(`^[LSW]\d{3}$`)
+
+// CoGraph counts how often pairs of lessons are cited in the same batch,
+// keyed both ways: graph["L001"]["L002"] == graph["L002"]["L001"]
+type CoGraph map[string]map[string]int
+
+// CoCitation is a lesson cited alongside another, and how many times
+type CoCitation struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+// CoGraphPath returns <stateDir>/lesson-cograph.json
+func CoGraphPath(stateDir string) string {
+	return filepath.Join(stateDir, CoGraphFile)
+}
+
+// LoadCoGraph reads a co-citation matrix. A missing file is an empty graph.
+func LoadCoGraph(path string) (CoGraph, error) {
+	graph := CoGraph{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return graph, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return graph, nil
+}
+
+// Save writes the matrix atomically (temp file + rename)
+func (g CoGraph) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Record counts one co-citation for every pair of distinct lesson IDs in
+// ids. Handoff IDs and repeats within the batch are ignored.
+func (g CoGraph) Record(ids []string) {
+	seen := make(map[string]bool, len(ids))
+	var unique []string
+	for _, id := range ids {
+		if lessonIDPattern.MatchString(id) && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	for i, a := range unique {
+		for _, b := range unique[i+1:] {
+			g.increment(a, b)
+			g.increment(b, a)
+		}
+	}
+}
+
+// increment adds one to graph[a][b]
+func (g CoGraph) increment(a, b string) {
+	if g[a] == nil {
+		g[a] = make(map[string]int)
+	}
+	g[a][b]++
+}
+
+// Related returns the lessons co-cited with id, most frequent first (ties
+// by ID)
+func (g CoGraph) Related(id string) []CoCitation {
+	related := make([]CoCitation, 0, len(g[id]))
+	for other, count := range g[id] {
+		related = append(related, CoCitation{ID: other, Count: count})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Count != related[j].Count {
+			return related[i].Count > related[j].Count
+		}
+		return related[i].ID < related[j].ID
+	})
+	return related
+}
+
+// RecordCoCitations adds a batch of citations to <stateDir>/lesson-cograph.json
+// under a file lock. Batches with fewer than two lessons are a no-op.
+func RecordCoCitations(stateDir string, ids []string) error {
+	batch := CoGraph{}
+	batch.Record(ids)
+	if len(batch) == 0 {
+		return nil
+	}
+
+	path := CoGraphPath(stateDir)
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	graph, err := LoadCoGraph(path)
+	if err != nil {
+		return err
+	}
+	graph.Record(ids)
+	return graph.Save(path)
+}
@@ -0,0 +1,55 @@
+package lessons
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRecordCoCitations_ThreeSessions(t *testing.T) {
+	stateDir := t.TempDir()
+
+	sessions := [][]string{
+		{"L001", "L002", "S001"},
+		{"L001", "L002", "H001"},
+		{"L002", "L001", "L001", "W001"},
+	}
+	for _, ids := range sessions {
+		if err := RecordCoCitations(stateDir, ids); err != nil {
+			t.Fatalf("RecordCoCitations failed: %v", err)
+		}
+	}
+
+	graph, err := LoadCoGraph(CoGraphPath(stateDir))
+	if err != nil {
+		t.Fatalf("LoadCoGraph failed: %v", err)
+	}
+	want := CoGraph{
+		"L001": {"L002": 3, "S001": 1, "W001": 1},
+		"L002": {"L001": 3, "S001": 1, "W001": 1},
+		"S001": {"L001": 1, "L002": 1},
+		"W001": {"L001": 1, "L002": 1},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		t.Errorf("graph = %v, want %v", graph, want)
+	}
+
+	related := graph.Related("L001")
+	wantRelated := []CoCitation{{"L002", 3}, {"S001", 1}, {"W001", 1}}
+	if !reflect.DeepEqual(related, wantRelated) {
+		t.Errorf("Related(L001) = %v, want %v", related, wantRelated)
+	}
+	if got := graph.Related("L999"); len(got) != 0 {
+		t.Errorf("expected no related lessons for L999, got %v", got)
+	}
+}
+
+func TestRecordCoCitations_SingleLessonIsNoop(t *testing.T) {
+	stateDir := t.TempDir()
+	if err := RecordCoCitations(stateDir, []string{"L001", "H001", "L001"}); err != nil {
+		t.Fatalf("RecordCoCitations failed: %v", err)
+	}
+	if _, err := os.Stat(CoGraphPath(stateDir)); !os.IsNotExist(err) {
+		t.Errorf("expected no graph file for a single-lesson batch, got err=%v", err)
+	}
+}
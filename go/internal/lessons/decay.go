@@ -2,18 +2,41 @@ package lessons
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/pbrown/claude-recall/internal/lock"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
+// Expire actions for TTLConfig
+const (
+	ExpireActionWarn   = "warn"   // Flag expired lessons with **Expired**: true
+	ExpireActionDelete = "delete" // Remove expired lessons
+)
+
+// TTLConfig configures expiry of lessons that were never cited
+type TTLConfig struct {
+	ZeroUseTTL   time.Duration // Age after which zero-use lessons expire (0 = disabled)
+	ExpireAction string        // ExpireActionWarn or ExpireActionDelete
+}
+
 // DecayConfig configures decay behavior
 type DecayConfig struct {
 	StateFile     string        // Path to state file
 	DecayInterval time.Duration // Time between decays (e.g., 7 days)
+	TTL           TTLConfig     // Zero-use expiry applied with each decay
+	DryRun        bool          // Compute velocity changes without writing anything
+}
+
+// DecayPreview describes the velocity change decay makes to one lesson
+type DecayPreview struct {
+	LessonID    string  `json:"lesson_id"`
+	OldVelocity float64 `json:"old_velocity"`
+	NewVelocity float64 `json:"new_velocity"`
 }
 
 // DecayState tracks when decay was last run
@@ -21,80 +44,190 @@ type DecayState struct {
 	LastDecay time.Time `json:"last_decay"`
 }
 
-// Decay applies decay logic to lessons if interval has passed
-// Returns number of lessons decayed, or 0 if decay was skipped
-func Decay(store *Store, config DecayConfig) (int, error) {
+// Decay applies decay logic to lessons if interval has passed.
+// Returns number of lessons decayed (0 if decay was skipped) and the
+// velocity changes made. With config.DryRun nothing is written: the TTL
+// and state file are left alone and the changes are only reported.
+func Decay(store *Store, config DecayConfig) (int, []DecayPreview, error) {
 	if !NeedsDecay(config) {
-		return 0, nil
+		return 0, nil, nil
 	}
 
-	count, err := ForceDecay(store)
+	count, previews, err := ForceDecay(store, config)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
+	}
+	if config.DryRun {
+		return count, previews, nil
+	}
+
+	if config.TTL.ZeroUseTTL > 0 {
+		if _, err := ExpireLessons(store, config.TTL); err != nil {
+			return count, previews, err
+		}
 	}
 
 	// Update state file
 	if err := saveDecayState(config.StateFile); err != nil {
-		return count, err
+		return count, previews, err
 	}
 
-	return count, nil
+	return count, previews, nil
 }
 
-// ForceDecay applies decay logic regardless of interval
-func ForceDecay(store *Store) (int, error) {
+// ForceDecay applies decay logic regardless of interval. Only
+// config.DryRun is consulted; see Decay.
+func ForceDecay(store *Store, config DecayConfig) (int, []DecayPreview, error) {
 	count := 0
+	var previews []DecayPreview
 
-	// Decay project lessons
-	projectCount, err := decayLessonsInFile(store.projectPath, "project")
-	if err != nil {
-		return 0, err
+	// Decay project, system, and workspace lessons
+	for _, f := range store.levelFiles() {
+		n, filePreviews, err := store.decayLessonsInFile(f.path, f.level, config.DryRun)
+		if err != nil {
+			return 0, nil, err
+		}
+		count += n
+		previews = append(previews, filePreviews...)
 	}
-	count += projectCount
 
-	// Decay system lessons
-	systemCount, err := decayLessonsInFile(store.systemPath, "system")
-	if err != nil {
-		return 0, err
+	if !config.DryRun {
+		for _, p := range previews {
+			store.logAudit(AuditEvent{Event: AuditDecay, LessonID: p.LessonID, Field: "velocity",
+				OldValue: strconv.FormatFloat(p.OldVelocity, 'g', -1, 64), NewValue: strconv.FormatFloat(p.NewVelocity, 'g', -1, 64)})
+		}
 	}
-	count += systemCount
 
-	return count, nil
+	return count, previews, nil
 }
 
-// decayLessonsInFile applies decay to all lessons in a file
-func decayLessonsInFile(path, level string) (int, error) {
+// decayLessonsInFile applies decay to all lessons in a file, returning the
+// lessons whose velocity changed. With dryRun the file is not rewritten.
+func (s *Store) decayLessonsInFile(path, level string, dryRun bool) (int, []DecayPreview, error) {
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return 0, nil
+		return 0, nil, nil
 	}
 
 	// Acquire lock
 	lockPath := path + ".lock"
 	fl, err := lock.Acquire(lockPath)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	defer fl.Release()
 
 	// Parse lessons
 	lessons, err := ParseFile(path)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
 	// Apply decay to each lesson
+	var previews []DecayPreview
 	for _, l := range lessons {
+		old := l.Velocity
 		DecayLesson(l)
+		if l.Velocity != old {
+			previews = append(previews, DecayPreview{LessonID: l.ID, OldVelocity: old, NewVelocity: l.Velocity})
+		}
+	}
+
+	if dryRun {
+		return len(lessons), previews, nil
 	}
 
 	// Write back
-	content := Serialize(lessons, level)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return 0, err
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return 0, nil, err
+	}
+
+	return len(lessons), previews, nil
+}
+
+// ExpireLessons applies the TTL to zero-use lessons in the writable files.
+// Returns the IDs newly flagged (warn) or deleted (delete).
+func ExpireLessons(store *Store, ttl TTLConfig) ([]string, error) {
+	if ttl.ZeroUseTTL <= 0 {
+		return nil, nil
+	}
+	if ttl.ExpireAction != ExpireActionWarn && ttl.ExpireAction != ExpireActionDelete {
+		return nil, fmt.Errorf("invalid expire action %q (use %s or %s)", ttl.ExpireAction, ExpireActionWarn, ExpireActionDelete)
+	}
+
+	now := time.Now()
+	var expired []string
+
+	if ttl.ExpireAction == ExpireActionDelete {
+		// Only the writable files; shared libraries belong to their own repos
+		for _, f := range store.levelFiles() {
+			lessons, err := store.loadLessons(f.path, f.level)
+			if err != nil {
+				return expired, err
+			}
+			for _, l := range lessons {
+				if IsExpired(l, ttl.ZeroUseTTL, now) {
+					if err := store.Delete(l.ID); err != nil {
+						return expired, err
+					}
+					expired = append(expired, l.ID)
+				}
+			}
+		}
+		return expired, nil
+	}
+
+	for _, f := range store.levelFiles() {
+		flagged, err := store.flagExpiredInFile(f.path, f.level, ttl.ZeroUseTTL, now)
+		if err != nil {
+			return expired, err
+		}
+		expired = append(expired, flagged...)
+	}
+
+	return expired, nil
+}
+
+// flagExpiredInFile marks expired lessons in a file, returning newly flagged IDs
+func (s *Store) flagExpiredInFile(path, level string, ttl time.Duration, now time.Time) ([]string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Release()
+
+	lessons, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var flagged []string
+	for _, l := range lessons {
+		if !l.Expired && IsExpired(l, ttl, now) {
+			l.Expired = true
+			flagged = append(flagged, l.ID)
+		}
+	}
+
+	if len(flagged) == 0 {
+		return nil, nil
+	}
+
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return nil, err
 	}
 
-	return len(lessons), nil
+	return flagged, nil
+}
+
+// IsExpired reports whether a never-cited lesson is older than ttl at now.
+// A lesson exactly ttl old has not yet expired.
+func IsExpired(l *models.Lesson, ttl time.Duration, now time.Time) bool {
+	return l.Uses == 0 && now.Sub(l.Learned) > ttl
 }
 
 // DecayLesson applies decay to a single lesson (modifies in place)
@@ -1,9 +1,12 @@
 package lessons
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -173,7 +176,7 @@ func TestDecay_SkipsIfRecent(t *testing.T) {
 		DecayInterval: 7 * 24 * time.Hour,
 	}
 
-	count, err := Decay(store, config)
+	count, _, err := Decay(store, config)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -202,7 +205,7 @@ func TestForceDecay_AlwaysRuns(t *testing.T) {
 
 	store := NewStore(projectPath, systemPath)
 
-	count, err := ForceDecay(store)
+	count, _, err := ForceDecay(store, DecayConfig{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -251,7 +254,7 @@ func TestDecay_UpdatesStateFile(t *testing.T) {
 	}
 
 	before := time.Now()
-	_, err := Decay(store, config)
+	_, _, err := Decay(store, config)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -309,7 +312,7 @@ func TestDecay_DecaysAllLessons(t *testing.T) {
 
 	store := NewStore(projectPath, systemPath)
 
-	count, err := ForceDecay(store)
+	count, _, err := ForceDecay(store, DecayConfig{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -335,3 +338,239 @@ func TestDecay_DecaysAllLessons(t *testing.T) {
 		}
 	}
 }
+
+func TestIsExpired_Boundary(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ttl := 90 * 24 * time.Hour
+
+	atTTL := &models.Lesson{ID: "L001", Learned: now.Add(-ttl)}
+	if IsExpired(atTTL, ttl, now) {
+		t.Error("expected lesson exactly at TTL to not be expired")
+	}
+
+	dayPast := &models.Lesson{ID: "L002", Learned: now.Add(-ttl - 24*time.Hour)}
+	if !IsExpired(dayPast, ttl, now) {
+		t.Error("expected lesson one day past TTL to be expired")
+	}
+
+	cited := &models.Lesson{ID: "L003", Uses: 1, Learned: now.Add(-ttl - 24*time.Hour)}
+	if IsExpired(cited, ttl, now) {
+		t.Error("expected cited lesson to never expire")
+	}
+}
+
+func writeTTLFixture(t *testing.T) *Store {
+	t.Helper()
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "project", "LESSONS.md")
+	systemPath := filepath.Join(tmpDir, "system", "LESSONS.md")
+
+	old := time.Now().AddDate(0, 0, -100).Format("2006-01-02")
+	recent := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+
+	os.MkdirAll(filepath.Dir(projectPath), 0755)
+	os.WriteFile(projectPath, []byte(`# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [-----|-----] Old Unused
+- **Uses**: 0 | **Velocity**: 0 | **Learned**: `+old+` | **Last**: `+old+` | **Category**: pattern
+> Never cited
+
+### [L002] [-----|-----] Recent Unused
+- **Uses**: 0 | **Velocity**: 0 | **Learned**: `+recent+` | **Last**: `+recent+` | **Category**: pattern
+> Too young to expire
+
+### [L003] [*----|-----] Old Cited
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: `+old+` | **Last**: `+old+` | **Category**: pattern
+> Cited once
+`), 0644)
+
+	return NewStore(projectPath, systemPath)
+}
+
+func TestExpireLessons_Warn(t *testing.T) {
+	store := writeTTLFixture(t)
+
+	ttl := TTLConfig{ZeroUseTTL: 90 * 24 * time.Hour, ExpireAction: ExpireActionWarn}
+	expired, err := ExpireLessons(store, ttl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 1 || expired[0] != "L001" {
+		t.Fatalf("expected [L001] expired, got %v", expired)
+	}
+
+	data, _ := os.ReadFile(store.projectPath)
+	if !strings.Contains(string(data), "**Expired**: true") {
+		t.Errorf("expected Expired flag in file, got:\n%s", data)
+	}
+
+	l, _ := store.Get("L001")
+	if !l.Expired {
+		t.Error("expected L001 to parse as expired")
+	}
+
+	// Already-flagged lessons are not reported again
+	expired, _ = ExpireLessons(store, ttl)
+	if len(expired) != 0 {
+		t.Errorf("expected no newly expired lessons, got %v", expired)
+	}
+
+	// Citing clears the flag
+	store.Cite("L001")
+	l, _ = store.Get("L001")
+	if l.Expired {
+		t.Error("expected citation to clear Expired")
+	}
+}
+
+func TestExpireLessons_Delete(t *testing.T) {
+	store := writeTTLFixture(t)
+
+	expired, err := ExpireLessons(store, TTLConfig{ZeroUseTTL: 90 * 24 * time.Hour, ExpireAction: ExpireActionDelete})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 1 || expired[0] != "L001" {
+		t.Fatalf("expected [L001] deleted, got %v", expired)
+	}
+
+	lessons, _ := store.List()
+	if len(lessons) != 2 {
+		t.Errorf("expected 2 lessons remaining, got %d", len(lessons))
+	}
+	if _, err := store.Get("L001"); err == nil {
+		t.Error("expected L001 to be deleted")
+	}
+}
+
+func TestExpireLessons_DeleteSkipsSharedLessons(t *testing.T) {
+	base := writeTTLFixture(t)
+	old := time.Now().AddDate(0, 0, -200).Format("2006-01-02")
+	sharedPath := filepath.Join(t.TempDir(), "LESSONS.md")
+	shared := []byte(`# LESSONS.md - Shared
+
+## Active Lessons
+
+### [L007] [-----|-----] Shared Unused
+- **Uses**: 0 | **Velocity**: 0 | **Learned**: ` + old + ` | **Last**: ` + old + ` | **Category**: pattern
+> Owned by another repo
+`)
+	os.WriteFile(sharedPath, shared, 0644)
+	store := NewStore(base.projectPath, base.systemPath, sharedPath)
+
+	expired, err := ExpireLessons(store, TTLConfig{ZeroUseTTL: 90 * 24 * time.Hour, ExpireAction: ExpireActionDelete})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expired) != 1 || expired[0] != "L001" {
+		t.Fatalf("expected only [L001] deleted, got %v", expired)
+	}
+	if data, _ := os.ReadFile(sharedPath); !bytes.Equal(data, shared) {
+		t.Errorf("shared library was modified:\n%s", data)
+	}
+
+	for name, err := range map[string]error{"Delete": store.Delete("L007"), "Cite": store.Cite("L007")} {
+		if !errors.Is(err, ErrSharedLesson) {
+			t.Errorf("%s on a shared lesson: expected ErrSharedLesson, got %v", name, err)
+		}
+	}
+}
+
+func TestDecay_AppliesTTL(t *testing.T) {
+	store := writeTTLFixture(t)
+
+	cfg := DecayConfig{
+		StateFile:     filepath.Join(t.TempDir(), "decay_state.json"),
+		DecayInterval: 7 * 24 * time.Hour,
+		TTL:           TTLConfig{ZeroUseTTL: 90 * 24 * time.Hour, ExpireAction: ExpireActionWarn},
+	}
+	if _, _, err := Decay(store, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	l, _ := store.Get("L001")
+	if !l.Expired {
+		t.Error("expected Decay to flag L001 as expired")
+	}
+}
+
+func TestDecay_DryRunPreviewsWithoutWriting(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "project", "LESSONS.md")
+	systemPath := filepath.Join(tmpDir, "system", "LESSONS.md")
+	stateFile := filepath.Join(tmpDir, "decay_state.json")
+
+	os.MkdirAll(filepath.Dir(projectPath), 0755)
+	os.WriteFile(projectPath, []byte(`# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*****|*****] Fast Lesson
+- **Uses**: 100 | **Velocity**: 4.0 | **Learned**: 2024-01-01 | **Last**: 2024-01-15 | **Category**: pattern
+> Fast content
+
+### [L002] [*----|-----] Idle Lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2024-01-01 | **Last**: 2024-01-15 | **Category**: pattern
+> Idle content
+
+### [L003] [**---|*----] Slow Lesson
+- **Uses**: 5 | **Velocity**: 0.015 | **Learned**: 2024-01-01 | **Last**: 2024-01-15 | **Category**: pattern
+> Slow content
+`), 0644)
+	before := readFile(t, projectPath)
+
+	store := NewStore(projectPath, systemPath)
+	config := DecayConfig{
+		StateFile:     stateFile,
+		DecayInterval: 7 * 24 * time.Hour,
+		TTL:           TTLConfig{ZeroUseTTL: time.Hour, ExpireAction: ExpireActionDelete},
+		DryRun:        true,
+	}
+
+	count, preview, err := Decay(store, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 lessons considered, got %d", count)
+	}
+
+	if readFile(t, projectPath) != before {
+		t.Error("dry run modified LESSONS.md")
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Error("dry run wrote the decay state file")
+	}
+
+	// L002 has no velocity to lose, so only L001 and L003 change
+	want := []DecayPreview{
+		{LessonID: "L001", OldVelocity: 4.0, NewVelocity: 2.0},
+		{LessonID: "L003", OldVelocity: 0.015, NewVelocity: 0},
+	}
+	if len(preview) != len(want) {
+		t.Fatalf("expected %d previews, got %#v", len(want), preview)
+	}
+	for i := range want {
+		if preview[i] != want[i] {
+			t.Errorf("preview[%d] = %#v, want %#v", i, preview[i], want[i])
+		}
+	}
+
+	// A real decay produces exactly the previewed velocities
+	config.DryRun = false
+	config.TTL = TTLConfig{}
+	if _, _, err := Decay(store, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range preview {
+		l, err := store.Get(p.LessonID)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", p.LessonID, err)
+		}
+		if l.Velocity != p.NewVelocity {
+			t.Errorf("%s: real decay velocity %g, preview said %g", p.LessonID, l.Velocity, p.NewVelocity)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package lessons
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/similarity"
+)
+
+// DefaultDedupThreshold is the similarity (0-1) at or above which Add treats a
+// new lesson as a duplicate of an existing one
+const DefaultDedupThreshold = 0.85
+
+// ErrDuplicateLesson is returned (wrapped in *DuplicateError) when Add finds a
+// lesson similar to the one being added
+var ErrDuplicateLesson = errors.New("duplicate lesson")
+
+// DuplicateError identifies the existing lesson that blocked an Add
+type DuplicateError struct {
+	ID         string  // ID of the most similar existing lesson
+	Similarity float64 // 0-1 similarity to that lesson
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate lesson: similar to %s (%.0f%% match)", e.ID, e.Similarity*100)
+}
+
+// Unwrap allows errors.Is(err, ErrDuplicateLesson)
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicateLesson
+}
+
+// SetDedupThreshold overrides the similarity threshold used by Add.
+// A threshold above 1 effectively disables duplicate detection.
+func (s *Store) SetDedupThreshold(threshold float64) {
+	s.dedupThreshold = threshold
+}
+
+// SetDedupAlgo selects the text similarity algorithm used by Add (see
+// similarity.ForAlgo). Unknown names are rejected.
+func (s *Store) SetDedupAlgo(algo string) error {
+	fn, ok := similarity.ForAlgo(algo)
+	if !ok {
+		return fmt.Errorf("unknown similarity algorithm %q (use %s or %s)", algo, similarity.AlgoJaccard, similarity.AlgoCosine)
+	}
+	s.similarity = fn
+	return nil
+}
+
+// FindSimilar returns existing lessons at or above the dedup threshold, most
+// similar first, along with the highest similarity found (0 if none)
+func (s *Store) FindSimilar(title, content string) ([]*models.Lesson, float64, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type match struct {
+		lesson     *models.Lesson
+		similarity float64
+	}
+	var matches []match
+	best := 0.0
+
+	for _, l := range all {
+		sim := lessonSimilarity(s.similarity, title, content, l.Title, l.Content)
+		if sim > best {
+			best = sim
+		}
+		if sim >= s.dedupThreshold {
+			matches = append(matches, match{lesson: l, similarity: sim})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].similarity > matches[j].similarity
+	})
+
+	similar := make([]*models.Lesson, len(matches))
+	for i, m := range matches {
+		similar[i] = m.lesson
+	}
+
+	return similar, best, nil
+}
+
+// Similarity scores two lessons 0-1. Identical titles (ignoring case and
+// surrounding whitespace) score 1; otherwise it is the Jaccard overlap of the
+// words in title + content.
+func Similarity(titleA, contentA, titleB, contentB string) float64 {
+	return lessonSimilarity(similarity.JaccardSimilarity, titleA, contentA, titleB, contentB)
+}
+
+// lessonSimilarity is Similarity with the text comparison supplied by fn
+// (nil means Jaccard)
+func lessonSimilarity(fn similarity.Func, titleA, contentA, titleB, contentB string) float64 {
+	if strings.EqualFold(strings.TrimSpace(titleA), strings.TrimSpace(titleB)) {
+		return 1.0
+	}
+	if fn == nil {
+		fn = similarity.JaccardSimilarity
+	}
+	return fn(titleA+" "+contentA, titleB+" "+contentB)
+}
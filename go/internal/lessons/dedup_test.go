@@ -0,0 +1,112 @@
+package lessons
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Similarity_ExactTitle(t *testing.T) {
+	sim := Similarity("Use venv", "one thing", "  use VENV ", "something else entirely")
+	if sim != 1.0 {
+		t.Errorf("Expected 1.0 for identical titles, got %f", sim)
+	}
+}
+
+func Test_Similarity_Unrelated(t *testing.T) {
+	sim := Similarity("Git hooks", "Run pre-commit before push", "Docker networking", "Containers use bridge networks")
+	if sim > 0.1 {
+		t.Errorf("Expected near-zero similarity, got %f", sim)
+	}
+}
+
+func Test_Store_Add_RejectsExactTitle(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	first, err := store.Add("project", "pattern", "Always use venv", "Isolate Python dependencies per project")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	_, err = store.Add("project", "pattern", "Always use venv", "Different wording of the same idea")
+	if !errors.Is(err, ErrDuplicateLesson) {
+		t.Fatalf("Expected ErrDuplicateLesson, got %v", err)
+	}
+
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.ID != first.ID {
+		t.Errorf("Expected DuplicateError for %s, got %v", first.ID, err)
+	}
+}
+
+func Test_Store_Add_RejectsSimilarContent(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	content := "Run the full test suite with race detection enabled before pushing any change to the shared main branch of the repository"
+	if _, err := store.Add("project", "pattern", "Race detector before push", content); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	_, err := store.Add("project", "pattern", "Race detector before push always", content)
+	if !errors.Is(err, ErrDuplicateLesson) {
+		t.Errorf("Expected ErrDuplicateLesson for 90%%+ similar lesson, got %v", err)
+	}
+
+	_, best, _ := store.FindSimilar("Race detector before push always", content)
+	if best < DefaultDedupThreshold {
+		t.Errorf("Expected similarity >= %f, got %f", DefaultDedupThreshold, best)
+	}
+}
+
+func Test_Store_ForceAdd_BypassesDedup(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	store.Add("project", "pattern", "Always use venv", "Isolate dependencies")
+	lesson, err := store.ForceAdd("project", "pattern", "Always use venv", "Isolate dependencies")
+	if err != nil {
+		t.Fatalf("ForceAdd failed: %v", err)
+	}
+	if lesson.ID != "L002" {
+		t.Errorf("Expected L002, got %s", lesson.ID)
+	}
+}
+
+func Test_Store_Add_AllowsDistinctLessons(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	store.Add("project", "pattern", "Always use venv", "Isolate Python dependencies")
+	if _, err := store.Add("project", "gotcha", "Docker networking", "Containers use bridge networks"); err != nil {
+		t.Errorf("Expected distinct lesson to be added, got %v", err)
+	}
+}
+
+func Test_Store_SetDedupAlgo(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	if err := store.SetDedupAlgo("levenshtein"); err == nil {
+		t.Error("Expected error for unknown algorithm")
+	}
+
+	store.Add("project", "pattern", "Configuration reload", "Reload configuration files when they change on disk")
+
+	// A typo-level rewording is a word-level miss but an n-gram match
+	title, content := "Configuraton reloading", "Reload configuraton files when they change on disk"
+	_, jaccardBest, _ := store.FindSimilar(title, content)
+
+	if err := store.SetDedupAlgo("cosine"); err != nil {
+		t.Fatalf("SetDedupAlgo failed: %v", err)
+	}
+	store.SetDedupThreshold(0.85)
+	_, cosineBest, _ := store.FindSimilar(title, content)
+	if cosineBest <= jaccardBest {
+		t.Errorf("Expected cosine similarity (%f) above Jaccard (%f)", cosineBest, jaccardBest)
+	}
+	if _, err := store.Add("project", "pattern", title, content); !errors.Is(err, ErrDuplicateLesson) {
+		t.Errorf("Expected cosine dedup to reject the reworded lesson (similarity %f), got %v", cosineBest, err)
+	}
+}
@@ -0,0 +1,134 @@
+package lessons
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of a line diff: ' ' unchanged, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff returns a unified diff of two texts, line by line, with
+// fromLabel and toLabel in the ---/+++ headers. Returns "" when the texts
+// are identical.
+func UnifiedDiff(from, to, fromLabel, toLabel string) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// Group changes, with their context, into hunks [start, end) of ops
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := max(0, i-diffContext), min(len(ops), i+diffContext+1)
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	oldLine, newLine, next := 1, 1, 0
+	for _, h := range hunks {
+		for ; next < h[0]; next++ {
+			oldLine, newLine = advance(ops[next].kind, oldLine, newLine)
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[h[0]:h[1]] {
+			oldCount, newCount = advance(op.kind, oldCount, newCount)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		for _, op := range ops[h[0]:h[1]] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		oldLine += oldCount
+		newLine += newCount
+		next = h[1]
+	}
+	return sb.String()
+}
+
+// advance counts a diff line against the old and new sides it belongs to
+func advance(kind byte, oldN, newN int) (int, int) {
+	if kind != '+' {
+		oldN++
+	}
+	if kind != '-' {
+		newN++
+	}
+	return oldN, newN
+}
+
+// hunkRange formats a hunk header range the way diff -u does: a single line
+// is just its number, and an empty range names the line before it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits text into lines; empty text has none
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines computes a minimal line diff from the longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
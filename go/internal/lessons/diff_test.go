@@ -0,0 +1,37 @@
+package lessons
+
+import "testing"
+
+func Test_UnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{"identical", "a\nb", "a\nb", ""},
+		{
+			"changed line",
+			"keep\nold line\nkeep too",
+			"keep\nnew line\nkeep too",
+			"--- L001\n+++ L002\n@@ -1,3 +1,3 @@\n keep\n-old line\n+new line\n keep too\n",
+		},
+		{
+			"added to empty",
+			"",
+			"first\nsecond",
+			"--- L001\n+++ L002\n@@ -0,0 +1,2 @@\n+first\n+second\n",
+		},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve",
+			"--- L001\n+++ L002\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := UnifiedDiff(tt.from, tt.to, "L001", "L002"); got != tt.want {
+			t.Errorf("%s: UnifiedDiff() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
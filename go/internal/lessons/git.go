@@ -0,0 +1,40 @@
+package lessons
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// GitContextProvider reports the git branch and commit for a directory
+type GitContextProvider interface {
+	GetGitContext(dir string) (*models.GitContext, error)
+}
+
+// ExecGitContext is the default GitContextProvider, backed by the git CLI
+type ExecGitContext struct{}
+
+// GetGitContext runs git rev-parse in dir for the branch name and short SHA
+func (ExecGitContext) GetGitContext(dir string) (*models.GitContext, error) {
+	branch, err := gitRevParse(dir, "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	sha, err := gitRevParse(dir, "--short", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &models.GitContext{Branch: branch, ShortSHA: sha}, nil
+}
+
+func gitRevParse(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"rev-parse"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
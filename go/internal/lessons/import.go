@@ -0,0 +1,305 @@
+package lessons
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/lock"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// Conflict policies for Import when an incoming ID already exists
+const (
+	ConflictSkip      = "skip"      // Keep the existing lesson
+	ConflictOverwrite = "overwrite" // Replace the existing lesson
+	ConflictRenumber  = "renumber"  // Import under the next free ID
+)
+
+// ImportResult summarizes the outcome of an Import
+type ImportResult struct {
+	Added       int
+	Overwritten int
+	Skipped     int
+	Renumbered  map[string]string // Original ID -> newly assigned ID
+}
+
+// Import merges lessons into the store. Each lesson goes to the file for its
+// Level (workspace lessons go to the project file when no workspace is
+// configured); a lesson whose ID prefix doesn't match its level (e.g. an S###
+// lesson imported at project level) is always given a new ID. Every level
+// file is locked for the duration and rewritten atomically.
+func (s *Store) Import(incoming []*models.Lesson, policy string) (*ImportResult, error) {
+	switch policy {
+	case ConflictSkip, ConflictOverwrite, ConflictRenumber:
+	default:
+		return nil, fmt.Errorf("invalid conflict policy '%s': must be skip, overwrite, or renumber", policy)
+	}
+	for _, l := range incoming {
+		if err := ValidateCategory(l.Category); err != nil {
+			return nil, fmt.Errorf("lesson %s: %w", l.ID, err)
+		}
+	}
+
+	files := s.levelFiles()
+	paths := make(map[string]string, len(files))
+
+	// Lock every level file in a fixed order
+	for _, f := range files {
+		paths[f.level] = f.path
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		fl, err := lock.Acquire(f.path + ".lock")
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		defer fl.Release()
+	}
+
+	existing := make(map[string][]*models.Lesson)
+	maxNum := make(map[string]int)
+	for _, f := range files {
+		loaded, err := s.loadLessons(f.path, f.level)
+		if err != nil {
+			return nil, err
+		}
+		existing[f.level] = loaded
+		for _, l := range loaded {
+			if num := idNumber(l.ID, levelPrefix(f.level)); num > maxNum[f.level] {
+				maxNum[f.level] = num
+			}
+		}
+	}
+
+	result := &ImportResult{Renumbered: make(map[string]string)}
+	dirty := make(map[string]bool)
+
+	for _, in := range incoming {
+		l := *in
+		level := l.Level
+		if _, ok := paths[level]; !ok {
+			level = "project"
+		}
+		l.Level = level
+		prefix := levelPrefix(level)
+
+		idx := -1
+		for i, e := range existing[level] {
+			if e.ID == l.ID {
+				idx = i
+				break
+			}
+		}
+
+		renumber := !strings.HasPrefix(l.ID, prefix) || idNumber(l.ID, prefix) == 0
+		if idx >= 0 {
+			switch policy {
+			case ConflictSkip:
+				result.Skipped++
+				continue
+			case ConflictOverwrite:
+				existing[level][idx] = &l
+				dirty[level] = true
+				result.Overwritten++
+				continue
+			case ConflictRenumber:
+				renumber = true
+			}
+		}
+
+		if renumber {
+			maxNum[level]++
+			newID := fmt.Sprintf("%s%03d", prefix, maxNum[level])
+			result.Renumbered[l.ID] = newID
+			l.ID = newID
+		} else if num := idNumber(l.ID, prefix); num > maxNum[level] {
+			maxNum[level] = num
+		}
+
+		if l.Triggers == nil {
+			l.Triggers = []string{}
+		}
+		if l.Tags == nil {
+			l.Tags = []string{}
+		}
+		existing[level] = append(existing[level], &l)
+		dirty[level] = true
+		result.Added++
+	}
+
+	for _, f := range files {
+		if !dirty[f.level] {
+			continue
+		}
+		if err := s.writeLessons(f.path, existing[f.level], f.level); err != nil {
+			return nil, fmt.Errorf("failed to write lessons: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// CategoryWildcard in a RewriteCategories map matches any category without
+// its own entry
+const CategoryWildcard = "*"
+
+// ValidateCategoryMap checks that every target in a RewriteCategories map
+// is a valid category name (see ValidateCategory)
+func ValidateCategoryMap(overrideMap map[string]string) error {
+	for _, to := range overrideMap {
+		if err := ValidateCategory(to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RewriteCategories renames lesson categories in place per overrideMap
+// (old -> new) and returns the same slice. A CategoryWildcard entry applies
+// to every category not listed explicitly. Invalid targets are never
+// applied; check the map with ValidateCategoryMap to report them.
+func RewriteCategories(lessons []*models.Lesson, overrideMap map[string]string) []*models.Lesson {
+	for _, l := range lessons {
+		to, ok := overrideMap[l.Category]
+		if !ok {
+			to, ok = overrideMap[CategoryWildcard]
+		}
+		if ok && ValidateCategory(to) == nil {
+			l.Category = to
+		}
+	}
+	return lessons
+}
+
+// idNumber parses the numeric part of an ID like "L042" (0 if malformed)
+func idNumber(id, prefix string) int {
+	num, err := strconv.Atoi(strings.TrimPrefix(id, prefix))
+	if err != nil || !strings.HasPrefix(id, prefix) {
+		return 0
+	}
+	return num
+}
+
+// Markdown import patterns
+var (
+	mdCategoryHeadingPattern = regexp.MustCompile(`^###\s+\[(\w+)\]\s+(.+)$`)
+	mdHeadingPattern         = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	mdTipPattern             = regexp.MustCompile(`(?i)^>\s*tip:\s*(.*)$`)
+)
+
+// maxTipTitleLen caps the title derived from a one-line tip
+const maxTipTitleLen = 60
+
+// ImportMarkdown extracts lessons from freeform Markdown notes. It recognizes
+// "## Title" followed by content (using defaultCategory), "### [category] Title"
+// followed by content, and one-line "> tip: content" entries. Headings with no
+// content are skipped with a warning written to warn (nil discards them). The
+// returned lessons have no IDs; Store.Import assigns them.
+func ImportMarkdown(r io.Reader, level, defaultCategory string, warn io.Writer) ([]*models.Lesson, error) {
+	if warn == nil {
+		warn = io.Discard
+	}
+	var result []*models.Lesson
+	var title, category string
+	var titleLine int
+	var body []string
+	inBlock := false
+
+	flush := func() {
+		if !inBlock {
+			return
+		}
+		inBlock = false
+		content := strings.Join(body, " ")
+		body = nil
+		if content == "" {
+			fmt.Fprintf(warn, "warning: line %d: skipping %q (no content)\n", titleLine, title)
+			return
+		}
+		result = append(result, newImportedLesson(level, category, title, content))
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if m := mdTipPattern.FindStringSubmatch(line); m != nil {
+			// A tip ends the current block so lessons stay in document order
+			flush()
+			tip := strings.TrimSpace(m[1])
+			if tip == "" {
+				fmt.Fprintf(warn, "warning: line %d: skipping empty tip\n", lineNum)
+				continue
+			}
+			result = append(result, newImportedLesson(level, defaultCategory, tipTitle(tip), tip))
+			continue
+		}
+
+		if m := mdCategoryHeadingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			inBlock, titleLine = true, lineNum
+			category, title = strings.ToLower(m[1]), strings.TrimSpace(m[2])
+			continue
+		}
+
+		if m := mdHeadingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			// A top-level "# Heading" is a document title, not a lesson
+			if len(m[1]) >= 2 {
+				inBlock, titleLine = true, lineNum
+				category, title = defaultCategory, strings.TrimSpace(m[2])
+			}
+			continue
+		}
+
+		if inBlock && line != "" {
+			body = append(body, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read markdown: %w", err)
+	}
+	flush()
+
+	return result, nil
+}
+
+// newImportedLesson builds an unnumbered lesson for ImportMarkdown
+func newImportedLesson(level, category, title, content string) *models.Lesson {
+	now := time.Now()
+	return &models.Lesson{
+		Title:      title,
+		Content:    content,
+		Learned:    now,
+		LastUsed:   now,
+		Category:   category,
+		Source:     "human",
+		Level:      level,
+		Promotable: true,
+		Triggers:   []string{},
+		Tags:       []string{},
+		Confidence: models.DefaultConfidence,
+		Weight:     models.DefaultWeight,
+	}
+}
+
+// tipTitle derives a title from a tip, cut at a word boundary
+func tipTitle(tip string) string {
+	if len(tip) <= maxTipTitleLen {
+		return tip
+	}
+	cut := strings.LastIndex(tip[:maxTipTitleLen], " ")
+	if cut <= 0 {
+		cut = maxTipTitleLen
+	}
+	return strings.TrimSpace(tip[:cut]) + "..."
+}
@@ -0,0 +1,235 @@
+package lessons
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func newImportLesson(id, level, title string) *models.Lesson {
+	l := models.NewLesson(id, title, "Content for "+title)
+	l.Level = level
+	l.Category = "pattern"
+	l.Uses = 7
+	l.Learned = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	return l
+}
+
+func Test_Store_Import_Policies(t *testing.T) {
+	tests := []struct {
+		policy        string
+		wantCount     int
+		wantTitleL001 string
+	}{
+		{ConflictSkip, 1, "Existing"},
+		{ConflictOverwrite, 1, "Incoming"},
+		{ConflictRenumber, 2, "Existing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			dir := t.TempDir()
+			store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+			store.Add("project", "pattern", "Existing", "Already here")
+
+			result, err := store.Import([]*models.Lesson{newImportLesson("L001", "project", "Incoming")}, tt.policy)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+
+			all, _ := store.List()
+			if len(all) != tt.wantCount {
+				t.Fatalf("Expected %d lessons, got %d", tt.wantCount, len(all))
+			}
+			if all[0].ID != "L001" || all[0].Title != tt.wantTitleL001 {
+				t.Errorf("Expected L001 titled %q, got %s %q", tt.wantTitleL001, all[0].ID, all[0].Title)
+			}
+			if tt.policy == ConflictRenumber {
+				if result.Renumbered["L001"] != "L002" {
+					t.Errorf("Expected L001 -> L002, got %v", result.Renumbered)
+				}
+				if all[1].Uses != 7 || all[1].Learned.Format("2006-01-02") != "2025-06-01" {
+					t.Errorf("Expected metadata preserved, got uses=%d learned=%s", all[1].Uses, all[1].Learned)
+				}
+			}
+		})
+	}
+}
+
+func Test_Store_Import_SystemLessonIntoProject(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project", "LESSONS.md")
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	// An S-prefixed lesson forced to project level must get an L ID
+	result, err := store.Import([]*models.Lesson{newImportLesson("S004", "project", "Was system")}, ConflictSkip)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Renumbered["S004"] != "L001" {
+		t.Errorf("Expected S004 -> L001, got %v", result.Renumbered)
+	}
+	if content := readFile(t, projectPath); !strings.Contains(content, "[L001]") {
+		t.Errorf("Expected project file to contain L001, got:\n%s", content)
+	}
+}
+
+func Test_Store_Import_WorkspaceLessons(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project", "LESSONS.md")
+	workspacePath := filepath.Join(dir, "workspace", "LESSONS.md")
+	store := OpenStore(StorePaths{Project: projectPath, System: filepath.Join(dir, "system", "LESSONS.md"), Workspace: workspacePath})
+	store.Add(LevelWorkspace, "pattern", "Team lesson", "Shared with the team")
+
+	// Re-importing an export skips the existing W001 instead of copying it
+	// into the project file
+	result, err := store.Import([]*models.Lesson{
+		newImportLesson("W001", LevelWorkspace, "Team lesson"),
+		newImportLesson("W007", LevelWorkspace, "Another team lesson"),
+	}, ConflictSkip)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Skipped != 1 || result.Added != 1 || len(result.Renumbered) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if content := readFile(t, workspacePath); !strings.Contains(content, "[W007]") {
+		t.Errorf("Expected workspace file to contain W007, got:\n%s", content)
+	}
+	if _, err := os.Stat(projectPath); !os.IsNotExist(err) {
+		t.Errorf("expected no project lessons, stat err = %v", err)
+	}
+
+	// Without a workspace, workspace lessons land in the project file
+	plain := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+	result, err = plain.Import([]*models.Lesson{newImportLesson("W001", LevelWorkspace, "Team lesson")}, ConflictSkip)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Renumbered["W001"] != "L001" {
+		t.Errorf("Expected W001 -> L001 without a workspace, got %v", result.Renumbered)
+	}
+}
+
+func Test_Store_Import_InvalidPolicy(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	if _, err := store.Import(nil, "merge"); err == nil {
+		t.Error("Expected error for invalid policy")
+	}
+}
+
+func Test_ImportMarkdown_Patterns(t *testing.T) {
+	var warnings bytes.Buffer
+
+	md := `# My Notes
+
+## Use context managers
+Always close files with a context manager.
+It avoids leaked handles.
+
+### [gotcha] Mutable default args
+Default arguments are evaluated once.
+
+> tip: Run tests with -race before merging
+
+## Empty heading
+
+### [decision] Also empty
+`
+	got, err := ImportMarkdown(strings.NewReader(md), "project", "pattern", &warnings)
+	if err != nil {
+		t.Fatalf("ImportMarkdown failed: %v", err)
+	}
+
+	want := []struct{ title, category, content string }{
+		{"Use context managers", "pattern", "Always close files with a context manager. It avoids leaked handles."},
+		{"Mutable default args", "gotcha", "Default arguments are evaluated once."},
+		{"Run tests with -race before merging", "pattern", "Run tests with -race before merging"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lessons, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		l := got[i]
+		if l.Title != w.title || l.Category != w.category || l.Content != w.content {
+			t.Errorf("lesson %d = (%q, %q, %q), want (%q, %q, %q)",
+				i, l.Title, l.Category, l.Content, w.title, w.category, w.content)
+		}
+		if l.Level != "project" || l.ID != "" {
+			t.Errorf("lesson %d: expected unnumbered project lesson, got ID %q level %q", i, l.ID, l.Level)
+		}
+	}
+
+	for _, title := range []string{"Empty heading", "Also empty"} {
+		if !strings.Contains(warnings.String(), title) {
+			t.Errorf("expected warning about %q, got: %s", title, warnings.String())
+		}
+	}
+}
+
+func Test_ImportMarkdown_LongTipTitle(t *testing.T) {
+	tip := "Prefer table driven tests because they keep every case visible and make adding new cases trivial"
+	got, err := ImportMarkdown(strings.NewReader("> tip: "+tip), "system", "preference", nil)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("expected one lesson, got %d (%v)", len(got), err)
+	}
+	if len(got[0].Title) > maxTipTitleLen+3 || !strings.HasSuffix(got[0].Title, "...") {
+		t.Errorf("expected truncated title, got %q", got[0].Title)
+	}
+	if got[0].Content != tip {
+		t.Errorf("expected full tip as content, got %q", got[0].Content)
+	}
+}
+
+func Test_RewriteCategories(t *testing.T) {
+	incoming := []*models.Lesson{
+		newImportLesson("L001", "project", "Pattern"),
+		newImportLesson("L002", "project", "Gotcha"),
+		newImportLesson("L003", "project", "Decision"),
+	}
+	incoming[0].Category = "pattern"
+	incoming[1].Category = "gotcha"
+	incoming[2].Category = "decision"
+
+	RewriteCategories(incoming, map[string]string{"pattern": "practice", "gotcha": "pitfall"})
+	if incoming[0].Category != "practice" || incoming[1].Category != "pitfall" || incoming[2].Category != "decision" {
+		t.Errorf("unexpected categories after map: %s, %s, %s", incoming[0].Category, incoming[1].Category, incoming[2].Category)
+	}
+
+	RewriteCategories(incoming, map[string]string{"pitfall": "gotcha", CategoryWildcard: "misc"})
+	if incoming[0].Category != "misc" || incoming[1].Category != "gotcha" || incoming[2].Category != "misc" {
+		t.Errorf("unexpected categories after wildcard: %s, %s, %s", incoming[0].Category, incoming[1].Category, incoming[2].Category)
+	}
+
+	// Targets the lessons file can't parse back are rejected and never applied
+	bad := map[string]string{"gotcha": "pit fall"}
+	if err := ValidateCategoryMap(bad); err == nil {
+		t.Error("expected ValidateCategoryMap to reject \"pit fall\"")
+	}
+	RewriteCategories(incoming, bad)
+	if incoming[1].Category != "gotcha" {
+		t.Errorf("invalid target applied: %s", incoming[1].Category)
+	}
+}
+
+func Test_Store_Import_RejectsInvalidCategory(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project", "LESSONS.md")
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	l := newImportLesson("L001", "project", "Bad category")
+	l.Category = "two words"
+	if _, err := store.Import([]*models.Lesson{l}, ConflictSkip); err == nil {
+		t.Error("Expected error for an invalid category")
+	}
+	if _, err := os.Stat(projectPath); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written, stat err = %v", err)
+	}
+}
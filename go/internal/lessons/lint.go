@@ -0,0 +1,171 @@
+package lessons
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pbrown/claude-recall/internal/checksum"
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+var (
+	// lintHeaderPattern matches any lesson header, including ones with IDs
+	// the parser would silently skip
+	lintHeaderPattern = regexp.MustCompile(`^### \[([^\]]*)\]`)
+
+	// Individual metadata fields, matched loosely so bad values can be reported
+	lintUsesPattern     = regexp.MustCompile(`\*\*Uses\*\*: ([^|\s]+)`)
+	lintVelocityPattern = regexp.MustCompile(`\*\*Velocity\*\*: ([^|\s]+)`)
+	lintLearnedPattern  = regexp.MustCompile(`\*\*Learned\*\*: ([^|\s]+)`)
+	lintLastPattern     = regexp.MustCompile(`\*\*Last\*\*: ([^|\s]+)`)
+)
+
+// Lint validates the project, system, and workspace lesson files line by line,
+// reporting malformed IDs, duplicate IDs, bad dates, and negative counters.
+// Missing files are skipped.
+func Lint(store *Store) ([]models.LintIssue, error) {
+	seen := make(map[string]string) // ID -> "file:line" of its first definition
+	now := time.Now()
+	var issues []models.LintIssue
+	for _, f := range store.levelFiles() {
+		fileIssues, err := lintLessonFile(f.path, levelPrefix(f.level), seen, now)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
+
+// LintChecksums reports lesson files whose checksum footer is missing or
+// doesn't match their content. Missing files are skipped.
+func LintChecksums(store *Store) ([]models.LintIssue, error) {
+	var issues []models.LintIssue
+	for _, f := range store.levelFiles() {
+		fileIssues, err := checksum.LintFile(f.path)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
+
+// lintLessonFile checks one LESSONS.md whose IDs must use prefix
+func lintLessonFile(path, prefix string, seen map[string]string, now time.Time) ([]models.LintIssue, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var issues []models.LintIssue
+	report := func(line int, format string, args ...interface{}) {
+		issues = append(issues, models.LintIssue{File: path, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	idPattern := regexp.MustCompile(`^` + prefix + `\d{3}$`)
+
+	// Header awaiting its metadata line (0 when none)
+	pendingLine := 0
+	pendingID := ""
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		if matches := lintHeaderPattern.FindStringSubmatch(line); matches != nil {
+			if pendingLine != 0 {
+				report(pendingLine, "lesson %s has no metadata line", pendingID)
+			}
+			id := matches[1]
+			if !idPattern.MatchString(id) {
+				report(lineNo, "invalid lesson ID %q (expected %s### in this file)", id, prefix)
+			}
+			if first, ok := seen[id]; ok {
+				report(lineNo, "duplicate lesson ID %s (first defined at %s)", id, first)
+			} else {
+				seen[id] = fmt.Sprintf("%s:%d", path, lineNo)
+			}
+			pendingLine, pendingID = lineNo, id
+			continue
+		}
+
+		if pendingLine != 0 && strings.HasPrefix(line, "- **Uses**:") {
+			pendingLine = 0
+			for _, msg := range lintMetadata(line, now) {
+				report(lineNo, "%s", msg)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if pendingLine != 0 {
+		report(pendingLine, "lesson %s has no metadata line", pendingID)
+	}
+	return issues, nil
+}
+
+// lintMetadata checks the counters and dates on a lesson's metadata line
+func lintMetadata(line string, now time.Time) []string {
+	var problems []string
+
+	if m := lintUsesPattern.FindStringSubmatch(line); m == nil {
+		problems = append(problems, "metadata line has no Uses value")
+	} else if uses, err := strconv.Atoi(m[1]); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid Uses value %q", m[1]))
+	} else if uses < 0 {
+		problems = append(problems, fmt.Sprintf("negative Uses value %d", uses))
+	}
+
+	if m := lintVelocityPattern.FindStringSubmatch(line); m == nil {
+		problems = append(problems, "metadata line has no Velocity value")
+	} else if velocity, err := strconv.ParseFloat(m[1], 64); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid Velocity value %q", m[1]))
+	} else if velocity < 0 {
+		problems = append(problems, fmt.Sprintf("negative Velocity value %g", velocity))
+	}
+
+	learned, learnedOK := lintDate(line, "Learned", lintLearnedPattern, now, &problems)
+	last, lastOK := lintDate(line, "Last", lintLastPattern, now, &problems)
+	if learnedOK && lastOK && last.Before(learned) {
+		problems = append(problems, fmt.Sprintf("Last date %s is before Learned date %s",
+			last.Format("2006-01-02"), learned.Format("2006-01-02")))
+	}
+
+	if len(problems) == 0 && !metadataPattern.MatchString(line) {
+		problems = append(problems, "malformed metadata line")
+	}
+	return problems
+}
+
+// lintDate parses a named date field, recording a problem if it is
+// missing, unparseable, or out of range
+func lintDate(line, name string, pattern *regexp.Regexp, now time.Time, problems *[]string) (time.Time, bool) {
+	m := pattern.FindStringSubmatch(line)
+	if m == nil {
+		*problems = append(*problems, fmt.Sprintf("metadata line has no %s date", name))
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", m[1])
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("invalid %s date %q", name, m[1]))
+		return time.Time{}, false
+	}
+	if !models.IsReasonableDate(t, now) {
+		*problems = append(*problems, fmt.Sprintf("%s date %s is out of range", name, m[1]))
+		return t, false
+	}
+	return t, true
+}
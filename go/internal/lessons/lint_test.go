@@ -0,0 +1,113 @@
+package lessons
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func lintMessages(issues []models.LintIssue) string {
+	var lines []string
+	for _, i := range issues {
+		lines = append(lines, i.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func Test_Lint_CleanFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	if _, err := store.Add("project", "pattern", "Clean lesson", "Nothing wrong here"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	issues, err := Lint(store)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got:\n%s", lintMessages(issues))
+	}
+}
+
+func Test_Lint_ReportsEachProblem(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "LESSONS.md")
+	systemPath := filepath.Join(dir, "SYSTEM.md")
+
+	project := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Fine
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> ok
+
+### [L001] [*----|-----] Duplicate
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> dup
+
+### [S002] [*----|-----] Wrong prefix
+- **Uses**: -3 | **Velocity**: -0.5 | **Learned**: 2026-13-01 | **Last**: 1999-01-01 | **Category**: pattern
+> bad values
+
+### [L004] [*----|-----] Time travel
+- **Uses**: 2 | **Velocity**: 0 | **Learned**: 2026-01-05 | **Last**: 2026-01-01 | **Category**: pattern
+> last before learned
+
+### [L005] [*----|-----] No metadata
+> content only
+`
+	system := `### [L010] [*----|-----] Project ID in system file
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> wrong file
+`
+	os.WriteFile(projectPath, []byte(project), 0644)
+	os.WriteFile(systemPath, []byte(system), 0644)
+
+	issues, err := Lint(NewStore(projectPath, systemPath))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	output := lintMessages(issues)
+
+	for _, want := range []string{
+		projectPath + ":9: duplicate lesson ID L001 (first defined at " + projectPath + ":5)",
+		projectPath + `:13: invalid lesson ID "S002" (expected L### in this file)`,
+		projectPath + ":14: negative Uses value -3",
+		projectPath + ":14: negative Velocity value -0.5",
+		projectPath + `:14: invalid Learned date "2026-13-01"`,
+		projectPath + ":14: Last date 1999-01-01 is out of range",
+		projectPath + ":18: Last date 2026-01-01 is before Learned date 2026-01-05",
+		projectPath + ":21: lesson L005 has no metadata line",
+		systemPath + `:1: invalid lesson ID "L010" (expected S### in this file)`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing issue %q in:\n%s", want, output)
+		}
+	}
+	if len(issues) != 9 {
+		t.Errorf("expected 9 issues, got %d:\n%s", len(issues), output)
+	}
+}
+
+func Test_Lint_FutureDate(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "LESSONS.md")
+	os.WriteFile(projectPath, []byte(`### [L001] [*----|-----] From the future
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2999-01-01 | **Last**: 2999-01-02 | **Category**: pattern
+> nope
+`), 0644)
+
+	issues, err := Lint(NewStore(projectPath, filepath.Join(dir, "missing.md")))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	output := lintMessages(issues)
+	if !strings.Contains(output, "Learned date 2999-01-01 is out of range") || !strings.Contains(output, "Last date 2999-01-02 is out of range") {
+		t.Errorf("expected future dates flagged, got:\n%s", output)
+	}
+}
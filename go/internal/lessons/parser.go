@@ -2,6 +2,7 @@ package lessons
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -10,12 +11,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pbrown/claude-recall/internal/checksum"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
 var (
 	// Header pattern: ### [L001] [***--|-----] Lesson Title
-	headerPattern = regexp.MustCompile(`^### \[([LS]\d{3})\] \[([*+\-| ]+)\] (.*)$`)
+	headerPattern = regexp.MustCompile(`^### \[([LSW]\d{3})\] \[([*+\-| ]+)\] (.*)$`)
 
 	// Metadata pattern: - **Uses**: 7 | **Velocity**: 0.01 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern
 	metadataPattern = regexp.MustCompile(`^\- \*\*Uses\*\*: (\d+) \| \*\*Velocity\*\*: ([\d.]+) \| \*\*Learned\*\*: (\d{4}-\d{2}-\d{2}) \| \*\*Last\*\*: (\d{4}-\d{2}-\d{2}) \| \*\*Category\*\*: (\w+)`)
@@ -24,21 +26,97 @@ var (
 	typePattern       = regexp.MustCompile(`\*\*Type\*\*: (\w+)`)
 	sourcePattern     = regexp.MustCompile(`\*\*Source\*\*: (\w+)`)
 	promotablePattern = regexp.MustCompile(`\*\*Promotable\*\*: (yes|no)`)
+	expiredPattern    = regexp.MustCompile(`\*\*Expired\*\*: (true|false)`)
+	confidencePattern = regexp.MustCompile(`\*\*Confidence\*\*: (\d+)`)
+	weightPattern     = regexp.MustCompile(`\*\*Weight\*\*: ([\d.]+)`)
+	preventedPattern  = regexp.MustCompile(`\*\*Prevented\*\*: (\d+)`)
+	gitPattern        = regexp.MustCompile(`\*\*Git\*\*: (\S+)@([0-9a-f]+)`)
 	triggersPattern   = regexp.MustCompile(`\*\*Triggers\*\*: (.+?)(?:\s*\||\s*$)`)
+	tagsPattern       = regexp.MustCompile(`\*\*Tags\*\*: (.+?)(?:\s*\||\s*$)`)
+
+	// Legacy tags line: - **Tags**: tag1, tag2, tag3 (now on the metadata line,
+	// where the Python core expects only content to follow)
+	legacyTagsPattern = regexp.MustCompile(`^\- \*\*Tags\*\*: (.*)$`)
 
 	// Content pattern: > Content line
 	contentPattern = regexp.MustCompile(`^> (.*)$`)
+
+	// Keyword patterns: code identifiers (fmt.Errorf, snake_case, camelCase)
+	// and capitalized words (Postgres, HTTP)
+	identifierPattern  = regexp.MustCompile(`\b[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)+\b|\b[A-Za-z0-9]+(?:_[A-Za-z0-9]+)+\b|\b[a-z]+[A-Z]\w*\b`)
+	capitalizedPattern = regexp.MustCompile(`\b[A-Z][A-Za-z0-9]+\b`)
 )
 
+// ExtractKeywords returns a lesson's high-signal terms for search indexing:
+// code identifiers and capitalized words in its content, plus its triggers.
+// Capitalized words that only start a sentence are skipped. Keywords are
+// returned once each, in order of appearance.
+func ExtractKeywords(l *models.Lesson) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	add := func(k string) {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			keywords = append(keywords, k)
+		}
+	}
+
+	for _, m := range identifierPattern.FindAllString(l.Content, -1) {
+		add(m)
+	}
+	for _, loc := range capitalizedPattern.FindAllStringIndex(l.Content, -1) {
+		if !startsSentence(l.Content, loc[0]) {
+			add(l.Content[loc[0]:loc[1]])
+		}
+	}
+	for _, t := range l.Triggers {
+		add(strings.TrimSpace(t))
+	}
+	return keywords
+}
+
+// startsSentence reports whether the word at index i of text is the first
+// of a sentence
+func startsSentence(text string, i int) bool {
+	before := strings.TrimRight(text[:i], " \t\n")
+	return before == "" || strings.ContainsAny(before[len(before)-1:], ".!?:")
+}
+
+// ParseOptions controls optional checks made while parsing
+type ParseOptions struct {
+	// VerifyChecksum fails parsing with *checksum.ErrChecksumMismatch when
+	// the file's checksum footer doesn't match its content
+	VerifyChecksum bool
+}
+
 // ParseFile reads and parses a LESSONS.md file
 func ParseFile(path string) ([]*models.Lesson, error) {
+	return ParseFileWithOptions(path, ParseOptions{})
+}
+
+// ParseFileWithOptions reads and parses a LESSONS.md file with opts
+func ParseFileWithOptions(path string, opts ParseOptions) ([]*models.Lesson, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
+	return ParseWithOptions(f, opts)
+}
 
-	return Parse(f)
+// ParseWithOptions parses LESSONS.md content from a reader with opts
+func ParseWithOptions(r io.Reader, opts ParseOptions) ([]*models.Lesson, error) {
+	if opts.VerifyChecksum {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := checksum.Verify(data); err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	return Parse(r)
 }
 
 // Parse parses LESSONS.md content from a reader
@@ -72,11 +150,16 @@ func Parse(r io.Reader) ([]*models.Lesson, error) {
 				Level:      "project",
 				Promotable: true,
 				Triggers:   []string{},
+				Tags:       []string{},
+				Confidence: models.DefaultConfidence,
+				Weight:     models.DefaultWeight,
 			}
 
 			// Determine level from ID
 			if strings.HasPrefix(id, "S") {
 				current.Level = "system"
+			} else if strings.HasPrefix(id, "W") {
+				current.Level = LevelWorkspace
 			}
 
 			continue
@@ -104,6 +187,28 @@ func Parse(r io.Reader) ([]*models.Lesson, error) {
 					current.Promotable = promMatch[1] == "yes"
 				}
 
+				if expMatch := expiredPattern.FindStringSubmatch(line); expMatch != nil {
+					current.Expired = expMatch[1] == "true"
+				}
+
+				if confMatch := confidencePattern.FindStringSubmatch(line); confMatch != nil {
+					current.Confidence, _ = strconv.Atoi(confMatch[1])
+				}
+
+				if weightMatch := weightPattern.FindStringSubmatch(line); weightMatch != nil {
+					if w, err := strconv.ParseFloat(weightMatch[1], 64); err == nil && models.IsValidWeight(w) {
+						current.Weight = w
+					}
+				}
+
+				if prevMatch := preventedPattern.FindStringSubmatch(line); prevMatch != nil {
+					current.Preventions, _ = strconv.Atoi(prevMatch[1])
+				}
+
+				if gitMatch := gitPattern.FindStringSubmatch(line); gitMatch != nil {
+					current.Git = &models.GitContext{Branch: gitMatch[1], ShortSHA: gitMatch[2]}
+				}
+
 				if trigMatch := triggersPattern.FindStringSubmatch(line); trigMatch != nil {
 					triggers := strings.Split(trigMatch[1], ",")
 					for i, t := range triggers {
@@ -112,6 +217,16 @@ func Parse(r io.Reader) ([]*models.Lesson, error) {
 					current.Triggers = triggers
 				}
 
+				if tagMatch := tagsPattern.FindStringSubmatch(line); tagMatch != nil {
+					current.Tags = models.NormalizeTags(strings.Split(tagMatch[1], ","))
+				}
+
+				continue
+			}
+
+			// Try to parse legacy tags line
+			if matches := legacyTagsPattern.FindStringSubmatch(line); matches != nil {
+				current.Tags = models.NormalizeTags(strings.Split(matches[1], ","))
 				continue
 			}
 
@@ -138,14 +253,18 @@ func Parse(r io.Reader) ([]*models.Lesson, error) {
 	return lessons, nil
 }
 
-// Serialize writes lessons back to LESSONS.md format
+// Serialize writes lessons back to LESSONS.md format, ending with a
+// checksum footer over everything above it
 func Serialize(lessons []*models.Lesson, level string) string {
 	var sb strings.Builder
 
 	// Write header
 	levelTitle := "Project"
-	if level == "system" {
+	switch level {
+	case "system":
 		levelTitle = "System"
+	case LevelWorkspace:
+		levelTitle = "Workspace"
 	}
 
 	sb.WriteString(fmt.Sprintf("# LESSONS.md - %s Level\n\n", levelTitle))
@@ -162,7 +281,7 @@ func Serialize(lessons []*models.Lesson, level string) string {
 		sb.WriteString("\n")
 	}
 
-	return sb.String()
+	return checksum.Append(sb.String())
 }
 
 // SerializeLesson formats a single lesson entry
@@ -198,10 +317,34 @@ func SerializeLesson(l *models.Lesson) string {
 		sb.WriteString(" | **Promotable**: no")
 	}
 
+	if l.Expired {
+		sb.WriteString(" | **Expired**: true")
+	}
+
+	if l.Confidence != models.DefaultConfidence {
+		sb.WriteString(fmt.Sprintf(" | **Confidence**: %d", l.Confidence))
+	}
+
+	if l.Weight != models.DefaultWeight && models.IsValidWeight(l.Weight) {
+		sb.WriteString(fmt.Sprintf(" | **Weight**: %g", l.Weight))
+	}
+
+	if l.Preventions > 0 {
+		sb.WriteString(fmt.Sprintf(" | **Prevented**: %d", l.Preventions))
+	}
+
+	if l.Git != nil {
+		sb.WriteString(fmt.Sprintf(" | **Git**: %s", l.Git))
+	}
+
 	if len(l.Triggers) > 0 {
 		sb.WriteString(fmt.Sprintf(" | **Triggers**: %s", strings.Join(l.Triggers, ", ")))
 	}
 
+	if len(l.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf(" | **Tags**: %s", strings.Join(l.Tags, ", ")))
+	}
+
 	sb.WriteString("\n")
 
 	// Content lines
@@ -1,10 +1,14 @@
 package lessons
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pbrown/claude-recall/internal/checksum"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
@@ -355,3 +359,201 @@ func TestParse_MultilineContent(t *testing.T) {
 		t.Errorf("Expected Content '%s', got '%s'", expectedContent, lessons[0].Content)
 	}
 }
+
+func TestParse_Tags(t *testing.T) {
+	// L001 uses the legacy Tags line; L003 has tags on the metadata line
+	input := `### [L001] [*----|-----] Tagged Lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern
+- **Tags**: go, Concurrency , testing
+> Tagged content.
+
+### [L002] [*----|-----] Untagged Lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern
+> Untagged content.
+
+### [L003] [*----|-----] Inline Tags
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern | **Triggers**: gofmt | **Tags**: Go, style
+> Inline content.
+`
+
+	lessons, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(lessons) != 3 {
+		t.Fatalf("Expected 3 lessons, got %d", len(lessons))
+	}
+	if strings.Join(lessons[2].Tags, ",") != "go,style" || strings.Join(lessons[2].Triggers, ",") != "gofmt" {
+		t.Errorf("Unexpected inline tags %v / triggers %v", lessons[2].Tags, lessons[2].Triggers)
+	}
+	if lessons[2].Content != "Inline content." {
+		t.Errorf("Unexpected content: %q", lessons[2].Content)
+	}
+
+	want := []string{"go", "concurrency", "testing"}
+	if strings.Join(lessons[0].Tags, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected Tags %v, got %v", want, lessons[0].Tags)
+	}
+	if lessons[0].Content != "Tagged content." {
+		t.Errorf("Tags line leaked into content: %q", lessons[0].Content)
+	}
+
+	// Lessons without a Tags line parse with an empty (non-nil) slice
+	if lessons[1].Tags == nil || len(lessons[1].Tags) != 0 {
+		t.Errorf("Expected empty Tags, got %#v", lessons[1].Tags)
+	}
+}
+
+func TestSerializeLesson_Tags(t *testing.T) {
+	l := models.NewLesson("L001", "Tagged", "Body")
+	l.Category = "pattern"
+	l.Tags = []string{"go", "testing"}
+
+	output := SerializeLesson(l)
+	// Tags share the metadata line so content still follows it directly,
+	// which is where the Python core reads it from
+	if !strings.Contains(output, "| **Category**: pattern | **Tags**: go, testing\n> Body\n") {
+		t.Errorf("Expected Tags on the metadata line, got:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if strings.Join(parsed[0].Tags, ",") != "go,testing" {
+		t.Errorf("Round trip lost tags: %v", parsed[0].Tags)
+	}
+
+	l.Tags = []string{}
+	if strings.Contains(SerializeLesson(l), "**Tags**") {
+		t.Error("Expected no Tags line for untagged lesson")
+	}
+}
+
+func TestSerializeLesson_GitContext(t *testing.T) {
+	l := models.NewLesson("L001", "Branch lesson", "Body")
+	l.Category = "gotcha"
+	l.Git = &models.GitContext{Branch: "main", ShortSHA: "abc1234"}
+	l.Triggers = []string{"deploy"}
+
+	output := SerializeLesson(l)
+	if !strings.Contains(output, " | **Git**: main@abc1234 | **Triggers**: deploy\n") {
+		t.Errorf("Expected Git field in metadata line, got:\n%s", output)
+	}
+
+	parsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed[0].Git == nil || parsed[0].Git.String() != "main@abc1234" {
+		t.Errorf("Round trip lost git context: %v", parsed[0].Git)
+	}
+	if strings.Join(parsed[0].Triggers, ",") != "deploy" {
+		t.Errorf("Expected triggers to still parse, got %v", parsed[0].Triggers)
+	}
+
+	l.Git = nil
+	if strings.Contains(SerializeLesson(l), "**Git**") {
+		t.Error("Expected no Git field without git context")
+	}
+}
+
+func TestParse_ConfidenceDefaultsAndRoundTrips(t *testing.T) {
+	input := `### [L001] [*----|-----] Unrated
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> No confidence field
+`
+	parsed, err := Parse(strings.NewReader(input))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed[0].Confidence != models.DefaultConfidence {
+		t.Errorf("Expected default confidence %d, got %d", models.DefaultConfidence, parsed[0].Confidence)
+	}
+	if strings.Contains(SerializeLesson(parsed[0]), "**Confidence**") {
+		t.Error("Expected default confidence to be omitted from metadata")
+	}
+
+	parsed[0].Confidence = 75
+	output := SerializeLesson(parsed[0])
+	if !strings.Contains(output, " | **Confidence**: 75") {
+		t.Errorf("Expected Confidence in metadata line, got:\n%s", output)
+	}
+
+	reparsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(reparsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if reparsed[0].Confidence != 75 {
+		t.Errorf("Expected confidence 75 after round trip, got %d", reparsed[0].Confidence)
+	}
+}
+
+func TestParse_WeightDefaultsAndRoundTrips(t *testing.T) {
+	input := `### [L001] [*----|-----] Unweighted
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-02 | **Category**: pattern
+> No weight field
+`
+	parsed, err := Parse(strings.NewReader(input))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed[0].Weight != models.DefaultWeight {
+		t.Errorf("Expected default weight %g, got %g", models.DefaultWeight, parsed[0].Weight)
+	}
+	if strings.Contains(SerializeLesson(parsed[0]), "**Weight**") {
+		t.Error("Expected default weight to be omitted from metadata")
+	}
+
+	parsed[0].Weight = 2.5
+	output := SerializeLesson(parsed[0])
+	if !strings.Contains(output, " | **Weight**: 2.5") {
+		t.Errorf("Expected Weight in metadata line, got:\n%s", output)
+	}
+
+	reparsed, err := Parse(strings.NewReader(output))
+	if err != nil || len(reparsed) != 1 {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if reparsed[0].Weight != 2.5 {
+		t.Errorf("Expected weight 2.5 after round trip, got %g", reparsed[0].Weight)
+	}
+}
+
+func TestParseFile_VerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "LESSONS.md")
+	lessons := []*models.Lesson{{ID: "L001", Title: "Checksummed", Content: "Intact content", Category: "pattern",
+		Learned: time.Now(), LastUsed: time.Now(), Confidence: models.DefaultConfidence, Weight: models.DefaultWeight}}
+	data := []byte(Serialize(lessons, "project"))
+	os.WriteFile(path, data, 0644)
+
+	opts := ParseOptions{VerifyChecksum: true}
+	if parsed, err := ParseFileWithOptions(path, opts); err != nil || len(parsed) != 1 {
+		t.Fatalf("expected intact file to parse, got %v (%d lessons)", err, len(parsed))
+	}
+
+	// Corrupt one byte of the content
+	i := strings.Index(string(data), "Intact")
+	data[i] = 'X'
+	os.WriteFile(path, data, 0644)
+
+	var mismatch *checksum.ErrChecksumMismatch
+	if _, err := ParseFileWithOptions(path, opts); !errors.As(err, &mismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, err := ParseFile(path); err != nil {
+		t.Errorf("expected parsing without verification to succeed, got %v", err)
+	}
+}
+
+func TestExtractKeywords(t *testing.T) {
+	l := &models.Lesson{
+		Content:  "Wrap errors with fmt.Errorf in Postgres code. Never log and return; check max_retries and retryCount.",
+		Triggers: []string{"wrapping", "fmt.Errorf"},
+	}
+	got := ExtractKeywords(l)
+	want := []string{"fmt.Errorf", "max_retries", "retryCount", "Postgres", "wrapping"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExtractKeywords = %v, want %v", got, want)
+	}
+}
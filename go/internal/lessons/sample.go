@@ -0,0 +1,48 @@
+package lessons
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// WeightedRandomSample picks up to n lessons without replacement, each
+// draw favouring lessons in proportion to weight(l). The result is in draw
+// order, so it also varies which lesson comes first. The same seed always
+// yields the same sample. Lessons with no positive weight are only picked
+// once every positively weighted lesson has been.
+func WeightedRandomSample(lessons []*models.Lesson, n int, seed int64, weight func(*models.Lesson) float64) []*models.Lesson {
+	if n > len(lessons) {
+		n = len(lessons)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	// Efraimidis-Spirakis: key u^(1/w) for uniform u; the n largest keys
+	// are a weighted sample without replacement
+	rng := rand.New(rand.NewSource(seed))
+	type keyed struct {
+		lesson *models.Lesson
+		key    float64
+	}
+	keys := make([]keyed, len(lessons))
+	for i, l := range lessons {
+		k := 0.0
+		if w := weight(l); w > 0 {
+			k = math.Pow(rng.Float64(), 1/w)
+		}
+		keys[i] = keyed{lesson: l, key: k}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].key > keys[j].key
+	})
+
+	sample := make([]*models.Lesson, n)
+	for i := range sample {
+		sample[i] = keys[i].lesson
+	}
+	return sample
+}
@@ -0,0 +1,66 @@
+package lessons
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func sampleIDs(sample []*models.Lesson) []string {
+	ids := make([]string, len(sample))
+	for i, l := range sample {
+		ids[i] = l.ID
+	}
+	return ids
+}
+
+func Test_WeightedRandomSample(t *testing.T) {
+	var all []*models.Lesson
+	for i := 1; i <= 10; i++ {
+		l := models.NewLesson(fmt.Sprintf("L%03d", i), fmt.Sprintf("Lesson %d", i), "content")
+		l.Uses = i
+		all = append(all, l)
+	}
+	byUses := func(l *models.Lesson) float64 { return float64(l.Uses) }
+
+	first := sampleIDs(WeightedRandomSample(all, 5, 42, byUses))
+	if len(first) != 5 {
+		t.Fatalf("expected 5 lessons, got %v", first)
+	}
+	if again := sampleIDs(WeightedRandomSample(all, 5, 42, byUses)); !reflect.DeepEqual(first, again) {
+		t.Errorf("same seed gave different samples: %v vs %v", first, again)
+	}
+
+	// Different seeds vary the ordering
+	varied := false
+	for seed := int64(1); seed <= 10 && !varied; seed++ {
+		varied = !reflect.DeepEqual(first, sampleIDs(WeightedRandomSample(all, 5, seed, byUses)))
+	}
+	if !varied {
+		t.Error("expected different seeds to produce different orderings")
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range first {
+		if seen[id] {
+			t.Errorf("lesson %s sampled twice: %v", id, first)
+		}
+		seen[id] = true
+	}
+
+	// Zero-weight lessons only fill in after weighted ones
+	zeroFirst := func(l *models.Lesson) float64 {
+		if l.ID == "L001" {
+			return 1
+		}
+		return 0
+	}
+	if got := sampleIDs(WeightedRandomSample(all, 2, 7, zeroFirst)); got[0] != "L001" {
+		t.Errorf("expected the only weighted lesson first, got %v", got)
+	}
+	if got := WeightedRandomSample(all, 20, 1, byUses); len(got) != 10 {
+		t.Errorf("expected n capped at 10, got %d", len(got))
+	}
+}
@@ -1,9 +1,11 @@
 package lessons
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,39 +13,141 @@ import (
 
 	"github.com/pbrown/claude-recall/internal/lock"
 	"github.com/pbrown/claude-recall/internal/models"
+	"github.com/pbrown/claude-recall/internal/similarity"
 )
 
-// Store manages lessons in project and system LESSONS.md files
+// LevelShared marks lessons read from a shared LESSONS.md library
+const LevelShared = "shared"
+
+// ErrSharedLesson is returned when a change targets a lesson that only
+// exists in a read-only shared library
+var ErrSharedLesson = errors.New("read-only shared lesson")
+
+// LevelWorkspace marks lessons from a team workspace LESSONS.md, which sits
+// above the system level
+const LevelWorkspace = "workspace"
+
+// Store manages lessons in project, system, and optional workspace
+// LESSONS.md files, plus any read-only shared lesson libraries
 type Store struct {
-	projectPath string // Path to project LESSONS.md
-	systemPath  string // Path to system LESSONS.md
+	projectPath    string   // Path to project LESSONS.md
+	systemPath     string   // Path to system LESSONS.md
+	workspacePath  string   // Optional path to workspace LESSONS.md
+	sharedPaths    []string // Additional read-only LESSONS.md files
+	dedupThreshold float64  // Similarity at which Add rejects a duplicate
+
+	similarity similarity.Func // Text similarity for dedup (nil = Jaccard)
+
+	gitProvider GitContextProvider // Optional; attaches git context to new lessons
+	gitDir      string             // Directory the git context is read from
+
+	audit *AuditLog // Optional; records adds, edits, deletes, and citations
 }
 
-// NewStore creates a store with paths to lesson files
-func NewStore(projectPath, systemPath string) *Store {
+// NewStore creates a store with paths to lesson files. Shared paths are
+// merged into List but never written to.
+func NewStore(projectPath, systemPath string, sharedPaths ...string) *Store {
 	return &Store{
-		projectPath: projectPath,
-		systemPath:  systemPath,
+		projectPath:    projectPath,
+		systemPath:     systemPath,
+		sharedPaths:    sharedPaths,
+		dedupThreshold: DefaultDedupThreshold,
+	}
+}
+
+// StorePaths lists the lessons files a store reads
+type StorePaths struct {
+	Project   string
+	System    string
+	Shared    []string // Read-only shared libraries
+	Workspace string   // Team workspace file ("" = no workspace level)
+}
+
+// Files returns every lessons file in paths: project, system, workspace
+// when set, then the shared libraries
+func (p StorePaths) Files() []string {
+	files := []string{p.Project, p.System}
+	if p.Workspace != "" {
+		files = append(files, p.Workspace)
+	}
+	return append(files, p.Shared...)
+}
+
+// OpenStore creates a store over every file in paths
+func OpenStore(paths StorePaths) *Store {
+	s := NewStore(paths.Project, paths.System, paths.Shared...)
+	s.SetWorkspacePath(paths.Workspace)
+	return s
+}
+
+// SetGitContextProvider makes new lessons record the branch and commit of
+// dir. A nil provider disables git context.
+func (s *Store) SetGitContextProvider(p GitContextProvider, dir string) {
+	s.gitProvider = p
+	s.gitDir = dir
+}
+
+// SetWorkspacePath adds a workspace LESSONS.md (W### IDs) to the store. An
+// empty path disables the workspace level.
+func (s *Store) SetWorkspacePath(path string) {
+	s.workspacePath = path
+}
+
+// levelFile pairs a writable lessons file with its level
+type levelFile struct {
+	path, level string
+}
+
+// levelFiles returns the writable lesson files in precedence order: project,
+// system, then workspace when configured
+func (s *Store) levelFiles() []levelFile {
+	files := []levelFile{{s.projectPath, "project"}, {s.systemPath, "system"}}
+	if s.workspacePath != "" {
+		files = append(files, levelFile{s.workspacePath, LevelWorkspace})
 	}
+	return files
 }
 
-// List returns all lessons (project + system) sorted by ID
+// levelPrefix returns the ID prefix for lessons at level
+func levelPrefix(level string) string {
+	switch level {
+	case "system":
+		return "S"
+	case LevelWorkspace:
+		return "W"
+	default:
+		return "L"
+	}
+}
+
+// SetAuditLog records subsequent Add, Edit, Delete, and Cite calls in log.
+// A nil log disables auditing.
+func (s *Store) SetAuditLog(log *AuditLog) {
+	s.audit = log
+}
+
+// List returns all lessons (project + system + workspace + shared) sorted
+// by ID. When the same ID appears more than once, project, system, and
+// workspace lessons win over shared ones, and earlier shared paths win over
+// later ones.
 func (s *Store) List() ([]*models.Lesson, error) {
 	var all []*models.Lesson
 
-	// Load project lessons (NotExist is handled in loadLessons)
-	projectLessons, err := s.loadLessons(s.projectPath, "project")
-	if err != nil {
-		return nil, fmt.Errorf("loading project lessons: %w", err)
+	// Load each level's lessons (NotExist is handled in loadLessons)
+	for _, f := range s.levelFiles() {
+		loaded, err := s.loadLessons(f.path, f.level)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s lessons: %w", f.level, err)
+		}
+		all = append(all, loaded...)
 	}
-	all = append(all, projectLessons...)
 
-	// Load system lessons (NotExist is handled in loadLessons)
-	systemLessons, err := s.loadLessons(s.systemPath, "system")
+	shared, err := s.loadShared()
 	if err != nil {
-		return nil, fmt.Errorf("loading system lessons: %w", err)
+		return nil, err
 	}
-	all = append(all, systemLessons...)
+	all = append(all, shared...)
+	all = dedupByID(all)
 
 	// Sort by ID
 	sort.Slice(all, func(i, j int) bool {
@@ -53,7 +157,47 @@ func (s *Store) List() ([]*models.Lesson, error) {
 	return all, nil
 }
 
-// Get returns a lesson by ID (searches both project and system)
+// ListShared returns only lessons from the shared paths, sorted by ID
+func (s *Store) ListShared() ([]*models.Lesson, error) {
+	shared, err := s.loadShared()
+	if err != nil {
+		return nil, err
+	}
+	shared = dedupByID(shared)
+	sort.Slice(shared, func(i, j int) bool {
+		return shared[i].ID < shared[j].ID
+	})
+	return shared, nil
+}
+
+// loadShared reads every shared path in order (missing files are skipped)
+func (s *Store) loadShared() ([]*models.Lesson, error) {
+	var shared []*models.Lesson
+	for _, path := range s.sharedPaths {
+		loaded, err := s.loadLessons(path, LevelShared)
+		if err != nil {
+			return nil, fmt.Errorf("loading shared lessons from %s: %w", path, err)
+		}
+		shared = append(shared, loaded...)
+	}
+	return shared, nil
+}
+
+// dedupByID keeps the first lesson seen for each ID
+func dedupByID(all []*models.Lesson) []*models.Lesson {
+	seen := make(map[string]bool, len(all))
+	deduped := all[:0]
+	for _, l := range all {
+		if seen[l.ID] {
+			continue
+		}
+		seen[l.ID] = true
+		deduped = append(deduped, l)
+	}
+	return deduped
+}
+
+// Get returns a lesson by ID (searches every level)
 func (s *Store) Get(id string) (*models.Lesson, error) {
 	lessons, err := s.List()
 	if err != nil {
@@ -69,15 +213,87 @@ func (s *Store) Get(id string) (*models.Lesson, error) {
 	return nil, fmt.Errorf("lesson %s not found", id)
 }
 
-// Add creates a new lesson (returns new ID)
+// FindByTriggers returns lessons with any trigger appearing in text as a whole
+// word (case-insensitive), sorted by uses + velocity descending. See
+// triggerPattern for triggers that start or end with punctuation.
+func (s *Store) FindByTriggers(text string) ([]*models.Lesson, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []*models.Lesson{}
+	for _, l := range all {
+		for _, trigger := range l.Triggers {
+			trigger = strings.TrimSpace(trigger)
+			if trigger == "" {
+				continue
+			}
+			if triggerPattern(trigger).MatchString(text) {
+				matched = append(matched, l)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return float64(matched[i].Uses)+matched[i].Velocity > float64(matched[j].Uses)+matched[j].Velocity
+	})
+
+	return matched, nil
+}
+
+// triggerPattern matches trigger case-insensitively, requiring a word
+// boundary only at an edge that is a word character: \b next to punctuation
+// would need a word character outside it, so "c++", ".env", and "--force"
+// could never match on their own
+func triggerPattern(trigger string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(trigger)
+	if isWordByte(trigger[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(trigger[len(trigger)-1]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+// isWordByte reports whether c is in \w ([0-9A-Za-z_])
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// Add creates a new lesson (returns new ID). If a similar lesson already
+// exists, it returns a *DuplicateError wrapping ErrDuplicateLesson.
 func (s *Store) Add(level, category, title, content string) (*models.Lesson, error) {
+	content = normalizeContent(content)
+	similar, similarity, err := s.FindSimilar(title, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+	if len(similar) > 0 {
+		return nil, &DuplicateError{ID: similar[0].ID, Similarity: similarity}
+	}
+
+	return s.ForceAdd(level, category, title, content)
+}
+
+// ForceAdd creates a new lesson without checking for duplicates
+func (s *Store) ForceAdd(level, category, title, content string) (*models.Lesson, error) {
+	content = normalizeContent(content)
+
 	// Determine which file to use
 	path := s.projectPath
-	prefix := "L"
-	if level == "system" {
+	switch level {
+	case "system":
 		path = s.systemPath
-		prefix = "S"
+	case LevelWorkspace:
+		if s.workspacePath == "" {
+			return nil, fmt.Errorf("no workspace configured (set workspace_path in config.json)")
+		}
+		path = s.workspacePath
 	}
+	prefix := levelPrefix(level)
 
 	// Ensure directory exists
 	dir := filepath.Dir(path)
@@ -85,6 +301,14 @@ func (s *Store) Add(level, category, title, content string) (*models.Lesson, err
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	// Acquire lock before picking the ID so concurrent adds can't collide
+	lockPath := path + ".lock"
+	fl, err := lock.Acquire(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
 	// Get next ID
 	nextID, err := s.NextID(prefix)
 	if err != nil {
@@ -106,15 +330,17 @@ func (s *Store) Add(level, category, title, content string) (*models.Lesson, err
 		Level:      level,
 		Promotable: true,
 		Triggers:   []string{},
+		Tags:       []string{},
+		Confidence: models.DefaultConfidence,
+		Weight:     models.DefaultWeight,
 	}
 
-	// Acquire lock and write
-	lockPath := path + ".lock"
-	fl, err := lock.Acquire(lockPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	// Git context is best-effort: outside a repository the lesson is still added
+	if s.gitProvider != nil {
+		if gc, err := s.gitProvider.GetGitContext(s.gitDir); err == nil {
+			lesson.Git = gc
+		}
 	}
-	defer fl.Release()
 
 	// Load existing lessons
 	lessons, _ := s.loadLessons(path, level)
@@ -125,11 +351,17 @@ func (s *Store) Add(level, category, title, content string) (*models.Lesson, err
 		return nil, fmt.Errorf("failed to write lessons: %w", err)
 	}
 
+	s.logAudit(AuditEvent{Event: AuditAdd, LessonID: lesson.ID, Field: "title", NewValue: lesson.Title})
 	return lesson, nil
 }
 
 // Cite increments uses and velocity for a lesson
 func (s *Store) Cite(id string) error {
+	return s.CiteInSession(id, "")
+}
+
+// CiteInSession is Cite with the citing session's ID, kept in the audit log
+func (s *Store) CiteInSession(id, sessionID string) error {
 	// Find the lesson and its file
 	path, level, err := s.findLessonFile(id)
 	if err != nil {
@@ -152,14 +384,18 @@ func (s *Store) Cite(id string) error {
 
 	// Find and update the lesson
 	found := false
+	var oldUses, newUses int
 	for _, l := range lessons {
 		if l.ID == id {
+			oldUses = l.Uses
 			l.Uses++
 			if l.Uses > models.MaxUses {
 				l.Uses = models.MaxUses
 			}
 			l.Velocity += 1.0
 			l.LastUsed = time.Now()
+			l.Expired = false
+			newUses = l.Uses
 			found = true
 			break
 		}
@@ -170,11 +406,69 @@ func (s *Store) Cite(id string) error {
 	}
 
 	// Write back
-	return s.writeLessons(path, lessons, level)
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return err
+	}
+
+	s.logAudit(AuditEvent{Event: AuditCite, LessonID: id, Field: "uses",
+		OldValue: strconv.Itoa(oldUses), NewValue: strconv.Itoa(newUses), SessionID: sessionID})
+	return nil
+}
+
+// RecordPrevention increments the count of mistakes a lesson prevented
+func (s *Store) RecordPrevention(id string) error {
+	return s.RecordPreventionNote(id, "")
+}
+
+// RecordPreventionNote is RecordPrevention with a description of what was
+// prevented, kept in the audit log
+func (s *Store) RecordPreventionNote(id, note string) error {
+	path, level, err := s.findLessonFile(id)
+	if err != nil {
+		return err
+	}
+
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	lessons, err := s.loadLessons(path, level)
+	if err != nil {
+		return err
+	}
+
+	var target *models.Lesson
+	for _, l := range lessons {
+		if l.ID == id {
+			target = l
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("lesson %s not found", id)
+	}
+	target.Preventions++
+
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return err
+	}
+
+	s.logAudit(AuditEvent{Event: AuditPrevent, LessonID: id, Field: "preventions",
+		OldValue: strconv.Itoa(target.Preventions - 1), NewValue: strconv.Itoa(target.Preventions), Note: note})
+	return nil
 }
 
 // Edit modifies an existing lesson
 func (s *Store) Edit(id string, updates map[string]interface{}) error {
+	if confidence, ok := updates["confidence"].(int); ok && !models.IsValidConfidence(confidence) {
+		return fmt.Errorf("invalid confidence %d (use 0-%d)", confidence, models.MaxConfidence)
+	}
+	if weight, ok := updates["weight"].(float64); ok && !models.IsValidWeight(weight) {
+		return fmt.Errorf("invalid weight %g (must be positive)", weight)
+	}
+
 	// Find the lesson and its file
 	path, level, err := s.findLessonFile(id)
 	if err != nil {
@@ -196,21 +490,32 @@ func (s *Store) Edit(id string, updates map[string]interface{}) error {
 	}
 
 	// Find and update the lesson
-	found := false
+	var before, after [][2]string
 	for _, l := range lessons {
 		if l.ID == id {
+			before = auditFields(l)
 			applyUpdates(l, updates)
-			found = true
+			after = auditFields(l)
 			break
 		}
 	}
 
-	if !found {
+	if before == nil {
 		return fmt.Errorf("lesson %s not found", id)
 	}
 
 	// Write back
-	return s.writeLessons(path, lessons, level)
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return err
+	}
+
+	for i := range before {
+		if before[i][1] != after[i][1] {
+			s.logAudit(AuditEvent{Event: AuditEdit, LessonID: id, Field: before[i][0],
+				OldValue: before[i][1], NewValue: after[i][1]})
+		}
+	}
+	return nil
 }
 
 // Delete removes a lesson by ID
@@ -237,24 +542,250 @@ func (s *Store) Delete(id string) error {
 
 	// Filter out the deleted lesson
 	var remaining []*models.Lesson
-	found := false
+	var deleted *models.Lesson
 	for _, l := range lessons {
 		if l.ID == id {
-			found = true
+			deleted = l
 		} else {
 			remaining = append(remaining, l)
 		}
 	}
 
-	if !found {
+	if deleted == nil {
 		return fmt.Errorf("lesson %s not found", id)
 	}
 
 	// Write back
-	return s.writeLessons(path, remaining, level)
+	if err := s.writeLessons(path, remaining, level); err != nil {
+		return err
+	}
+
+	s.logAudit(AuditEvent{Event: AuditDelete, LessonID: id, Field: "title", OldValue: deleted.Title})
+	return nil
 }
 
-// NextID returns the next available ID for a level ("L" or "S")
+// Promote moves a project lesson to the system file under a new S### ID,
+// preserving its uses, velocity, dates, and content. Returns the new lesson.
+func (s *Store) Promote(id string) (*models.Lesson, error) {
+	if !strings.HasPrefix(id, "L") {
+		return nil, fmt.Errorf("lesson %s is not a project lesson", id)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.systemPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Lock both files in a fixed order (project, then system)
+	projectLock, err := lock.Acquire(s.projectPath + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer projectLock.Release()
+
+	systemLock, err := lock.Acquire(s.systemPath + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer systemLock.Release()
+
+	projectLessons, err := s.loadLessons(s.projectPath, "project")
+	if err != nil {
+		return nil, err
+	}
+	systemLessons, err := s.loadLessons(s.systemPath, "system")
+	if err != nil {
+		return nil, err
+	}
+
+	// Split out the lesson being promoted
+	var promoted *models.Lesson
+	var remaining []*models.Lesson
+	for _, l := range projectLessons {
+		if l.ID == id {
+			promoted = l
+		} else {
+			remaining = append(remaining, l)
+		}
+	}
+	if promoted == nil {
+		return nil, fmt.Errorf("lesson %s not found", id)
+	}
+
+	maxNum := 0
+	for _, l := range systemLessons {
+		if num := idNumber(l.ID, "S"); num > maxNum {
+			maxNum = num
+		}
+	}
+	promoted.ID = fmt.Sprintf("S%03d", maxNum+1)
+	promoted.Level = "system"
+
+	// Write the system file first so a failure never loses the lesson
+	systemLessons = append(systemLessons, promoted)
+	if err := s.writeLessons(s.systemPath, systemLessons, "system"); err != nil {
+		return nil, fmt.Errorf("failed to write system lessons: %w", err)
+	}
+	if err := s.writeLessons(s.projectPath, remaining, "project"); err != nil {
+		return nil, fmt.Errorf("failed to write project lessons: %w", err)
+	}
+
+	return promoted, nil
+}
+
+// Split carves newContent out of an overly broad lesson into a new lesson
+// titled newTitle in the same file. The original keeps its ID and its
+// content minus newContent (unchanged if newContent is not part of it). The
+// new lesson takes half the uses (rounded down) and half the velocity.
+// Returns the new lesson.
+func (s *Store) Split(id string, newTitle, newContent string) (*models.Lesson, error) {
+	if strings.TrimSpace(newTitle) == "" || strings.TrimSpace(newContent) == "" {
+		return nil, fmt.Errorf("split requires a title and content for the new lesson")
+	}
+
+	path, level, err := s.findLessonFile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	lessons, err := s.loadLessons(path, level)
+	if err != nil {
+		return nil, err
+	}
+
+	var original *models.Lesson
+	maxNum := 0
+	prefix := id[:1]
+	for _, l := range lessons {
+		if l.ID == id {
+			original = l
+		}
+		if num := idNumber(l.ID, prefix); num > maxNum {
+			maxNum = num
+		}
+	}
+	if original == nil {
+		return nil, fmt.Errorf("lesson %s not found", id)
+	}
+
+	remainder := strings.TrimSpace(strings.Replace(original.Content, strings.TrimSpace(newContent), "", 1))
+	if remainder == "" {
+		return nil, fmt.Errorf("splitting would leave lesson %s with no content", id)
+	}
+
+	split := &models.Lesson{
+		ID:         fmt.Sprintf("%s%03d", prefix, maxNum+1),
+		Title:      newTitle,
+		Content:    newContent,
+		Uses:       original.Uses / 2,
+		Velocity:   original.Velocity / 2,
+		Learned:    original.Learned,
+		LastUsed:   original.LastUsed,
+		Category:   original.Category,
+		Source:     original.Source,
+		Level:      level,
+		Promotable: original.Promotable,
+		Triggers:   []string{},
+		Tags:       append([]string{}, original.Tags...),
+		Confidence: original.Confidence,
+		Weight:     original.Weight,
+		Git:        original.Git,
+	}
+
+	oldContent := original.Content
+	original.Content = remainder
+	original.Uses -= split.Uses
+	original.Velocity -= split.Velocity
+
+	// Both lessons live in the same file, so one write keeps them consistent
+	lessons = append(lessons, split)
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return nil, fmt.Errorf("failed to write lessons: %w", err)
+	}
+
+	if remainder != oldContent {
+		s.logAudit(AuditEvent{Event: AuditEdit, LessonID: id, Field: "content", OldValue: oldContent, NewValue: remainder})
+	}
+	s.logAudit(AuditEvent{Event: AuditAdd, LessonID: split.ID, Field: "title", NewValue: split.Title})
+	return split, nil
+}
+
+// categoryNamePattern matches the category names the metadata line parses
+// back: letters, digits, and underscores
+var categoryNamePattern = regexp.MustCompile(`^\w+$`)
+
+// ValidateCategory returns an error if name can't be stored as a category
+func ValidateCategory(name string) error {
+	if name == "" {
+		return fmt.Errorf("category name cannot be empty")
+	}
+	if !categoryNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid category name %q (use letters, digits, and underscores)", name)
+	}
+	return nil
+}
+
+// RenameCategory changes the category of every project, system, and
+// workspace lesson in oldName to newName, returning how many lessons were
+// updated. Only files with matching lessons are rewritten. Shared lessons
+// are never modified.
+func (s *Store) RenameCategory(oldName, newName string) (int, error) {
+	if err := ValidateCategory(newName); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, f := range s.levelFiles() {
+		n, err := s.renameCategoryInFile(f.path, f.level, oldName, newName)
+		if err != nil {
+			return updated, err
+		}
+		updated += n
+	}
+
+	return updated, nil
+}
+
+// renameCategoryInFile renames a category within a single lessons file
+func (s *Store) renameCategoryInFile(path, level, oldName, newName string) (int, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	fl, err := lock.Acquire(path + ".lock")
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fl.Release()
+
+	lessons, err := s.loadLessons(path, level)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, l := range lessons {
+		if l.Category == oldName {
+			l.Category = newName
+			updated++
+		}
+	}
+	if updated == 0 {
+		return 0, nil
+	}
+
+	if err := s.writeLessons(path, lessons, level); err != nil {
+		return 0, fmt.Errorf("failed to write lessons: %w", err)
+	}
+	return updated, nil
+}
+
+// NextID returns the next available ID for a level ("L", "S", or "W")
 func (s *Store) NextID(prefix string) (string, error) {
 	lessons, err := s.List()
 	if err != nil {
@@ -292,28 +823,36 @@ func (s *Store) loadLessons(path, level string) ([]*models.Lesson, error) {
 	return lessons, nil
 }
 
-// writeLessons writes lessons to a file
+// writeLessons writes lessons to a file atomically (temp file + rename)
 func (s *Store) writeLessons(path string, lessons []*models.Lesson, level string) error {
 	content := Serialize(lessons, level)
-	return os.WriteFile(path, []byte(content), 0644)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
 
 // findLessonFile returns the path and level for a lesson ID
 func (s *Store) findLessonFile(id string) (string, string, error) {
-	if strings.HasPrefix(id, "L") {
-		// Check if it exists in project file
-		lessons, _ := s.loadLessons(s.projectPath, "project")
+	for _, f := range s.levelFiles() {
+		if !strings.HasPrefix(id, levelPrefix(f.level)) {
+			continue
+		}
+		// Check if it exists in the level's file
+		lessons, _ := s.loadLessons(f.path, f.level)
 		for _, l := range lessons {
 			if l.ID == id {
-				return s.projectPath, "project", nil
+				return f.path, f.level, nil
 			}
 		}
-	} else if strings.HasPrefix(id, "S") {
-		// Check if it exists in system file
-		lessons, _ := s.loadLessons(s.systemPath, "system")
-		for _, l := range lessons {
+	}
+
+	if shared, err := s.loadShared(); err == nil {
+		for _, l := range shared {
 			if l.ID == id {
-				return s.systemPath, "system", nil
+				return "", "", fmt.Errorf("lesson %s: %w", id, ErrSharedLesson)
 			}
 		}
 	}
@@ -321,13 +860,29 @@ func (s *Store) findLessonFile(id string) (string, string, error) {
 	return "", "", fmt.Errorf("lesson %s not found", id)
 }
 
+// normalizeContent cleans up pasted lesson content: CRLF becomes LF,
+// trailing whitespace is stripped from each line, runs of blank lines
+// collapse to one, and the whole is trimmed. Clean content is unchanged.
+func normalizeContent(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	s = strings.Join(lines, "\n")
+	for strings.Contains(s, "\n\n\n") {
+		s = strings.ReplaceAll(s, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(s)
+}
+
 // applyUpdates applies update map to a lesson
 func applyUpdates(l *models.Lesson, updates map[string]interface{}) {
 	if title, ok := updates["title"].(string); ok {
 		l.Title = title
 	}
 	if content, ok := updates["content"].(string); ok {
-		l.Content = content
+		l.Content = normalizeContent(content)
 	}
 	if category, ok := updates["category"].(string); ok {
 		l.Category = category
@@ -341,7 +896,35 @@ func applyUpdates(l *models.Lesson, updates map[string]interface{}) {
 	if promotable, ok := updates["promotable"].(bool); ok {
 		l.Promotable = promotable
 	}
+	if confidence, ok := updates["confidence"].(int); ok {
+		l.Confidence = confidence
+	}
+	if weight, ok := updates["weight"].(float64); ok {
+		l.Weight = weight
+	}
 	if triggers, ok := updates["triggers"].([]string); ok {
 		l.Triggers = triggers
 	}
+	if tags, ok := updates["tags"].([]string); ok {
+		l.Tags = models.NormalizeTags(tags)
+	}
+	if addTags, ok := updates["add_tags"].([]string); ok {
+		l.Tags = models.NormalizeTags(append(l.Tags, addTags...))
+	}
+	if removeTags, ok := updates["remove_tags"].([]string); ok {
+		kept := []string{}
+		for _, t := range l.Tags {
+			remove := false
+			for _, r := range removeTags {
+				if strings.EqualFold(t, strings.TrimSpace(r)) {
+					remove = true
+					break
+				}
+			}
+			if !remove {
+				kept = append(kept, t)
+			}
+		}
+		l.Tags = kept
+	}
 }
@@ -1,11 +1,16 @@
 package lessons
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/pbrown/claude-recall/internal/models"
 )
 
 // Helper to create a test LESSONS.md file
@@ -98,6 +103,60 @@ func Test_Store_List_Multiple(t *testing.T) {
 	}
 }
 
+func Test_Store_List_SharedPaths(t *testing.T) {
+	dir := t.TempDir()
+	lesson := func(id, title string) string {
+		return "### [" + id + "] [*----|-----] " + title + "\n" +
+			"- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2026-01-01 | **Last**: 2026-01-01 | **Category**: pattern\n" +
+			"> " + title + " content.\n\n"
+	}
+
+	projectPath := createTestLessonsFile(t, dir, "project.md", "# LESSONS.md - Project Level\n\n"+
+		lesson("L001", "Project lesson"))
+	sharedA := createTestLessonsFile(t, dir, "shared-a.md", "# LESSONS.md - Project Level\n\n"+
+		lesson("L001", "Shadowed by project")+lesson("L010", "Team lesson A"))
+	sharedB := createTestLessonsFile(t, dir, "shared-b.md", "# LESSONS.md - Project Level\n\n"+
+		lesson("L010", "Shadowed by shared A")+lesson("L020", "Team lesson B"))
+	systemPath := filepath.Join(dir, "system", "LESSONS.md")
+
+	store := NewStore(projectPath, systemPath, sharedA, sharedB)
+	lessons, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	want := map[string]string{"L001": "Project lesson", "L010": "Team lesson A", "L020": "Team lesson B"}
+	if len(lessons) != len(want) {
+		t.Fatalf("Expected %d lessons after dedup, got %d", len(want), len(lessons))
+	}
+	for _, l := range lessons {
+		if l.Title != want[l.ID] {
+			t.Errorf("%s: expected title %q, got %q", l.ID, want[l.ID], l.Title)
+		}
+	}
+	if lessons[1].Level != LevelShared {
+		t.Errorf("Expected shared lesson level %q, got %q", LevelShared, lessons[1].Level)
+	}
+
+	shared, err := store.ListShared()
+	if err != nil || len(shared) != 3 {
+		t.Fatalf("Expected 3 deduped shared lessons, got %d (%v)", len(shared), err)
+	}
+
+	// Add writes only to the primary project file
+	beforeA, beforeB := readFile(t, sharedA), readFile(t, sharedB)
+	added, err := store.Add("project", "pattern", "Brand new lesson", "Something nobody wrote down yet")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if !strings.Contains(readFile(t, projectPath), added.Title) {
+		t.Error("Expected new lesson in project file")
+	}
+	if readFile(t, sharedA) != beforeA || readFile(t, sharedB) != beforeB {
+		t.Error("Add must not modify shared files")
+	}
+}
+
 func Test_Store_Get_Found(t *testing.T) {
 	dir := t.TempDir()
 	projectDir := filepath.Join(dir, "project")
@@ -522,6 +581,107 @@ func Test_Store_Add_SystemLesson(t *testing.T) {
 	}
 }
 
+func Test_Store_WorkspaceLevel(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	if _, err := store.Add(LevelWorkspace, "pattern", "Too early", "No workspace yet."); err == nil {
+		t.Fatal("Expected error adding a workspace lesson without a workspace path")
+	}
+
+	workspacePath := filepath.Join(dir, "team", "LESSONS.md")
+	store.SetWorkspacePath(workspacePath)
+	store.Add("project", "pattern", "Project lesson", "Local to this repo.")
+	store.Add("system", "pattern", "System lesson", "Personal habit.")
+	lesson, err := store.Add(LevelWorkspace, "decision", "Team lesson", "Agreed by the whole team.")
+	if err != nil {
+		t.Fatalf("Add workspace lesson failed: %v", err)
+	}
+	if lesson.ID != "W001" || lesson.Level != LevelWorkspace {
+		t.Errorf("Expected W001 at workspace level, got %s at %s", lesson.ID, lesson.Level)
+	}
+	if content := readFile(t, workspacePath); !strings.Contains(content, "# LESSONS.md - Workspace Level") {
+		t.Errorf("Expected workspace header, got:\n%s", content)
+	}
+
+	all, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	levels := map[string]string{}
+	for _, l := range all {
+		levels[l.ID] = l.Level
+	}
+	want := map[string]string{"L001": "project", "S001": "system", "W001": LevelWorkspace}
+	if len(levels) != len(want) {
+		t.Errorf("Expected %v, got %v", want, levels)
+	}
+	for id, level := range want {
+		if levels[id] != level {
+			t.Errorf("Expected %s at level %s, got %q", id, level, levels[id])
+		}
+	}
+
+	if err := store.Cite("W001"); err != nil {
+		t.Fatalf("Cite workspace lesson failed: %v", err)
+	}
+	if cited, _ := store.Get("W001"); cited.Uses != 1 {
+		t.Errorf("Expected W001 uses 1, got %d", cited.Uses)
+	}
+}
+
+func Test_Store_RecordPrevention(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+	os.MkdirAll(projectDir, 0755)
+
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [***--|-----] Test Lesson
+- **Uses**: 10 | **Velocity**: 0.5 | **Learned**: 2025-12-27 | **Last**: 2025-12-01 | **Category**: pattern | **Prevented**: 2
+> Test content.
+`
+
+	projectPath := createTestLessonsFile(t, projectDir, "LESSONS.md", projectContent)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+	audit := NewAuditLog(filepath.Join(dir, "state"))
+	store.SetAuditLog(audit)
+
+	if err := store.RecordPreventionNote("L001", "caught nil map write"); err != nil {
+		t.Fatalf("RecordPreventionNote failed: %v", err)
+	}
+	if err := store.RecordPrevention("L001"); err != nil {
+		t.Fatalf("RecordPrevention failed: %v", err)
+	}
+
+	lesson, err := store.Get("L001")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if lesson.Preventions != 4 {
+		t.Errorf("Expected Preventions 4, got %d", lesson.Preventions)
+	}
+	if lesson.Uses != 10 {
+		t.Errorf("Expected Uses unchanged at 10, got %d", lesson.Uses)
+	}
+
+	data, _ := os.ReadFile(projectPath)
+	if !strings.Contains(string(data), "| **Prevented**: 4") {
+		t.Errorf("Expected serialized Prevented count, got:\n%s", data)
+	}
+
+	events, _ := audit.Read()
+	if len(events) != 2 || events[0].Event != AuditPrevent || events[0].Note != "caught nil map write" || events[0].NewValue != "3" {
+		t.Errorf("unexpected audit events: %+v", events)
+	}
+
+	if err := store.RecordPrevention("L999"); err == nil {
+		t.Error("Expected error for non-existent lesson")
+	}
+}
+
 func Test_Store_Cite_NotFound(t *testing.T) {
 	dir := t.TempDir()
 	projectPath := filepath.Join(dir, "project", "LESSONS.md")
@@ -593,3 +753,512 @@ func Test_Store_Get_SystemLesson(t *testing.T) {
 		t.Errorf("Expected Level 'system', got '%s'", lesson.Level)
 	}
 }
+
+func Test_Store_FindByTriggers_MatchesWholeWord(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+	os.MkdirAll(projectDir, 0755)
+
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Leaky goroutines
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha | **Triggers**: goroutine, leak
+> Always give goroutines a way to exit.
+
+### [L002] [*----|-----] Docker networking
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern | **Triggers**: docker
+> Containers use bridge networks.
+`
+
+	projectPath := createTestLessonsFile(t, projectDir, "LESSONS.md", projectContent)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	found, err := store.FindByTriggers("debugging a goroutine leak in the worker")
+	if err != nil {
+		t.Fatalf("FindByTriggers failed: %v", err)
+	}
+
+	if len(found) != 1 || found[0].ID != "L001" {
+		t.Errorf("Expected only L001, got %v", found)
+	}
+}
+
+func Test_Store_FindByTriggers_EdgeCases(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+	os.MkdirAll(projectDir, 0755)
+
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Leaky goroutines
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha | **Triggers**: goroutine, leak
+> Always give goroutines a way to exit.
+
+### [L002] [***--|-----] Channel ownership
+- **Uses**: 12 | **Velocity**: 2 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: pattern | **Triggers**: channel
+> The sender closes the channel.
+`
+
+	projectPath := createTestLessonsFile(t, projectDir, "LESSONS.md", projectContent)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	// Word boundaries: "goroutines" must not match trigger "goroutine"
+	found, _ := store.FindByTriggers("too many goroutines")
+	if len(found) != 0 {
+		t.Errorf("Expected no partial-word matches, got %v", found)
+	}
+
+	// Multiple triggers on one lesson return it once
+	found, _ = store.FindByTriggers("goroutine leak")
+	if len(found) != 1 {
+		t.Errorf("Expected L001 exactly once, got %d results", len(found))
+	}
+
+	// No matches returns an empty (non-nil) slice
+	found, err := store.FindByTriggers("nothing relevant here")
+	if err != nil {
+		t.Fatalf("FindByTriggers failed: %v", err)
+	}
+	if found == nil || len(found) != 0 {
+		t.Errorf("Expected empty slice, got %v", found)
+	}
+
+	// Sorted by score: L002 has more uses + velocity
+	found, _ = store.FindByTriggers("goroutine writes to a channel")
+	if len(found) != 2 || found[0].ID != "L002" {
+		t.Errorf("Expected L002 first, got %v", found)
+	}
+}
+
+func Test_Store_FindByTriggers_PunctuationEdges(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+	os.MkdirAll(projectDir, 0755)
+
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Header-only templates
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha | **Triggers**: c++
+> Template definitions belong in headers.
+
+### [L002] [*----|-----] Secrets in dotenv
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha | **Triggers**: .env
+> Never commit .env files.
+
+### [L003] [*----|-----] Force pushes
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha | **Triggers**: --force
+> Prefer --force-with-lease.
+`
+
+	projectPath := createTestLessonsFile(t, projectDir, "LESSONS.md", projectContent)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	for _, tc := range []struct {
+		text string
+		want string // matched lesson ID, "" for none
+	}{
+		{"porting the parser to C++ today", "L001"},
+		{"C++", "L001"},
+		{"abc++ is not a language", ""},
+		{"load secrets from .env first", "L002"},
+		{"see the .environment docs", ""},
+		{"git push --force origin main", "L003"},
+		{"git push --forceful", ""},
+	} {
+		found, err := store.FindByTriggers(tc.text)
+		if err != nil {
+			t.Fatalf("FindByTriggers(%q) failed: %v", tc.text, err)
+		}
+		if tc.want == "" {
+			if len(found) != 0 {
+				t.Errorf("FindByTriggers(%q): expected no match, got %v", tc.text, found)
+			}
+			continue
+		}
+		if len(found) != 1 || found[0].ID != tc.want {
+			t.Errorf("FindByTriggers(%q): expected %s, got %v", tc.text, tc.want, found)
+		}
+	}
+}
+
+func Test_Store_Promote_MovesToSystem(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+	systemDir := filepath.Join(dir, "system")
+	os.MkdirAll(projectDir, 0755)
+	os.MkdirAll(systemDir, 0755)
+
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [***--|**---] Promote Me
+- **Uses**: 42 | **Velocity**: 0.5 | **Learned**: 2025-03-14 | **Last**: 2026-01-18 | **Category**: pattern
+> Worth sharing across projects.
+
+### [L002] [*----|-----] Stay Here
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha
+> Project specific.
+`
+
+	systemContent := `# LESSONS.md - System Level
+
+## Active Lessons
+
+### [S006] [*----|-----] Existing System Lesson
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-01-01 | **Last**: 2025-12-01 | **Category**: decision
+> System content.
+`
+
+	projectPath := createTestLessonsFile(t, projectDir, "LESSONS.md", projectContent)
+	systemPath := createTestLessonsFile(t, systemDir, "LESSONS.md", systemContent)
+
+	store := NewStore(projectPath, systemPath)
+	promoted, err := store.Promote("L001")
+	if err != nil {
+		t.Fatalf("Promote failed: %v", err)
+	}
+
+	if promoted.ID != "S007" {
+		t.Errorf("Expected new ID S007, got %s", promoted.ID)
+	}
+
+	if content := readFile(t, projectPath); strings.Contains(content, "[L001]") {
+		t.Errorf("Expected L001 removed from project file, got:\n%s", content)
+	}
+
+	lesson, err := store.Get("S007")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if lesson.Title != "Promote Me" || lesson.Content != "Worth sharing across projects." {
+		t.Errorf("Expected title and content preserved, got %q / %q", lesson.Title, lesson.Content)
+	}
+	if lesson.Uses != 42 || lesson.Velocity != 0.5 {
+		t.Errorf("Expected uses=42 velocity=0.5, got uses=%d velocity=%g", lesson.Uses, lesson.Velocity)
+	}
+	if lesson.Learned.Format("2006-01-02") != "2025-03-14" {
+		t.Errorf("Expected learned 2025-03-14, got %s", lesson.Learned.Format("2006-01-02"))
+	}
+	if lesson.Level != "system" {
+		t.Errorf("Expected level system, got %s", lesson.Level)
+	}
+}
+
+func Test_Store_Promote_RejectsSystemLesson(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	if _, err := store.Promote("S001"); err == nil {
+		t.Error("Expected error promoting a system lesson")
+	}
+	if _, err := store.Promote("L999"); err == nil {
+		t.Error("Expected error promoting a missing lesson")
+	}
+}
+
+func Test_Store_Split(t *testing.T) {
+	dir := t.TempDir()
+	projectContent := `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [***--|**---] Go tooling
+- **Uses**: 7 | **Velocity**: 3 | **Learned**: 2025-03-14 | **Last**: 2026-01-18 | **Category**: pattern
+> Run go vet before committing. Use table-driven tests for parsers.
+
+### [L002] [*----|-----] Unrelated
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha
+> Something else.
+`
+	projectPath := createTestLessonsFile(t, dir, "LESSONS.md", projectContent)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	split, err := store.Split("L001", "Table-driven parser tests", "Use table-driven tests for parsers.")
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if split.ID != "L003" {
+		t.Errorf("Expected new ID L003, got %s", split.ID)
+	}
+
+	original, err := store.Get("L001")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if original.Content != "Run go vet before committing." {
+		t.Errorf("Expected remainder content, got %q", original.Content)
+	}
+
+	added, err := store.Get("L003")
+	if err != nil {
+		t.Fatalf("Get of split lesson failed: %v", err)
+	}
+	if added.Title != "Table-driven parser tests" || added.Category != "pattern" {
+		t.Errorf("Unexpected split lesson: %q (%s)", added.Title, added.Category)
+	}
+	if added.Uses != 3 || added.Velocity != 1.5 {
+		t.Errorf("Expected uses=3 velocity=1.5 on split lesson, got uses=%d velocity=%g", added.Uses, added.Velocity)
+	}
+	if total := original.Uses + added.Uses; total < 6 || total > 8 {
+		t.Errorf("Expected uses to sum to 7 +/- 1, got %d", total)
+	}
+	if original.Velocity+added.Velocity != 3 {
+		t.Errorf("Expected velocity to sum to 3, got %g", original.Velocity+added.Velocity)
+	}
+}
+
+func Test_Store_Split_Errors(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := createTestLessonsFile(t, dir, "LESSONS.md", `# LESSONS.md - Project Level
+
+## Active Lessons
+
+### [L001] [*----|-----] Narrow
+- **Uses**: 1 | **Velocity**: 0 | **Learned**: 2025-12-27 | **Last**: 2026-01-18 | **Category**: gotcha
+> Only one idea.
+`)
+	store := NewStore(projectPath, filepath.Join(dir, "system", "LESSONS.md"))
+
+	if _, err := store.Split("L999", "New", "Content"); err == nil {
+		t.Error("Expected error splitting a missing lesson")
+	}
+	if _, err := store.Split("L001", "Same", "Only one idea."); err == nil {
+		t.Error("Expected error when nothing would remain")
+	}
+	if _, err := store.Split("L001", "", "Content"); err == nil {
+		t.Error("Expected error for empty title")
+	}
+}
+
+// stubGitProvider returns a fixed git context (or error) and records the dir
+type stubGitProvider struct {
+	ctx *models.GitContext
+	err error
+	dir string
+}
+
+func (p *stubGitProvider) GetGitContext(dir string) (*models.GitContext, error) {
+	p.dir = dir
+	return p.ctx, p.err
+}
+
+func Test_Store_Add_AttachesGitContext(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project", "LESSONS.md")
+	systemPath := filepath.Join(dir, "system", "LESSONS.md")
+
+	provider := &stubGitProvider{ctx: &models.GitContext{Branch: "feature/login", ShortSHA: "abc1234"}}
+	store := NewStore(projectPath, systemPath)
+	store.SetGitContextProvider(provider, "/repo")
+
+	lesson, err := store.Add("project", "pattern", "Git lesson", "Learned on a branch")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if provider.dir != "/repo" {
+		t.Errorf("Expected provider to be asked about /repo, got %q", provider.dir)
+	}
+	if lesson.Git == nil || lesson.Git.String() != "feature/login@abc1234" {
+		t.Errorf("Expected git context feature/login@abc1234, got %v", lesson.Git)
+	}
+
+	content := readFile(t, projectPath)
+	if !strings.Contains(content, "| **Git**: feature/login@abc1234") {
+		t.Errorf("Expected Git metadata in file, got:\n%s", content)
+	}
+
+	reloaded, err := store.Get(lesson.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if reloaded.Git == nil || reloaded.Git.Branch != "feature/login" || reloaded.Git.ShortSHA != "abc1234" {
+		t.Errorf("Expected git context to survive reload, got %v", reloaded.Git)
+	}
+}
+
+func Test_Store_Add_GitContextErrorIsIgnored(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	store.SetGitContextProvider(&stubGitProvider{err: errors.New("not a git repository")}, dir)
+
+	lesson, err := store.Add("project", "pattern", "No repo", "Still added")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if lesson.Git != nil {
+		t.Errorf("Expected no git context, got %v", lesson.Git)
+	}
+}
+
+func Test_Store_RenameCategory(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	store.ForceAdd("project", "gotcha", "Project gotcha", "Alpha")
+	store.ForceAdd("project", "pattern", "Project pattern", "Beta")
+	store.ForceAdd("system", "gotcha", "System gotcha", "Gamma")
+	store.ForceAdd("system", "decision", "System decision", "Delta")
+
+	count, err := store.RenameCategory("gotcha", "pitfall")
+	if err != nil {
+		t.Fatalf("RenameCategory failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 renamed lessons, got %d", count)
+	}
+
+	all, _ := store.List()
+	want := map[string]string{
+		"Project gotcha":  "pitfall",
+		"Project pattern": "pattern",
+		"System gotcha":   "pitfall",
+		"System decision": "decision",
+	}
+	for _, l := range all {
+		if l.Category != want[l.Title] {
+			t.Errorf("%s: expected category %q, got %q", l.Title, want[l.Title], l.Category)
+		}
+	}
+
+	if count, err := store.RenameCategory("nonexistent", "other"); err != nil || count != 0 {
+		t.Errorf("Expected 0 renames for unknown category, got %d (%v)", count, err)
+	}
+
+	// Names the metadata line can't parse back are rejected
+	for _, bad := range []string{"", "two words", "pit-fall", "pitfall | x"} {
+		if count, err := store.RenameCategory("pitfall", bad); err == nil || count != 0 {
+			t.Errorf("Expected an error renaming to %q, got %d (%v)", bad, count, err)
+		}
+	}
+	if l, _ := store.Get("L001"); l.Category != "pitfall" {
+		t.Errorf("rejected rename changed category to %q", l.Category)
+	}
+}
+
+func Test_Store_ConcurrentAdds(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			title := fmt.Sprintf("Concurrent lesson %d", i)
+			if _, err := store.ForceAdd("project", "pattern", title, "Written concurrently"); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("ForceAdd failed: %v", err)
+	}
+
+	all, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != writers {
+		t.Fatalf("Expected %d lessons, got %d", writers, len(all))
+	}
+	ids := make(map[string]bool)
+	for _, l := range all {
+		if ids[l.ID] {
+			t.Errorf("Duplicate ID %s", l.ID)
+		}
+		ids[l.ID] = true
+	}
+}
+
+func Test_NormalizeContent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"trims surrounding whitespace", "  \n\tUse defer for cleanup \n ", "Use defer for cleanup"},
+		{"converts CRLF", "line one\r\nline two", "line one\nline two"},
+		{"collapses blank lines", "para one\n\n\n\n\npara two", "para one\n\npara two"},
+		{"strips trailing spaces per line", "line one  \nline two\t\nline three", "line one\nline two\nline three"},
+		{"keeps single blank line", "para one\n\npara two", "para one\n\npara two"},
+		{"keeps indentation", "steps:\n  - one\n  - two", "steps:\n  - one\n  - two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeContent(tt.in)
+			if got != tt.want {
+				t.Errorf("normalizeContent(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			// Already-clean content is left alone
+			if again := normalizeContent(got); again != got {
+				t.Errorf("normalizeContent not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
+
+func Test_Store_NormalizesContentOnAddAndEdit(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+
+	l, err := store.Add("project", "pattern", "Pasted lesson", "  Check errors before use   \r\n")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	got, _ := store.Get(l.ID)
+	if got.Content != "Check errors before use" {
+		t.Errorf("Add stored content %q", got.Content)
+	}
+
+	if err := store.Edit(l.ID, map[string]interface{}{"content": "\tWrap errors with context \t"}); err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	got, _ = store.Get(l.ID)
+	if got.Content != "Wrap errors with context" {
+		t.Errorf("Edit stored content %q", got.Content)
+	}
+}
+
+func Test_Store_EditBulk(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "project", "LESSONS.md"), filepath.Join(dir, "system", "LESSONS.md"))
+	store.Add("project", "pattern", "First", "Alpha content")
+	store.Add("system", "gotcha", "Second", "Beta material")
+
+	err := store.EditBulk([]BulkEdit{
+		{ID: "L001", Updates: map[string]interface{}{"title": "First renamed"}},
+		{ID: "S001", Updates: map[string]interface{}{"content": "Beta revised"}},
+	})
+	if err != nil {
+		t.Fatalf("EditBulk failed: %v", err)
+	}
+	if l, _ := store.Get("L001"); l.Title != "First renamed" {
+		t.Errorf("expected L001 title updated, got %q", l.Title)
+	}
+	if l, _ := store.Get("S001"); l.Content != "Beta revised" {
+		t.Errorf("expected S001 content updated, got %q", l.Content)
+	}
+
+	err = store.EditBulk([]BulkEdit{
+		{ID: "L001", Updates: map[string]interface{}{"title": "Should not apply"}},
+		{ID: "L999", Updates: map[string]interface{}{"title": "Missing"}},
+	})
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || len(notFound.IDs) != 1 || notFound.IDs[0] != "L999" {
+		t.Fatalf("expected NotFoundError for L999, got %v", err)
+	}
+	if l, _ := store.Get("L001"); l.Title != "First renamed" {
+		t.Errorf("expected no partial write, got title %q", l.Title)
+	}
+}
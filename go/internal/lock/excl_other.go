@@ -0,0 +1,45 @@
+//go:build !unix
+
+package lock
+
+import (
+	"os"
+	"time"
+)
+
+// On platforms without flock the lock is the existence of the lock file,
+// created with O_EXCL and removed on release. Unlike flock, a lock file left
+// by a crashed process must be removed by hand.
+
+// lockBlocking polls until the lock file can be created
+func lockBlocking(path string) (*os.File, error) {
+	for {
+		file, ok, err := tryLock(path)
+		if err != nil || ok {
+			return file, err
+		}
+		time.Sleep(DefaultRetryInterval)
+	}
+}
+
+// tryLock attempts to create the lock file exclusively. ok is false if the
+// file already exists.
+func tryLock(path string) (file *os.File, ok bool, err error) {
+	file, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return file, true, nil
+}
+
+// unlock closes and removes the lock file
+func unlock(path string, file *os.File) error {
+	closeErr := file.Close()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return closeErr
+}
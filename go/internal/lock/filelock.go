@@ -1,11 +1,24 @@
 package lock
 
 import (
+	"errors"
 	"os"
 	"sync"
-	"syscall"
+	"time"
 )
 
+// DefaultRetryInterval is how often Acquire retries when a Timeout is set
+const DefaultRetryInterval = 10 * time.Millisecond
+
+// ErrTimeout is returned when a lock is not acquired within Options.Timeout
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// Options controls how Acquire waits for a lock
+type Options struct {
+	Timeout       time.Duration // Give up after this long (0 = wait forever)
+	RetryInterval time.Duration // Delay between attempts when Timeout is set (default 10ms)
+}
+
 // FileLock represents a file lock for safe concurrent access
 type FileLock struct {
 	path     string
@@ -14,47 +27,47 @@ type FileLock struct {
 	mu       sync.Mutex
 }
 
-// Acquire obtains an exclusive lock on the file. Blocks until lock available.
-func Acquire(path string) (*FileLock, error) {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return nil, err
+// Acquire obtains an exclusive lock on the file. Without options (or with a
+// zero Timeout) it blocks until the lock is available; otherwise it retries
+// every RetryInterval and returns ErrTimeout once Timeout has elapsed.
+func Acquire(path string, opts ...Options) (*FileLock, error) {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
 	}
 
-	// Block until lock acquired (LOCK_EX = exclusive lock)
-	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
-		file.Close()
-		return nil, err
+	if o.Timeout <= 0 {
+		file, err := lockBlocking(path)
+		if err != nil {
+			return nil, err
+		}
+		return &FileLock{path: path, file: file}, nil
 	}
 
-	return &FileLock{
-		path: path,
-		file: file,
-	}, nil
+	interval := o.RetryInterval
+	if interval <= 0 {
+		interval = DefaultRetryInterval
+	}
+	deadline := time.Now().Add(o.Timeout)
+	for {
+		l, err := TryAcquire(path)
+		if err != nil || l != nil {
+			return l, err
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrTimeout
+		}
+		time.Sleep(interval)
+	}
 }
 
 // TryAcquire attempts to obtain a lock without blocking. Returns nil if unavailable.
 func TryAcquire(path string) (*FileLock, error) {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return nil, err
-	}
-
-	// Try to acquire lock without blocking (LOCK_NB = non-blocking)
-	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-	if err != nil {
-		file.Close()
-		// EWOULDBLOCK means lock is held by another process
-		if err == syscall.EWOULDBLOCK {
-			return nil, nil
-		}
+	file, ok, err := tryLock(path)
+	if err != nil || !ok {
 		return nil, err
 	}
-
-	return &FileLock{
-		path: path,
-		file: file,
-	}, nil
+	return &FileLock{path: path, file: file}, nil
 }
 
 // Release releases the lock and closes the file
@@ -67,12 +80,5 @@ func (l *FileLock) Release() error {
 	}
 
 	l.released = true
-
-	// Unlock the file
-	if err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN); err != nil {
-		l.file.Close()
-		return err
-	}
-
-	return l.file.Close()
+	return unlock(l.path, l.file)
 }
@@ -162,3 +162,71 @@ func Test_Release_Idempotent(t *testing.T) {
 		t.Fatalf("second Release should be safe: %v", err)
 	}
 }
+
+func Test_Acquire_MutualExclusion(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "test.lock")
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			l, err := Acquire(lockPath)
+			if err != nil {
+				t.Errorf("Acquire failed: %v", err)
+				return
+			}
+			mu.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+
+			time.Sleep(30 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+			l.Release()
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	if peak != 1 {
+		t.Errorf("expected only one holder at a time, peak was %d", peak)
+	}
+}
+
+func Test_Acquire_Timeout(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "test.lock")
+
+	held, err := Acquire(lockPath)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	begin := time.Now()
+	l, err := Acquire(lockPath, Options{Timeout: 50 * time.Millisecond, RetryInterval: 5 * time.Millisecond})
+	if err != ErrTimeout || l != nil {
+		t.Fatalf("expected ErrTimeout, got %v, %v", l, err)
+	}
+	if elapsed := time.Since(begin); elapsed < 50*time.Millisecond {
+		t.Errorf("returned after %v, before the timeout", elapsed)
+	}
+
+	// Once released, a timed acquire succeeds
+	held.Release()
+	l, err = Acquire(lockPath, Options{Timeout: time.Second})
+	if err != nil || l == nil {
+		t.Fatalf("expected lock after release, got %v, %v", l, err)
+	}
+	l.Release()
+}
@@ -0,0 +1,53 @@
+//go:build unix
+
+package lock
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockBlocking opens path and blocks until an exclusive flock is held.
+// flock locks are released by the kernel if the process dies.
+func lockBlocking(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block until lock acquired (LOCK_EX = exclusive lock)
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// tryLock attempts a non-blocking exclusive flock. ok is false if another
+// holder has the lock.
+func tryLock(path string) (file *os.File, ok bool, err error) {
+	file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Try to acquire lock without blocking (LOCK_NB = non-blocking)
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		// EWOULDBLOCK means lock is held by another process
+		if err == syscall.EWOULDBLOCK {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return file, true, nil
+}
+
+// unlock releases the flock and closes the file
+func unlock(path string, file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,31 @@ var validHandoffPhases = map[string]bool{
 	"review":       true,
 }
 
+// HandoffPhaseTransitions lists the phases each phase may move to. Work
+// normally flows research -> planning -> implementing -> review, and may
+// step back one phase when plans change or review finds problems.
+var HandoffPhaseTransitions = map[string][]string{
+	"research":     {"planning", "implementing"},
+	"planning":     {"research", "implementing"},
+	"implementing": {"planning", "review"},
+	"review":       {"implementing"},
+}
+
+// ErrInvalidPhaseTransition is returned when a handoff's phase may not
+// move from From to To
+type ErrInvalidPhaseTransition struct {
+	From string
+	To   string
+}
+
+func (e *ErrInvalidPhaseTransition) Error() string {
+	allowed := strings.Join(HandoffPhaseTransitions[e.From], ", ")
+	if allowed == "" {
+		allowed = "none"
+	}
+	return fmt.Sprintf("invalid phase transition %s -> %s (allowed from %s: %s)", e.From, e.To, e.From, allowed)
+}
+
 // Valid handoff agents
 var validHandoffAgents = map[string]bool{
 	"explore":         true,
@@ -38,6 +64,17 @@ var validHandoffAgents = map[string]bool{
 	"user":            true,
 }
 
+// DefaultHandoffPriority is assigned to handoffs without an explicit priority
+const DefaultHandoffPriority = "medium"
+
+// Valid handoff priorities, mapped to sort rank (lower sorts first)
+var validHandoffPriorities = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+}
+
 // Valid tried step outcomes
 var validTriedStepOutcomes = map[string]bool{
 	"success": true,
@@ -47,58 +84,118 @@ var validTriedStepOutcomes = map[string]bool{
 
 // TriedStep represents an attempted step in a handoff
 type TriedStep struct {
-	Outcome     string // "success", "fail", "partial"
-	Description string
+	Outcome     string    `json:"outcome"` // "success", "fail", "partial"
+	Description string    `json:"description"`
+	Timestamp   time.Time `json:"timestamp"` // When the step was logged (falls back to handoff Updated)
+}
+
+// HandoffNote is a free-form, timestamped note attached to a handoff
+type HandoffNote struct {
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"` // May span multiple lines
+}
+
+// TimeEntry records minutes spent on a handoff during one session
+type TimeEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Minutes   int       `json:"minutes"`
+	SessionID string    `json:"session_id"` // Empty if not logged from a session
+}
+
+// Milestone is an intermediate goal within a long-running handoff
+type Milestone struct {
+	Name        string     `json:"name"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"` // nil until completed
+}
+
+// CheckpointEntry snapshots a handoff's next steps when its phase changes
+type CheckpointEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Phase     string    `json:"phase"` // Phase entered
+	Notes     string    `json:"notes"` // Next steps as they stood before the change
 }
 
 // HandoffContext contains rich context for handoff continuation
 type HandoffContext struct {
-	Summary       string
-	CriticalFiles []string
-	RecentChanges []string
-	Learnings     []string
-	Blockers      []string
-	GitRef        string
+	Summary       string   `json:"summary"`
+	CriticalFiles []string `json:"critical_files"`
+	RecentChanges []string `json:"recent_changes"`
+	Learnings     []string `json:"learnings"`
+	Blockers      []string `json:"blockers"`
+	GitRef        string   `json:"git_ref"`
 }
 
 // Handoff represents a multi-step work item tracked across sessions
 type Handoff struct {
-	ID          string          // "hf-a1b2c3d" or legacy "A001"
-	Title       string
-	Status      string          // not_started|in_progress|blocked|ready_for_review|completed
-	Created     time.Time
-	Updated     time.Time
-	Description string
-	NextSteps   string
-	Phase       string          // research|planning|implementing|review (default: "research")
-	Agent       string          // explore|general-purpose|plan|review|user (default: "user")
-	Refs        []string        // File references
-	Tried       []TriedStep
-	Checkpoint  string          // Legacy progress summary
-	LastSession *time.Time      // When checkpoint was last updated (nil if not set)
-	Handoff     *HandoffContext // Rich context (nil if not set)
-	BlockedBy   []string        // IDs of blocking handoffs
-	Stealth     bool            // If true, stored in HANDOFFS_LOCAL.md
-	Sessions    []string        // Session IDs linked
+	ID          string          `json:"id"` // "hf-a1b2c3d" or legacy "A001"
+	Title       string          `json:"title"`
+	Status      string          `json:"status"` // not_started|in_progress|blocked|ready_for_review|completed
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+	Description string          `json:"description"`
+	NextSteps   string          `json:"next_steps"`
+	Phase       string          `json:"phase"`    // research|planning|implementing|review (default: "research")
+	Agent       string          `json:"agent"`    // explore|general-purpose|plan|review|user (default: "user")
+	Priority    string          `json:"priority"` // critical|high|medium|low (default: "medium")
+	Refs        []string        `json:"refs"`     // File references
+	Tried       []TriedStep     `json:"tried"`
+	Checkpoint  string          `json:"checkpoint"`   // Legacy progress summary
+	LastSession *time.Time      `json:"last_session"` // When checkpoint was last updated (nil if not set)
+	Handoff     *HandoffContext `json:"handoff"`      // Rich context (nil if not set)
+	BlockedBy   []string        `json:"blocked_by"`   // IDs of blocking handoffs
+	Stealth     bool            `json:"stealth"`      // If true, stored in HANDOFFS_LOCAL.md
+	Stale       bool            `json:"stale"`        // Flagged by check-stale; cleared by any update
+	Sessions    []string        `json:"sessions"`     // Session IDs linked
+	Notes       []HandoffNote   `json:"notes"`        // Free-form notes in the order added
+	Milestones  []Milestone     `json:"milestones"`   // Intermediate goals in the order added
+	TimeLog     []TimeEntry     `json:"time_log"`     // Effort logged across sessions
+
+	CheckpointHistory []CheckpointEntry `json:"checkpoint_history"` // Appended on each phase change
+}
+
+// TotalMinutes returns the sum of all logged time
+func (h *Handoff) TotalMinutes() int {
+	total := 0
+	for _, e := range h.TimeLog {
+		total += e.Minutes
+	}
+	return total
 }
 
 // NewHandoff creates a new Handoff with default values
 func NewHandoff(id, title string) *Handoff {
 	now := time.Now()
 	return &Handoff{
-		ID:        id,
-		Title:     title,
-		Status:    "not_started",
-		Created:   now,
-		Updated:   now,
-		Phase:     "research",
-		Agent:     "user",
-		Refs:      []string{},
-		Tried:     []TriedStep{},
-		BlockedBy: []string{},
-		Sessions:  []string{},
-		Stealth:   false,
+		ID:         id,
+		Title:      title,
+		Status:     "not_started",
+		Created:    now,
+		Updated:    now,
+		Phase:      "research",
+		Agent:      "user",
+		Priority:   DefaultHandoffPriority,
+		Refs:       []string{},
+		Tried:      []TriedStep{},
+		BlockedBy:  []string{},
+		Sessions:   []string{},
+		Notes:      []HandoffNote{},
+		Milestones: []Milestone{},
+		TimeLog:    []TimeEntry{},
+		Stealth:    false,
+
+		CheckpointHistory: []CheckpointEntry{},
+	}
+}
+
+// MilestoneProgress returns how many milestones are completed out of the total
+func (h *Handoff) MilestoneProgress() (completed, total int) {
+	for _, m := range h.Milestones {
+		if m.Completed {
+			completed++
+		}
 	}
+	return completed, len(h.Milestones)
 }
 
 // IsValidTriedStepOutcome checks if the outcome is valid
@@ -106,6 +203,21 @@ func IsValidTriedStepOutcome(outcome string) bool {
 	return validTriedStepOutcomes[outcome]
 }
 
+// IsValidPriority checks if the priority is valid
+func IsValidPriority(priority string) bool {
+	_, ok := validHandoffPriorities[priority]
+	return ok
+}
+
+// PriorityRank returns the sort rank of a priority (critical = 0).
+// Unknown priorities rank as the default.
+func PriorityRank(priority string) int {
+	if rank, ok := validHandoffPriorities[priority]; ok {
+		return rank
+	}
+	return validHandoffPriorities[DefaultHandoffPriority]
+}
+
 // IsValidHandoffStatus checks if the status is valid
 func IsValidHandoffStatus(status string) bool {
 	return validHandoffStatuses[status]
@@ -116,6 +228,25 @@ func IsValidHandoffPhase(phase string) bool {
 	return validHandoffPhases[phase]
 }
 
+// ValidatePhaseTransition checks that a handoff may move from phase from to
+// phase to. Staying in the same phase is always allowed, as is any move from
+// a phase with no transition rules (e.g. one missing from legacy files).
+func ValidatePhaseTransition(from, to string) error {
+	if !IsValidHandoffPhase(to) {
+		return fmt.Errorf("invalid phase %q", to)
+	}
+	allowed, ok := HandoffPhaseTransitions[from]
+	if from == to || !ok {
+		return nil
+	}
+	for _, p := range allowed {
+		if p == to {
+			return nil
+		}
+	}
+	return &ErrInvalidPhaseTransition{From: from, To: to}
+}
+
 // IsValidHandoffAgent checks if the agent is valid
 func IsValidHandoffAgent(agent string) bool {
 	return validHandoffAgents[agent]
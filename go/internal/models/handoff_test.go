@@ -204,3 +204,24 @@ func TestHandoff_ValidAgents(t *testing.T) {
 		}
 	}
 }
+
+func TestHandoff_ValidPriorities(t *testing.T) {
+	for _, priority := range []string{"critical", "high", "medium", "low"} {
+		if !IsValidPriority(priority) {
+			t.Errorf("Priority %q should be valid", priority)
+		}
+	}
+
+	for _, priority := range []string{"", "urgent", "HIGH", "p1"} {
+		if IsValidPriority(priority) {
+			t.Errorf("Priority %q should be invalid", priority)
+		}
+	}
+
+	if NewHandoff("hf-1234567", "Title").Priority != "medium" {
+		t.Error("Expected default priority 'medium'")
+	}
+	if PriorityRank("critical") >= PriorityRank("high") || PriorityRank("medium") >= PriorityRank("low") {
+		t.Error("Expected critical < high < medium < low")
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"strings"
 	"time"
 )
@@ -12,23 +14,65 @@ const (
 	VelocityDecayFactor      = 0.5
 	VelocityEpsilon          = 0.01
 	StaleDaysDefault         = 60
+	DefaultConfidence        = 50
+	MaxConfidence            = 100
+	DefaultWeight            = 1.0
 )
 
 // Lesson represents a learned lesson from coding sessions
 type Lesson struct {
-	ID         string    // "L001" or "S001"
-	Title      string
-	Content    string
-	Uses       int       // Total citations (capped at 100)
-	Velocity   float64   // Recency score (decays 50% per cycle)
-	Learned    time.Time // Date first learned
-	LastUsed   time.Time // Date last cited
-	Category   string    // pattern|correction|decision|gotcha|preference
-	Source     string    // "human" or "ai" (default: "human")
-	Level      string    // "project" or "system" (default: "project")
-	Promotable bool      // false = never auto-promote (default: true)
-	LessonType string    // constraint|informational|preference (auto-classified if empty)
-	Triggers   []string  // Keywords for relevance matching
+	ID          string      `json:"id"` // "L001" or "S001"
+	Title       string      `json:"title"`
+	Content     string      `json:"content"`
+	Uses        int         `json:"uses"`          // Total citations (capped at 100)
+	Velocity    float64     `json:"velocity"`      // Recency score (decays 50% per cycle)
+	Learned     time.Time   `json:"learned"`       // Date first learned
+	LastUsed    time.Time   `json:"last_used"`     // Date last cited
+	Category    string      `json:"category"`      // pattern|correction|decision|gotcha|preference
+	Source      string      `json:"source"`        // "human" or "ai" (default: "human")
+	Level       string      `json:"level"`         // "project", "system", or "workspace" (default: "project")
+	Promotable  bool        `json:"promotable"`    // false = never auto-promote (default: true)
+	LessonType  string      `json:"type"`          // constraint|informational|preference (auto-classified if empty)
+	Triggers    []string    `json:"triggers"`      // Keywords for relevance matching
+	Tags        []string    `json:"tags"`          // Cross-cutting labels for filtering
+	Expired     bool        `json:"expired"`       // Flagged by TTL expiry (never cited past the TTL)
+	Git         *GitContext `json:"git,omitempty"` // Branch and commit the lesson was learned on
+	Confidence  int         `json:"confidence"`    // Human-assigned certainty 0-100 (default: 50)
+	Weight      float64     `json:"weight"`        // Manual ranking multiplier (default: 1.0)
+	Preventions int         `json:"preventions"`   // Times the lesson prevented a mistake
+}
+
+// UnmarshalJSON decodes a lesson, defaulting Confidence and Weight when the
+// fields are absent (e.g. exports written before they existed)
+func (l *Lesson) UnmarshalJSON(data []byte) error {
+	type plain Lesson
+	p := plain{Confidence: DefaultConfidence, Weight: DefaultWeight}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*l = Lesson(p)
+	return nil
+}
+
+// IsValidConfidence reports whether n is within 0-MaxConfidence
+func IsValidConfidence(n int) bool {
+	return n >= 0 && n <= MaxConfidence
+}
+
+// IsValidWeight reports whether w is a usable ranking weight (positive)
+func IsValidWeight(w float64) bool {
+	return w > 0 && !math.IsInf(w, 0)
+}
+
+// GitContext records where in the repository history a lesson was learned
+type GitContext struct {
+	Branch   string `json:"branch"`
+	ShortSHA string `json:"short_sha"`
+}
+
+// String formats the context as "branch@sha" (e.g. "main@abc1234")
+func (g *GitContext) String() string {
+	return g.Branch + "@" + g.ShortSHA
 }
 
 // NewLesson creates a new Lesson with default values
@@ -46,7 +90,35 @@ func NewLesson(id, title, content string) *Lesson {
 		Level:      "project",
 		Promotable: true,
 		Triggers:   []string{},
+		Tags:       []string{},
+		Confidence: DefaultConfidence,
+		Weight:     DefaultWeight,
+	}
+}
+
+// HasTag reports whether the lesson carries tag (case-insensitive)
+func (l *Lesson) HasTag(tag string) bool {
+	for _, t := range l.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags lowercases and trims tags, dropping empties and duplicates
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
 	}
+	return out
 }
 
 // Tokens estimates the token count for this lesson
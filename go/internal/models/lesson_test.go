@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -305,3 +306,20 @@ func TestLesson_Constants(t *testing.T) {
 		t.Errorf("StaleDaysDefault = %d, want %d", StaleDaysDefault, 60)
 	}
 }
+
+func TestLesson_UnmarshalJSON_DefaultsConfidence(t *testing.T) {
+	var l Lesson
+	if err := json.Unmarshal([]byte(`{"id":"L001","title":"Old export"}`), &l); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if l.Confidence != DefaultConfidence {
+		t.Errorf("Expected default confidence %d, got %d", DefaultConfidence, l.Confidence)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":"L002","confidence":0}`), &l); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if l.Confidence != 0 || l.ID != "L002" {
+		t.Errorf("Expected explicit confidence 0 to be kept, got %+v", l)
+	}
+}
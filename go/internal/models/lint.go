@@ -0,0 +1,28 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// lintEarliestDate is the oldest date lint accepts; anything earlier is
+// almost certainly a typo or a corrupted edit
+var lintEarliestDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// LintIssue is a problem found while validating a LESSONS.md or HANDOFFS.md file
+type LintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"` // 1-based
+	Message string `json:"message"`
+}
+
+// String formats the issue as "file:line: message"
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+// IsReasonableDate reports whether t falls between 2000-01-01 and one day
+// after now (the slack absorbs timezone differences)
+func IsReasonableDate(t, now time.Time) bool {
+	return !t.Before(lintEarliestDate) && !t.After(now.Add(24*time.Hour))
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// SessionMetrics summarizes what a session did. Both the opencode
+// session-end command and the stop hook report it so the hook system can
+// track session health.
+type SessionMetrics struct {
+	CitationCount     int `json:"citation_count"`
+	LessonsAdded      int `json:"lessons_added"`
+	HandoffOpsCount   int `json:"handoff_ops_count"`
+	MessagesProcessed int `json:"messages_processed"`
+
+	// SessionDurationEstimate spans the first to last message timestamp
+	// ("1h5m0s"); empty when fewer than two messages carry one
+	SessionDurationEstimate string `json:"session_duration_estimate,omitempty"`
+}
+
+// EstimateSessionDuration returns the time between the earliest and latest
+// non-zero timestamps, rounded to the second, or "" if there are fewer
+// than two
+func EstimateSessionDuration(timestamps []time.Time) string {
+	var first, last time.Time
+	count := 0
+	for _, t := range timestamps {
+		if t.IsZero() {
+			continue
+		}
+		if count == 0 || t.Before(first) {
+			first = t
+		}
+		if count == 0 || t.After(last) {
+			last = t
+		}
+		count++
+	}
+	if count < 2 {
+		return ""
+	}
+	return last.Sub(first).Round(time.Second).String()
+}
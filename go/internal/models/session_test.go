@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateSessionDuration(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		timestamps []time.Time
+		want       string
+	}{
+		{"none", nil, ""},
+		{"single", []time.Time{start}, ""},
+		{"zero values ignored", []time.Time{{}, start, {}}, ""},
+		{"out of order", []time.Time{start.Add(90 * time.Minute), start, start.Add(30 * time.Minute)}, "1h30m0s"},
+		{"rounded", []time.Time{start, start.Add(2*time.Minute + 400*time.Millisecond)}, "2m0s"},
+	}
+	for _, tt := range tests {
+		if got := EstimateSessionDuration(tt.timestamps); got != tt.want {
+			t.Errorf("%s: EstimateSessionDuration() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
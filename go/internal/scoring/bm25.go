@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pbrown/claude-recall/internal/lessons"
 	"github.com/pbrown/claude-recall/internal/models"
 )
 
@@ -33,58 +34,168 @@ var stopWords = map[string]bool{
 // splitRe splits on non-alphanumeric characters
 var splitRe = regexp.MustCompile(`[^a-z0-9]+`)
 
-// BM25Scorer scores lessons against queries using BM25
+// WildcardWeight is the multiplier applied to terms matched via a wildcard prefix,
+// so that "context*" ranks below an exact "context" match
+const WildcardWeight = 0.7
+
+// Query is a parsed search query with exact terms and wildcard prefixes
+type Query struct {
+	Terms    []string // Exact terms (tokenized)
+	Prefixes []string // Wildcard prefixes: "err*" -> "err", "*" -> ""
+}
+
+// BM25Scorer scores lessons against queries using BM25. Titles and bodies
+// (content and tags) are scored as separate fields and combined as
+// TitleBoost*titleScore + contentScore, so a title match outranks the same
+// match buried in the body. Body keywords (see lessons.ExtractKeywords)
+// count KeywordWeight times in term frequency.
 type BM25Scorer struct {
-	lessons   []*models.Lesson
-	k1        float64
-	b         float64
-	docTokens [][]string
-	docLens   []int
-	avgDL     float64
-	df        map[string]int // term -> document frequency
-	n         int
+	TitleBoost float64 // Multiplier for the title field's score
+
+	lessons     []*models.Lesson
+	k1          float64
+	b           float64
+	titleTokens [][]string
+	titleLens   []int
+	avgTitleDL  float64
+	docTokens   [][]string // Body tokens: content and tags
+	docLens     []int
+	avgDL       float64
+	keywords    [][]string        // Keyword tokens per lesson
+	keywordSets []map[string]bool // keywords as sets, for scoring
+	df          map[string]int    // term -> document frequency (title or body)
+	vocab       []string          // sorted vocabulary for prefix lookups
+	n           int
+}
+
+// Default BM25 tuning parameters
+const (
+	DefaultK1         = 1.2  // Term frequency saturation
+	DefaultB          = 0.75 // Document length normalization
+	DefaultTitleBoost = 2.0  // Title matches count double
+)
+
+// KeywordWeight multiplies the term frequency of a lesson's keywords
+const KeywordWeight = 3.0
+
+// BM25Option configures a BM25Scorer
+type BM25Option func(*BM25Scorer)
+
+// WithK1 sets the term frequency saturation parameter (higher = slower saturation)
+func WithK1(v float64) BM25Option {
+	return func(s *BM25Scorer) {
+		s.k1 = v
+	}
+}
+
+// WithB sets the length normalization parameter (0 = none, 1 = full)
+func WithB(v float64) BM25Option {
+	return func(s *BM25Scorer) {
+		s.b = v
+	}
+}
+
+// WithTitleBoost sets the title field's weight relative to the body (1 =
+// equal weight)
+func WithTitleBoost(v float64) BM25Option {
+	return func(s *BM25Scorer) {
+		s.TitleBoost = v
+	}
 }
 
 // NewBM25Scorer creates a scorer from a set of lessons
-func NewBM25Scorer(lessons []*models.Lesson) *BM25Scorer {
+func NewBM25Scorer(lessonList []*models.Lesson, opts ...BM25Option) *BM25Scorer {
 	s := &BM25Scorer{
-		lessons: lessons,
-		k1:      1.5,
-		b:       0.75,
-		df:      make(map[string]int),
-		n:       len(lessons),
+		TitleBoost: DefaultTitleBoost,
+		lessons:    lessonList,
+		k1:         DefaultK1,
+		b:          DefaultB,
+		df:         make(map[string]int),
+		n:          len(lessonList),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	if s.n == 0 {
 		return s
 	}
 
-	// Tokenize each lesson (title + content)
-	totalLen := 0
-	for _, l := range lessons {
-		text := l.Title + " " + l.Content
-		tokens := Tokenize(text)
+	// Tokenize each lesson's title and body (content + tags) separately
+	titleLen, totalLen := 0, 0
+	for _, l := range lessonList {
+		title := Tokenize(l.Title)
+		s.titleTokens = append(s.titleTokens, title)
+		s.titleLens = append(s.titleLens, len(title))
+		titleLen += len(title)
+
+		tokens := Tokenize(bodyText(l))
 		s.docTokens = append(s.docTokens, tokens)
 		s.docLens = append(s.docLens, len(tokens))
 		totalLen += len(tokens)
+
+		s.keywords = append(s.keywords, Tokenize(strings.Join(lessons.ExtractKeywords(l), " ")))
 	}
+	s.buildKeywordSets()
 
+	s.avgTitleDL = float64(titleLen) / float64(s.n)
 	s.avgDL = float64(totalLen) / float64(s.n)
 
-	// Build document frequency counts
-	for _, tokens := range s.docTokens {
+	// Build document frequency counts; a term in either field (or only in
+	// the keywords, e.g. a trigger) counts once
+	for i, tokens := range s.docTokens {
 		seen := make(map[string]bool)
+		for _, t := range s.titleTokens[i] {
+			seen[t] = true
+		}
 		for _, t := range tokens {
 			seen[t] = true
 		}
+		for _, t := range s.keywords[i] {
+			seen[t] = true
+		}
 		for term := range seen {
 			s.df[term]++
 		}
 	}
 
+	// Sorted vocabulary for wildcard prefix expansion
+	s.vocab = make([]string, 0, len(s.df))
+	for term := range s.df {
+		s.vocab = append(s.vocab, term)
+	}
+	sort.Strings(s.vocab)
+
 	return s
 }
 
+// buildKeywordSets indexes each lesson's keyword tokens for lookup
+func (s *BM25Scorer) buildKeywordSets() {
+	s.keywordSets = make([]map[string]bool, len(s.keywords))
+	for i, kws := range s.keywords {
+		s.keywordSets[i] = make(map[string]bool, len(kws))
+		for _, t := range kws {
+			s.keywordSets[i][t] = true
+		}
+	}
+}
+
+// Len returns the number of indexed lessons
+func (s *BM25Scorer) Len() int {
+	return s.n
+}
+
+// lessonText returns the full text of a lesson: title, content, and tags
+func lessonText(l *models.Lesson) string {
+	return l.Title + " " + bodyText(l)
+}
+
+// bodyText returns the indexed body of a lesson: content and tags
+func bodyText(l *models.Lesson) string {
+	return l.Content + " " + strings.Join(l.Tags, " ")
+}
+
 // Tokenize converts text to tokens: lowercase, split on non-alphanumeric, remove stop words, min length 2
 func Tokenize(text string) []string {
 	if text == "" {
@@ -102,6 +213,43 @@ func Tokenize(text string) []string {
 	return tokens
 }
 
+// ParseQuery splits a query into exact terms and wildcard prefixes.
+// Words ending in "*" become prefixes ("context*" -> "context"); a bare "*"
+// yields the empty prefix, which matches every term. Everything else is
+// tokenized with the same rules as Tokenize.
+func ParseQuery(query string) Query {
+	var q Query
+	for _, word := range strings.Fields(query) {
+		if !strings.HasSuffix(word, "*") {
+			q.Terms = append(q.Terms, Tokenize(word)...)
+			continue
+		}
+
+		// Leading segments of "foo.bar*" are exact terms, the last is the prefix
+		parts := splitRe.Split(strings.ToLower(strings.TrimRight(word, "*")), -1)
+		prefix := ""
+		for i := len(parts) - 1; i >= 0; i-- {
+			if parts[i] != "" {
+				prefix = parts[i]
+				q.Terms = append(q.Terms, Tokenize(strings.Join(parts[:i], " "))...)
+				break
+			}
+		}
+		q.Prefixes = append(q.Prefixes, prefix)
+	}
+	return q
+}
+
+// expandPrefix returns all vocabulary terms starting with prefix (binary search)
+func (s *BM25Scorer) expandPrefix(prefix string) []string {
+	start := sort.SearchStrings(s.vocab, prefix)
+	var terms []string
+	for i := start; i < len(s.vocab) && strings.HasPrefix(s.vocab[i], prefix); i++ {
+		terms = append(terms, s.vocab[i])
+	}
+	return terms
+}
+
 // idf computes IDF for a term using standard BM25 formula
 func (s *BM25Scorer) idf(term string) float64 {
 	df := s.df[term]
@@ -112,12 +260,20 @@ func (s *BM25Scorer) idf(term string) float64 {
 	return math.Log((float64(s.n-df)+0.5)/(float64(df)+0.5) + 1.0)
 }
 
-// scoreDoc computes raw BM25 score for a single document
-func (s *BM25Scorer) scoreDoc(docIdx int, queryTerms []string) float64 {
-	tokens := s.docTokens[docIdx]
-	dl := s.docLens[docIdx]
+// scoreDoc computes raw BM25 score for a single document: the boosted
+// title score plus the body score
+func (s *BM25Scorer) scoreDoc(docIdx int, queryTerms map[string]float64) float64 {
+	title := s.scoreField(s.titleTokens[docIdx], s.titleLens[docIdx], s.avgTitleDL, nil, queryTerms)
+	body := s.scoreField(s.docTokens[docIdx], s.docLens[docIdx], s.avgDL, s.keywordSets[docIdx], queryTerms)
+	return s.TitleBoost*title + body
+}
 
-	if dl == 0 {
+// scoreField computes the BM25 score of one field of a document.
+// Each query term contributes its BM25 score multiplied by its weight.
+// Terms in keywords count KeywordWeight times (at least once, so a
+// trigger-only keyword still matches).
+func (s *BM25Scorer) scoreField(tokens []string, dl int, avgDL float64, keywords map[string]bool, queryTerms map[string]float64) float64 {
+	if dl == 0 && len(keywords) == 0 {
 		return 0.0
 	}
 
@@ -128,37 +284,64 @@ func (s *BM25Scorer) scoreDoc(docIdx int, queryTerms []string) float64 {
 	}
 
 	score := 0.0
-	for _, term := range queryTerms {
-		tf := tfMap[term]
+	for term, weight := range queryTerms {
+		tf := float64(tfMap[term])
+		if keywords[term] {
+			tf = KeywordWeight * math.Max(tf, 1)
+		}
 		if tf == 0 {
 			continue
 		}
 		idf := s.idf(term)
-		numerator := float64(tf) * (s.k1 + 1.0)
-		denominator := float64(tf) + s.k1*(1.0-s.b+s.b*float64(dl)/s.avgDL)
-		score += idf * numerator / denominator
+		numerator := tf * (s.k1 + 1.0)
+		denominator := tf + s.k1*(1.0-s.b+s.b*float64(dl)/avgDL)
+		score += weight * idf * numerator / denominator
 	}
 
 	return score
 }
 
-// Score scores all lessons against a query, returning sorted results (0-10 scale)
-func (s *BM25Scorer) Score(query string) []ScoredLesson {
-	if len(s.lessons) == 0 {
-		return nil
+// queryWeights resolves a parsed query into term weights. Each exact term
+// occurrence adds 1.0; wildcard expansions get WildcardWeight unless also exact.
+func (s *BM25Scorer) queryWeights(q Query) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, term := range q.Terms {
+		weights[term] += 1.0
 	}
+	for _, prefix := range q.Prefixes {
+		for _, term := range s.expandPrefix(prefix) {
+			if _, exact := weights[term]; !exact {
+				weights[term] = WildcardWeight
+			}
+		}
+	}
+	return weights
+}
 
-	queryTerms := Tokenize(query)
+// rawScores computes unnormalized BM25 scores for every document
+func (s *BM25Scorer) rawScores(query string) []float64 {
+	queryTerms := s.queryWeights(ParseQuery(query))
 
-	// Compute raw BM25 scores
-	rawScores := make([]float64, s.n)
+	scores := make([]float64, s.n)
 	for i := 0; i < s.n; i++ {
 		if len(queryTerms) == 0 {
-			rawScores[i] = 0.0
+			scores[i] = 0.0
 		} else {
-			rawScores[i] = s.scoreDoc(i, queryTerms)
+			scores[i] = s.scoreDoc(i, queryTerms)
 		}
 	}
+	return scores
+}
+
+// Score scores all lessons against a query, returning sorted results (0-10 scale).
+// Query words ending in "*" are expanded to every indexed term with that prefix.
+func (s *BM25Scorer) Score(query string) []ScoredLesson {
+	if len(s.lessons) == 0 {
+		return nil
+	}
+
+	// Compute raw BM25 scores
+	rawScores := s.rawScores(query)
 
 	// Find max for normalization
 	maxRaw := 0.0
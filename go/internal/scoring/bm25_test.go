@@ -1,6 +1,8 @@
 package scoring
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pbrown/claude-recall/internal/models"
@@ -162,3 +164,227 @@ func TestScore_TiebreakByUses(t *testing.T) {
 		t.Errorf("expected same scores, got %d and %d", results[0].Score, results[1].Score)
 	}
 }
+
+func TestParseQuery_Wildcards(t *testing.T) {
+	q := ParseQuery("goroutine err* context.*")
+	if len(q.Terms) != 1 || q.Terms[0] != "goroutine" {
+		t.Errorf("expected terms [goroutine], got %v", q.Terms)
+	}
+	if len(q.Prefixes) != 2 || q.Prefixes[0] != "err" || q.Prefixes[1] != "context" {
+		t.Errorf("expected prefixes [err context], got %v", q.Prefixes)
+	}
+}
+
+func TestParseQuery_BareWildcard(t *testing.T) {
+	q := ParseQuery("*")
+	if len(q.Terms) != 0 {
+		t.Errorf("expected no terms, got %v", q.Terms)
+	}
+	if len(q.Prefixes) != 1 || q.Prefixes[0] != "" {
+		t.Errorf("expected single empty prefix, got %v", q.Prefixes)
+	}
+}
+
+func makeContextLessons() []*models.Lesson {
+	return []*models.Lesson{
+		{ID: "L001", Title: "Pass context.Context first", Content: "Functions doing IO take ctx as the first argument"},
+		{ID: "L002", Title: "Cancel derived contexts", Content: "Always defer cancel after context.WithCancel"},
+		{ID: "L003", Title: "Docker container networking", Content: "Containers communicate via bridge networks by default"},
+	}
+}
+
+func TestScore_WildcardMatchesPrefix(t *testing.T) {
+	scorer := NewBM25Scorer(makeContextLessons())
+
+	results := scorer.Score("context*")
+	matched := make(map[string]int)
+	for _, r := range results {
+		matched[r.Lesson.ID] = r.Score
+	}
+
+	if matched["L001"] == 0 {
+		t.Errorf("expected L001 (context.Context) to match context*")
+	}
+	if matched["L002"] == 0 {
+		t.Errorf("expected L002 (context.WithCancel) to match context*")
+	}
+	if matched["L003"] != 0 {
+		t.Errorf("expected L003 not to match context*, got %d", matched["L003"])
+	}
+}
+
+func TestScore_WildcardLowerThanExact(t *testing.T) {
+	scorer := NewBM25Scorer(makeContextLessons())
+
+	exact := scorer.rawScores("context")
+	wildcard := scorer.rawScores("context*")
+
+	// L001 contains "context" exactly; the wildcard contribution is discounted
+	if wildcard[0] >= exact[0] {
+		t.Errorf("expected wildcard score (%f) < exact score (%f)", wildcard[0], exact[0])
+	}
+}
+
+func TestScore_BareWildcardMatchesAll(t *testing.T) {
+	scorer := NewBM25Scorer(makeContextLessons())
+
+	for _, r := range scorer.Score("*") {
+		if r.Score == 0 {
+			t.Errorf("expected bare wildcard to match %s", r.Lesson.ID)
+		}
+	}
+}
+
+// makeLengthLessons returns a short lesson mentioning "cache" once and a long
+// lesson mentioning it three times, all in the body, so length normalization
+// decides the winner
+func makeLengthLessons() []*models.Lesson {
+	return []*models.Lesson{
+		{ID: "L001", Title: "Invalidation", Content: "Bust the cache on deploy"},
+		{ID: "L002", Title: "Build pipeline notes", Content: "The build cache speeds up CI runs, but a stale cache " +
+			"breaks reproducibility across runners, containers, branches, release tags, nightly jobs, " +
+			"artifact uploads, dependency mirrors and cache restores between unrelated workflow stages"},
+		{ID: "L003", Title: "Unrelated", Content: "Docker bridge networking"},
+	}
+}
+
+func TestScore_DefaultParameters(t *testing.T) {
+	scorer := NewBM25Scorer(makeLessons())
+	if scorer.k1 != DefaultK1 || scorer.b != DefaultB || scorer.TitleBoost != DefaultTitleBoost {
+		t.Errorf("expected defaults k1=%v b=%v boost=%v, got k1=%v b=%v boost=%v",
+			DefaultK1, DefaultB, DefaultTitleBoost, scorer.k1, scorer.b, scorer.TitleBoost)
+	}
+}
+
+func TestScore_BChangesRanking(t *testing.T) {
+	// Full length normalization favors the short lesson
+	normalized := NewBM25Scorer(makeLengthLessons(), WithB(1.0)).Score("cache")
+	if normalized[0].Lesson.ID != "L001" {
+		t.Errorf("expected L001 first with b=1, got %s", normalized[0].Lesson.ID)
+	}
+
+	// No length normalization lets raw term frequency win
+	raw := NewBM25Scorer(makeLengthLessons(), WithB(0.0)).Score("cache")
+	if raw[0].Lesson.ID != "L002" {
+		t.Errorf("expected L002 first with b=0, got %s", raw[0].Lesson.ID)
+	}
+}
+
+func TestScore_K1ChangesRanking(t *testing.T) {
+	// With k1=0, term frequency saturates immediately so only length matters
+	saturated := NewBM25Scorer(makeLengthLessons(), WithK1(0.0), WithB(0.0)).Score("cache")
+	if saturated[0].Score != saturated[1].Score {
+		t.Errorf("expected tie with k1=0, got %d vs %d", saturated[0].Score, saturated[1].Score)
+	}
+
+	// A large k1 rewards repeated terms
+	linear := NewBM25Scorer(makeLengthLessons(), WithK1(10.0), WithB(0.0)).Score("cache")
+	if linear[0].Lesson.ID != "L002" || linear[0].Score <= linear[1].Score {
+		t.Errorf("expected L002 to clearly lead with k1=10, got %s (%d vs %d)",
+			linear[0].Lesson.ID, linear[0].Score, linear[1].Score)
+	}
+}
+
+// makeSyntheticLessons builds a corpus of n lessons drawn from a small vocabulary
+func makeSyntheticLessons(n int) []*models.Lesson {
+	vocab := []string{"git", "commit", "hook", "python", "venv", "docker", "network", "cache",
+		"goroutine", "channel", "context", "cancel", "test", "race", "deploy", "config"}
+	lessons := make([]*models.Lesson, n)
+	for i := 0; i < n; i++ {
+		var words []string
+		for j := 0; j < 12+i%9; j++ {
+			words = append(words, vocab[(i*7+j*3)%len(vocab)])
+		}
+		lessons[i] = &models.Lesson{
+			ID:      fmt.Sprintf("L%03d", i+1),
+			Title:   vocab[i%len(vocab)] + " " + vocab[(i+5)%len(vocab)],
+			Content: strings.Join(words, " "),
+			Uses:    i % 13,
+		}
+	}
+	return lessons
+}
+
+func BenchmarkBM25_Default(b *testing.B) {
+	scorer := NewBM25Scorer(makeSyntheticLessons(100))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scorer.Score("goroutine context cancel race")
+	}
+}
+
+func BenchmarkBM25_Tuned(b *testing.B) {
+	scorer := NewBM25Scorer(makeSyntheticLessons(100), WithK1(1.5), WithB(0.6))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scorer.Score("goroutine context cancel race")
+	}
+}
+
+func TestBM25_IndexesTags(t *testing.T) {
+	lessons := []*models.Lesson{
+		{ID: "L001", Title: "Close response bodies", Content: "Always defer the close", Tags: []string{"http"}},
+		{ID: "L002", Title: "Docker networking", Content: "Bridge networks by default"},
+	}
+
+	results := NewBM25Scorer(lessons).Score("http")
+	if results[0].Lesson.ID != "L001" || results[0].Score == 0 {
+		t.Errorf("expected tagged lesson to match, got %s (%d)", results[0].Lesson.ID, results[0].Score)
+	}
+}
+
+func TestScore_TitleBoost(t *testing.T) {
+	lessons := []*models.Lesson{
+		{ID: "L001", Title: "Notes on builds", Content: "Bust the cache after every deploy"},
+		{ID: "L002", Title: "Cache invalidation", Content: "Bust it after every deploy"},
+		{ID: "L003", Title: "Unrelated", Content: "Docker bridge networking"},
+	}
+
+	boosted := NewBM25Scorer(lessons).Score("cache")
+	if boosted[0].Lesson.ID != "L002" || boosted[0].Score <= boosted[1].Score {
+		t.Errorf("expected title match L002 to lead with default boost, got %s (%d vs %d)",
+			boosted[0].Lesson.ID, boosted[0].Score, boosted[1].Score)
+	}
+
+	// Setting the field directly works the same as the option
+	scorer := NewBM25Scorer(lessons)
+	scorer.TitleBoost = 5.0
+	if results := scorer.Score("cache"); results[0].Lesson.ID != "L002" || results[1].Score > 3 {
+		t.Errorf("expected content match far behind with boost 5, got %s (%d vs %d)",
+			results[0].Lesson.ID, results[0].Score, results[1].Score)
+	}
+}
+
+func TestScore_KeywordHints(t *testing.T) {
+	lessons := []*models.Lesson{
+		{ID: "L001", Title: "Go error handling", Content: "Wrap errors with fmt.Errorf and %w so callers can unwrap them",
+			Triggers: []string{"panic"}},
+		{ID: "L002", Title: "Docker networking", Content: "Containers talk over bridge networks by default"},
+		{ID: "L003", Title: "Python packaging", Content: "Pin versions in requirements files"},
+	}
+	scorer := NewBM25Scorer(lessons)
+
+	raw := func(query string) float64 {
+		return scorer.rawScores(query)[0]
+	}
+	if hit, miss := raw("fmt.Errorf"), raw("kubernetes ingress"); hit <= miss {
+		t.Errorf("expected fmt.Errorf (%f) to outscore an unrelated query (%f)", hit, miss)
+	}
+	if results := scorer.Score("fmt.Errorf"); results[0].Lesson.ID != "L001" || results[0].Score != 10 {
+		t.Errorf("expected L001 to lead for fmt.Errorf, got %s (%d)", results[0].Lesson.ID, results[0].Score)
+	}
+
+	// A keyword outweighs the same term as plain prose
+	plain := NewBM25Scorer([]*models.Lesson{
+		{ID: "L001", Title: "Go error handling", Content: "Wrap errors with fmt errorf and %w so callers can unwrap them"},
+		lessons[1], lessons[2],
+	})
+	if boosted, unboosted := raw("errorf"), plain.rawScores("errorf")[0]; boosted <= unboosted {
+		t.Errorf("expected keyword score %f > plain score %f", boosted, unboosted)
+	}
+
+	// Triggers are keywords even when absent from the content
+	if raw("panic") == 0 {
+		t.Error("expected trigger-only keyword to match")
+	}
+}
@@ -0,0 +1,66 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// ScoredHandoff represents a handoff with a relevance score (0-10)
+type ScoredHandoff struct {
+	Handoff *models.Handoff
+	Score   int
+}
+
+// HandoffText joins the free-text fields of a handoff (description, next
+// steps, and tried step descriptions) for indexing. The title is indexed
+// separately as the document title.
+func HandoffText(h *models.Handoff) string {
+	parts := []string{h.Description, h.NextSteps}
+	for _, step := range h.Tried {
+		parts = append(parts, step.Description)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ScoreHandoffs ranks handoffs against a query with BM25 over their title
+// and HandoffText. Results are ordered by raw BM25 score, so handoffs that
+// round to the same 0-10 score still rank by how well they match.
+func ScoreHandoffs(handoffList []*models.Handoff, query string) []ScoredHandoff {
+	if len(handoffList) == 0 {
+		return nil
+	}
+
+	docs := make([]*models.Lesson, len(handoffList))
+	for i, h := range handoffList {
+		docs[i] = &models.Lesson{ID: h.ID, Title: h.Title, Content: HandoffText(h)}
+	}
+	rawScores := NewBM25Scorer(docs).rawScores(query)
+
+	maxRaw := 0.0
+	for _, r := range rawScores {
+		if r > maxRaw {
+			maxRaw = r
+		}
+	}
+
+	order := make([]int, len(handoffList))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rawScores[order[i]] > rawScores[order[j]]
+	})
+
+	results := make([]ScoredHandoff, len(handoffList))
+	for rank, i := range order {
+		normalized := 0
+		if maxRaw > 0.0 {
+			normalized = int(math.Round(10.0 * rawScores[i] / maxRaw))
+		}
+		results[rank] = ScoredHandoff{Handoff: handoffList[i], Score: normalized}
+	}
+	return results
+}
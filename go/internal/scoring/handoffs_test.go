@@ -0,0 +1,42 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func TestScoreHandoffs_MoreMatchesRankHigher(t *testing.T) {
+	one := models.NewHandoff("hf-0000001", "Cache invalidation")
+	one.Description = "Stale entries after deploy"
+
+	two := models.NewHandoff("hf-0000002", "Redis cache rollout")
+	two.Description = "Move session cache to redis"
+	two.NextSteps = "Benchmark redis cluster failover"
+
+	three := models.NewHandoff("hf-0000003", "Login page polish")
+	three.Description = "Button alignment"
+
+	four := models.NewHandoff("hf-0000004", "Connection pool")
+	four.Tried = []models.TriedStep{{Outcome: "fail", Description: "Raised redis pool size"}}
+
+	results := ScoreHandoffs([]*models.Handoff{one, three, four, two}, "redis cache")
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	if results[0].Handoff.ID != "hf-0000002" || results[0].Score != 10 {
+		t.Errorf("expected handoff matching both terms first, got %s (%d)", results[0].Handoff.ID, results[0].Score)
+	}
+	if results[3].Handoff.ID != "hf-0000003" || results[3].Score != 0 {
+		t.Errorf("expected non-matching handoff last, got %s (%d)", results[3].Handoff.ID, results[3].Score)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("results not sorted by score: %d then %d", results[i-1].Score, results[i].Score)
+		}
+	}
+
+	if ScoreHandoffs(nil, "redis") != nil {
+		t.Error("expected nil for empty handoff list")
+	}
+}
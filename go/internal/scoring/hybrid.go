@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// HybridScorer blends BM25 relevance with historical usage (uses + velocity)
+type HybridScorer struct {
+	bm25  *BM25Scorer
+	alpha float64 // Weight of BM25 relevance; usage gets 1 - alpha
+}
+
+// NewHybridScorer creates a scorer where alpha (0-1) is the weight given to
+// query relevance and the remainder goes to usage. Alpha is clamped to [0, 1].
+func NewHybridScorer(lessons []*models.Lesson, alpha float64) *HybridScorer {
+	if alpha < 0 {
+		alpha = 0
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+	return &HybridScorer{
+		bm25:  NewBM25Scorer(lessons),
+		alpha: alpha,
+	}
+}
+
+// usageScore is the combined usage score used by inject
+func usageScore(l *models.Lesson) float64 {
+	return float64(l.Uses) + l.Velocity
+}
+
+// Score scores all lessons against a query, returning sorted results (0-10 scale)
+func (h *HybridScorer) Score(query string) []ScoredLesson {
+	relevance := h.bm25.Score(query)
+	if len(relevance) == 0 {
+		return nil
+	}
+
+	// Normalize usage to 0-10 against the most-used lesson
+	maxUsage := 0.0
+	for _, sl := range relevance {
+		if u := usageScore(sl.Lesson); u > maxUsage {
+			maxUsage = u
+		}
+	}
+
+	type blended struct {
+		lesson *models.Lesson
+		score  float64
+	}
+	scored := make([]blended, len(relevance))
+	for i, sl := range relevance {
+		usage := 0.0
+		if maxUsage > 0 {
+			usage = 10.0 * usageScore(sl.Lesson) / maxUsage
+		}
+		scored[i] = blended{
+			lesson: sl.Lesson,
+			score:  h.alpha*float64(sl.Score) + (1-h.alpha)*usage,
+		}
+	}
+
+	// Sort by blended score descending, tiebreak by uses descending
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].lesson.Uses > scored[j].lesson.Uses
+	})
+
+	results := make([]ScoredLesson, len(scored))
+	for i, b := range scored {
+		results[i] = ScoredLesson{
+			Lesson: b.lesson,
+			Score:  int(math.Round(b.score)),
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,53 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func makeUsageLessons() []*models.Lesson {
+	return []*models.Lesson{
+		{ID: "L001", Title: "Git commit hooks", Content: "Always run pre-commit hooks before pushing code", Uses: 50, Velocity: 3},
+		{ID: "L002", Title: "Python virtual environments", Content: "Use venv for Python project isolation", Uses: 1},
+		{ID: "L003", Title: "Docker container networking", Content: "Containers communicate via bridge networks by default", Uses: 2},
+	}
+}
+
+func TestHybrid_RelevanceOutranksUsage(t *testing.T) {
+	scorer := NewHybridScorer(makeUsageLessons(), 0.6)
+
+	results := scorer.Score("python venv isolation")
+	if results[0].Lesson.ID != "L002" {
+		t.Errorf("expected L002 first for python query, got %s", results[0].Lesson.ID)
+	}
+}
+
+func TestHybrid_EmptyQueryFallsBackToUsage(t *testing.T) {
+	scorer := NewHybridScorer(makeUsageLessons(), 0.6)
+
+	results := scorer.Score("")
+	if results[0].Lesson.ID != "L001" {
+		t.Errorf("expected most-used L001 first, got %s", results[0].Lesson.ID)
+	}
+}
+
+func TestHybrid_AlphaClamped(t *testing.T) {
+	scorer := NewHybridScorer(makeUsageLessons(), 5)
+	if scorer.alpha != 1 {
+		t.Errorf("expected alpha clamped to 1, got %f", scorer.alpha)
+	}
+
+	// Pure relevance: usage must not lift L001 over the docker lesson
+	results := scorer.Score("docker bridge networks")
+	if results[0].Lesson.ID != "L003" {
+		t.Errorf("expected L003 first, got %s", results[0].Lesson.ID)
+	}
+}
+
+func TestHybrid_EmptyLessons(t *testing.T) {
+	scorer := NewHybridScorer(nil, 0.6)
+	if results := scorer.Score("anything"); len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
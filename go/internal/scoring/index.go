@@ -0,0 +1,154 @@
+package scoring
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// bm25IndexVersion is bumped whenever the on-disk index format changes
+const bm25IndexVersion = 3
+
+// ErrStaleIndex is returned by BM25Index.Load when the saved index was built
+// from different lesson files (or an older format) and must be rebuilt
+var ErrStaleIndex = errors.New("bm25 index is stale")
+
+// BM25Index persists a BM25Scorer's precomputed statistics to disk. Checksum
+// identifies the source lesson files (see ChecksumFiles); a saved index is
+// only reused when its checksum matches.
+type BM25Index struct {
+	Checksum string
+	Scorer   *BM25Scorer
+}
+
+// bm25IndexFile is the JSON representation of a saved index. k1, b, and the
+// title boost are applied at query time, so they are not part of the index.
+type bm25IndexFile struct {
+	Version     int              `json:"version"`
+	Checksum    string           `json:"checksum"`
+	Lessons     []*models.Lesson `json:"lessons"`
+	TitleTokens [][]string       `json:"title_tokens"`
+	TitleLens   []int            `json:"title_lens"`
+	AvgTitleDL  float64          `json:"avg_title_dl"`
+	DocTokens   [][]string       `json:"doc_tokens"`
+	DocLens     []int            `json:"doc_lens"`
+	AvgDL       float64          `json:"avg_dl"`
+	Keywords    [][]string       `json:"keywords"`
+	DF          map[string]int   `json:"df"`
+}
+
+// ChecksumFiles hashes the contents of the given files. Missing files hash
+// as empty, so creating one later changes the checksum.
+func ChecksumFiles(paths ...string) (string, error) {
+	h := sha256.New()
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", p, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Save writes the index to path atomically, so concurrent readers never see
+// a partially written file
+func (idx *BM25Index) Save(path string) error {
+	s := idx.Scorer
+	data, err := json.Marshal(bm25IndexFile{
+		Version:     bm25IndexVersion,
+		Checksum:    idx.Checksum,
+		Lessons:     s.lessons,
+		TitleTokens: s.titleTokens,
+		TitleLens:   s.titleLens,
+		AvgTitleDL:  s.avgTitleDL,
+		DocTokens:   s.docTokens,
+		DocLens:     s.docLens,
+		AvgDL:       s.avgDL,
+		Keywords:    s.keywords,
+		DF:          s.df,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads the index at path and returns a ready scorer. It returns
+// ErrStaleIndex if the saved checksum differs from idx.Checksum; opts set
+// the query-time k1, b, and title boost parameters.
+func (idx *BM25Index) Load(path string, opts ...BM25Option) (*BM25Scorer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f bm25IndexFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStaleIndex, err)
+	}
+	if f.Version != bm25IndexVersion || f.Checksum != idx.Checksum {
+		return nil, ErrStaleIndex
+	}
+	if len(f.DocTokens) != len(f.Lessons) || len(f.DocLens) != len(f.Lessons) ||
+		len(f.TitleTokens) != len(f.Lessons) || len(f.TitleLens) != len(f.Lessons) ||
+		len(f.Keywords) != len(f.Lessons) {
+		return nil, fmt.Errorf("%w: corrupt index", ErrStaleIndex)
+	}
+
+	s := &BM25Scorer{
+		TitleBoost:  DefaultTitleBoost,
+		lessons:     f.Lessons,
+		k1:          DefaultK1,
+		b:           DefaultB,
+		titleTokens: f.TitleTokens,
+		titleLens:   f.TitleLens,
+		avgTitleDL:  f.AvgTitleDL,
+		docTokens:   f.DocTokens,
+		docLens:     f.DocLens,
+		avgDL:       f.AvgDL,
+		keywords:    f.Keywords,
+		df:          f.DF,
+		n:           len(f.Lessons),
+	}
+	if s.df == nil {
+		s.df = make(map[string]int)
+	}
+	s.buildKeywordSets()
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.vocab = make([]string, 0, len(s.df))
+	for term := range s.df {
+		s.vocab = append(s.vocab, term)
+	}
+	sort.Strings(s.vocab)
+
+	return s, nil
+}
@@ -0,0 +1,107 @@
+package scoring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestBM25Index_LoadMatchesFreshScores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bm25-index.json")
+	scorer := NewBM25Scorer(makeSyntheticLessons(50))
+
+	index := &BM25Index{Checksum: "abc", Scorer: scorer}
+	if err := index.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := (&BM25Index{Checksum: "abc"}).Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Len() != scorer.Len() {
+		t.Fatalf("expected %d lessons, got %d", scorer.Len(), loaded.Len())
+	}
+
+	for _, query := range []string{"git commit", "goroutine channel cancel", "dep*", "nothing matches"} {
+		want := scorer.Score(query)
+		got := loaded.Score(query)
+		for i := range want {
+			if got[i].Lesson.ID != want[i].Lesson.ID || got[i].Score != want[i].Score {
+				t.Errorf("%q result %d: got %s=%d, want %s=%d", query, i,
+					got[i].Lesson.ID, got[i].Score, want[i].Lesson.ID, want[i].Score)
+			}
+		}
+	}
+}
+
+func TestBM25Index_StaleChecksum(t *testing.T) {
+	dir := t.TempDir()
+	lessonsPath := filepath.Join(dir, "LESSONS.md")
+	indexPath := filepath.Join(dir, "bm25-index.json")
+	os.WriteFile(lessonsPath, []byte("### [L001] original\n"), 0644)
+
+	before, err := ChecksumFiles(lessonsPath, filepath.Join(dir, "missing.md"))
+	if err != nil {
+		t.Fatalf("ChecksumFiles failed: %v", err)
+	}
+	if err := (&BM25Index{Checksum: before, Scorer: NewBM25Scorer(makeLessons())}).Save(indexPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	os.WriteFile(lessonsPath, []byte("### [L001] edited\n"), 0644)
+	after, _ := ChecksumFiles(lessonsPath, filepath.Join(dir, "missing.md"))
+	if after == before {
+		t.Fatal("expected checksum to change when the lessons file changes")
+	}
+
+	if _, err := (&BM25Index{Checksum: after}).Load(indexPath); !errors.Is(err, ErrStaleIndex) {
+		t.Errorf("expected ErrStaleIndex, got %v", err)
+	}
+
+	os.WriteFile(indexPath, []byte("{not json"), 0644)
+	if _, err := (&BM25Index{Checksum: before}).Load(indexPath); !errors.Is(err, ErrStaleIndex) {
+		t.Errorf("expected ErrStaleIndex for corrupt index, got %v", err)
+	}
+}
+
+func TestBM25Index_ConcurrentReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bm25-index.json")
+	index := &BM25Index{Checksum: "abc", Scorer: NewBM25Scorer(makeSyntheticLessons(100))}
+	if err := index.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	want := index.Scorer.Score("docker network")[0].Lesson.ID
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Interleave rewrites with reads; atomic saves keep reads consistent
+			if i%5 == 0 {
+				if err := index.Save(path); err != nil {
+					errs <- err
+					return
+				}
+			}
+			loaded, err := (&BM25Index{Checksum: "abc"}).Load(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got := loaded.Score("docker network")[0].Lesson.ID; got != want {
+				errs <- errors.New("unexpected top result " + got)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,12 @@
+package scoring
+
+import "github.com/pbrown/claude-recall/internal/models"
+
+// InjectScore ranks lessons for injection: uses + velocity, plus each
+// recorded prevention counted as preventionWeight uses, scaled by the
+// lesson's confidence so uncertain lessons rank lower and by its manual
+// weight so critical-but-rare lessons can rank higher
+func InjectScore(l *models.Lesson, preventionWeight float64) float64 {
+	base := float64(l.Uses) + l.Velocity + preventionWeight*float64(l.Preventions)
+	return base * l.Weight * float64(l.Confidence) / 100.0
+}
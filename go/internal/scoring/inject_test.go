@@ -0,0 +1,29 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+func TestInjectScore_CountsPreventions(t *testing.T) {
+	l := models.NewLesson("L001", "Check inputs", "Validate before use")
+	l.Uses = 4
+	l.Velocity = 1
+	l.Preventions = 3
+	l.Confidence = 100
+	l.Weight = 1
+
+	if got := InjectScore(l, 2); got != 11 {
+		t.Errorf("InjectScore with weight 2 = %g, want 11", got)
+	}
+	if got := InjectScore(l, 0); got != 5 {
+		t.Errorf("InjectScore with weight 0 = %g, want 5", got)
+	}
+
+	l.Confidence = 50
+	l.Weight = 2
+	if got := InjectScore(l, 2); got != 11 {
+		t.Errorf("InjectScore at half confidence and double weight = %g, want 11", got)
+	}
+}
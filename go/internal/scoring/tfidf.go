@@ -0,0 +1,136 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// Scorer ranks lessons against a query on a 0-10 scale
+type Scorer interface {
+	Score(query string) []ScoredLesson
+}
+
+// TFIDFScorer scores lessons by TF-IDF cosine similarity
+type TFIDFScorer struct {
+	lessons []*models.Lesson
+	idf     map[string]float64   // term -> inverse document frequency
+	vectors []map[string]float64 // per-lesson TF-IDF weights
+	norms   []float64            // per-lesson vector magnitude
+}
+
+// NewTFIDFScorer creates a scorer from a set of lessons, precomputing IDF
+// values and document vectors so queries only need a dot product
+func NewTFIDFScorer(lessons []*models.Lesson) *TFIDFScorer {
+	s := &TFIDFScorer{
+		lessons: lessons,
+		idf:     make(map[string]float64),
+	}
+
+	n := len(lessons)
+	if n == 0 {
+		return s
+	}
+
+	// Term frequencies per lesson (title + content + tags) and document frequencies
+	termFreqs := make([]map[string]int, n)
+	df := make(map[string]int)
+	for i, l := range lessons {
+		tf := make(map[string]int)
+		for _, t := range Tokenize(lessonText(l)) {
+			tf[t]++
+		}
+		termFreqs[i] = tf
+		for term := range tf {
+			df[term]++
+		}
+	}
+
+	// Smoothed IDF: terms in every document still carry a little weight
+	for term, count := range df {
+		s.idf[term] = math.Log(float64(n)/float64(count)) + 1.0
+	}
+
+	s.vectors = make([]map[string]float64, n)
+	s.norms = make([]float64, n)
+	for i, tf := range termFreqs {
+		vec := make(map[string]float64, len(tf))
+		sumSq := 0.0
+		for term, count := range tf {
+			w := tfWeight(count) * s.idf[term]
+			vec[term] = w
+			sumSq += w * w
+		}
+		s.vectors[i] = vec
+		s.norms[i] = math.Sqrt(sumSq)
+	}
+
+	return s
+}
+
+// tfWeight dampens raw term counts with sublinear scaling
+func tfWeight(count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return 1.0 + math.Log(float64(count))
+}
+
+// Score scores all lessons against a query, returning sorted results (0-10 scale)
+func (s *TFIDFScorer) Score(query string) []ScoredLesson {
+	if len(s.lessons) == 0 {
+		return nil
+	}
+
+	// Build the query vector using corpus IDF (unknown terms contribute nothing)
+	queryTF := make(map[string]int)
+	for _, t := range Tokenize(query) {
+		queryTF[t]++
+	}
+	queryVec := make(map[string]float64, len(queryTF))
+	querySumSq := 0.0
+	for term, count := range queryTF {
+		w := tfWeight(count) * s.idf[term]
+		queryVec[term] = w
+		querySumSq += w * w
+	}
+	queryNorm := math.Sqrt(querySumSq)
+
+	// Cosine similarity per lesson
+	sims := make([]float64, len(s.lessons))
+	maxSim := 0.0
+	for i, vec := range s.vectors {
+		if queryNorm == 0 || s.norms[i] == 0 {
+			continue
+		}
+		dot := 0.0
+		for term, qw := range queryVec {
+			dot += qw * vec[term]
+		}
+		sims[i] = dot / (queryNorm * s.norms[i])
+		if sims[i] > maxSim {
+			maxSim = sims[i]
+		}
+	}
+
+	// Normalize to 0-10 integer scale
+	results := make([]ScoredLesson, len(s.lessons))
+	for i, l := range s.lessons {
+		normalized := 0
+		if maxSim > 0 {
+			normalized = int(math.Round(10.0 * sims[i] / maxSim))
+		}
+		results[i] = ScoredLesson{Lesson: l, Score: normalized}
+	}
+
+	// Sort by score descending, tiebreak by uses descending
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Lesson.Uses > results[j].Lesson.Uses
+	})
+
+	return results
+}
@@ -0,0 +1,70 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/pbrown/claude-recall/internal/models"
+)
+
+// Compile-time checks that both scorers satisfy Scorer
+var (
+	_ Scorer = (*BM25Scorer)(nil)
+	_ Scorer = (*TFIDFScorer)(nil)
+)
+
+func TestTFIDF_AllTermsBeatHalfTerms(t *testing.T) {
+	lessons := []*models.Lesson{
+		{ID: "L001", Title: "Goroutine leaks", Content: "Cancel the context so the goroutine exits"},
+		{ID: "L002", Title: "Goroutine basics", Content: "Spawn with the go keyword"},
+		{ID: "L003", Title: "Docker networking", Content: "Containers communicate via bridge networks"},
+	}
+	scorer := NewTFIDFScorer(lessons)
+
+	// L001 has all four terms, L002 only half of them
+	results := scorer.Score("goroutine cancel context leaks")
+	if results[0].Lesson.ID != "L001" {
+		t.Errorf("expected L001 first, got %s", results[0].Lesson.ID)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected L001 (%d) to outscore %s (%d)", results[0].Score, results[1].Lesson.ID, results[1].Score)
+	}
+	if results[2].Score != 0 {
+		t.Errorf("expected unrelated lesson to score 0, got %d", results[2].Score)
+	}
+}
+
+func TestTFIDF_Normalization(t *testing.T) {
+	scorer := NewTFIDFScorer(makeLessons())
+
+	results := scorer.Score("python virtual environment venv")
+	if results[0].Lesson.ID != "L002" || results[0].Score != 10 {
+		t.Errorf("expected L002 first with score 10, got %s (%d)", results[0].Lesson.ID, results[0].Score)
+	}
+}
+
+func TestTFIDF_EmptyQueryAndLessons(t *testing.T) {
+	for _, r := range NewTFIDFScorer(makeLessons()).Score("") {
+		if r.Score != 0 {
+			t.Errorf("expected score 0 for empty query, got %d", r.Score)
+		}
+	}
+	if results := NewTFIDFScorer(nil).Score("anything"); len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
+
+func BenchmarkTFIDF_200(b *testing.B) {
+	scorer := NewTFIDFScorer(makeSyntheticLessons(200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scorer.Score("goroutine context cancel race")
+	}
+}
+
+func BenchmarkBM25_200(b *testing.B) {
+	scorer := NewBM25Scorer(makeSyntheticLessons(200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scorer.Score("goroutine context cancel race")
+	}
+}
@@ -0,0 +1,112 @@
+// Package similarity scores how alike two pieces of text are, on a 0-1 scale.
+package similarity
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Algorithm names accepted by ForAlgo (and the dedup_algo config key)
+const (
+	AlgoJaccard = "jaccard"
+	AlgoCosine  = "cosine"
+)
+
+// NGramSize is the character n-gram length used by CosineSimilarity
+const NGramSize = 3
+
+// Func scores two strings 0-1
+type Func func(a, b string) float64
+
+// ForAlgo returns the similarity function for an algorithm name, or false if
+// the name is unknown
+func ForAlgo(algo string) (Func, bool) {
+	switch algo {
+	case AlgoJaccard:
+		return JaccardSimilarity, true
+	case AlgoCosine:
+		return CosineSimilarity, true
+	}
+	return nil, false
+}
+
+// IsValidAlgo reports whether algo names a known similarity algorithm
+func IsValidAlgo(algo string) bool {
+	_, ok := ForAlgo(algo)
+	return ok
+}
+
+// JaccardSimilarity is |A ∩ B| / |A ∪ B| over the lowercased alphanumeric
+// words of a and b (0 when both are empty)
+func JaccardSimilarity(a, b string) float64 {
+	setA, setB := wordSet(a), wordSet(b)
+	intersection := 0
+	for w := range setA {
+		if setB[w] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// CosineSimilarity is the cosine of the angle between the character n-gram
+// count vectors of a and b, after lowercasing and collapsing whitespace.
+// Tolerant of typos and word-form changes that defeat word-level Jaccard.
+func CosineSimilarity(a, b string) float64 {
+	va, vb := ngrams(a), ngrams(b)
+	if len(va) == 0 || len(vb) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for g, ca := range va {
+		normA += float64(ca * ca)
+		if cb, ok := vb[g]; ok {
+			dot += float64(ca * cb)
+		}
+	}
+	for _, cb := range vb {
+		normB += float64(cb * cb)
+	}
+	sim := dot / (math.Sqrt(normA) * math.Sqrt(normB))
+	// Guard against float drift for identical inputs
+	if sim > 1 {
+		sim = 1
+	}
+	return sim
+}
+
+// wordSet lowercases text and splits it into a set of alphanumeric words
+func wordSet(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// ngrams counts the NGramSize-rune substrings of normalized text. Text
+// shorter than NGramSize counts as a single gram.
+func ngrams(text string) map[string]int {
+	runes := []rune(strings.Join(strings.Fields(strings.ToLower(text)), " "))
+	counts := make(map[string]int)
+	if len(runes) == 0 {
+		return counts
+	}
+	if len(runes) < NGramSize {
+		counts[string(runes)]++
+		return counts
+	}
+	for i := 0; i+NGramSize <= len(runes); i++ {
+		counts[string(runes[i:i+NGramSize])]++
+	}
+	return counts
+}
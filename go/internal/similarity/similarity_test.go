@@ -0,0 +1,58 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_Similarity_Bounds(t *testing.T) {
+	for _, algo := range []string{AlgoJaccard, AlgoCosine} {
+		fn, ok := ForAlgo(algo)
+		if !ok {
+			t.Fatalf("ForAlgo(%q) not found", algo)
+		}
+
+		same := "Run the race detector before pushing"
+		if sim := fn(same, same); math.Abs(sim-1) > 1e-9 {
+			t.Errorf("%s: expected 1.0 for identical strings, got %f", algo, sim)
+		}
+		if sim := fn("abc def", "xyz uvw"); sim != 0 {
+			t.Errorf("%s: expected 0.0 for different strings, got %f", algo, sim)
+		}
+		if sim := fn("", ""); sim != 0 {
+			t.Errorf("%s: expected 0.0 for empty strings, got %f", algo, sim)
+		}
+
+		sim := fn("Run the race detector before pushing", "Run the linter before pushing to main")
+		if sim <= 0.2 || sim >= 0.8 {
+			t.Errorf("%s: expected mid-range similarity for partial overlap, got %f", algo, sim)
+		}
+	}
+}
+
+func Test_JaccardSimilarity_IgnoresCaseAndPunctuation(t *testing.T) {
+	if sim := JaccardSimilarity("Use venv!", "use VENV"); sim != 1 {
+		t.Errorf("expected 1.0, got %f", sim)
+	}
+	// {a, b, c} vs {b, c, d}: 2 shared of 4
+	if sim := JaccardSimilarity("a b c", "b c d"); sim != 0.5 {
+		t.Errorf("expected 0.5, got %f", sim)
+	}
+}
+
+func Test_CosineSimilarity_ToleratesTypos(t *testing.T) {
+	cosine := CosineSimilarity("configuration reload", "configuraton reload")
+	jaccard := JaccardSimilarity("configuration reload", "configuraton reload")
+	if cosine <= jaccard {
+		t.Errorf("expected n-gram cosine (%f) to beat word Jaccard (%f) on a typo", cosine, jaccard)
+	}
+}
+
+func Test_ForAlgo_Unknown(t *testing.T) {
+	if _, ok := ForAlgo("levenshtein"); ok {
+		t.Error("expected unknown algorithm to be rejected")
+	}
+	if IsValidAlgo("") {
+		t.Error("expected empty algorithm to be invalid")
+	}
+}
@@ -5,18 +5,21 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"time"
 )
 
 // Message represents a parsed transcript line.
 type Message struct {
-	Type    string // "user", "assistant", "progress", etc.
-	Content string // Extracted text content (empty for non-assistant)
+	Type      string    // "user", "assistant", "progress", etc.
+	Content   string    // Extracted text content (empty for non-assistant)
+	Timestamp time.Time // Zero if the line has no valid timestamp
 }
 
 // transcriptLine is the top-level structure of a transcript JSONL line.
 type transcriptLine struct {
-	Type    string          `json:"type"`
-	Message *messagePayload `json:"message,omitempty"`
+	Type      string          `json:"type"`
+	Timestamp string          `json:"timestamp,omitempty"`
+	Message   *messagePayload `json:"message,omitempty"`
 }
 
 // messagePayload is the message field within a transcript line.
@@ -115,6 +118,9 @@ func parseLine(line string) (Message, bool) {
 	msg := Message{
 		Type: tl.Type,
 	}
+	if ts, err := time.Parse(time.RFC3339Nano, tl.Timestamp); err == nil {
+		msg.Timestamp = ts
+	}
 
 	// Only extract content from assistant messages
 	if tl.Type == "assistant" && tl.Message != nil {
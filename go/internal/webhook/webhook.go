@@ -0,0 +1,59 @@
+// Package webhook posts JSON event payloads to a user-configured URL.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Timeout bounds each webhook request; failed requests are not retried
+const Timeout = 5 * time.Second
+
+// SecretEnv names the environment variable holding the HMAC signing secret
+const SecretEnv = "CLAUDE_RECALL_WEBHOOK_SECRET"
+
+// SignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>" when
+// SecretEnv is set, so receivers can verify the sender
+const SignatureHeader = "X-Recall-Signature"
+
+// Send POSTs payload as JSON to url. The body is signed with the secret in
+// SecretEnv if it is set. A non-2xx response is an error.
+func Send(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := os.Getenv(SecretEnv); secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(body, secret))
+	}
+
+	resp, err := (&http.Client{Timeout: Timeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed by secret
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
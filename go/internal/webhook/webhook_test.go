@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSend_PostsSignedJSON(t *testing.T) {
+	var gotBody []byte
+	var gotSig, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(SignatureHeader)
+		gotType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+	t.Setenv(SecretEnv, "s3cret")
+
+	if err := Send(server.URL, map[string]string{"event": "complete"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if string(gotBody) != `{"event":"complete"}` {
+		t.Errorf("unexpected body: %s", gotBody)
+	}
+	if gotType != "application/json" {
+		t.Errorf("unexpected content type: %q", gotType)
+	}
+	if want := "sha256=" + Sign(gotBody, "s3cret"); gotSig != want {
+		t.Errorf("signature = %q, want %q", gotSig, want)
+	}
+}
+
+func TestSend_UnsignedWithoutSecret(t *testing.T) {
+	hasSig := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasSig = r.Header[SignatureHeader]
+	}))
+	defer server.Close()
+	t.Setenv(SecretEnv, "")
+
+	if err := Send(server.URL, map[string]string{}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if hasSig {
+		t.Error("expected no signature header without a secret")
+	}
+}
+
+func TestSend_ErrorStatus(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := Send(server.URL, map[string]string{}); err == nil {
+		t.Error("expected error for 500 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
@@ -1985,6 +1985,188 @@ class TestHandoffPhaseAgentFormat:
         assert handoff.agent == "general-purpose"
         assert handoff.title == "Test approach with new format"
 
+    def test_handoff_parse_tried_steps_with_dates(self, manager: "LessonsManager"):
+        """Tried steps dated by the Go CLI should parse and keep their dates on rewrite."""
+        handoffs_file = manager.project_handoffs_file
+        handoffs_file.parent.mkdir(parents=True, exist_ok=True)
+        handoffs_file.write_text("""# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Dated steps
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-12
+
+**Tried**:
+1. [fail 2026-01-11] First attempt
+2. [success] Undated attempt
+
+**Next**: Ship it
+
+---
+""")
+
+        handoff = manager.handoff_get("hf-0000001")
+        assert [(t.outcome, t.description, t.logged) for t in handoff.tried] == [
+            ("fail", "First attempt", "2026-01-11"),
+            ("success", "Undated attempt", None),
+        ]
+
+        manager.handoff_add_tried("hf-0000001", "partial", "Third attempt")
+        content = handoffs_file.read_text()
+        assert "1. [fail 2026-01-11] First attempt" in content
+        assert "2. [success] Undated attempt" in content
+        assert "3. [partial] Third attempt" in content
+
+    def test_handoff_rewrite_keeps_go_priority_line(self, manager: "LessonsManager"):
+        """A Go-written Priority line must not stop Refs/Description parsing, and survives a rewrite."""
+        handoffs_file = manager.project_handoffs_file
+        handoffs_file.parent.mkdir(parents=True, exist_ok=True)
+        handoffs_file.write_text("""# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Go-written handoff
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-12
+- **Priority**: high
+- **Refs**: core/main.py:50 | core/models.py:10
+- **Description**: Keep me
+
+**Tried**:
+1. [fail 2026-01-11] First attempt
+
+**Next**: Ship it
+
+---
+""")
+
+        handoff = manager.handoff_get("hf-0000001")
+        assert handoff.refs == ["core/main.py:50", "core/models.py:10"]
+        assert handoff.description == "Keep me"
+        assert handoff.extra_metadata == ["- **Priority**: high"]
+
+        manager.handoff_update_next("hf-0000001", "Ship it today")
+        content = handoffs_file.read_text()
+        assert "- **Priority**: high" in content
+
+        rewritten = manager.handoff_get("hf-0000001")
+        assert rewritten.refs == ["core/main.py:50", "core/models.py:10"]
+        assert rewritten.description == "Keep me"
+        assert rewritten.next_steps == "Ship it today"
+        assert rewritten.extra_metadata == ["- **Priority**: high"]
+
+    def test_handoff_rewrite_keeps_go_sections(self, manager: "LessonsManager"):
+        """Milestones, Time Log, Checkpoint History, and Notes written by Go survive a Python rewrite."""
+        sections = """**Milestones**:
+- [x] Parser (2026-01-11)
+- [ ] Writer
+
+**Time Log**:
+- [2026-01-11 09:30] 45m sess-1
+
+**Checkpoint History**:
+- [2026-01-11 10:15] implementing: Wire the parser
+
+**Notes**:
+- [2026-01-11 10:20] First note
+  continues here
+
+- [2026-01-12 08:00] Second note"""
+        handoffs_file = manager.project_handoffs_file
+        handoffs_file.parent.mkdir(parents=True, exist_ok=True)
+        handoffs_file.write_text(f"""# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Go sections
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-12
+- **Description**: Has sections
+
+**Tried**:
+1. [fail 2026-01-11] First attempt
+
+{sections}
+
+**Next**: Ship it
+
+---
+
+### [hf-0000002] No tried steps
+- **Status**: not_started | **Phase**: research | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-10
+- **Description**: Go omits an empty Tried section
+
+**Notes**:
+- [2026-01-10 12:00] Only a note
+
+**Next**: Start
+
+---
+""")
+
+        manager.handoff_add_tried("hf-0000001", "partial", "Second attempt")
+        content = handoffs_file.read_text()
+        assert sections in content
+        assert "- [2026-01-10 12:00] Only a note" in content
+
+        first = manager.handoff_get("hf-0000001")
+        assert len(first.tried) == 2
+        assert first.next_steps == "Ship it"
+        assert "\n".join(first.extra_sections) == sections
+
+        second = manager.handoff_get("hf-0000002")
+        assert second.tried == []
+        assert second.next_steps == "Start"
+        assert second.extra_sections == ["**Notes**:", "- [2026-01-10 12:00] Only a note"]
+
+    def test_handoff_rewrite_handles_go_stale_line(self, manager: "LessonsManager"):
+        """A Go-written Stale line must not break parsing; updating the handoff clears it."""
+        handoffs_file = manager.project_handoffs_file
+        handoffs_file.parent.mkdir(parents=True, exist_ok=True)
+        handoffs_file.write_text("""# HANDOFFS.md - Active Work Tracking
+
+## Active Handoffs
+
+### [hf-0000001] Stale handoff
+- **Status**: in_progress | **Phase**: implementing | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-12
+- **Priority**: low
+- **Stale**: true
+- **Refs**: core/main.py:50
+- **Description**: Keep me
+
+**Next**: Ship it
+
+---
+
+### [hf-0000002] Other stale handoff
+- **Status**: in_progress | **Phase**: research | **Agent**: user
+- **Created**: 2026-01-10 | **Updated**: 2026-01-12
+- **Stale**: true
+- **Description**: Untouched
+
+**Next**: Later
+
+---
+""")
+
+        handoff = manager.handoff_get("hf-0000001")
+        assert handoff.refs == ["core/main.py:50"]
+        assert handoff.description == "Keep me"
+
+        manager.handoff_update_next("hf-0000001", "Ship it today")
+
+        updated = manager.handoff_get("hf-0000001")
+        assert updated.refs == ["core/main.py:50"]
+        assert updated.description == "Keep me"
+        assert updated.extra_metadata == ["- **Priority**: low"]
+
+        other = manager.handoff_get("hf-0000002")
+        assert other.description == "Untouched"
+        assert other.extra_metadata == ["- **Stale**: true"]
+
     def test_handoff_format_phase_agent_on_status_line(self, manager: "LessonsManager"):
         """Phase and agent should be on the status line after status."""
         handoff_id = manager.handoff_add(title="Test format", phase="planning", agent="plan")